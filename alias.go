@@ -0,0 +1,102 @@
+package kuid
+
+import (
+	"context"
+	"sync"
+)
+
+// AliasStore maps KUIDs to and from legacy identifiers, so both identifier
+// schemes keep resolving during long migrations. Single lookups return
+// ErrNotFound for unknown IDs; batch lookups omit them from the result.
+type AliasStore interface {
+	// Put links id and legacyID in both directions. Links either one
+	// already had are removed, so each KUID has at most one legacy ID and
+	// each legacy ID at most one KUID.
+	Put(ctx context.Context, id *KUID, legacyID string) error
+	// ToLegacy returns the legacy ID linked to id
+	ToLegacy(ctx context.Context, id *KUID) (string, error)
+	// FromLegacy returns the KUID linked to legacyID
+	FromLegacy(ctx context.Context, legacyID string) (*KUID, error)
+	// ToLegacyBatch resolves many KUIDs at once
+	ToLegacyBatch(ctx context.Context, ids []*KUID) (map[KUID]string, error)
+	// FromLegacyBatch resolves many legacy IDs at once
+	FromLegacyBatch(ctx context.Context, legacyIDs []string) (map[string]*KUID, error)
+}
+
+// MemoryAliasStore is an in-process AliasStore
+type MemoryAliasStore struct {
+	mu       sync.RWMutex
+	toLegacy map[KUID]string
+	fromLeg  map[string]KUID
+}
+
+// NewMemoryAliasStore creates an empty MemoryAliasStore
+func NewMemoryAliasStore() *MemoryAliasStore {
+	return &MemoryAliasStore{
+		toLegacy: make(map[KUID]string),
+		fromLeg:  make(map[string]KUID),
+	}
+}
+
+// Put implements AliasStore
+func (m *MemoryAliasStore) Put(_ context.Context, id *KUID, legacyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.toLegacy[*id]; ok {
+		delete(m.fromLeg, old)
+	}
+	if old, ok := m.fromLeg[legacyID]; ok {
+		delete(m.toLegacy, old)
+	}
+	m.toLegacy[*id] = legacyID
+	m.fromLeg[legacyID] = *id
+	return nil
+}
+
+// ToLegacy implements AliasStore
+func (m *MemoryAliasStore) ToLegacy(_ context.Context, id *KUID) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	legacyID, ok := m.toLegacy[*id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return legacyID, nil
+}
+
+// FromLegacy implements AliasStore
+func (m *MemoryAliasStore) FromLegacy(_ context.Context, legacyID string) (*KUID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	id, ok := m.fromLeg[legacyID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &id, nil
+}
+
+// ToLegacyBatch implements AliasStore
+func (m *MemoryAliasStore) ToLegacyBatch(_ context.Context, ids []*KUID) (map[KUID]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[KUID]string, len(ids))
+	for _, id := range ids {
+		if legacyID, ok := m.toLegacy[*id]; ok {
+			out[*id] = legacyID
+		}
+	}
+	return out, nil
+}
+
+// FromLegacyBatch implements AliasStore
+func (m *MemoryAliasStore) FromLegacyBatch(_ context.Context, legacyIDs []string) (map[string]*KUID, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string]*KUID, len(legacyIDs))
+	for _, legacyID := range legacyIDs {
+		if id, ok := m.fromLeg[legacyID]; ok {
+			out[legacyID] = &id
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+)
+
+// RedisDoer is the subset of a Redis client used by RedisAliasStore. It is
+// satisfied by redigo's ConnWithContext; go-redis clients can be adapted
+// with a one-line function calling client.Do(ctx, ...).Result().
+type RedisDoer interface {
+	DoContext(ctx context.Context, cmd string, args ...any) (any, error)
+}
+
+// RedisAliasStore is an AliasStore keeping both directions in two Redis
+// hashes, "<prefix>:to_legacy" and "<prefix>:from_legacy"
+type RedisAliasStore struct {
+	conn       RedisDoer
+	toLegacy   string
+	fromLegacy string
+}
+
+// NewRedisAliasStore creates a RedisAliasStore using keys under prefix
+func NewRedisAliasStore(conn RedisDoer, prefix string) *RedisAliasStore {
+	return &RedisAliasStore{
+		conn:       conn,
+		toLegacy:   prefix + ":to_legacy",
+		fromLegacy: prefix + ":from_legacy",
+	}
+}
+
+// putAliasScript drops the old links of both IDs and writes the new one to
+// both hashes atomically, even on pooled connections
+const putAliasScript = `local old = redis.call('HGET', KEYS[1], ARGV[1])
+if old then redis.call('HDEL', KEYS[2], old) end
+old = redis.call('HGET', KEYS[2], ARGV[2])
+if old then redis.call('HDEL', KEYS[1], old) end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('HSET', KEYS[2], ARGV[2], ARGV[1])
+return 1`
+
+// Put implements AliasStore. Both hashes are updated atomically by a Lua
+// script.
+func (r *RedisAliasStore) Put(ctx context.Context, id *KUID, legacyID string) error {
+	_, err := r.conn.DoContext(ctx, "EVAL", putAliasScript, 2, r.toLegacy, r.fromLegacy, id.String(), legacyID)
+	return err
+}
+
+// ToLegacy implements AliasStore
+func (r *RedisAliasStore) ToLegacy(ctx context.Context, id *KUID) (string, error) {
+	reply, err := r.conn.DoContext(ctx, "HGET", r.toLegacy, id.String())
+	if err != nil {
+		return "", err
+	}
+	legacyID, ok, err := redisString(reply)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrNotFound
+	}
+	return legacyID, nil
+}
+
+// FromLegacy implements AliasStore
+func (r *RedisAliasStore) FromLegacy(ctx context.Context, legacyID string) (*KUID, error) {
+	reply, err := r.conn.DoContext(ctx, "HGET", r.fromLegacy, legacyID)
+	if err != nil {
+		return nil, err
+	}
+	s, ok, err := redisString(reply)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return FromString(s)
+}
+
+// ToLegacyBatch implements AliasStore with a single HMGET
+func (r *RedisAliasStore) ToLegacyBatch(ctx context.Context, ids []*KUID) (map[KUID]string, error) {
+	out := make(map[KUID]string, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, r.toLegacy)
+	for _, id := range ids {
+		args = append(args, id.String())
+	}
+	values, err := r.hmget(ctx, args, len(ids))
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		legacyID, ok, err := redisString(v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out[*ids[i]] = legacyID
+		}
+	}
+	return out, nil
+}
+
+// FromLegacyBatch implements AliasStore with a single HMGET
+func (r *RedisAliasStore) FromLegacyBatch(ctx context.Context, legacyIDs []string) (map[string]*KUID, error) {
+	out := make(map[string]*KUID, len(legacyIDs))
+	if len(legacyIDs) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, 0, len(legacyIDs)+1)
+	args = append(args, r.fromLegacy)
+	for _, legacyID := range legacyIDs {
+		args = append(args, legacyID)
+	}
+	values, err := r.hmget(ctx, args, len(legacyIDs))
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		s, ok, err := redisString(v)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		id, err := FromString(s)
+		if err != nil {
+			return nil, err
+		}
+		out[legacyIDs[i]] = id
+	}
+	return out, nil
+}
+
+func (r *RedisAliasStore) hmget(ctx context.Context, args []any, n int) ([]any, error) {
+	reply, err := r.conn.DoContext(ctx, "HMGET", args...)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != n {
+		return nil, fmt.Errorf("unexpected HMGET reply %T", reply)
+	}
+	return values, nil
+}
+
+// redisString converts a bulk string reply, reporting false for nil
+func redisString(reply any) (string, bool, error) {
+	switch v := reply.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case []byte:
+		return string(v), true, nil
+	}
+	return "", false, fmt.Errorf("unexpected Redis reply %T", reply)
+}
@@ -0,0 +1,65 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// fakeRedis implements the hash commands used by RedisAliasStore
+type fakeRedis struct {
+	hashes map[string]map[string]string
+}
+
+func (f *fakeRedis) DoContext(_ context.Context, cmd string, args ...any) (any, error) {
+	str := func(v any) string { return fmt.Sprint(v) }
+	hash := func(key any) map[string]string {
+		if f.hashes[str(key)] == nil {
+			f.hashes[str(key)] = make(map[string]string)
+		}
+		return f.hashes[str(key)]
+	}
+
+	switch cmd {
+	case "EVAL":
+		// Only the alias script is supported: KEYS = args[2:4], ARGV = args[4:6]
+		if args[0] != putAliasScript {
+			return nil, fmt.Errorf("unexpected script")
+		}
+		to, from := hash(args[2]), hash(args[3])
+		id, legacyID := str(args[4]), str(args[5])
+		if old, ok := to[id]; ok {
+			delete(from, old)
+		}
+		if old, ok := from[legacyID]; ok {
+			delete(to, old)
+		}
+		to[id] = legacyID
+		from[legacyID] = id
+		return int64(1), nil
+	case "HGET":
+		if v, ok := hash(args[0])[str(args[1])]; ok {
+			return []byte(v), nil
+		}
+		return nil, nil
+	case "HMGET":
+		h := hash(args[0])
+		out := make([]any, len(args)-1)
+		for i, field := range args[1:] {
+			if v, ok := h[str(field)]; ok {
+				out[i] = []byte(v)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unsupported command %s", cmd)
+}
+
+func TestRedisAliasStore(t *testing.T) {
+	conn := &fakeRedis{hashes: make(map[string]map[string]string)}
+	testAliasStore(t, NewRedisAliasStore(conn, "aliases"))
+
+	if _, ok := conn.hashes["aliases:to_legacy"]; !ok {
+		t.Errorf("Expected hash aliases:to_legacy to be written")
+	}
+}
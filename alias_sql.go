@@ -0,0 +1,174 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SQLAliasStore is an AliasStore backed by a table with two unique columns:
+//
+//	CREATE TABLE kuid_aliases (
+//	    kuid      CHAR(22)     NOT NULL PRIMARY KEY,
+//	    legacy_id VARCHAR(255) NOT NULL UNIQUE
+//	);
+//
+// KUIDs are stored in their base62 string form so the table is readable
+// and portable across databases.
+type SQLAliasStore struct {
+	db           *sql.DB
+	table        string
+	dollarParams bool
+}
+
+// NewSQLAliasStore creates a SQLAliasStore on table. Set dollarParams for
+// drivers using $1-style placeholders (PostgreSQL); otherwise ? is used.
+func NewSQLAliasStore(db *sql.DB, table string, dollarParams bool) (*SQLAliasStore, error) {
+	if !validIdentifier(table) {
+		return nil, errors.New("invalid alias table name")
+	}
+	return &SQLAliasStore{db: db, table: table, dollarParams: dollarParams}, nil
+}
+
+// Put implements AliasStore. Existing links for either ID are replaced.
+func (s *SQLAliasStore) Put(ctx context.Context, id *KUID, legacyID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	del := fmt.Sprintf("DELETE FROM %s WHERE kuid = %s OR legacy_id = %s", s.table, s.param(1), s.param(2))
+	if _, err := tx.ExecContext(ctx, del, id.String(), legacyID); err != nil {
+		return err
+	}
+	ins := fmt.Sprintf("INSERT INTO %s (kuid, legacy_id) VALUES (%s, %s)", s.table, s.param(1), s.param(2))
+	if _, err := tx.ExecContext(ctx, ins, id.String(), legacyID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ToLegacy implements AliasStore
+func (s *SQLAliasStore) ToLegacy(ctx context.Context, id *KUID) (string, error) {
+	query := fmt.Sprintf("SELECT legacy_id FROM %s WHERE kuid = %s", s.table, s.param(1))
+	var legacyID string
+	err := s.db.QueryRowContext(ctx, query, id.String()).Scan(&legacyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	return legacyID, err
+}
+
+// FromLegacy implements AliasStore
+func (s *SQLAliasStore) FromLegacy(ctx context.Context, legacyID string) (*KUID, error) {
+	query := fmt.Sprintf("SELECT kuid FROM %s WHERE legacy_id = %s", s.table, s.param(1))
+	var str string
+	err := s.db.QueryRowContext(ctx, query, legacyID).Scan(&str)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return FromString(str)
+}
+
+// ToLegacyBatch implements AliasStore with a single IN query
+func (s *SQLAliasStore) ToLegacyBatch(ctx context.Context, ids []*KUID) (map[KUID]string, error) {
+	out := make(map[KUID]string, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id.String()
+	}
+	query := fmt.Sprintf("SELECT kuid, legacy_id FROM %s WHERE kuid IN (%s)", s.table, s.params(len(ids)))
+	err := s.scanPairs(ctx, query, args, func(id *KUID, legacyID string) {
+		out[*id] = legacyID
+	})
+	return out, err
+}
+
+// FromLegacyBatch implements AliasStore with a single IN query
+func (s *SQLAliasStore) FromLegacyBatch(ctx context.Context, legacyIDs []string) (map[string]*KUID, error) {
+	out := make(map[string]*KUID, len(legacyIDs))
+	if len(legacyIDs) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, len(legacyIDs))
+	for i, legacyID := range legacyIDs {
+		args[i] = legacyID
+	}
+	query := fmt.Sprintf("SELECT kuid, legacy_id FROM %s WHERE legacy_id IN (%s)", s.table, s.params(len(legacyIDs)))
+	err := s.scanPairs(ctx, query, args, func(id *KUID, legacyID string) {
+		out[legacyID] = id
+	})
+	return out, err
+}
+
+func (s *SQLAliasStore) scanPairs(ctx context.Context, query string, args []any, fn func(*KUID, string)) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var str, legacyID string
+		if err := rows.Scan(&str, &legacyID); err != nil {
+			return err
+		}
+		id, err := FromString(str)
+		if err != nil {
+			return err
+		}
+		fn(id, legacyID)
+	}
+	return rows.Err()
+}
+
+// param returns the placeholder for the nth argument
+func (s *SQLAliasStore) param(n int) string {
+	if s.dollarParams {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// params returns a comma separated list of n placeholders
+func (s *SQLAliasStore) params(n int) string {
+	ps := make([]string, n)
+	for i := range ps {
+		ps[i] = s.param(i + 1)
+	}
+	return strings.Join(ps, ", ")
+}
+
+// validIdentifier reports whether name is a plain, optionally
+// schema-qualified SQL identifier that is safe to interpolate
+func validIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			return false
+		}
+		for i := 0; i < len(part); i++ {
+			c := part[i]
+			letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			if !letter && (i == 0 || c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSQLAliasStore(t *testing.T) {
+	db := sql.OpenDB(&aliasDB{})
+	defer db.Close()
+	store, err := NewSQLAliasStore(db, "kuid_aliases", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAliasStore(t, store)
+}
+
+func TestSQLAliasStorePlaceholders(t *testing.T) {
+	dollar, err := NewSQLAliasStore(nil, "public.kuid_aliases", true)
+	if err != nil {
+		t.Fatalf("NewSQLAliasStore() error = %v", err)
+	}
+	if got := dollar.params(3); got != "$1, $2, $3" {
+		t.Errorf("params() = %v, want $1, $2, $3", got)
+	}
+
+	question, _ := NewSQLAliasStore(nil, "kuid_aliases", false)
+	if got := question.params(3); got != "?, ?, ?" {
+		t.Errorf("params() = %v, want ?, ?, ?", got)
+	}
+}
+
+func TestValidIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"kuid_aliases", true},
+		{"public.kuid_aliases", true},
+		{"_t1", true},
+		{"", false},
+		{"1table", false},
+		{"public.", false},
+		{"aliases; DROP TABLE users", false},
+		{"`aliases`", false},
+	}
+
+	for _, tt := range tests {
+		if got := validIdentifier(tt.name); got != tt.want {
+			t.Errorf("validIdentifier(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// aliasDB is a database/sql driver running the statements SQLAliasStore
+// issues against an in-memory table with two unique columns
+type aliasDB struct {
+	rows [][2]string // kuid, legacy_id
+}
+
+func (d *aliasDB) Connect(context.Context) (driver.Conn, error) { return d, nil }
+func (d *aliasDB) Driver() driver.Driver                        { return nil }
+func (d *aliasDB) Prepare(query string) (driver.Stmt, error) {
+	return &aliasStmt{db: d, query: query}, nil
+}
+func (d *aliasDB) Close() error              { return nil }
+func (d *aliasDB) Begin() (driver.Tx, error) { return d, nil }
+
+// Statements apply immediately; the test never rolls back a change
+func (d *aliasDB) Commit() error   { return nil }
+func (d *aliasDB) Rollback() error { return nil }
+
+type aliasStmt struct {
+	db    *aliasDB
+	query string
+}
+
+func (s *aliasStmt) Close() error  { return nil }
+func (s *aliasStmt) NumInput() int { return -1 }
+
+func (s *aliasStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.db
+	switch {
+	case strings.HasPrefix(s.query, "DELETE FROM kuid_aliases WHERE kuid = ? OR legacy_id = ?"):
+		kept := d.rows[:0]
+		for _, row := range d.rows {
+			if row[0] != args[0] && row[1] != args[1] {
+				kept = append(kept, row)
+			}
+		}
+		d.rows = kept
+	case strings.HasPrefix(s.query, "INSERT INTO kuid_aliases (kuid, legacy_id)"):
+		for _, row := range d.rows {
+			if row[0] == args[0] || row[1] == args[1] {
+				return nil, fmt.Errorf("unique constraint violated by %v", args)
+			}
+		}
+		d.rows = append(d.rows, [2]string{args[0].(string), args[1].(string)})
+	default:
+		return nil, fmt.Errorf("unsupported statement %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *aliasStmt) Query(args []driver.Value) (driver.Rows, error) {
+	var col, want int // column matched and column or columns returned
+	switch {
+	case strings.HasPrefix(s.query, "SELECT legacy_id FROM kuid_aliases WHERE kuid ="):
+		col, want = 0, 1
+	case strings.HasPrefix(s.query, "SELECT kuid FROM kuid_aliases WHERE legacy_id ="):
+		col, want = 1, 0
+	case strings.HasPrefix(s.query, "SELECT kuid, legacy_id FROM kuid_aliases WHERE kuid IN"):
+		col, want = 0, -1
+	case strings.HasPrefix(s.query, "SELECT kuid, legacy_id FROM kuid_aliases WHERE legacy_id IN"):
+		col, want = 1, -1
+	default:
+		return nil, fmt.Errorf("unsupported query %q", s.query)
+	}
+	out := &aliasRows{pair: want < 0}
+	for _, row := range s.db.rows {
+		for _, arg := range args {
+			if row[col] != arg {
+				continue
+			}
+			if want < 0 {
+				out.values = append(out.values, []driver.Value{row[0], row[1]})
+			} else {
+				out.values = append(out.values, []driver.Value{row[want]})
+			}
+		}
+	}
+	return out, nil
+}
+
+type aliasRows struct {
+	pair   bool
+	values [][]driver.Value
+}
+
+func (r *aliasRows) Columns() []string {
+	if r.pair {
+		return []string{"kuid", "legacy_id"}
+	}
+	return []string{"value"}
+}
+
+func (r *aliasRows) Close() error { return nil }
+
+func (r *aliasRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,88 @@
+package kuid
+
+import (
+	"context"
+	"testing"
+)
+
+// testAliasStore checks the AliasStore contract; every implementation
+// runs it
+func testAliasStore(t *testing.T, store AliasStore) {
+	ctx := context.Background()
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	unknown, _ := NewKUID()
+
+	if err := store.Put(ctx, a, "user-1"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := store.Put(ctx, b, "user-2"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	legacyID, err := store.ToLegacy(ctx, a)
+	if err != nil || legacyID != "user-1" {
+		t.Errorf("ToLegacy() = %v, %v, want user-1", legacyID, err)
+	}
+	id, err := store.FromLegacy(ctx, "user-2")
+	if err != nil || !id.Equal(b) {
+		t.Errorf("FromLegacy() = %v, %v, want %v", id, err, b)
+	}
+
+	if _, err := store.ToLegacy(ctx, unknown); err != ErrNotFound {
+		t.Errorf("ToLegacy() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := store.FromLegacy(ctx, "user-3"); err != ErrNotFound {
+		t.Errorf("FromLegacy() error = %v, want %v", err, ErrNotFound)
+	}
+
+	legacy, err := store.ToLegacyBatch(ctx, []*KUID{a, b, unknown})
+	if err != nil {
+		t.Fatalf("ToLegacyBatch() error = %v", err)
+	}
+	if len(legacy) != 2 || legacy[*a] != "user-1" || legacy[*b] != "user-2" {
+		t.Errorf("ToLegacyBatch() = %v", legacy)
+	}
+
+	ids, err := store.FromLegacyBatch(ctx, []string{"user-1", "user-2", "user-3"})
+	if err != nil {
+		t.Fatalf("FromLegacyBatch() error = %v", err)
+	}
+	if len(ids) != 2 || !ids["user-1"].Equal(a) || !ids["user-2"].Equal(b) {
+		t.Errorf("FromLegacyBatch() = %v", ids)
+	}
+
+	// Re-linking a KUID frees its old legacy ID
+	if err := store.Put(ctx, a, "user-9"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if legacyID, err := store.ToLegacy(ctx, a); err != nil || legacyID != "user-9" {
+		t.Errorf("ToLegacy() after re-link = %v, %v, want user-9", legacyID, err)
+	}
+	if _, err := store.FromLegacy(ctx, "user-1"); err != ErrNotFound {
+		t.Errorf("FromLegacy() of replaced legacy ID error = %v, want %v", err, ErrNotFound)
+	}
+
+	// Re-linking a legacy ID frees its old KUID
+	if err := store.Put(ctx, b, "user-9"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if id, err := store.FromLegacy(ctx, "user-9"); err != nil || !id.Equal(b) {
+		t.Errorf("FromLegacy() after re-link = %v, %v, want %v", id, err, b)
+	}
+	if _, err := store.ToLegacy(ctx, a); err != ErrNotFound {
+		t.Errorf("ToLegacy() of replaced KUID error = %v, want %v", err, ErrNotFound)
+	}
+	legacy, err = store.ToLegacyBatch(ctx, []*KUID{a, b})
+	if err != nil || len(legacy) != 1 || legacy[*b] != "user-9" {
+		t.Errorf("ToLegacyBatch() after re-link = %v, %v", legacy, err)
+	}
+	ids, err = store.FromLegacyBatch(ctx, []string{"user-1", "user-2", "user-9"})
+	if err != nil || len(ids) != 1 || !ids["user-9"].Equal(b) {
+		t.Errorf("FromLegacyBatch() after re-link = %v, %v", ids, err)
+	}
+}
+
+func TestMemoryAliasStore(t *testing.T) {
+	testAliasStore(t, NewMemoryAliasStore())
+}
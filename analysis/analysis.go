@@ -0,0 +1,165 @@
+// Package analysis checks the statistical quality of a stream of KUIDs. It
+// is meant for verifying generators, including third-party implementations
+// claiming KUID compatibility, rather than for use on hot paths: an Analyzer
+// remembers every KUID it sees.
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+const (
+	bits = 128
+
+	// biasSigmas is how many standard deviations a bit's frequency of ones
+	// may stray from one half before it is reported as biased
+	biasSigmas = 5
+
+	// histogramBuckets is the number of buckets in the timestamp histogram
+	histogramBuckets = 10
+)
+
+// Report summarises a stream of KUIDs
+type Report struct {
+	Count      int // valid KUIDs analysed
+	Invalid    int // lines that did not parse
+	Duplicates int // KUIDs seen more than once, counting each repeat
+
+	// OnesRatio is the fraction of KUIDs with each bit set, most significant
+	// bit first. Random bits should all be close to 0.5.
+	OnesRatio  [bits]float64
+	MaxBias    float64 // largest |OnesRatio - 0.5|
+	BiasedBits []int   // bits more than 5 standard deviations from 0.5
+
+	// Violations counts KUIDs that sort before their predecessor in the
+	// stream. Only meaningful for streams of ordered KUIDs.
+	Violations int
+
+	// Timestamps is the distribution of embedded timestamps. Only
+	// meaningful for ordered and HLC KUIDs.
+	Timestamps Histogram
+}
+
+// Histogram is a distribution of timestamps over equal-width buckets
+type Histogram struct {
+	Min, Max time.Time
+	Width    time.Duration
+	Counts   []int
+}
+
+// Analyzer accumulates KUIDs for a Report. The zero value is ready to use.
+type Analyzer struct {
+	count      int
+	invalid    int
+	duplicates int
+	violations int
+	ones       [bits]int
+	seen       map[kuid.KUID]struct{}
+	prev       []byte
+	timestamps []int64
+}
+
+// Add analyses the next KUID in the stream
+func (a *Analyzer) Add(k *kuid.KUID) {
+	if a.seen == nil {
+		a.seen = make(map[kuid.KUID]struct{})
+	}
+	a.count++
+	if _, ok := a.seen[*k]; ok {
+		a.duplicates++
+	} else {
+		a.seen[*k] = struct{}{}
+	}
+
+	b := k.Bytes()
+	for i := 0; i < bits; i++ {
+		if b[i/8]&(0x80>>(i%8)) != 0 {
+			a.ones[i]++
+		}
+	}
+	if a.prev != nil && bytes.Compare(b, a.prev) < 0 {
+		a.violations++
+	}
+	a.prev = b
+	a.timestamps = append(a.timestamps, k.Timestamp().UnixMilli())
+}
+
+// AddString parses s as a KUID or UUID and analyses it. Unparseable input
+// is counted as invalid.
+func (a *Analyzer) AddString(s string) {
+	k, err := kuid.Parse(s)
+	if err != nil {
+		a.invalid++
+		return
+	}
+	a.Add(k)
+}
+
+// Report summarises the KUIDs added so far
+func (a *Analyzer) Report() Report {
+	r := Report{
+		Count:      a.count,
+		Invalid:    a.invalid,
+		Duplicates: a.duplicates,
+		Violations: a.violations,
+	}
+	if a.count == 0 {
+		return r
+	}
+
+	limit := biasSigmas * 0.5 / math.Sqrt(float64(a.count))
+	for i, n := range a.ones {
+		r.OnesRatio[i] = float64(n) / float64(a.count)
+		bias := math.Abs(r.OnesRatio[i] - 0.5)
+		r.MaxBias = max(r.MaxBias, bias)
+		if bias > limit {
+			r.BiasedBits = append(r.BiasedBits, i)
+		}
+	}
+
+	r.Timestamps = histogram(a.timestamps)
+	return r
+}
+
+func histogram(ms []int64) Histogram {
+	lo, hi := ms[0], ms[0]
+	for _, t := range ms {
+		lo, hi = min(lo, t), max(hi, t)
+	}
+	width := (hi-lo)/histogramBuckets + 1
+	h := Histogram{
+		Min:    time.UnixMilli(lo),
+		Max:    time.UnixMilli(hi),
+		Width:  time.Duration(width) * time.Millisecond,
+		Counts: make([]int, histogramBuckets),
+	}
+	for _, t := range ms {
+		h.Counts[(t-lo)/width]++
+	}
+	return h
+}
+
+// Read analyses newline-separated KUIDs or UUIDs from r. Blank lines are
+// skipped.
+func Read(r io.Reader) (Report, error) {
+	var a Analyzer
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		a.AddString(line)
+	}
+	if err := sc.Err(); err != nil {
+		return Report{}, err
+	}
+	return a.Report(), nil
+}
@@ -0,0 +1,85 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestRandomStream(t *testing.T) {
+	var a Analyzer
+	for i := 0; i < 20000; i++ {
+		k, _ := kuid.NewKUID()
+		a.Add(k)
+	}
+	r := a.Report()
+
+	if r.Count != 20000 || r.Duplicates != 0 {
+		t.Errorf("Count = %d, Duplicates = %d", r.Count, r.Duplicates)
+	}
+	if len(r.BiasedBits) > 0 {
+		t.Errorf("BiasedBits = %v, want none", r.BiasedBits)
+	}
+}
+
+func TestBiasedStream(t *testing.T) {
+	var a Analyzer
+	for i := 0; i < 1000; i++ {
+		k, _ := kuid.NewKUID()
+		b := k.Bytes()
+		b[15] |= 1 // stuck bit
+		k, _ = kuid.FromBytes(b)
+		a.Add(k)
+	}
+	r := a.Report()
+	if len(r.BiasedBits) != 1 || r.BiasedBits[0] != 127 {
+		t.Errorf("BiasedBits = %v, want [127]", r.BiasedBits)
+	}
+	if r.MaxBias != 0.5 {
+		t.Errorf("MaxBias = %v, want 0.5", r.MaxBias)
+	}
+}
+
+func TestOrderedStream(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	var ids []*kuid.KUID
+	for i := 0; i < 100; i++ {
+		k, _ := g.NewOrdered()
+		ids = append(ids, k)
+	}
+
+	var a Analyzer
+	for _, k := range ids {
+		a.Add(k)
+	}
+	a.Add(ids[10]) // duplicate, and out of order
+	r := a.Report()
+
+	if r.Duplicates != 1 || r.Violations != 1 {
+		t.Errorf("Duplicates = %d, Violations = %d, want 1, 1", r.Duplicates, r.Violations)
+	}
+	total := 0
+	for _, n := range r.Timestamps.Counts {
+		total += n
+	}
+	if total != r.Count {
+		t.Errorf("Histogram holds %d KUIDs, want %d", total, r.Count)
+	}
+	if r.Timestamps.Min.After(ids[0].Timestamp()) || r.Timestamps.Max.Before(ids[99].Timestamp()) {
+		t.Errorf("Histogram range %v..%v excludes stream", r.Timestamps.Min, r.Timestamps.Max)
+	}
+}
+
+func TestRead(t *testing.T) {
+	k, _ := kuid.NewKUID()
+	input := k.String() + "\n\n" + k.ToUUID() + "\nnot-an-id\n"
+
+	r, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Count != 2 || r.Invalid != 1 || r.Duplicates != 1 {
+		t.Errorf("Count = %d, Invalid = %d, Duplicates = %d, want 2, 1, 1", r.Count, r.Invalid, r.Duplicates)
+	}
+}
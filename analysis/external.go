@@ -0,0 +1,162 @@
+package analysis
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+)
+
+const (
+	defaultPartitions = 256
+	recordSize        = 16
+)
+
+// DedupeOptions configures FindDuplicates
+type DedupeOptions struct {
+	// Partitions is the number of temporary files the stream is spread
+	// over; each is sorted in memory on its own, so peak memory is about
+	// 16 bytes per KUID divided by Partitions. Zero means 256, which keeps
+	// a billion KUIDs under 64 MiB.
+	Partitions int
+	// TempDir holds the partition files; os.TempDir() when empty
+	TempDir string
+}
+
+// Duplicate is a KUID that occurs more than once
+type Duplicate struct {
+	ID    kuid.KUID
+	Count int
+}
+
+// DedupeStats summarises a FindDuplicates run
+type DedupeStats struct {
+	Count      int64 // valid KUIDs read
+	Invalid    int64 // lines that did not parse
+	Duplicates int64 // distinct KUIDs that occur more than once
+}
+
+// FindDuplicates reads newline-separated KUIDs or UUIDs from r and calls fn
+// for every KUID that occurs more than once. Unlike Analyzer it does not
+// hold the stream in memory: KUIDs are hash-partitioned into temporary files
+// which are then sorted one at a time. Duplicates are reported in no
+// particular order. An error from fn stops the search and is returned.
+func FindDuplicates(r io.Reader, opts DedupeOptions, fn func(Duplicate) error) (DedupeStats, error) {
+	n := opts.Partitions
+	if n <= 0 {
+		n = defaultPartitions
+	}
+
+	dir, err := os.MkdirTemp(opts.TempDir, "kuid-dedupe-")
+	if err != nil {
+		return DedupeStats{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	files := make([]*os.File, n)
+	writers := make([]*bufio.Writer, n)
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+	for i := range files {
+		if files[i], err = os.CreateTemp(dir, "part-"); err != nil {
+			return DedupeStats{}, err
+		}
+		writers[i] = bufio.NewWriterSize(files[i], 64<<10)
+	}
+
+	stats, err := partition(r, writers)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, f := range files {
+		dups, err := duplicatesIn(f, fn)
+		stats.Duplicates += dups
+		if err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// partition spreads the parsed stream over the writers by hash
+func partition(r io.Reader, writers []*bufio.Writer) (DedupeStats, error) {
+	var stats DedupeStats
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		k, err := kuid.Parse(line)
+		if err != nil {
+			stats.Invalid++
+			continue
+		}
+		// Partition hashes both halves, so ordered, typed and v6 KUIDs
+		// with fixed bits still spread evenly and no file outgrows the
+		// memory bound
+		if _, err := writers[k.Partition(len(writers))].Write(k.Bytes()); err != nil {
+			return stats, err
+		}
+		stats.Count++
+	}
+	if err := sc.Err(); err != nil {
+		return stats, err
+	}
+	for _, w := range writers {
+		if err := w.Flush(); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// duplicatesIn sorts one partition file in memory and reports its
+// duplicates
+func duplicatesIn(f *os.File, fn func(Duplicate) error) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if info.Size()%recordSize != 0 {
+		return 0, errors.New("corrupt partition file")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	records := make([][recordSize]byte, info.Size()/recordSize)
+	br := bufio.NewReaderSize(f, 64<<10)
+	for i := range records {
+		if _, err := io.ReadFull(br, records[i][:]); err != nil {
+			return 0, err
+		}
+	}
+	sortRecords(records)
+
+	var dups int64
+	for i := 0; i < len(records); {
+		j := i + 1
+		for j < len(records) && records[j] == records[i] {
+			j++
+		}
+		if j-i > 1 {
+			dups++
+			k, _ := kuid.FromBytes(records[i][:])
+			if err := fn(Duplicate{ID: *k, Count: j - i}); err != nil {
+				return dups, err
+			}
+		}
+		i = j
+	}
+	return dups, nil
+}
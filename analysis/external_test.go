@@ -0,0 +1,81 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	var lines []string
+	for i := 0; i < 5000; i++ {
+		k, _ := g.NewOrdered()
+		lines = append(lines, k.String())
+	}
+	// lines[10] three times in total, lines[20] twice (once as a UUID)
+	a, _ := kuid.FromString(lines[10])
+	b, _ := kuid.FromString(lines[20])
+	lines = append(lines, lines[10], "", "garbage", lines[10], b.ToUUID())
+
+	found := map[kuid.KUID]int{}
+	stats, err := FindDuplicates(strings.NewReader(strings.Join(lines, "\n")),
+		DedupeOptions{Partitions: 8, TempDir: t.TempDir()},
+		func(d Duplicate) error {
+			found[d.ID] = d.Count
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Count != 5003 || stats.Invalid != 1 || stats.Duplicates != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if found[*a] != 3 || found[*b] != 2 || len(found) != 2 {
+		t.Errorf("found = %v", found)
+	}
+}
+
+func TestPartitionSpread(t *testing.T) {
+	// Random KUIDs whose low 16 bits are fixed in both halves, as with
+	// type tags, and whose halves share a fixed pattern
+	var lines []string
+	for i := 0; i < 4000; i++ {
+		k, _ := kuid.NewValue()
+		id := k.Array()
+		id[6], id[7], id[14], id[15] = 0, 0, 0x12, 0x34
+		lines = append(lines, kuid.FromArray(id).String())
+	}
+
+	const n = 16
+	var bufs [n]bytes.Buffer
+	writers := make([]*bufio.Writer, n)
+	for i := range writers {
+		writers[i] = bufio.NewWriter(&bufs[i])
+	}
+	if _, err := partition(strings.NewReader(strings.Join(lines, "\n")), writers); err != nil {
+		t.Fatal(err)
+	}
+	for i := range bufs {
+		if got := bufs[i].Len() / recordSize; got > 2*len(lines)/n {
+			t.Errorf("Partition %d holds %d of %d KUIDs", i, got, len(lines))
+		}
+	}
+}
+
+func TestFindDuplicatesStop(t *testing.T) {
+	k, _ := kuid.NewKUID()
+	input := strings.Repeat(k.String()+"\n", 3)
+	stop := errors.New("stop")
+
+	_, err := FindDuplicates(strings.NewReader(input), DedupeOptions{TempDir: t.TempDir()},
+		func(Duplicate) error { return stop })
+	if err != stop {
+		t.Errorf("FindDuplicates() error = %v, want %v", err, stop)
+	}
+}
@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+// ViolationKind classifies an ordering violation
+type ViolationKind int
+
+const (
+	// OutOfOrder marks a KUID that does not sort after every KUID before it
+	OutOfOrder ViolationKind = iota
+	// Backdated marks an out-of-order KUID whose timestamp lags the newest
+	// timestamp seen by more than the checker's tolerance, typically one
+	// minted on a node with a slow clock
+	Backdated
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case OutOfOrder:
+		return "out of order"
+	case Backdated:
+		return "backdated"
+	default:
+		return fmt.Sprintf("ViolationKind(%d)", int(k))
+	}
+}
+
+// Violation describes a KUID that breaks the ordering of its stream
+type Violation struct {
+	Kind      ViolationKind
+	Position  int           // position in the stream, counting from 1
+	ID        kuid.KUID     // the offending KUID
+	HighWater kuid.KUID     // the greatest KUID before it
+	Lag       time.Duration // how far ID's timestamp trails HighWater's
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%d: %s %s after %s (lag %s)", v.Position, v.Kind, v.ID.String(), v.HighWater.String(), v.Lag)
+}
+
+// MonotonicityChecker verifies that a stream of ordered KUIDs is strictly
+// increasing. The zero value reports every regression as OutOfOrder
+// or, when the timestamp went backwards at all, Backdated.
+type MonotonicityChecker struct {
+	// Tolerance is how far a timestamp may trail the newest one seen before
+	// an out-of-order KUID is reported as Backdated
+	Tolerance time.Duration
+
+	position  int
+	highWater *kuid.KUID
+	highBytes []byte
+}
+
+// Check records the next KUID in the stream and reports whether it
+// violates ordering
+func (c *MonotonicityChecker) Check(k *kuid.KUID) (Violation, bool) {
+	c.position++
+	b := k.Bytes()
+	if c.highWater == nil || bytes.Compare(b, c.highBytes) > 0 {
+		c.highWater, c.highBytes = k, b
+		return Violation{}, false
+	}
+
+	v := Violation{
+		Kind:      OutOfOrder,
+		Position:  c.position,
+		ID:        *k,
+		HighWater: *c.highWater,
+		Lag:       c.highWater.Timestamp().Sub(k.Timestamp()),
+	}
+	if v.Lag > c.Tolerance {
+		v.Kind = Backdated
+	}
+	return v, true
+}
+
+// CheckMonotonic checks newline-separated KUIDs from r, one per line, and
+// returns every violation. Blank lines are skipped, and violation positions
+// are line numbers.
+func CheckMonotonic(r io.Reader, tolerance time.Duration) ([]Violation, error) {
+	c := MonotonicityChecker{Tolerance: tolerance}
+	var violations []Violation
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		k, err := kuid.Parse(text)
+		if err != nil {
+			return violations, fmt.Errorf("line %d: %w", line, err)
+		}
+		if v, bad := c.Check(k); bad {
+			v.Position = line
+			violations = append(violations, v)
+		}
+	}
+	return violations, sc.Err()
+}
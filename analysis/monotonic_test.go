@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestMonotonicityChecker(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	var ids []*kuid.KUID
+	for i := 0; i < 10; i++ {
+		k, _ := g.NewOrdered()
+		ids = append(ids, k)
+	}
+	// An ordered-layout KUID from an hour ago
+	old, _ := kuid.FromSequence(uint64(time.Now().Add(-time.Hour).UnixMilli())<<16, kuid.SequenceOptions{})
+
+	c := MonotonicityChecker{Tolerance: time.Second}
+	for i, k := range ids[:5] {
+		if v, bad := c.Check(k); bad {
+			t.Fatalf("Check(ids[%d]) = %v", i, v)
+		}
+	}
+
+	v, bad := c.Check(ids[2])
+	if !bad || v.Kind != OutOfOrder || v.Position != 6 || v.HighWater != *ids[4] {
+		t.Errorf("Check(repeat) = %v, %v", v, bad)
+	}
+
+	v, bad = c.Check(old)
+	if !bad || v.Kind != Backdated || v.Lag < 59*time.Minute {
+		t.Errorf("Check(old) = %v, %v", v, bad)
+	}
+
+	// The stream recovers once IDs pass the high-water mark again
+	if v, bad := c.Check(ids[5]); bad {
+		t.Errorf("Check(ids[5]) = %v", v)
+	}
+}
+
+func TestCheckMonotonic(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	a, _ := g.NewOrdered()
+	b, _ := g.NewOrdered()
+
+	violations, err := CheckMonotonic(strings.NewReader(a.String()+"\n"+b.String()+"\n"+a.String()+"\n"), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Position != 3 || violations[0].Kind != OutOfOrder {
+		t.Errorf("CheckMonotonic() = %v", violations)
+	}
+
+	if _, err := CheckMonotonic(strings.NewReader(a.String()+"\nbad\n"), 0); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("CheckMonotonic() error = %v, want line 2", err)
+	}
+
+	// Blank lines are skipped but still count towards line numbers
+	violations, err = CheckMonotonic(strings.NewReader(a.String()+"\n\n"+b.String()+"\n  \n"+a.String()+"\n"), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 1 || violations[0].Position != 5 {
+		t.Errorf("CheckMonotonic() with blank lines = %v, want one violation on line 5", violations)
+	}
+}
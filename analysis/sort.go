@@ -0,0 +1,233 @@
+package analysis
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+)
+
+const defaultRunSize = 4 << 20
+
+// SortOptions configures Sorted
+type SortOptions struct {
+	// RunSize is the number of KUIDs sorted in memory before they are
+	// spilled to a temporary run file. Peak memory is about 16 bytes per
+	// KUID in a run. Zero means 4Mi KUIDs, or 64 MiB.
+	RunSize int
+	// TempDir holds the run files; os.TempDir() when empty
+	TempDir string
+}
+
+// SortStats summarises the input of a Sorted stream
+type SortStats struct {
+	Count   int64 // valid KUIDs read
+	Invalid int64 // lines that did not parse
+	Runs    int   // run files spilled to disk
+}
+
+// SortedStream yields KUIDs in binary order, the order of their Bytes and
+// of their base62 strings. Iterate it like a bufio.Scanner and Close it
+// when done to remove its temporary files.
+type SortedStream struct {
+	stats SortStats
+	dir   string
+	files []*os.File
+	runs  runHeap
+	id    kuid.KUID
+	err   error
+}
+
+// Sorted reads newline-separated KUIDs or UUIDs from r and returns them
+// sorted, duplicates included. Inputs larger than RunSize are sorted with
+// an external merge sort, so the stream may be far larger than memory.
+func Sorted(r io.Reader, opts SortOptions) (*SortedStream, error) {
+	size := opts.RunSize
+	if size <= 0 {
+		size = defaultRunSize
+	}
+
+	s := &SortedStream{}
+	records := make([][recordSize]byte, 0, min(size, 1<<16))
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		k, err := kuid.Parse(line)
+		if err != nil {
+			s.stats.Invalid++
+			continue
+		}
+		records = append(records, [recordSize]byte(k.Bytes()))
+		s.stats.Count++
+		if len(records) == size {
+			if err := s.spill(records, opts.TempDir); err != nil {
+				s.Close()
+				return nil, err
+			}
+			records = records[:0]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	// The final run never touches the disk
+	sortRecords(records)
+	s.runs = append(s.runs, &run{records: records})
+	for _, f := range s.files {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.runs = append(s.runs, &run{r: bufio.NewReaderSize(f, 64<<10)})
+	}
+
+	live := s.runs[:0]
+	for _, rn := range s.runs {
+		ok, err := rn.advance()
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		if ok {
+			live = append(live, rn)
+		}
+	}
+	s.runs = live
+	heap.Init(&s.runs)
+	return s, nil
+}
+
+// spill sorts records into a new run file
+func (s *SortedStream) spill(records [][recordSize]byte, tempDir string) error {
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(tempDir, "kuid-sort-")
+		if err != nil {
+			return err
+		}
+		s.dir = dir
+	}
+	f, err := os.CreateTemp(s.dir, "run-")
+	if err != nil {
+		return err
+	}
+	s.files = append(s.files, f)
+
+	sortRecords(records)
+	w := bufio.NewWriterSize(f, 64<<10)
+	for i := range records {
+		if _, err := w.Write(records[i][:]); err != nil {
+			return err
+		}
+	}
+	s.stats.Runs++
+	return w.Flush()
+}
+
+// Next advances to the next KUID, returning false at the end of the stream
+// or on error
+func (s *SortedStream) Next() bool {
+	if s.err != nil || len(s.runs) == 0 {
+		return false
+	}
+	rn := s.runs[0]
+	s.id = rn.id()
+	ok, err := rn.advance()
+	switch {
+	case err != nil:
+		s.err = err
+		return false
+	case ok:
+		heap.Fix(&s.runs, 0)
+	default:
+		heap.Pop(&s.runs)
+	}
+	return true
+}
+
+// ID returns the KUID Next advanced to
+func (s *SortedStream) ID() kuid.KUID {
+	return s.id
+}
+
+// Err returns the first error hit while merging
+func (s *SortedStream) Err() error {
+	return s.err
+}
+
+// Stats summarises the input
+func (s *SortedStream) Stats() SortStats {
+	return s.stats
+}
+
+// Close removes the stream's temporary files
+func (s *SortedStream) Close() error {
+	for _, f := range s.files {
+		f.Close()
+	}
+	s.files = nil
+	s.runs = nil
+	if s.dir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.dir)
+}
+
+// run is one sorted run, held in memory or read back from disk
+type run struct {
+	records [][recordSize]byte
+	r       *bufio.Reader
+	cur     [recordSize]byte
+}
+
+// advance loads the run's next record into cur
+func (rn *run) advance() (bool, error) {
+	if rn.r == nil {
+		if len(rn.records) == 0 {
+			return false, nil
+		}
+		rn.cur, rn.records = rn.records[0], rn.records[1:]
+		return true, nil
+	}
+	if _, err := io.ReadFull(rn.r, rn.cur[:]); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (rn *run) id() kuid.KUID {
+	k, _ := kuid.FromBytes(rn.cur[:])
+	return *k
+}
+
+// runHeap orders runs by their current record
+type runHeap []*run
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return bytes.Compare(h[i].cur[:], h[j].cur[:]) < 0 }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*run)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	rn := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return rn
+}
+
+func sortRecords(records [][recordSize]byte) {
+	slices.SortFunc(records, func(a, b [recordSize]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestSorted(t *testing.T) {
+	var lines []string
+	var want [][]byte
+	for i := 0; i < 1000; i++ {
+		k, _ := kuid.NewKUID()
+		// Mix representations; UUID and KUID strings sort differently
+		if i%3 == 0 {
+			lines = append(lines, k.ToUUID())
+		} else {
+			lines = append(lines, k.String())
+		}
+		want = append(want, k.Bytes())
+	}
+	lines = append(lines, lines[5], "", "garbage")
+	want = append(want, want[5])
+	slices.SortFunc(want, bytes.Compare)
+
+	for _, runSize := range []int{0, 1, 7, 1000} {
+		s, err := Sorted(strings.NewReader(strings.Join(lines, "\n")),
+			SortOptions{RunSize: runSize, TempDir: t.TempDir()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got [][]byte
+		for s.Next() {
+			id := s.ID()
+			got = append(got, id.Bytes())
+		}
+		if err := s.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.EqualFunc(got, want, bytes.Equal) {
+			t.Errorf("RunSize %d: output not in binary order", runSize)
+		}
+		stats := s.Stats()
+		if stats.Count != 1001 || stats.Invalid != 1 {
+			t.Errorf("RunSize %d: stats = %+v", runSize, stats)
+		}
+		if runSize == 7 && stats.Runs != 143 {
+			t.Errorf("RunSize 7: Runs = %d, want 143", stats.Runs)
+		}
+	}
+}
+
+func TestSortedEmpty(t *testing.T) {
+	s, err := Sorted(strings.NewReader(""), SortOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Next() {
+		t.Errorf("Next() = true on empty input")
+	}
+}
@@ -0,0 +1,147 @@
+// Package apikey builds API keys around KUIDs.
+//
+// A key has the form
+//
+//	<prefix>_<id><secret><checksum>
+//
+// where id is the 22-character KUID identifying the key, secret is 22
+// characters of base62 encoded randomness and checksum is a 6-character
+// base62 CRC32 of everything before it. The checksum lets typos and
+// truncated keys be rejected without a database lookup, and the fixed
+// prefix lets secret scanners recognise leaked keys.
+//
+// Services should store the ID and Fingerprint of a key, never the key
+// itself, and authenticate requests with Verify.
+package apikey
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+)
+
+const (
+	base62Chars  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	idSize       = 22
+	secretSize   = 22
+	checksumSize = 6
+	bodySize     = idSize + secretSize + checksumSize
+	maxPrefix    = 32
+)
+
+var (
+	ErrInvalidPrefix = errors.New("invalid API key prefix")
+	ErrInvalidKey    = errors.New("invalid API key format")
+	ErrChecksum      = errors.New("API key checksum mismatch")
+)
+
+// Key is a parsed API key
+type Key struct {
+	Prefix string     // identifies the key type, e.g. "sk_live"
+	ID     *kuid.KUID // public identifier, safe to store and log
+	Secret string     // random secret, never store or log
+}
+
+// Generate creates a new API key with the given prefix. Prefixes may contain
+// lowercase letters, digits and underscores and be at most 32 characters.
+func Generate(prefix string) (*Key, error) {
+	if err := validatePrefix(prefix); err != nil {
+		return nil, err
+	}
+
+	id, err := kuid.NewKUID()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := kuid.NewKUID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{Prefix: prefix, ID: id, Secret: secret.String()}, nil
+}
+
+// Parse parses and checksums a key string
+func Parse(s string) (*Key, error) {
+	sep := strings.LastIndexByte(s, '_')
+	if sep < 0 {
+		return nil, ErrInvalidKey
+	}
+	prefix, body := s[:sep], s[sep+1:]
+	if err := validatePrefix(prefix); err != nil {
+		return nil, err
+	}
+	if len(body) != bodySize {
+		return nil, ErrInvalidKey
+	}
+
+	payload, sum := s[:len(s)-checksumSize], body[idSize+secretSize:]
+	if sum != checksum(payload) {
+		return nil, ErrChecksum
+	}
+
+	id, err := kuid.FromString(body[:idSize])
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	secret := body[idSize : idSize+secretSize]
+	if _, err := kuid.FromString(secret); err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	return &Key{Prefix: prefix, ID: id, Secret: secret}, nil
+}
+
+// String returns the full key, including its secret
+func (k *Key) String() string {
+	payload := k.Prefix + "_" + k.ID.String() + k.Secret
+	return payload + checksum(payload)
+}
+
+// Fingerprint returns the hex SHA-256 of the full key. Store this instead of
+// the key and compare it with Verify.
+func (k *Key) Fingerprint() string {
+	return Fingerprint(k.String())
+}
+
+// Fingerprint returns the hex SHA-256 of a key string
+func Fingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify reports whether the presented key matches a stored fingerprint. The
+// comparison runs in constant time.
+func Verify(presented, fingerprint string) bool {
+	got := Fingerprint(presented)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(fingerprint)) == 1
+}
+
+func validatePrefix(prefix string) error {
+	if prefix == "" || len(prefix) > maxPrefix {
+		return ErrInvalidPrefix
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return ErrInvalidPrefix
+		}
+	}
+	return nil
+}
+
+// checksum returns the base62 encoded CRC32 of s
+func checksum(s string) string {
+	sum := crc32.ChecksumIEEE([]byte(s))
+	out := make([]byte, checksumSize)
+	for i := checksumSize - 1; i >= 0; i-- {
+		out[i] = base62Chars[sum%62]
+		sum /= 62
+	}
+	return string(out)
+}
@@ -0,0 +1,86 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateParse(t *testing.T) {
+	key, err := Generate("sk_live")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	str := key.String()
+	if !strings.HasPrefix(str, "sk_live_") {
+		t.Errorf("String() = %v, want prefix sk_live_", str)
+	}
+	if len(str) != len("sk_live_")+bodySize {
+		t.Errorf("String() length = %d, want %d", len(str), len("sk_live_")+bodySize)
+	}
+
+	parsed, err := Parse(str)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.Prefix != key.Prefix || !parsed.ID.Equal(key.ID) || parsed.Secret != key.Secret {
+		t.Errorf("Parse() = %+v, want %+v", parsed, key)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	key, _ := Generate("pk")
+	valid := key.String()
+
+	// Change one character of the secret to break the checksum
+	last := valid[len(valid)-checksumSize-1]
+	replacement := byte('0')
+	if last == '0' {
+		replacement = '1'
+	}
+	typo := valid[:len(valid)-checksumSize-1] + string(replacement) + valid[len(valid)-checksumSize:]
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{name: "Empty", key: "", wantErr: ErrInvalidKey},
+		{name: "No prefix", key: valid[len("pk_"):], wantErr: ErrInvalidKey},
+		{name: "Bad prefix", key: "PK" + valid[len("pk"):], wantErr: ErrInvalidPrefix},
+		{name: "Truncated", key: valid[:len(valid)-1], wantErr: ErrInvalidKey},
+		{name: "Typo", key: typo, wantErr: ErrChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.key); err != tt.wantErr {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, _ := Generate("sk")
+	other, _ := Generate("sk")
+	fingerprint := key.Fingerprint()
+
+	if !Verify(key.String(), fingerprint) {
+		t.Errorf("Verify() = false for matching key")
+	}
+	if Verify(other.String(), fingerprint) {
+		t.Errorf("Verify() = true for different key")
+	}
+	if strings.Contains(fingerprint, key.Secret) {
+		t.Errorf("Fingerprint() leaks the secret")
+	}
+}
+
+func TestGenerateInvalidPrefix(t *testing.T) {
+	for _, prefix := range []string{"", "Live", "sk-live", strings.Repeat("a", maxPrefix+1)} {
+		if _, err := Generate(prefix); err != ErrInvalidPrefix {
+			t.Errorf("Generate(%q) error = %v, want %v", prefix, err, ErrInvalidPrefix)
+		}
+	}
+}
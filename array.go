@@ -0,0 +1,21 @@
+package kuid
+
+import "encoding/binary"
+
+// Array returns the KUID as a 16-byte array in UUID (big-endian) byte order.
+// Unlike Bytes it does not allocate, and the result is comparable, so it can
+// be used as a map key or a fixed-size struct field.
+func (k *KUID) Array() [16]byte {
+	var a [16]byte
+	binary.BigEndian.PutUint64(a[0:8], k.msb)
+	binary.BigEndian.PutUint64(a[8:16], k.lsb)
+	return a
+}
+
+// FromArray creates a KUID from a 16-byte array in UUID byte order
+func FromArray(a [16]byte) *KUID {
+	return &KUID{
+		msb: binary.BigEndian.Uint64(a[0:8]),
+		lsb: binary.BigEndian.Uint64(a[8:16]),
+	}
+}
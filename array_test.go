@@ -0,0 +1,17 @@
+package kuid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestArray(t *testing.T) {
+	kuid, _ := NewKUID()
+	a := kuid.Array()
+	if !bytes.Equal(a[:], kuid.Bytes()) {
+		t.Errorf("Array() = %x, want %x", a, kuid.Bytes())
+	}
+	if !FromArray(a).Equal(kuid) {
+		t.Errorf("FromArray() roundtrip failed")
+	}
+}
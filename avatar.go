@@ -0,0 +1,108 @@
+package kuid
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// IdenticonCells is the number of cells along each side of an identicon
+const IdenticonCells = 5
+
+// identiconBackground is the RGB color of unset identicon cells
+var identiconBackground = [3]byte{0xf0, 0xf0, 0xf0}
+
+// digest hashes the KUID so that KUIDs differing only in a few bits, such as
+// ordered KUIDs minted in the same millisecond, still look unrelated
+func (k *KUID) digest() [sha256.Size]byte {
+	return sha256.Sum256(k.Bytes())
+}
+
+// RGB returns a stable color for the KUID. Only the hue varies; saturation
+// and lightness are fixed so every color is readable on light and dark
+// backgrounds.
+func (k *KUID) RGB() (r, g, b uint8) {
+	d := k.digest()
+	hue := (int(d[0])<<8 | int(d[1])) % 360
+	return hslToRGB(hue, 0.65, 0.5)
+}
+
+// Color returns the KUID's stable color as a CSS hex string, e.g. "#3fa2d9"
+func (k *KUID) Color() string {
+	r, g, b := k.RGB()
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// IdenticonPattern returns the KUID's horizontally symmetric identicon
+// pattern, indexed [row][column]. A true cell is drawn in Color.
+func (k *KUID) IdenticonPattern() [IdenticonCells][IdenticonCells]bool {
+	d := k.digest()
+	var p [IdenticonCells][IdenticonCells]bool
+	bit := 0
+	for col := 0; col < (IdenticonCells+1)/2; col++ {
+		for row := 0; row < IdenticonCells; row++ {
+			on := d[2+bit/8]>>(bit%8)&1 == 1
+			p[row][col] = on
+			p[row][IdenticonCells-1-col] = on
+			bit++
+		}
+	}
+	return p
+}
+
+// Identicon renders the identicon as raw RGBA pixels, scale pixels per cell,
+// so the image is IdenticonCells*scale pixels square. The result can be
+// wrapped without copying:
+//
+//	size := kuid.IdenticonCells * scale
+//	img := &image.RGBA{Pix: pix, Stride: 4 * size, Rect: image.Rect(0, 0, size, size)}
+func (k *KUID) Identicon(scale int) []byte {
+	scale = max(scale, 1)
+	size := IdenticonCells * scale
+	pattern := k.IdenticonPattern()
+	r, g, b := k.RGB()
+	fg := [3]byte{r, g, b}
+
+	pix := make([]byte, 4*size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := identiconBackground
+			if pattern[y/scale][x/scale] {
+				c = fg
+			}
+			i := 4 * (y*size + x)
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = c[0], c[1], c[2], 0xff
+		}
+	}
+	return pix
+}
+
+// hslToRGB converts a hue in degrees with saturation and lightness in [0, 1]
+func hslToRGB(hue int, s, l float64) (r, g, b uint8) {
+	c := (1 - abs(2*l-1)) * s
+	h := float64(hue) / 60
+	x := c * (1 - abs(h-2*float64(int(h)/2)-1))
+	var r1, g1, b1 float64
+	switch int(h) {
+	case 0:
+		r1, g1 = c, x
+	case 1:
+		r1, g1 = x, c
+	case 2:
+		g1, b1 = c, x
+	case 3:
+		g1, b1 = x, c
+	case 4:
+		r1, b1 = x, c
+	default:
+		r1, b1 = c, x
+	}
+	m := l - c/2
+	return uint8((r1+m)*255 + 0.5), uint8((g1+m)*255 + 0.5), uint8((b1+m)*255 + 0.5)
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,70 @@
+package kuid
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestColor(t *testing.T) {
+	k, _ := FromString("7n42DGM5Tfl2CQZcquv8Vb")
+	same, _ := FromString("7n42DGM5Tfl2CQZcquv8Vb")
+	if k.Color() != same.Color() || k.IdenticonPattern() != same.IdenticonPattern() {
+		t.Errorf("Rendering not stable for equal KUIDs")
+	}
+	if !regexp.MustCompile(`^#[0-9a-f]{6}$`).MatchString(k.Color()) {
+		t.Errorf("Color() = %q, want #rrggbb", k.Color())
+	}
+
+	other, _ := FromString("7n42DGM5Tfl2CQZcquv8Vc")
+	if k.Color() == other.Color() && k.IdenticonPattern() == other.IdenticonPattern() {
+		t.Errorf("Adjacent KUIDs render identically")
+	}
+}
+
+func TestHSLToRGB(t *testing.T) {
+	tests := []struct {
+		hue     int
+		r, g, b uint8
+	}{
+		{0, 255, 0, 0},
+		{120, 0, 255, 0},
+		{240, 0, 0, 255},
+		{60, 255, 255, 0},
+	}
+	for _, tt := range tests {
+		r, g, b := hslToRGB(tt.hue, 1, 0.5)
+		if r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("hslToRGB(%d) = %d,%d,%d, want %d,%d,%d", tt.hue, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+}
+
+func TestIdenticon(t *testing.T) {
+	k, _ := NewKUID()
+	p := k.IdenticonPattern()
+	for row := range p {
+		for col := range p[row] {
+			if p[row][col] != p[row][IdenticonCells-1-col] {
+				t.Fatalf("Pattern not symmetric at %d,%d", row, col)
+			}
+		}
+	}
+
+	const scale = 4
+	size := IdenticonCells * scale
+	pix := k.Identicon(scale)
+	if len(pix) != 4*size*size {
+		t.Fatalf("len(Identicon()) = %d, want %d", len(pix), 4*size*size)
+	}
+
+	r, g, b := k.RGB()
+	for row := 0; row < IdenticonCells; row++ {
+		for col := 0; col < IdenticonCells; col++ {
+			i := 4 * ((row*scale+scale/2)*size + col*scale + scale/2)
+			isFG := pix[i] == r && pix[i+1] == g && pix[i+2] == b
+			if isFG != p[row][col] || pix[i+3] != 0xff {
+				t.Errorf("Cell %d,%d does not match pattern", row, col)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package kuid
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AvroSchema returns an Avro schema declaring a KUID column as fixed[16]
+// with the uuid logical type (Avro 1.12+). Readers without logical type
+// support still see plain 16-byte fixed values, half the size of the string
+// form.
+func AvroSchema(name string) string {
+	schema, _ := json.Marshal(map[string]any{
+		"type":        "fixed",
+		"name":        name,
+		"size":        16,
+		"logicalType": "uuid",
+	})
+	return string(schema)
+}
+
+// AppendAvro appends the Avro binary encoding of the KUID as fixed[16],
+// which is its 16 bytes without a length prefix
+func (k *KUID) AppendAvro(buf []byte) []byte {
+	a := k.Array()
+	return append(buf, a[:]...)
+}
+
+// AvroNative returns the KUID as the native Go value Avro libraries such as
+// goavro use for fixed[16] fields
+func (k *KUID) AvroNative() []byte {
+	return k.Bytes()
+}
+
+// FromAvro reads a KUID from an Avro fixed[16] value. Both the raw binary
+// encoding and native values decoded by Avro libraries ([]byte or [16]byte)
+// are accepted.
+func FromAvro(v any) (*KUID, error) {
+	switch v := v.(type) {
+	case []byte:
+		return FromBytes(v)
+	case [16]byte:
+		return FromArray(v), nil
+	}
+	return nil, fmt.Errorf("unsupported Avro value %T for KUID", v)
+}
@@ -0,0 +1,42 @@
+package kuid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAvroSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(AvroSchema("EventID")), &schema); err != nil {
+		t.Fatalf("AvroSchema() is not valid JSON: %v", err)
+	}
+	if schema["type"] != "fixed" || schema["size"] != float64(16) ||
+		schema["logicalType"] != "uuid" || schema["name"] != "EventID" {
+		t.Errorf("AvroSchema() = %v", schema)
+	}
+}
+
+func TestAvroRoundtrip(t *testing.T) {
+	kuid, _ := NewKUID()
+
+	encoded := kuid.AppendAvro([]byte{0xAA})
+	if len(encoded) != 17 {
+		t.Fatalf("AppendAvro() length = %d, want 17", len(encoded))
+	}
+	for _, v := range []any{encoded[1:], kuid.AvroNative(), kuid.Array()} {
+		decoded, err := FromAvro(v)
+		if err != nil {
+			t.Fatalf("FromAvro(%T) error = %v", v, err)
+		}
+		if !decoded.Equal(kuid) {
+			t.Errorf("FromAvro(%T) = %v, want %v", v, decoded, kuid)
+		}
+	}
+
+	if _, err := FromAvro("not bytes"); err == nil {
+		t.Errorf("FromAvro() expected error for string")
+	}
+	if _, err := FromAvro(encoded); err == nil {
+		t.Errorf("FromAvro() expected error for 17 bytes")
+	}
+}
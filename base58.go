@@ -0,0 +1,72 @@
+package kuid
+
+import (
+	"math/bits"
+)
+
+const (
+	base58Chars  = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base58Base   = uint64(len(base58Chars))
+	base58Digits = 22 // base58 digits needed for 128 bits
+)
+
+// base58Values maps each byte to its base58 value, or 0xff if it is not in
+// the alphabet
+var base58Values = func() (t [256]byte) {
+	for i := range t {
+		t[i] = 0xff
+	}
+	for i := 0; i < len(base58Chars); i++ {
+		t[base58Chars[i]] = byte(i)
+	}
+	return t
+}()
+
+// Base58 returns the KUID in the Bitcoin base58 alphabet, which leaves out
+// the easily confused 0, O, I and l. The result is always 22 characters,
+// padded with leading '1's, so it sorts like the binary form.
+func (k *KUID) Base58() string {
+	out := make([]byte, base58Digits)
+	hi, lo := k.msb, k.lsb
+	for i := base58Digits - 1; i >= 0; i-- {
+		var r uint64
+		hi, r = hi/base58Base, hi%base58Base
+		lo, r = bits.Div64(r, lo, base58Base)
+		out[i] = base58Chars[r]
+	}
+	return string(out)
+}
+
+// FromBase58 creates a KUID from its 22-character base58 representation
+func FromBase58(s string) (*KUID, error) {
+	if len(s) != base58Digits {
+		return nil, ErrInvalidLength
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		digit := base58Values[s[i]]
+		if digit == 0xff {
+			return nil, ErrInvalidChar
+		}
+
+		// (hi, lo) = (hi, lo) * 58 + digit, rejecting anything above 128 bits
+		carry, newLo := bits.Mul64(lo, base58Base)
+		overflow, newHi := bits.Mul64(hi, base58Base)
+		if overflow != 0 {
+			return nil, ErrOverflow
+		}
+		newHi, c := bits.Add64(newHi, carry, 0)
+		if c != 0 {
+			return nil, ErrOverflow
+		}
+		newLo, c = bits.Add64(newLo, uint64(digit), 0)
+		newHi, c = bits.Add64(newHi, 0, c)
+		if c != 0 {
+			return nil, ErrOverflow
+		}
+		hi, lo = newHi, newLo
+	}
+
+	return &KUID{msb: hi, lsb: lo}, nil
+}
@@ -0,0 +1,81 @@
+package kuid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		name string
+		uuid string
+		want string
+	}{
+		{
+			name: "Zero UUID",
+			uuid: "00000000-0000-0000-0000-000000000000",
+			want: strings.Repeat("1", base58Digits),
+		},
+		{
+			name: "Max UUID",
+			uuid: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+		},
+		{
+			name: "Random UUID",
+			uuid: "123e4567-e89b-12d3-a456-426614174000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kuid, err := FromUUID(tt.uuid)
+			if err != nil {
+				t.Fatalf("FromUUID() error = %v", err)
+			}
+
+			s := kuid.Base58()
+			if len(s) != base58Digits {
+				t.Errorf("Base58() = %v, want %d characters", s, base58Digits)
+			}
+			if tt.want != "" && s != tt.want {
+				t.Errorf("Base58() = %v, want %v", s, tt.want)
+			}
+
+			decoded, err := FromBase58(s)
+			if err != nil {
+				t.Fatalf("FromBase58() error = %v", err)
+			}
+			if !decoded.Equal(kuid) {
+				t.Errorf("FromBase58() = %v, want %v", decoded.ToUUID(), tt.uuid)
+			}
+		})
+	}
+}
+
+func TestBase58Order(t *testing.T) {
+	a, _ := FromUUID("0fffffff-ffff-ffff-ffff-ffffffffffff")
+	b, _ := FromUUID("10000000-0000-0000-0000-000000000000")
+	if a.Base58() >= b.Base58() {
+		t.Errorf("Base58() order: %v >= %v", a.Base58(), b.Base58())
+	}
+}
+
+func TestFromBase58Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{"Too short", "111", ErrInvalidLength},
+		{"Ambiguous character", strings.Repeat("1", base58Digits-1) + "0", ErrInvalidChar},
+		{"Overflow", strings.Repeat("z", base58Digits), ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromBase58(tt.input); err != tt.wantErr {
+				t.Errorf("FromBase58() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package kuid
+
+// UnmarshalParam parses a route parameter, query string or form value into
+// the KUID. It implements the BindUnmarshaler interfaces of both Gin
+// (binding.BindUnmarshaler) and Echo (echo.BindUnmarshaler), so handlers can
+// bind IDs directly:
+//
+//	type request struct {
+//	    ID kuid.KUID `uri:"id" param:"id"`
+//	}
+//
+// Malformed values fail binding with a *ParseError, which both frameworks
+// turn into a 400 response with the same message.
+func (k *KUID) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*k = *parsed
+	return nil
+}
@@ -0,0 +1,39 @@
+package kuid
+
+import (
+	"errors"
+	"testing"
+)
+
+// bindUnmarshaler mirrors the interface Gin and Echo check for when binding
+type bindUnmarshaler interface {
+	UnmarshalParam(param string) error
+}
+
+func TestUnmarshalParam(t *testing.T) {
+	var req struct {
+		ID KUID
+	}
+	var target any = &req.ID
+	binder, ok := target.(bindUnmarshaler)
+	if !ok {
+		t.Fatalf("*KUID does not implement UnmarshalParam")
+	}
+
+	want, _ := NewKUID()
+	if err := binder.UnmarshalParam(want.String()); err != nil {
+		t.Fatalf("UnmarshalParam() error = %v", err)
+	}
+	if !req.ID.Equal(want) {
+		t.Errorf("UnmarshalParam() = %v, want %v", req.ID, want)
+	}
+
+	err := binder.UnmarshalParam("not-an-id")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Errorf("UnmarshalParam() error = %v, want *ParseError", err)
+	}
+	if !req.ID.Equal(want) {
+		t.Errorf("UnmarshalParam() modified the KUID on error")
+	}
+}
@@ -0,0 +1,278 @@
+package kuid
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLeaseTTL     = 24 * time.Hour
+	defaultMaxBlockSize = 1 << 20
+)
+
+var (
+	ErrUnknownLease      = errors.New("unknown or expired lease")
+	ErrSequenceExhausted = errors.New("sequence space exhausted")
+	ErrBlockSize         = errors.New("block size must be between 1 and MaxBlockSize")
+)
+
+// BlockOptions configures a BlockAllocator
+type BlockOptions struct {
+	// Sequence is the layout of IDs minted from sequence blocks; clients
+	// must pass the same options to FromSequence
+	Sequence SequenceOptions
+	// Generator mints batch IDs; the default Generator when nil
+	Generator *Generator
+	// LeaseTTL is how long a block's lease lasts unless renewed; zero means
+	// 24 hours. Expired sequence ranges are never handed out again.
+	LeaseTTL time.Duration
+	// MaxBlockSize caps the IDs in one block; zero means 1Mi
+	MaxBlockSize uint64
+	// Next is the first sequence to hand out, such as the value last
+	// passed to Persist before a restart
+	Next uint64
+	// Persist, when set, is called with the next unallocated sequence
+	// before a sequence block is returned. An error fails the allocation,
+	// so a restarted allocator resuming from the persisted value never
+	// reissues a range.
+	Persist func(next uint64) error
+}
+
+// Block is a reservation of IDs for a client that mints or uses them
+// offline. Sequence blocks reserve the contiguous range First to
+// First+Count-1 for FromSequence; batch blocks carry their IDs.
+type Block struct {
+	Lease   KUID
+	First   uint64
+	Count   uint64
+	IDs     []KUID
+	Expires time.Time
+}
+
+// Contains reports whether a sequence block reserves seq
+func (b Block) Contains(seq uint64) bool {
+	return b.IDs == nil && seq >= b.First && seq-b.First < b.Count
+}
+
+// snapshot copies a block so callers cannot modify the allocator's records
+func (b *Block) snapshot() Block {
+	c := *b
+	c.IDs = slices.Clone(b.IDs)
+	return c
+}
+
+// BlockAllocator hands out blocks of IDs under expiring leases and keeps
+// track of which lease owns which IDs. It is safe for concurrent use.
+type BlockAllocator struct {
+	opts BlockOptions
+	bits int
+	now  func() time.Time
+
+	mu     sync.Mutex
+	next   uint64
+	leases map[KUID]*Block
+	owners map[KUID]KUID // batch ID to lease
+}
+
+// NewBlockAllocator creates a BlockAllocator
+func NewBlockAllocator(opts BlockOptions) (*BlockAllocator, error) {
+	bits, err := opts.Sequence.bits()
+	if err != nil {
+		return nil, err
+	}
+	if bits < 64 && opts.Next>>bits != 0 {
+		return nil, ErrOverflow
+	}
+	if opts.LeaseTTL <= 0 {
+		opts.LeaseTTL = defaultLeaseTTL
+	}
+	if opts.MaxBlockSize == 0 {
+		opts.MaxBlockSize = defaultMaxBlockSize
+	}
+	if opts.Generator == nil {
+		opts.Generator = defaultGenerator
+	}
+	return &BlockAllocator{
+		opts:   opts,
+		bits:   bits,
+		now:    time.Now,
+		next:   opts.Next,
+		leases: make(map[KUID]*Block),
+		owners: make(map[KUID]KUID),
+	}, nil
+}
+
+// AllocateBlock reserves the next n sequences under a new lease
+func (a *BlockAllocator) AllocateBlock(n uint64) (Block, error) {
+	if err := a.checkSize(n); err != nil {
+		return Block{}, err
+	}
+	lease, err := a.opts.Generator.New()
+	if err != nil {
+		return Block{}, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	limit := ^uint64(0) >> (64 - a.bits)
+	if a.next > limit || limit-a.next < n-1 {
+		return Block{}, ErrSequenceExhausted
+	}
+	next := a.next + n
+	if a.opts.Persist != nil {
+		if err := a.opts.Persist(next); err != nil {
+			return Block{}, err
+		}
+	}
+
+	b := &Block{Lease: *lease, First: a.next, Count: n, Expires: a.now().Add(a.opts.LeaseTTL)}
+	a.next = next
+	a.leases[b.Lease] = b
+	return b.snapshot(), nil
+}
+
+// AllocateBatch mints n random IDs under a new lease
+func (a *BlockAllocator) AllocateBatch(n uint64) (Block, error) {
+	if err := a.checkSize(n); err != nil {
+		return Block{}, err
+	}
+	lease, err := a.opts.Generator.New()
+	if err != nil {
+		return Block{}, err
+	}
+	ids := make([]KUID, n)
+	for i := range ids {
+		if ids[i], err = a.opts.Generator.NewValue(); err != nil {
+			return Block{}, err
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	b := &Block{Lease: *lease, Count: n, IDs: ids, Expires: a.now().Add(a.opts.LeaseTTL)}
+	a.leases[b.Lease] = b
+	for _, id := range ids {
+		a.owners[id] = b.Lease
+	}
+	return b.snapshot(), nil
+}
+
+func (a *BlockAllocator) checkSize(n uint64) error {
+	if n == 0 || n > a.opts.MaxBlockSize {
+		return ErrBlockSize
+	}
+	return nil
+}
+
+// Renew extends a live lease by the allocator's LeaseTTL
+func (a *BlockAllocator) Renew(lease *KUID) (Block, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	b, ok := a.leases[*lease]
+	if !ok {
+		return Block{}, ErrUnknownLease
+	}
+	b.Expires = a.now().Add(a.opts.LeaseTTL)
+	return b.snapshot(), nil
+}
+
+// Release ends a lease early. Its sequence range stays retired.
+func (a *BlockAllocator) Release(lease *KUID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	b, ok := a.leases[*lease]
+	if !ok {
+		return ErrUnknownLease
+	}
+	a.drop(b)
+	return nil
+}
+
+// Owner returns the live batch block an ID was allocated from. IDs minted
+// with FromSequence carry nothing that sets them apart from random IDs,
+// whose top bits would decode to an arbitrary sequence, so Owner never
+// attributes them; look those up with SequenceOwner instead.
+func (a *BlockAllocator) Owner(id *KUID) (Block, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	if lease, ok := a.owners[*id]; ok {
+		return a.leases[lease].snapshot(), true
+	}
+	return Block{}, false
+}
+
+// SequenceOwner returns the live sequence block reserving seq, for IDs the
+// caller knows were minted from a block:
+//
+//	seq, _ := id.ToSequence(opts.Sequence)
+//	owner, ok := blocks.SequenceOwner(seq)
+func (a *BlockAllocator) SequenceOwner(seq uint64) (Block, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	for _, b := range a.leases {
+		if b.Contains(seq) {
+			return b.snapshot(), true
+		}
+	}
+	return Block{}, false
+}
+
+// Leases returns the live blocks, soonest to expire first
+func (a *BlockAllocator) Leases() []Block {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	blocks := make([]Block, 0, len(a.leases))
+	for _, b := range a.leases {
+		blocks = append(blocks, b.snapshot())
+	}
+	slices.SortFunc(blocks, func(x, y Block) int {
+		return x.Expires.Compare(y.Expires)
+	})
+	return blocks
+}
+
+// SequenceBits returns the width of the sequence in sequence block IDs
+func (a *BlockAllocator) SequenceBits() int {
+	return a.bits
+}
+
+// Next returns the next unallocated sequence
+func (a *BlockAllocator) Next() uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.next
+}
+
+// expire drops lapsed leases. The caller must hold a.mu.
+func (a *BlockAllocator) expire() {
+	now := a.now()
+	for _, b := range a.leases {
+		if !now.Before(b.Expires) {
+			a.drop(b)
+		}
+	}
+}
+
+// drop forgets a lease. The caller must hold a.mu.
+func (a *BlockAllocator) drop(b *Block) {
+	delete(a.leases, b.Lease)
+	for _, id := range b.IDs {
+		delete(a.owners, id)
+	}
+}
@@ -0,0 +1,131 @@
+package kuid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBlockAllocatorSequence(t *testing.T) {
+	var persisted uint64
+	a, err := NewBlockAllocator(BlockOptions{
+		Sequence: SequenceOptions{Bits: 40},
+		Next:     100,
+		Persist:  func(next uint64) error { persisted = next; return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := a.AllocateBlock(10)
+	second, _ := a.AllocateBlock(5)
+	if first.First != 100 || first.Count != 10 || second.First != 110 || persisted != 115 {
+		t.Fatalf("blocks = %+v, %+v, persisted %d", first, second, persisted)
+	}
+
+	// IDs the client mints offline trace back to its lease
+	id, _ := FromSequence(second.First+4, SequenceOptions{Bits: 40})
+	seq, _ := id.ToSequence(SequenceOptions{Bits: 40})
+	owner, ok := a.SequenceOwner(seq)
+	if !ok || owner.Lease != second.Lease {
+		t.Errorf("SequenceOwner() = %+v, %v, want lease %v", owner, ok, second.Lease)
+	}
+	if _, ok := a.SequenceOwner(115); ok {
+		t.Errorf("SequenceOwner() found a lease for an unallocated sequence")
+	}
+
+	// Owner cannot tell sequence IDs from random ones, so it attributes
+	// neither, even when the random ID's top bits fall in a block
+	random, _ := NewKUID()
+	random.msb = random.msb&(1<<24-1) | (second.First+2)<<24
+	if owner, ok := a.Owner(random); ok {
+		t.Errorf("Owner() attributed a random ID to lease %v", owner.Lease)
+	}
+	if _, ok := a.Owner(id); ok {
+		t.Errorf("Owner() attributed a sequence ID")
+	}
+
+	// Released ranges are never reissued
+	if err := a.Release(&first.Lease); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Release(&first.Lease); err != ErrUnknownLease {
+		t.Errorf("second Release() error = %v, want %v", err, ErrUnknownLease)
+	}
+	if third, _ := a.AllocateBlock(1); third.First != 115 {
+		t.Errorf("AllocateBlock() after Release = %d, want 115", third.First)
+	}
+}
+
+func TestBlockAllocatorBatch(t *testing.T) {
+	a, _ := NewBlockAllocator(BlockOptions{})
+	b, err := a.AllocateBatch(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.IDs) != 3 {
+		t.Fatalf("AllocateBatch(3) returned %d IDs", len(b.IDs))
+	}
+	if owner, ok := a.Owner(&b.IDs[1]); !ok || owner.Lease != b.Lease {
+		t.Errorf("Owner() = %+v, %v, want lease %v", owner, ok, b.Lease)
+	}
+
+	// Callers cannot corrupt the allocator's bookkeeping
+	b.IDs[1] = KUID{}
+	if _, ok := a.Owner(&KUID{}); ok {
+		t.Errorf("Owner() saw a modified returned block")
+	}
+}
+
+func TestBlockAllocatorExpiry(t *testing.T) {
+	a, _ := NewBlockAllocator(BlockOptions{LeaseTTL: time.Minute})
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	short, _ := a.AllocateBlock(1)
+	now = now.Add(30 * time.Second)
+	long, _ := a.AllocateBlock(1)
+	if leases := a.Leases(); len(leases) != 2 || leases[0].Lease != short.Lease {
+		t.Fatalf("Leases() = %+v, want short lease first", leases)
+	}
+
+	now = now.Add(45 * time.Second)
+	if _, err := a.Renew(&short.Lease); err != ErrUnknownLease {
+		t.Errorf("Renew() of expired lease error = %v, want %v", err, ErrUnknownLease)
+	}
+	renewed, err := a.Renew(&long.Lease)
+	if err != nil || !renewed.Expires.Equal(now.Add(time.Minute)) {
+		t.Errorf("Renew() = %+v, %v", renewed, err)
+	}
+	if leases := a.Leases(); len(leases) != 1 {
+		t.Errorf("Leases() = %+v, want only the renewed lease", leases)
+	}
+}
+
+func TestBlockAllocatorErrors(t *testing.T) {
+	a, _ := NewBlockAllocator(BlockOptions{Sequence: SequenceOptions{Bits: 4}, MaxBlockSize: 10, Next: 10})
+	for _, n := range []uint64{0, 11} {
+		if _, err := a.AllocateBlock(n); err != ErrBlockSize {
+			t.Errorf("AllocateBlock(%d) error = %v, want %v", n, err, ErrBlockSize)
+		}
+	}
+	if _, err := a.AllocateBlock(7); err != ErrSequenceExhausted {
+		t.Errorf("AllocateBlock past 4 bits error = %v, want %v", err, ErrSequenceExhausted)
+	}
+	if b, err := a.AllocateBlock(6); err != nil || b.First != 10 {
+		t.Errorf("AllocateBlock(6) = %+v, %v, want the last 6 sequences", b, err)
+	}
+
+	errDisk := errors.New("disk full")
+	failing, _ := NewBlockAllocator(BlockOptions{Persist: func(uint64) error { return errDisk }})
+	if _, err := failing.AllocateBlock(1); err != errDisk {
+		t.Errorf("AllocateBlock() error = %v, want %v", err, errDisk)
+	}
+	if failing.Next() != 0 {
+		t.Errorf("Next() = %d after failed Persist, want 0", failing.Next())
+	}
+
+	if _, err := NewBlockAllocator(BlockOptions{Sequence: SequenceOptions{Bits: 4}, Next: 16}); err != ErrOverflow {
+		t.Errorf("NewBlockAllocator() with Next beyond bits error = %v, want %v", err, ErrOverflow)
+	}
+}
@@ -0,0 +1,106 @@
+package kuid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Bucket and Percent assign KUIDs to experiment arms and rollout
+// percentages. Any language reproduces them with:
+//
+//	h = first 8 bytes, big-endian, of SHA-256(salt | 0x00 | KUID's 16 bytes)
+//	Bucket  = h mod buckets
+//	Percent = float64(h >> 11) / 2^53 * 100
+//
+// The salt, typically the experiment or flag name, decorrelates
+// assignments: a user in the lowest 10% of one flag is no more likely to
+// be in the lowest 10% of another.
+
+// Bucket returns the bucket in [0, buckets) that k falls in for salt. It
+// returns 0 when buckets < 1.
+func Bucket(k KUID, salt string, buckets int) int {
+	if buckets < 1 {
+		return 0
+	}
+	return int(bucketHash(k, salt) % uint64(buckets))
+}
+
+// Percent returns k's position in [0, 100) for salt, for percentage
+// rollouts: enable a flag for k when Percent(k, flag) < rollout
+func Percent(k KUID, salt string) float64 {
+	return float64(bucketHash(k, salt)>>11) / (1 << 53) * 100
+}
+
+func bucketHash(k KUID, salt string) uint64 {
+	h := sha256.New()
+	h.Write([]byte(salt))
+	h.Write([]byte{0})
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:8], k.msb)
+	binary.BigEndian.PutUint64(b[8:16], k.lsb)
+	h.Write(b[:])
+	return binary.BigEndian.Uint64(h.Sum(nil)[:8])
+}
+
+// InRollout reports whether k is among the percent of KUIDs a rollout
+// keyed by salt enables. Raising percent only adds KUIDs, so a canary can
+// ramp up without flapping anyone back out.
+func InRollout(k KUID, salt string, percent float64) bool {
+	return Percent(k, salt) < percent
+}
+
+// Variant is one arm of a traffic split. A zero Weight disables it
+// without reshuffling the other variants' ranges.
+type Variant struct {
+	Name   string
+	Weight uint32
+}
+
+// Splitter deterministically assigns KUIDs to weighted variants. Each
+// variant owns a slice of the [0, 100) Percent range in the order given,
+// sized by its share of the total weight, so changing one weight only
+// moves KUIDs across the boundaries it shifts.
+type Splitter struct {
+	salt     string
+	variants []Variant
+	bounds   []float64 // upper Percent bound of each variant
+}
+
+// NewSplitter creates a Splitter over variants for salt. Names must be
+// unique and the weights must not all be zero.
+func NewSplitter(salt string, variants ...Variant) (*Splitter, error) {
+	var total uint64
+	names := make(map[string]bool, len(variants))
+	for _, v := range variants {
+		if v.Name == "" || names[v.Name] {
+			return nil, fmt.Errorf("invalid or duplicate variant name %q", v.Name)
+		}
+		names[v.Name] = true
+		total += uint64(v.Weight)
+	}
+	if total == 0 {
+		return nil, errors.New("splitter variants have no weight")
+	}
+
+	s := &Splitter{salt: salt, variants: append([]Variant(nil), variants...)}
+	var cum uint64
+	for _, v := range variants {
+		cum += uint64(v.Weight)
+		s.bounds = append(s.bounds, float64(cum)/float64(total)*100)
+	}
+	return s, nil
+}
+
+// Assign returns the name of the variant k falls in
+func (s *Splitter) Assign(k KUID) string {
+	p := Percent(k, s.salt)
+	for i, bound := range s.bounds {
+		// Zero-weight variants share the previous bound and never match
+		if p < bound {
+			return s.variants[i].Name
+		}
+	}
+	return s.variants[len(s.variants)-1].Name
+}
@@ -0,0 +1,159 @@
+package kuid
+
+import (
+	"math"
+	"testing"
+)
+
+// The vectors were computed independently from the documented algorithm,
+// so ports to other languages can check against them
+func TestBucketVectors(t *testing.T) {
+	zero := KUID{}
+	seq := KUID{msb: 0x0001020304050607, lsb: 0x08090a0b0c0d0e0f}
+	max := KUID{msb: math.MaxUint64, lsb: math.MaxUint64}
+
+	tests := []struct {
+		k        KUID
+		salt     string
+		bucket10 int
+		bucket1k int
+		percent  float64
+	}{
+		{zero, "checkout-v2", 0, 350, 51.97011131756113},
+		{seq, "checkout-v2", 1, 731, 6.228222682039386},
+		{max, "checkout-v2", 5, 985, 31.79342779231682},
+		{zero, "new-onboarding", 7, 907, 79.33095471764709},
+		{seq, "new-onboarding", 0, 970, 53.89034752051535},
+		{max, "new-onboarding", 9, 329, 92.2339335532875},
+	}
+	for _, tt := range tests {
+		if got := Bucket(tt.k, tt.salt, 10); got != tt.bucket10 {
+			t.Errorf("Bucket(%v, %q, 10) = %d, want %d", tt.k, tt.salt, got, tt.bucket10)
+		}
+		if got := Bucket(tt.k, tt.salt, 1000); got != tt.bucket1k {
+			t.Errorf("Bucket(%v, %q, 1000) = %d, want %d", tt.k, tt.salt, got, tt.bucket1k)
+		}
+		if got := Percent(tt.k, tt.salt); got != tt.percent {
+			t.Errorf("Percent(%v, %q) = %v, want %v", tt.k, tt.salt, got, tt.percent)
+		}
+	}
+}
+
+func TestBucketDistribution(t *testing.T) {
+	const n, buckets = 20000, 4
+	var counts [buckets]int
+	below := 0
+	for i := 0; i < n; i++ {
+		k, _ := NewValue()
+		b := Bucket(k, "dist", buckets)
+		if b < 0 || b >= buckets {
+			t.Fatalf("Bucket() = %d", b)
+		}
+		counts[b]++
+		p := Percent(k, "dist")
+		if p < 0 || p >= 100 {
+			t.Fatalf("Percent() = %v", p)
+		}
+		if p < 25 {
+			below++
+		}
+	}
+	for b, c := range counts {
+		if c < n/buckets*9/10 || c > n/buckets*11/10 {
+			t.Errorf("bucket %d has %d of %d KUIDs", b, c, n)
+		}
+	}
+	if below < n/4*9/10 || below > n/4*11/10 {
+		t.Errorf("%d of %d KUIDs below 25%%", below, n)
+	}
+}
+
+func TestBucketEdgeCases(t *testing.T) {
+	k, _ := NewValue()
+	for _, n := range []int{0, -3} {
+		if got := Bucket(k, "x", n); got != 0 {
+			t.Errorf("Bucket(%d buckets) = %d, want 0", n, got)
+		}
+	}
+	if Bucket(k, "x", 1) != 0 {
+		t.Error("Bucket(1 bucket) != 0")
+	}
+	if Percent(k, "a") == Percent(k, "b") {
+		t.Error("salts do not change Percent")
+	}
+}
+
+func TestInRollout(t *testing.T) {
+	k := KUID{msb: 0x0001020304050607, lsb: 0x08090a0b0c0d0e0f}
+	// Percent(k, "checkout-v2") is 6.228222682039386
+	tests := []struct {
+		percent float64
+		want    bool
+	}{
+		{0, false},
+		{6, false},
+		{6.3, true},
+		{100, true},
+	}
+	for _, tt := range tests {
+		if got := InRollout(k, "checkout-v2", tt.percent); got != tt.want {
+			t.Errorf("InRollout(%v) = %v, want %v", tt.percent, got, tt.want)
+		}
+	}
+
+	// Ramping up never removes anyone
+	for i := 0; i < 1000; i++ {
+		k, _ := NewValue()
+		if InRollout(k, "ramp", 5) && !InRollout(k, "ramp", 10) {
+			t.Fatal("KUID left the rollout when it grew")
+		}
+	}
+}
+
+func TestSplitter(t *testing.T) {
+	s, err := NewSplitter("checkout-v2", Variant{"control", 1}, Variant{"off", 0}, Variant{"treatment", 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Percent 51.97 falls in the second half, 6.23 and 31.79 in the first
+	tests := []struct {
+		k    KUID
+		want string
+	}{
+		{KUID{}, "treatment"},
+		{KUID{msb: 0x0001020304050607, lsb: 0x08090a0b0c0d0e0f}, "control"},
+		{KUID{msb: math.MaxUint64, lsb: math.MaxUint64}, "control"},
+	}
+	for _, tt := range tests {
+		if got := s.Assign(tt.k); got != tt.want {
+			t.Errorf("Assign(%v) = %q, want %q", tt.k, got, tt.want)
+		}
+	}
+
+	weighted, _ := NewSplitter("w", Variant{"a", 1}, Variant{"b", 3})
+	counts := map[string]int{}
+	for i := 0; i < 8000; i++ {
+		k, _ := NewValue()
+		counts[weighted.Assign(k)]++
+	}
+	if counts["a"] < 1800 || counts["a"] > 2200 || counts["a"]+counts["b"] != 8000 {
+		t.Errorf("weighted split = %v, want about 2000:6000", counts)
+	}
+}
+
+func TestNewSplitterInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []Variant
+	}{
+		{"none", nil},
+		{"zero weight", []Variant{{"a", 0}}},
+		{"empty name", []Variant{{"", 1}}},
+		{"duplicate", []Variant{{"a", 1}, {"a", 2}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewSplitter("s", tt.variants...); err == nil {
+			t.Errorf("%s: NewSplitter() succeeded", tt.name)
+		}
+	}
+}
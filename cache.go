@@ -0,0 +1,111 @@
+package kuid
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CacheStats reports the effectiveness of a Cache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// Cache is a bounded LRU cache mapping KUID strings to KUIDs and back, for
+// workloads that repeatedly parse or format the same small set of IDs, such
+// as tenant or organisation IDs on every request. It is safe for concurrent
+// use.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	byString map[string]*list.Element
+	byKUID   map[KUID]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+type cacheEntry struct {
+	str string
+	id  KUID
+}
+
+// NewCache creates a Cache holding up to capacity entries (at least 1)
+func NewCache(capacity int) *Cache {
+	capacity = max(capacity, 1)
+	return &Cache{
+		capacity: capacity,
+		order:    list.New(),
+		byString: make(map[string]*list.Element, capacity),
+		byKUID:   make(map[KUID]*list.Element, capacity),
+	}
+}
+
+// FromString behaves like the package-level FromString, serving repeated
+// strings from the cache. Invalid strings are not cached.
+func (c *Cache) FromString(s string) (*KUID, error) {
+	c.mu.Lock()
+	if e, ok := c.byString[s]; ok {
+		c.hits++
+		c.order.MoveToFront(e)
+		id := e.Value.(*cacheEntry).id
+		c.mu.Unlock()
+		return &id, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	id, err := FromString(s)
+	if err != nil {
+		return nil, err
+	}
+	c.add(s, *id)
+	return id, nil
+}
+
+// String returns the base62 form of id, serving repeated KUIDs from the
+// cache
+func (c *Cache) String(id *KUID) string {
+	c.mu.Lock()
+	if e, ok := c.byKUID[*id]; ok {
+		c.hits++
+		c.order.MoveToFront(e)
+		s := e.Value.(*cacheEntry).str
+		c.mu.Unlock()
+		return s
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	s := id.String()
+	c.add(s, *id)
+	return s
+}
+
+// Stats returns hit and miss counts and the current number of entries
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Size: c.order.Len()}
+}
+
+func (c *Cache) add(s string, id KUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another goroutine may have added the entry since the miss
+	if _, ok := c.byString[s]; ok {
+		return
+	}
+	e := c.order.PushFront(&cacheEntry{str: s, id: id})
+	c.byString[s] = e
+	c.byKUID[id] = e
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		entry := c.order.Remove(oldest).(*cacheEntry)
+		delete(c.byString, entry.str)
+		delete(c.byKUID, entry.id)
+	}
+}
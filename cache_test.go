@@ -0,0 +1,108 @@
+package kuid
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	d, _ := NewKUID()
+
+	got, err := c.FromString(a.String())
+	if err != nil || !got.Equal(a) {
+		t.Fatalf("FromString() = %v, %v, want %v", got, err, a)
+	}
+	got, _ = c.FromString(a.String())
+	if !got.Equal(a) {
+		t.Errorf("FromString() cached = %v, want %v", got, a)
+	}
+	if s := c.String(a); s != a.String() {
+		t.Errorf("String() = %v, want %v", s, a.String())
+	}
+	if stats := c.Stats(); stats != (CacheStats{Hits: 2, Misses: 1, Size: 1}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	// Adding two more entries evicts the least recently used one
+	c.String(b)
+	c.FromString(a.String())
+	c.String(d)
+	if stats := c.Stats(); stats.Size != 2 {
+		t.Errorf("Stats().Size = %d, want 2", stats.Size)
+	}
+	before := c.Stats().Misses
+	c.FromString(b.String())
+	if c.Stats().Misses != before+1 {
+		t.Errorf("Expected evicted entry to miss")
+	}
+
+	if _, err := c.FromString("invalid"); err == nil {
+		t.Errorf("FromString() expected error")
+	}
+	if c.Stats().Size != 2 {
+		t.Errorf("Invalid string was cached")
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	c := NewCache(4)
+	a, _ := NewKUID()
+
+	got, _ := c.FromString(a.String())
+	got.msb++ // callers may modify the result
+	again, _ := c.FromString(a.String())
+	if !again.Equal(a) {
+		t.Errorf("Cached value was modified through a returned pointer")
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	c := NewCache(8)
+	var ids []*KUID
+	for i := 0; i < 16; i++ {
+		id, _ := NewKUID()
+		ids = append(ids, id)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				id := ids[i%len(ids)]
+				got, err := c.FromString(c.String(id))
+				if err != nil || !got.Equal(id) {
+					t.Errorf("Roundtrip through cache failed for %v", id)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkCache(b *testing.B) {
+	sample, _ := NewKUID()
+	sampleStr := sample.String()
+	c := NewCache(16)
+
+	b.Run("FromString", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := FromString(sampleStr); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("CachedFromString", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := c.FromString(sampleStr); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
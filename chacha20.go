@@ -0,0 +1,146 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+const (
+	chachaBlockSize      = 64
+	chachaBufferBlocks   = 16
+	chachaReseedBytes    = 1 << 20 // reseed from crypto/rand after this much output
+	chachaReseedInterval = 5 * time.Minute
+)
+
+// chachaEntropy is a userspace CSPRNG producing a ChaCha20 keystream (RFC
+// 8439) keyed from crypto/rand. After every buffer refill the key is
+// replaced with fresh keystream ("fast key erasure"), so a later compromise
+// of the state does not reveal previously generated KUIDs. The key is
+// reseeded from crypto/rand periodically and after a fixed amount of output.
+type chachaEntropy struct {
+	mu       sync.Mutex
+	key      [8]uint32
+	nonce    [3]uint32
+	counter  uint32
+	buf      [chachaBlockSize * chachaBufferBlocks]byte
+	pos      int
+	served   int
+	seededAt time.Time
+	seeded   bool
+}
+
+func (c *chachaEntropy) uint128() (uint64, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pos+16 > len(c.buf) || !c.seeded {
+		// Reseed checks only run once per buffer to keep the fast path cheap
+		if !c.seeded || c.served >= chachaReseedBytes || time.Since(c.seededAt) >= chachaReseedInterval {
+			if err := c.reseed(); err != nil {
+				return 0, 0, err
+			}
+		} else {
+			c.refill()
+		}
+	}
+
+	msb := binary.BigEndian.Uint64(c.buf[c.pos:])
+	lsb := binary.BigEndian.Uint64(c.buf[c.pos+8:])
+	clear(c.buf[c.pos : c.pos+16])
+	c.pos += 16
+	c.served += 16
+	return msb, lsb, nil
+}
+
+// reseed replaces the key and nonce with fresh bytes from crypto/rand
+func (c *chachaEntropy) reseed() error {
+	var seed [44]byte
+	if err := readRandom(seed[:]); err != nil {
+		return err
+	}
+	for i := range c.key {
+		c.key[i] = binary.LittleEndian.Uint32(seed[i*4:])
+	}
+	for i := range c.nonce {
+		c.nonce[i] = binary.LittleEndian.Uint32(seed[32+i*4:])
+	}
+	clear(seed[:])
+
+	c.counter = 0
+	c.served = 0
+	c.seededAt = time.Now()
+	c.seeded = true
+	c.refill()
+	return nil
+}
+
+// refill regenerates the output buffer and rekeys from its first 32 bytes
+func (c *chachaEntropy) refill() {
+	var block [chachaBlockSize]byte
+	for i := 0; i < chachaBufferBlocks; i++ {
+		chachaBlock(&block, &c.key, c.counter, &c.nonce)
+		copy(c.buf[i*chachaBlockSize:], block[:])
+		c.counter++
+	}
+	clear(block[:])
+
+	for i := range c.key {
+		c.key[i] = binary.LittleEndian.Uint32(c.buf[i*4:])
+	}
+	clear(c.buf[:32])
+	c.pos = 32
+}
+
+// chachaBlock computes one 64-byte ChaCha20 keystream block
+func chachaBlock(out *[chachaBlockSize]byte, key *[8]uint32, counter uint32, nonce *[3]uint32) {
+	const c0, c1, c2, c3 = 0x61707865, 0x3320646e, 0x79622d32, 0x6b206574
+
+	x0, x1, x2, x3 := uint32(c0), uint32(c1), uint32(c2), uint32(c3)
+	x4, x5, x6, x7 := key[0], key[1], key[2], key[3]
+	x8, x9, x10, x11 := key[4], key[5], key[6], key[7]
+	x12, x13, x14, x15 := counter, nonce[0], nonce[1], nonce[2]
+
+	for i := 0; i < 10; i++ {
+		// Column rounds
+		x0, x4, x8, x12 = quarterRound(x0, x4, x8, x12)
+		x1, x5, x9, x13 = quarterRound(x1, x5, x9, x13)
+		x2, x6, x10, x14 = quarterRound(x2, x6, x10, x14)
+		x3, x7, x11, x15 = quarterRound(x3, x7, x11, x15)
+		// Diagonal rounds
+		x0, x5, x10, x15 = quarterRound(x0, x5, x10, x15)
+		x1, x6, x11, x12 = quarterRound(x1, x6, x11, x12)
+		x2, x7, x8, x13 = quarterRound(x2, x7, x8, x13)
+		x3, x4, x9, x14 = quarterRound(x3, x4, x9, x14)
+	}
+
+	binary.LittleEndian.PutUint32(out[0:], x0+c0)
+	binary.LittleEndian.PutUint32(out[4:], x1+c1)
+	binary.LittleEndian.PutUint32(out[8:], x2+c2)
+	binary.LittleEndian.PutUint32(out[12:], x3+c3)
+	binary.LittleEndian.PutUint32(out[16:], x4+key[0])
+	binary.LittleEndian.PutUint32(out[20:], x5+key[1])
+	binary.LittleEndian.PutUint32(out[24:], x6+key[2])
+	binary.LittleEndian.PutUint32(out[28:], x7+key[3])
+	binary.LittleEndian.PutUint32(out[32:], x8+key[4])
+	binary.LittleEndian.PutUint32(out[36:], x9+key[5])
+	binary.LittleEndian.PutUint32(out[40:], x10+key[6])
+	binary.LittleEndian.PutUint32(out[44:], x11+key[7])
+	binary.LittleEndian.PutUint32(out[48:], x12+counter)
+	binary.LittleEndian.PutUint32(out[52:], x13+nonce[0])
+	binary.LittleEndian.PutUint32(out[56:], x14+nonce[1])
+	binary.LittleEndian.PutUint32(out[60:], x15+nonce[2])
+}
+
+func quarterRound(a, b, c, d uint32) (uint32, uint32, uint32, uint32) {
+	a += b
+	d = bits.RotateLeft32(d^a, 16)
+	c += d
+	b = bits.RotateLeft32(b^c, 12)
+	a += b
+	d = bits.RotateLeft32(d^a, 8)
+	c += d
+	b = bits.RotateLeft32(b^c, 7)
+	return a, b, c, d
+}
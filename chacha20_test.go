@@ -0,0 +1,55 @@
+package kuid
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestChaChaBlock(t *testing.T) {
+	// Test vector from RFC 8439 section 2.3.2
+	var key [8]uint32
+	for i := range key {
+		b := byte(i * 4)
+		key[i] = uint32(b) | uint32(b+1)<<8 | uint32(b+2)<<16 | uint32(b+3)<<24
+	}
+	nonce := [3]uint32{0x09000000, 0x4a000000, 0x00000000}
+
+	var out [chachaBlockSize]byte
+	chachaBlock(&out, &key, 1, &nonce)
+
+	want := "10f1e7e4d13b5915500fdd1fa32071c4c7d1f4c733c068030422aa9ac3d46c4e" +
+		"d2826446079faa0914c2d705d98b02a2b5129cd1de164eb9cbd083e8a2503c4e"
+	if got := hex.EncodeToString(out[:]); got != want {
+		t.Errorf("chachaBlock() = %v, want %v", got, want)
+	}
+}
+
+func TestChaChaEntropyReseed(t *testing.T) {
+	var c chachaEntropy
+
+	seen := make(map[[2]uint64]bool)
+	for i := 0; i < 2*chachaReseedBytes/16; i++ {
+		msb, lsb, err := c.uint128()
+		if err != nil {
+			t.Fatalf("uint128() error = %v", err)
+		}
+		if seen[[2]uint64{msb, lsb}] {
+			t.Fatalf("Duplicate output after %d reads", i)
+		}
+		seen[[2]uint64{msb, lsb}] = true
+	}
+	if c.served > chachaReseedBytes+len(c.buf) {
+		t.Errorf("Expected reseed after %d bytes, served %d", chachaReseedBytes, c.served)
+	}
+
+	stale := time.Now().Add(-chachaReseedInterval)
+	c.seededAt = stale
+	c.pos = len(c.buf)
+	if _, _, err := c.uint128(); err != nil {
+		t.Fatalf("uint128() error = %v", err)
+	}
+	if !c.seededAt.After(stale) {
+		t.Errorf("Expected reseed after %v", chachaReseedInterval)
+	}
+}
@@ -0,0 +1,57 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// ClickHouseUUID returns the KUID in ClickHouse's native UUID byte layout,
+// as used by the RowBinary and Native formats: the two 64-bit halves are
+// each stored little-endian. The UUID shown by ClickHouse is the same as
+// ToUUID.
+func (k *KUID) ClickHouseUUID() [16]byte {
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[0:8], k.msb)
+	binary.LittleEndian.PutUint64(b[8:16], k.lsb)
+	return b
+}
+
+// FromClickHouseUUID creates a KUID from ClickHouse's native UUID layout
+func FromClickHouseUUID(b [16]byte) *KUID {
+	return &KUID{
+		msb: binary.LittleEndian.Uint64(b[0:8]),
+		lsb: binary.LittleEndian.Uint64(b[8:16]),
+	}
+}
+
+// AppendClickHouseRowBinary appends the RowBinary encoding of a UUID column
+// value, for bulk loaders writing RowBinary directly
+func (k *KUID) AppendClickHouseRowBinary(buf []byte) []byte {
+	b := k.ClickHouseUUID()
+	return append(buf, b[:]...)
+}
+
+// AppendUUIDs appends ids as 16-byte UUID values in RFC byte order to dst.
+// T can be any [16]byte type, such as github.com/google/uuid.UUID, which is
+// what clickhouse-go expects for UUID columns:
+//
+//	col := kuid.AppendUUIDs([]uuid.UUID(nil), ids)
+//	err := batch.Column(0).Append(col)
+//
+// This avoids formatting and reparsing a string per row.
+func AppendUUIDs[T ~[16]byte](dst []T, ids []*KUID) []T {
+	dst = slices.Grow(dst, len(ids))
+	for _, id := range ids {
+		dst = append(dst, T(id.Array()))
+	}
+	return dst
+}
+
+// FromUUIDs converts 16-byte UUID values in RFC byte order to KUIDs
+func FromUUIDs[T ~[16]byte](src []T) []*KUID {
+	ids := make([]*KUID, len(src))
+	for i, u := range src {
+		ids[i] = FromArray([16]byte(u))
+	}
+	return ids
+}
@@ -0,0 +1,46 @@
+package kuid
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestClickHouseUUID(t *testing.T) {
+	kuid, _ := FromUUID("00112233-4455-6677-8899-aabbccddeeff")
+
+	native := kuid.ClickHouseUUID()
+	want := "7766554433221100ffeeddccbbaa9988"
+	if got := hex.EncodeToString(native[:]); got != want {
+		t.Errorf("ClickHouseUUID() = %v, want %v", got, want)
+	}
+	if !FromClickHouseUUID(native).Equal(kuid) {
+		t.Errorf("FromClickHouseUUID() roundtrip failed")
+	}
+
+	row := kuid.AppendClickHouseRowBinary([]byte{1})
+	if hex.EncodeToString(row) != "01"+want {
+		t.Errorf("AppendClickHouseRowBinary() = %x", row)
+	}
+}
+
+// googleUUID mirrors github.com/google/uuid.UUID
+type googleUUID [16]byte
+
+func TestAppendUUIDs(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+
+	prefix := []googleUUID{{0xFF}}
+	col := AppendUUIDs(prefix[:1:1], []*KUID{a, b})
+	if len(col) != 3 || col[0] != prefix[0] {
+		t.Fatalf("AppendUUIDs() = %v, want prefix and 2 values", col)
+	}
+	if col[1] != googleUUID(a.Array()) || col[2] != googleUUID(b.Array()) {
+		t.Errorf("AppendUUIDs() values do not match the KUIDs")
+	}
+
+	ids := FromUUIDs(col[1:])
+	if !ids[0].Equal(a) || !ids[1].Equal(b) {
+		t.Errorf("FromUUIDs() = %v, want [%v %v]", ids, a, b)
+	}
+}
@@ -0,0 +1,64 @@
+package kuid
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to a Generator's ordered and HLC modes
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock makes the Generator, and any HLC built on it, read time from
+// clock instead of the system clock
+func WithClock(clock Clock) Option {
+	return func(c *config) error {
+		if clock == nil {
+			clock = systemClock{}
+		}
+		c.clock = clock
+		return nil
+	}
+}
+
+// ManualClock is a Clock that only moves when told to, for tests and
+// simulations that need deterministic timelines. It is safe for concurrent
+// use.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock creates a ManualClock frozen at t
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now returns the clock's current time
+func (m *ManualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set moves the clock to t, which may be in the past
+func (m *ManualClock) Set(t time.Time) {
+	m.mu.Lock()
+	m.now = t
+	m.mu.Unlock()
+}
+
+// Advance moves the clock forward by d, or backward if d is negative
+func (m *ManualClock) Advance(d time.Duration) {
+	m.mu.Lock()
+	m.now = m.now.Add(d)
+	m.mu.Unlock()
+}
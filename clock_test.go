@@ -0,0 +1,69 @@
+package kuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClockOrdered(t *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	clock := NewManualClock(start)
+	g, _ := NewGenerator(WithClock(clock))
+
+	for i := 0; i < 3; i++ {
+		k, _ := g.NewOrdered()
+		if !k.Timestamp().Equal(start) || k.Sequence() != uint16(i) {
+			t.Errorf("KUID %d = %v/%d, want %v/%d", i, k.Timestamp(), k.Sequence(), start, i)
+		}
+	}
+
+	clock.Advance(time.Second)
+	k, _ := g.NewOrdered()
+	if !k.Timestamp().Equal(start.Add(time.Second)) || k.Sequence() != 0 {
+		t.Errorf("After Advance = %v/%d", k.Timestamp(), k.Sequence())
+	}
+
+	// Going back in time must not break ordering
+	clock.Set(start)
+	back, _ := g.NewOrdered()
+	if back.msb <= k.msb {
+		t.Errorf("KUID after clock went back sorts before earlier KUID")
+	}
+}
+
+func TestManualClockHLC(t *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	clock := NewManualClock(start)
+	g, _ := NewGenerator(WithClock(clock))
+	h, _ := NewHLC(g, time.Minute)
+
+	a, _ := h.Now()
+	b, _ := h.Now()
+	if !a.HLCPhysical().Equal(start) || a.HLCLogical() != 0 || b.HLCLogical() != 1 {
+		t.Errorf("Now() = %v/%d, %v/%d", a.HLCPhysical(), a.HLCLogical(), b.HLCPhysical(), b.HLCLogical())
+	}
+
+	remote, _ := NewHLC(mustGenerator(t, WithClock(NewManualClock(start.Add(2*time.Minute)))), 0)
+	ahead, _ := remote.Now()
+	if _, err := h.Update(ahead); err != ErrClockDrift {
+		t.Errorf("Update() error = %v, want %v", err, ErrClockDrift)
+	}
+
+	clock.Advance(90 * time.Second)
+	merged, err := h.Update(ahead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged.HLCPhysical().Equal(ahead.HLCPhysical()) || merged.HLCLogical() != 1 {
+		t.Errorf("Update() = %v/%d", merged.HLCPhysical(), merged.HLCLogical())
+	}
+}
+
+func mustGenerator(t *testing.T, opts ...Option) *Generator {
+	t.Helper()
+	g, err := NewGenerator(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
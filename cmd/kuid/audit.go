@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alphabatem/kuid/analysis"
+)
+
+func runAudit(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ordered := fs.Bool("ordered", false, "audit ordered KUIDs: check ordering and only the random lower 64 bits for bias")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid audit [-ordered] [file]")
+		fmt.Fprintln(stderr, "\nReads one KUID or UUID per line from file or stdin and reports")
+		fmt.Fprintln(stderr, "bit bias, duplicates, ordering violations and timestamp spread.")
+		fmt.Fprintln(stderr, "Exits 1 if any check fails.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	r, err := analysis.Read(in)
+	if err != nil {
+		return err
+	}
+	printReport(stdout, r, *ordered)
+
+	// Ordered KUIDs carry a timestamp and sequence in the upper 64 bits,
+	// so only the lower half is expected to be random
+	biased := r.BiasedBits
+	if *ordered {
+		biased = slices.DeleteFunc(slices.Clone(biased), func(b int) bool { return b < 64 })
+	}
+
+	if r.Count == 0 || r.Invalid > 0 || r.Duplicates > 0 || len(biased) > 0 ||
+		(*ordered && r.Violations > 0) {
+		return errExitFailure
+	}
+	return nil
+}
+
+func printReport(w io.Writer, r analysis.Report, ordered bool) {
+	fmt.Fprintf(w, "ids:         %d\n", r.Count)
+	fmt.Fprintf(w, "invalid:     %d\n", r.Invalid)
+	fmt.Fprintf(w, "duplicates:  %d\n", r.Duplicates)
+	fmt.Fprintf(w, "max bias:    %.4f\n", r.MaxBias)
+	if len(r.BiasedBits) > 0 {
+		bits := make([]string, len(r.BiasedBits))
+		for i, b := range r.BiasedBits {
+			bits[i] = fmt.Sprint(b)
+		}
+		fmt.Fprintf(w, "biased bits: %s\n", strings.Join(bits, " "))
+	}
+	fmt.Fprintf(w, "violations:  %d\n", r.Violations)
+
+	if !ordered || r.Count == 0 {
+		return
+	}
+	h := r.Timestamps
+	fmt.Fprintf(w, "timestamps:  %s .. %s\n", h.Min.UTC().Format(time.RFC3339Nano), h.Max.UTC().Format(time.RFC3339Nano))
+	for i, n := range h.Counts {
+		start := h.Min.Add(time.Duration(i) * h.Width)
+		fmt.Fprintf(w, "  %s  %d\n", start.UTC().Format(time.RFC3339Nano), n)
+	}
+}
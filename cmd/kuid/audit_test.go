@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestAudit(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	var ids []string
+	for i := 0; i < 100; i++ {
+		k, _ := g.NewOrdered()
+		ids = append(ids, k.String())
+	}
+
+	var random []string
+	for i := 0; i < 1000; i++ {
+		k, _ := kuid.NewKUID()
+		random = append(random, k.String())
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    []string
+		wantCode int
+		wantOut  string
+	}{
+		{"clean", []string{"audit", "-ordered"}, ids, 0, "duplicates:  0"},
+		{"duplicate", []string{"audit", "-ordered"}, slices.Concat(ids, ids[:1]), 1, "duplicates:  1"},
+		{"random", []string{"audit"}, random, 0, "duplicates:  0"},
+		{"unordered", []string{"audit", "-ordered"}, slices.Concat(ids[99:], ids[:99]), 1, "violations:  1"},
+		{"invalid", []string{"audit", "-ordered"}, slices.Concat(ids, []string{"nope"}), 1, "invalid:     1"},
+		{"empty", []string{"audit"}, nil, 1, "ids:         0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			stdin := strings.NewReader(strings.Join(tt.input, "\n"))
+			code := run(tt.args, stdin, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("output missing %q:\n%s", tt.wantOut, stdout.String())
+			}
+		})
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"frobnicate"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("exit code = %d, want 2", code)
+	}
+}
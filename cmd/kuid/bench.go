@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+// benchModes maps each kuid bench mode to a constructor for its per-worker
+// operation. Workers get their own operation so fixtures are not shared.
+var benchModes = map[string]func(g *kuid.Generator) (func() error, error){
+	"new": func(g *kuid.Generator) (func() error, error) {
+		return func() error { _, err := g.NewValue(); return err }, nil
+	},
+	"ordered": func(g *kuid.Generator) (func() error, error) {
+		return func() error { _, err := g.NewOrdered(); return err }, nil
+	},
+	"v4": func(g *kuid.Generator) (func() error, error) {
+		return func() error { _, err := g.NewV4(); return err }, nil
+	},
+	"encode": func(g *kuid.Generator) (func() error, error) {
+		k, err := g.NewValue()
+		buf := make([]byte, 0, 22)
+		return func() error { buf = k.AppendString(buf[:0]); return nil }, err
+	},
+	"decode": func(g *kuid.Generator) (func() error, error) {
+		k, err := g.NewValue()
+		s := k.String()
+		return func() error { _, err := kuid.ParseValue(s); return err }, err
+	},
+	"uuid": func(g *kuid.Generator) (func() error, error) {
+		k, err := g.NewValue()
+		return func() error { _ = k.ToUUID(); return nil }, err
+	},
+	"from-uuid": func(g *kuid.Generator) (func() error, error) {
+		k, err := g.NewValue()
+		u := k.ToUUID()
+		return func() error { _, err := kuid.FromUUID(u); return err }, err
+	},
+}
+
+// benchReport is the JSON output of kuid bench
+type benchReport struct {
+	GoVersion  string        `json:"go_version"`
+	GOOS       string        `json:"goos"`
+	GOARCH     string        `json:"goarch"`
+	NumCPU     int           `json:"num_cpu"`
+	GOMAXPROCS int           `json:"gomaxprocs"`
+	Backend    string        `json:"backend"`
+	Duration   string        `json:"duration"`
+	Results    []benchResult `json:"results"`
+}
+
+type benchResult struct {
+	Mode        string  `json:"mode"`
+	Parallelism int     `json:"parallelism"`
+	Ops         uint64  `json:"ops"`
+	OpsPerSec   float64 `json:"ops_per_sec"`
+	NsPerOp     float64 `json:"ns_per_op"`
+}
+
+func runBench(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	modes := fs.String("mode", "new,ordered,encode,decode", "comma-separated modes: "+strings.Join(benchModeNames(), ", "))
+	defaultParallel := "1"
+	if procs := runtime.GOMAXPROCS(0); procs > 1 {
+		defaultParallel += "," + strconv.Itoa(procs)
+	}
+	parallel := fs.String("parallel", defaultParallel, "comma-separated worker counts")
+	duration := fs.Duration("duration", time.Second, "how long to run each mode at each parallelism")
+	backendName := fs.String("backend", "crypto", "entropy backend: crypto, chacha20 or unsafe-math")
+	asJSON := fs.Bool("json", false, "print JSON for tracking results across hosts")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid bench [flags]")
+		fmt.Fprintln(stderr, "\nMeasures KUID generation, encoding and decoding throughput on this machine.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+
+	backend, err := parseBackend(*backendName)
+	if err != nil {
+		return err
+	}
+	names := strings.Split(*modes, ",")
+	for _, name := range names {
+		if _, ok := benchModes[name]; !ok {
+			return fmt.Errorf("unknown mode %q", name)
+		}
+	}
+	var workers []int
+	for _, p := range strings.Split(*parallel, ",") {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid parallelism %q", p)
+		}
+		workers = append(workers, n)
+	}
+
+	g, err := kuid.NewGenerator(kuid.WithBackend(backend))
+	if err != nil {
+		return err
+	}
+	report := benchReport{
+		GoVersion:  runtime.Version(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Backend:    backend.String(),
+		Duration:   duration.String(),
+	}
+	for _, name := range names {
+		for _, n := range workers {
+			r, err := bench(g, name, n, *duration)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			report.Results = append(report.Results, r)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	fmt.Fprintf(stdout, "%s %s/%s, %d CPUs, backend %s\n",
+		report.GoVersion, report.GOOS, report.GOARCH, report.NumCPU, report.Backend)
+	for _, r := range report.Results {
+		fmt.Fprintf(stdout, "%-10s %4d workers %14.0f ops/s %10.1f ns/op\n",
+			r.Mode, r.Parallelism, r.OpsPerSec, r.NsPerOp)
+	}
+	return nil
+}
+
+// bench runs mode on n workers for d and reports the combined throughput
+func bench(g *kuid.Generator, mode string, n int, d time.Duration) (benchResult, error) {
+	ops := make([]func() error, n)
+	for i := range ops {
+		op, err := benchModes[mode](g)
+		if err != nil {
+			return benchResult{}, err
+		}
+		ops[i] = op
+	}
+
+	var (
+		total   atomic.Uint64
+		stop    atomic.Bool
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		opErr   error
+	)
+	start := time.Now()
+	for _, op := range ops {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var count uint64
+			// Check the clock in batches so it does not dominate fast modes
+			for !stop.Load() {
+				for range 256 {
+					if err := op(); err != nil {
+						errOnce.Do(func() { opErr = err })
+						stop.Store(true)
+						break
+					}
+					count++
+				}
+				if time.Since(start) >= d {
+					stop.Store(true)
+				}
+			}
+			total.Add(count)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	if opErr != nil {
+		return benchResult{}, opErr
+	}
+
+	r := benchResult{Mode: mode, Parallelism: n, Ops: total.Load()}
+	r.OpsPerSec = float64(r.Ops) / elapsed.Seconds()
+	if r.Ops > 0 {
+		r.NsPerOp = float64(elapsed.Nanoseconds()) * float64(n) / float64(r.Ops)
+	}
+	return r, nil
+}
+
+func benchModeNames() []string {
+	names := make([]string, 0, len(benchModes))
+	for name := range benchModes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseBackend returns the Backend whose String form is name
+func parseBackend(name string) (kuid.Backend, error) {
+	for _, b := range []kuid.Backend{kuid.BackendCrypto, kuid.BackendChaCha20, kuid.BackendUnsafeMath} {
+		if b.String() == name {
+			return b, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown backend %q", name)
+}
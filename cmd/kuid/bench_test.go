@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestBench(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"bench", "-json", "-duration", "10ms", "-parallel", "1,2", "-mode", "new,decode", "-backend", "chacha20"}
+	if code := run(args, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d; stderr: %s", code, stderr.String())
+	}
+
+	var report benchReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Backend != "chacha20" || len(report.Results) != 4 {
+		t.Fatalf("report = %+v, want 4 chacha20 results", report)
+	}
+	for _, r := range report.Results {
+		if r.Ops == 0 || r.OpsPerSec <= 0 {
+			t.Errorf("%s with %d workers measured no operations", r.Mode, r.Parallelism)
+		}
+	}
+}
+
+func TestBenchInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"-mode", "nope"},
+		{"-parallel", "0"},
+		{"-backend", "nope"},
+		{"-duration", "0s"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(append([]string{"bench"}, args...), nil, &stdout, &stderr); code != 1 {
+			t.Errorf("bench %v exit code = %d, want 1", args, code)
+		}
+	}
+}
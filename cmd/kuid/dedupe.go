@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/alphabatem/kuid/analysis"
+)
+
+func runDedupe(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var opts analysis.DedupeOptions
+	fs.IntVar(&opts.Partitions, "partitions", 0, "number of temporary partition files (default 256)")
+	fs.StringVar(&opts.TempDir, "tmp", "", "directory for partition files (default system temp dir)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid dedupe [-partitions n] [-tmp dir] [file]")
+		fmt.Fprintln(stderr, "\nReads one KUID or UUID per line from file or stdin, spilling to disk,")
+		fmt.Fprintln(stderr, "and prints each duplicated KUID with its count. Exits 1 if any are found.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	stats, err := analysis.FindDuplicates(in, opts, func(d analysis.Duplicate) error {
+		_, err := fmt.Fprintf(stdout, "%s %d\n", d.ID.String(), d.Count)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "%d ids, %d invalid, %d duplicated\n", stats.Count, stats.Invalid, stats.Duplicates)
+
+	if stats.Duplicates > 0 {
+		return errExitFailure
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestDedupe(t *testing.T) {
+	a, _ := kuid.NewKUID()
+	b, _ := kuid.NewKUID()
+
+	tests := []struct {
+		name     string
+		input    []string
+		wantCode int
+		wantOut  string
+	}{
+		{"unique", []string{a.String(), b.String()}, 0, ""},
+		{"duplicate", []string{a.String(), b.String(), a.ToUUID()}, 1, a.String() + " 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			stdin := strings.NewReader(strings.Join(tt.input, "\n"))
+			args := []string{"dedupe", "-partitions", "4", "-tmp", t.TempDir()}
+			if code := run(args, stdin, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			if stdout.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+// inspection is everything kuid inspect can tell about an ID
+type inspection struct {
+	Input      string            `json:"input"`
+	KUID       string            `json:"kuid"`
+	UUID       string            `json:"uuid"`
+	Bytes      string            `json:"bytes"`
+	MSB        string            `json:"msb"`
+	LSB        string            `json:"lsb"`
+	Variant    string            `json:"variant"`
+	Version    int               `json:"version,omitempty"`
+	UUIDTime   *time.Time        `json:"uuid_time,omitempty"`
+	Ordered    *orderedFields    `json:"ordered,omitempty"`
+	Embedded   map[string]uint64 `json:"embedded,omitempty"`
+	Region     string            `json:"region,omitempty"`
+	Percentile float64           `json:"percentile"`
+}
+
+type orderedFields struct {
+	Timestamp time.Time `json:"timestamp"`
+	Sequence  uint16    `json:"sequence"`
+	Node      *uint64   `json:"node,omitempty"`
+}
+
+// plausibleSince bounds the timestamps reported for ordered KUIDs. Random
+// KUIDs decode to arbitrary times, so only ones in a believable range
+// are shown.
+var plausibleSince = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func runInspect(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print JSON")
+	typeBits := fs.Int("type-bits", 0, "width of the embedded type tag")
+	regionBits := fs.Int("region-bits", 0, "width of the embedded region")
+	tenantBits := fs.Int("tenant-bits", 0, "width of the embedded tenant")
+	topologyFile := fs.String("topology", "", "topology JSON for decoding node and region of ordered KUIDs")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid inspect [flags] id...")
+		fmt.Fprintln(stderr, "\nDecodes KUIDs or UUIDs given as arguments, or one per line on stdin.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var opts []kuid.Option
+	if *regionBits > 0 {
+		opts = append(opts, kuid.WithRegion(*regionBits, 0))
+	}
+	if *tenantBits > 0 {
+		opts = append(opts, kuid.WithTenant(*tenantBits, 0))
+	}
+	layout, err := kuid.NewGenerator(opts...)
+	if err != nil {
+		return err
+	}
+	var types *kuid.TypeRegistry
+	if *typeBits > 0 {
+		if types, err = kuid.NewTypeRegistry(*typeBits); err != nil {
+			return err
+		}
+	}
+	var topo *kuid.Topology
+	if *topologyFile != "" {
+		f, err := os.Open(*topologyFile)
+		if err != nil {
+			return err
+		}
+		topo, err = kuid.LoadTopology(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	ids := fs.Args()
+	if len(ids) == 0 {
+		if ids, err = readLines(stdin); err != nil {
+			return err
+		}
+	}
+	if len(ids) == 0 {
+		fs.Usage()
+		return errors.New("no IDs given")
+	}
+
+	failed := false
+	enc := json.NewEncoder(stdout)
+	for i, input := range ids {
+		k, err := kuid.Parse(input)
+		if err != nil {
+			fmt.Fprintf(stderr, "kuid inspect: %v\n", err)
+			failed = true
+			continue
+		}
+
+		in := inspect(input, k, layout, types, topo)
+		if *asJSON {
+			if err := enc.Encode(in); err != nil {
+				return err
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		printInspection(stdout, in)
+	}
+	if failed {
+		return errExitFailure
+	}
+	return nil
+}
+
+func inspect(input string, k *kuid.KUID, layout *kuid.Generator, types *kuid.TypeRegistry, topo *kuid.Topology) inspection {
+	b := k.Bytes()
+	msb := binary.BigEndian.Uint64(b[:8])
+	in := inspection{
+		Input:      input,
+		KUID:       k.String(),
+		UUID:       k.ToUUID(),
+		Bytes:      hex.EncodeToString(b),
+		MSB:        fmt.Sprintf("%016x", msb),
+		LSB:        fmt.Sprintf("%016x", binary.BigEndian.Uint64(b[8:])),
+		Variant:    "none (raw KUID)",
+		Percentile: float64(msb) / math.Exp2(64) * 100,
+	}
+
+	if k.IsRFC9562() {
+		in.Variant = "RFC 9562"
+		in.Version = k.Version()
+		if t, err := k.UUIDTime(); err == nil {
+			in.UUIDTime = &t
+		}
+	}
+
+	if topo != nil {
+		of := orderedFields{Timestamp: topo.Timestamp(k), Sequence: topo.Sequence(k)}
+		node := topo.Node(k)
+		of.Node = &node
+		in.Ordered = &of
+		in.Region, _ = topo.Region(k)
+	} else if ts := k.Timestamp(); ts.After(plausibleSince) && ts.Before(time.Now().AddDate(1, 0, 0)) {
+		in.Ordered = &orderedFields{Timestamp: ts, Sequence: k.Sequence()}
+	}
+
+	embedded := map[string]uint64{}
+	if types != nil {
+		embedded["type"] = types.Tag(k)
+	}
+	if v, ok := layout.Region(k); ok {
+		embedded["region"] = v
+	}
+	if v, ok := layout.Tenant(k); ok {
+		embedded["tenant"] = v
+	}
+	if len(embedded) > 0 {
+		in.Embedded = embedded
+	}
+	return in
+}
+
+func printInspection(w io.Writer, in inspection) {
+	row := func(key, format string, args ...any) {
+		fmt.Fprintf(w, "%-12s %s\n", key+":", fmt.Sprintf(format, args...))
+	}
+	row("input", "%s", in.Input)
+	row("kuid", "%s", in.KUID)
+	row("uuid", "%s", in.UUID)
+	row("bytes", "%s", in.Bytes)
+	row("msb", "%s", in.MSB)
+	row("lsb", "%s", in.LSB)
+	if in.Version > 0 {
+		row("variant", "%s, version %d", in.Variant, in.Version)
+	} else {
+		row("variant", "%s", in.Variant)
+	}
+	if in.UUIDTime != nil {
+		row("uuid time", "%s", in.UUIDTime.UTC().Format(time.RFC3339Nano))
+	}
+	if in.Ordered != nil {
+		row("timestamp", "%s (if ordered)", in.Ordered.Timestamp.UTC().Format(time.RFC3339Nano))
+		row("sequence", "%d", in.Ordered.Sequence)
+		if in.Ordered.Node != nil {
+			row("node", "%d", *in.Ordered.Node)
+		}
+	}
+	if in.Region != "" {
+		row("region", "%s", in.Region)
+	}
+	for _, name := range []string{"type", "region", "tenant"} {
+		if v, ok := in.Embedded[name]; ok {
+			row(name+" bits", "%d", v)
+		}
+	}
+	row("percentile", "%.4f%%", in.Percentile)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestInspect(t *testing.T) {
+	g, _ := kuid.NewGenerator(kuid.WithTenant(8, 42))
+	// The top two bits of the lower half are random; skip the quarter of
+	// ordered KUIDs whose random bits happen to spell the RFC variant
+	ordered, _ := g.NewOrdered()
+	for ordered.IsRFC9562() {
+		ordered, _ = g.NewOrdered()
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantCode int
+		wantOut  []string
+	}{
+		{"uuid v4", []string{"f47ac10b-58cc-4372-a567-0e02b2c3d479"}, "", 0,
+			[]string{"variant:     RFC 9562, version 4", "msb:         f47ac10b58cc4372", "percentile:  95.4998%"}},
+		{"uuid v1", []string{"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}, "", 0,
+			[]string{"version 1", "uuid time:   1998-02-04T22:13:53"}},
+		{"ordered", []string{"-tenant-bits", "8", ordered.String()}, "", 0,
+			[]string{"timestamp:", "tenant bits: 42", "variant:     none"}},
+		{"stdin", nil, "0000000000000000000000\n", 0, []string{"percentile:  0.0000%"}},
+		{"invalid", []string{"bad"}, "", 1, nil},
+		{"no ids", nil, "", 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"inspect"}, tt.args...)
+			if code := run(args, strings.NewReader(tt.stdin), &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestInspectJSON(t *testing.T) {
+	topo := `{"node_bits": 4, "sequence_bits": 12, "regions": [{"name": "eu", "first_node": 0, "last_node": 15}]}`
+	path := filepath.Join(t.TempDir(), "topology.json")
+	if err := os.WriteFile(path, []byte(topo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tp, _ := kuid.LoadTopology(strings.NewReader(topo))
+	g, _ := kuid.NewGenerator(kuid.WithTopology(tp, "eu", 9))
+	id, _ := g.NewOrdered()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"inspect", "-json", "-topology", path, id.String()}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d; stderr: %s", code, stderr.String())
+	}
+	var got inspection
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.KUID != id.String() || got.Ordered == nil || got.Ordered.Node == nil || *got.Ordered.Node != 9 || got.Region != "eu" {
+		t.Errorf("inspect -json = %+v", got)
+	}
+}
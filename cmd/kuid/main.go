@@ -0,0 +1,100 @@
+// Command kuid works with KUIDs from the shell.
+//
+// Usage:
+//
+//	kuid <command> [flags] [args]
+//
+// Run "kuid help" for the list of commands.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command is a kuid subcommand. It returns errExitFailure to exit non-zero
+// without printing anything further.
+type command struct {
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var commands = map[string]command{
+	"audit":     {"analyse a stream of IDs for bias, duplicates and ordering", runAudit},
+	"bench":     {"measure generation, encoding and decoding throughput", runBench},
+	"dedupe":    {"find duplicate IDs in streams too large for memory", runDedupe},
+	"inspect":   {"decode the fields and layout of IDs", runInspect},
+	"join":      {"print IDs common to two files, or only in one", runJoin},
+	"migrate":   {"print SQL migrating UUID columns to 16-byte storage", runMigrate},
+	"new":       {"generate IDs in the requested format", runNew},
+	"serve":     {"serve ID validation and conversion over HTTP", runServe},
+	"sort":      {"sort IDs by binary value, spilling to disk", runSort},
+	"sql":       {"print SQL functions converting KUIDs in the database", runSQL},
+	"uniq":      {"print distinct IDs, optionally with counts", runUniq},
+	"monotonic": {"verify a stream of ordered KUIDs is strictly increasing", runMonotonic},
+}
+
+// errExitFailure reports a failed check whose details were already printed
+var errExitFailure = fmt.Errorf("check failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "kuid: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	if err := cmd.run(args[1:], stdin, stdout, stderr); err != nil {
+		if err != errExitFailure {
+			fmt.Fprintf(stderr, "kuid %s: %v\n", args[0], err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: kuid <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// readLines returns the non-blank lines of r, trimmed
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// openInput returns the named file, or stdin when name is empty or "-"
+func openInput(name string, stdin io.Reader) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(name)
+}
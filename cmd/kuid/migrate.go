@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alphabatem/kuid/sqlgen"
+)
+
+func runMigrate(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dialect := fs.String("dialect", "postgres", "SQL dialect: postgres, mysql or sqlite")
+	down := fs.Bool("down", false, "print the down migration instead of the up migration")
+	notNull := fs.Bool("not-null", false, "keep the columns NOT NULL (mysql)")
+	converted := fs.Bool("converted", false, "columns already hold 16 bytes; only create views")
+	noView := fs.Bool("no-view", false, "do not create base62 views")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid migrate [-dialect d] [-down] [flags] table.column...")
+		fmt.Fprintln(stderr, "\nPrints SQL converting UUID text columns to 16-byte storage, with views")
+		fmt.Fprintln(stderr, "adding a <column>_kuid column in base62 form.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no columns given")
+	}
+	d, err := sqlgen.ParseDialect(*dialect)
+	if err != nil {
+		return err
+	}
+
+	cols := make([]sqlgen.Column, fs.NArg())
+	for i, arg := range fs.Args() {
+		dot := strings.LastIndexByte(arg, '.')
+		if dot < 0 {
+			return fmt.Errorf("%q is not of the form table.column", arg)
+		}
+		cols[i] = sqlgen.Column{Table: arg[:dot], Name: arg[dot+1:], NotNull: *notNull, Converted: *converted}
+		if *noView {
+			cols[i].View = "-"
+		}
+	}
+
+	m, err := sqlgen.Generate(d, cols...)
+	if err != nil {
+		return err
+	}
+	if *down {
+		_, err = io.WriteString(stdout, m.DownScript())
+	} else {
+		_, err = io.WriteString(stdout, m.UpScript())
+	}
+	return err
+}
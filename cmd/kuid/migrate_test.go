@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{"postgres", []string{"public.users.id"}, 0, "ALTER TABLE public.users ALTER COLUMN id TYPE uuid"},
+		{"mysql down", []string{"-dialect", "mysql", "-down", "-not-null", "orders.id"}, 0, "MODIFY COLUMN id CHAR(36) NOT NULL;"},
+		{"sqlite no view", []string{"-dialect", "sqlite", "-no-view", "a.id"}, 0, "UPDATE a SET id = unhex"},
+		{"no columns", nil, 1, ""},
+		{"no table", []string{"id"}, 1, ""},
+		{"bad dialect", []string{"-dialect", "oracle", "t.id"}, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"migrate"}, tt.args...), nil, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("output = %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+	var stdout bytes.Buffer
+	run([]string{"migrate", "-dialect", "sqlite", "-no-view", "a.id"}, nil, &stdout, &bytes.Buffer{})
+	if strings.Contains(stdout.String(), "VIEW") {
+		t.Errorf("-no-view output creates a view: %s", stdout.String())
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/alphabatem/kuid/analysis"
+)
+
+func runMonotonic(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("monotonic", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	tolerance := fs.Duration("tolerance", 0, "clock lag allowed before a regression is reported as backdated")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid monotonic [-tolerance d] [file]")
+		fmt.Fprintln(stderr, "\nReads one ordered KUID per line from file or stdin and prints every")
+		fmt.Fprintln(stderr, "line that does not sort after all lines before it. Exits 1 if any do.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	violations, err := analysis.CheckMonotonic(in, *tolerance)
+	for _, v := range violations {
+		fmt.Fprintln(stdout, v)
+	}
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return errExitFailure
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestMonotonic(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	a, _ := g.NewOrdered()
+	b, _ := g.NewOrdered()
+
+	tests := []struct {
+		name     string
+		input    []string
+		wantCode int
+		wantOut  string
+	}{
+		{"ordered", []string{a.String(), b.String()}, 0, ""},
+		{"regression", []string{a.String(), b.String(), a.String()}, 1, "3: "},
+		{"invalid", []string{a.String(), "bad"}, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			stdin := strings.NewReader(strings.Join(tt.input, "\n"))
+			if code := run([]string{"monotonic"}, stdin, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.wantOut) {
+				t.Errorf("output = %q, want prefix %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
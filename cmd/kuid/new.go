@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/alphabatem/kuid"
+)
+
+// formats renders a KUID in each kuid new output format other than json
+var formats = map[string]func(k *kuid.KUID) string{
+	"kuid":   (*kuid.KUID).String,
+	"uuid":   (*kuid.KUID).ToUUID,
+	"hex":    func(k *kuid.KUID) string { return hex.EncodeToString(k.Bytes()) },
+	"base58": (*kuid.KUID).Base58,
+}
+
+// typePrefix matches TypeID prefixes: lowercase letters and underscores, at
+// most 63 characters, neither starting nor ending with an underscore
+var typePrefix = regexp.MustCompile(`^[a-z]([a-z_]{0,61}[a-z])?$`)
+
+// newRecord is one element of kuid new -format json output
+type newRecord struct {
+	KUID   string `json:"kuid"`
+	UUID   string `json:"uuid"`
+	Hex    string `json:"hex"`
+	Base58 string `json:"base58"`
+}
+
+func runNew(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "kuid", "output format: kuid, uuid, hex, base58 or json")
+	count := fs.Int("count", 1, "number of IDs to generate")
+	prefix := fs.String("prefix", "", "TypeID-style type prefix, joined to each ID with an underscore")
+	ordered := fs.Bool("ordered", false, "generate time-ordered KUIDs")
+	v4 := fs.Bool("v4", false, "generate RFC 9562 version 4 UUIDs")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid new [flags]")
+		fmt.Fprintln(stderr, "\nPrints newly generated IDs, one per line, or as a JSON array with -format json.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	render, ok := formats[*format]
+	if !ok && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if *count < 0 {
+		return errors.New("count must not be negative")
+	}
+	if *prefix != "" {
+		if !typePrefix.MatchString(*prefix) {
+			return fmt.Errorf("invalid prefix %q: want lowercase letters and inner underscores, at most 63 characters", *prefix)
+		}
+		*prefix += "_"
+	}
+	if *ordered && *v4 {
+		return errors.New("-ordered and -v4 are mutually exclusive")
+	}
+
+	g, err := kuid.NewGenerator()
+	if err != nil {
+		return err
+	}
+	next := g.New
+	switch {
+	case *ordered:
+		next = g.NewOrdered
+	case *v4:
+		next = g.NewV4
+	}
+
+	w := bufio.NewWriter(stdout)
+	if *format == "json" {
+		records := make([]newRecord, 0, *count)
+		for range *count {
+			k, err := next()
+			if err != nil {
+				return err
+			}
+			records = append(records, newRecord{
+				KUID:   *prefix + k.String(),
+				UUID:   *prefix + k.ToUUID(),
+				Hex:    *prefix + formats["hex"](k),
+				Base58: *prefix + k.Base58(),
+			})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(records); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	for range *count {
+		k, err := next()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s%s\n", *prefix, render(k))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantLine *regexp.Regexp
+		wantN    int
+	}{
+		{"default", nil, 0, regexp.MustCompile(`^[0-9A-Za-z]{22}$`), 1},
+		{"uuid", []string{"-format", "uuid", "-count", "3", "-v4"}, 0,
+			regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), 3},
+		{"hex", []string{"-format", "hex", "-count", "2"}, 0, regexp.MustCompile(`^[0-9a-f]{32}$`), 2},
+		{"base58 prefix", []string{"-format", "base58", "-prefix", "user_account", "-ordered"}, 0,
+			regexp.MustCompile(`^user_account_[1-9A-HJ-NP-Za-km-z]{22}$`), 1},
+		{"zero count", []string{"-count", "0"}, 0, nil, 0},
+		{"unknown format", []string{"-format", "xml"}, 1, nil, 0},
+		{"invalid prefix", []string{"-prefix", "User"}, 1, nil, 0},
+		{"negative count", []string{"-count", "-1"}, 1, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"new"}, tt.args...), nil, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			lines := strings.Fields(stdout.String())
+			if len(lines) != tt.wantN {
+				t.Fatalf("got %d lines, want %d", len(lines), tt.wantN)
+			}
+			for _, line := range lines {
+				if !tt.wantLine.MatchString(line) {
+					t.Errorf("line %q does not match %v", line, tt.wantLine)
+				}
+			}
+		})
+	}
+}
+
+func TestNewJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"new", "-format", "json", "-count", "5"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d; stderr: %s", code, stderr.String())
+	}
+	var records []newRecord
+	if err := json.Unmarshal(stdout.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("got %d records, want 5", len(records))
+	}
+	for _, r := range records {
+		k, err := kuid.FromString(r.KUID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.ToUUID() != r.UUID || k.Base58() != r.Base58 {
+			t.Errorf("record %+v does not describe a single ID", r)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+// maxIDBytes bounds the JSON encoding of one input ID, escapes included
+const maxIDBytes = 256
+
+// clockFloor is the earliest believable wall clock time. Hosts whose clock
+// has been reset, commonly to 1970, would mint ordered KUIDs that sort
+// before everything already issued.
+var clockFloor = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// serverConfig configures kuid serve
+type serverConfig struct {
+	maxBatch    int
+	ordered     bool          // /v1/new mints ordered KUIDs and readiness checks the clock
+	maxClockLag time.Duration // largest tolerated Generator.ClockLag
+}
+
+// server is the HTTP API of kuid serve
+type server struct {
+	cfg      serverConfig
+	gen      *kuid.Generator
+	now      func() time.Time
+	draining atomic.Bool
+	mux      *http.ServeMux
+}
+
+func newServer(gen *kuid.Generator, cfg serverConfig) *server {
+	s := &server{cfg: cfg, gen: gen, now: time.Now, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /v1/validate", s.batch(validateID))
+	s.mux.HandleFunc("POST /v1/convert", s.batch(convertID))
+	s.mux.HandleFunc("POST /v1/new", s.mint)
+	s.mux.HandleFunc("GET /healthz", s.healthz)
+	s.mux.HandleFunc("GET /readyz", s.readyz)
+	return s
+}
+
+// probe is the response body of /healthz and /readyz
+type probe struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// healthz reports whether the node can mint IDs at all. It draws fresh
+// entropy rather than trusting past reads.
+func (s *server) healthz(w http.ResponseWriter, r *http.Request) {
+	s.writeProbe(w, map[string]string{"entropy": s.checkEntropy()})
+}
+
+// readyz reports whether the node should receive traffic: it must pass
+// healthz, not be shutting down and, in ordered mode, have a sane clock
+func (s *server) readyz(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{"entropy": s.checkEntropy(), "shutdown": "ok"}
+	if s.draining.Load() {
+		checks["shutdown"] = "draining"
+	}
+	if s.cfg.ordered {
+		checks["clock"] = s.checkClock()
+	}
+	s.writeProbe(w, checks)
+}
+
+func (s *server) checkEntropy() string {
+	if _, err := s.gen.NewValue(); err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+func (s *server) checkClock() string {
+	if now := s.now(); now.Before(clockFloor) {
+		return fmt.Sprintf("clock reads %s, before %s", now.UTC().Format(time.RFC3339), clockFloor.Format(time.DateOnly))
+	}
+	if lag := s.gen.ClockLag(); lag > s.cfg.maxClockLag {
+		return fmt.Sprintf("ordered timestamps are %v ahead of the clock", lag)
+	}
+	return "ok"
+}
+
+func (s *server) writeProbe(w http.ResponseWriter, checks map[string]string) {
+	p := probe{Status: "ok", Checks: checks}
+	status := http.StatusOK
+	for _, result := range checks {
+		if result != "ok" {
+			p.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, p)
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// mint serves /v1/new, minting ?count= KUIDs, one by default. In ordered
+// mode it refuses while the clock check fails, as /readyz does, so a node
+// whose clock stepped back never hands out IDs that sort into the past.
+func (s *server) mint(w http.ResponseWriter, r *http.Request) {
+	count := 1
+	if q := r.URL.Query().Get("count"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "count must be a positive integer")
+			return
+		}
+		count = n
+	}
+	if count > s.cfg.maxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("batches are limited to %d IDs", s.cfg.maxBatch))
+		return
+	}
+
+	newID := s.gen.New
+	if s.cfg.ordered {
+		if clock := s.checkClock(); clock != "ok" {
+			writeError(w, http.StatusServiceUnavailable, clock)
+			return
+		}
+		newID = s.gen.NewOrdered
+	}
+	ids := make([]string, count)
+	for i := range ids {
+		k, err := newID()
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		ids[i] = k.String()
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string][]string{"ids": ids})
+}
+
+// validation is one item of a /v1/validate response
+type validation struct {
+	Input  string `json:"input"`
+	Valid  bool   `json:"valid"`
+	Format string `json:"format,omitempty"`
+	KUID   string `json:"kuid,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func validateID(input string) any {
+	k, err := kuid.Parse(input)
+	if err != nil {
+		return validation{Input: input, Error: errors.Unwrap(err).Error()}
+	}
+	format := "kuid"
+	if len(input) == 36 {
+		format = "uuid"
+	}
+	return validation{Input: input, Valid: true, Format: format, KUID: k.String()}
+}
+
+// conversion is one item of a /v1/convert response
+type conversion struct {
+	Input  string `json:"input"`
+	KUID   string `json:"kuid,omitempty"`
+	UUID   string `json:"uuid,omitempty"`
+	Hex    string `json:"hex,omitempty"`
+	Base58 string `json:"base58,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func convertID(input string) any {
+	k, err := kuid.Parse(input)
+	if err != nil {
+		return conversion{Input: input, Error: errors.Unwrap(err).Error()}
+	}
+	return conversion{
+		Input:  input,
+		KUID:   k.String(),
+		UUID:   k.ToUUID(),
+		Hex:    formats["hex"](k),
+		Base58: k.Base58(),
+	}
+}
+
+// batch serves an endpoint that takes a JSON array of IDs and answers with
+// one result per ID, in order. A bad ID fails only its own item.
+func (s *server) batch(fn func(string) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(s.cfg.maxBatch)*maxIDBytes+2)
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("batches are limited to %d IDs", s.cfg.maxBatch))
+				return
+			}
+			writeError(w, http.StatusBadRequest, "body must be a JSON array of strings")
+			return
+		}
+		if len(ids) > s.cfg.maxBatch {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("batches are limited to %d IDs", s.cfg.maxBatch))
+			return
+		}
+
+		results := make([]any, len(ids))
+		for i, id := range ids {
+			results[i] = fn(id)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"results": results})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func runServe(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	var cfg serverConfig
+	fs.IntVar(&cfg.maxBatch, "max-batch", 10000, "most IDs accepted in one request")
+	fs.BoolVar(&cfg.ordered, "ordered", false, "mint ordered KUIDs from /v1/new and check clock sanity in /readyz")
+	fs.DurationVar(&cfg.maxClockLag, "max-clock-lag", time.Second, "with -ordered, how far ordered timestamps may run ahead of the clock")
+	drain := fs.Duration("drain", 0, "on shutdown, how long /readyz fails before connections are refused")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid serve [flags]")
+		fmt.Fprintln(stderr, "\nServes ID minting, validation and conversion over HTTP:")
+		fmt.Fprintln(stderr, "  POST /v1/new       ?count=N -> N new KUIDs, ordered with -ordered")
+		fmt.Fprintln(stderr, "  POST /v1/validate  JSON array of IDs -> per-ID validity")
+		fmt.Fprintln(stderr, "  POST /v1/convert   JSON array of IDs -> per-ID KUID, UUID, hex and base58")
+		fmt.Fprintln(stderr, "  GET  /healthz      entropy check")
+		fmt.Fprintln(stderr, "  GET  /readyz       entropy, shutdown and, with -ordered, clock checks")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if cfg.maxBatch < 1 {
+		return errors.New("max-batch must be positive")
+	}
+
+	gen, err := kuid.NewGenerator()
+	if err != nil {
+		return err
+	}
+	s := newServer(gen, cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	fmt.Fprintf(stderr, "kuid serve: listening on %s\n", *addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	// Fail readiness first so load balancers stop routing here
+	s.draining.Store(true)
+	time.Sleep(*drain)
+	shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdown)
+}
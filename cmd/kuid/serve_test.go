@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+func testServer(maxBatch int) *server {
+	g, _ := kuid.NewGenerator()
+	return newServer(g, serverConfig{maxBatch: maxBatch})
+}
+
+func TestServeValidate(t *testing.T) {
+	k, _ := kuid.NewKUID()
+	body := `["` + k.String() + `", "` + k.ToUUID() + `", "nope"]`
+
+	rec := httptest.NewRecorder()
+	testServer(10).ServeHTTP(rec, httptest.NewRequest("POST", "/v1/validate", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	var resp struct{ Results []validation }
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := []validation{
+		{Input: k.String(), Valid: true, Format: "kuid", KUID: k.String()},
+		{Input: k.ToUUID(), Valid: true, Format: "uuid", KUID: k.String()},
+		{Input: "nope", Error: kuid.ErrInvalidLength.Error()},
+	}
+	if len(resp.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(resp.Results), len(want))
+	}
+	for i := range want {
+		if resp.Results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, resp.Results[i], want[i])
+		}
+	}
+}
+
+func TestServeConvert(t *testing.T) {
+	k, _ := kuid.NewKUID()
+	rec := httptest.NewRecorder()
+	testServer(10).ServeHTTP(rec, httptest.NewRequest("POST", "/v1/convert", strings.NewReader(`["`+k.ToUUID()+`", ""]`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	var resp struct{ Results []conversion }
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got := resp.Results[0]
+	if got.KUID != k.String() || got.Base58 != k.Base58() || len(got.Hex) != 32 || got.Error != "" {
+		t.Errorf("result = %+v", got)
+	}
+	if resp.Results[1].Error == "" || resp.Results[1].KUID != "" {
+		t.Errorf("empty input result = %+v, want error", resp.Results[1])
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"not an array", "POST", "/v1/validate", `{"ids": []}`, http.StatusBadRequest},
+		{"not strings", "POST", "/v1/convert", `[1, 2]`, http.StatusBadRequest},
+		{"too many", "POST", "/v1/validate", `["a", "b", "c"]`, http.StatusRequestEntityTooLarge},
+		{"too large", "POST", "/v1/validate", `["` + strings.Repeat("a", 2000) + `"]`, http.StatusRequestEntityTooLarge},
+		{"wrong method", "GET", "/v1/validate", "", http.StatusMethodNotAllowed},
+		{"unknown path", "POST", "/v1/nope", "[]", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			testServer(2).ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeProbes(t *testing.T) {
+	clock := kuid.NewManualClock(time.Now())
+	g, _ := kuid.NewGenerator(kuid.WithClock(clock))
+	s := newServer(g, serverConfig{maxBatch: 1, ordered: true, maxClockLag: time.Second})
+
+	probe := func(path string) (int, probe) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		var p probe
+		if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, p
+	}
+
+	if code, p := probe("/readyz"); code != http.StatusOK || p.Checks["clock"] != "ok" {
+		t.Errorf("/readyz = %d %+v, want ready", code, p)
+	}
+
+	// Ordered timestamps minted by /v1/new running ahead of a clock that
+	// stepped back
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/v1/new", nil))
+	clock.Advance(-time.Minute)
+	if code, p := probe("/readyz"); code != http.StatusServiceUnavailable || p.Checks["clock"] == "ok" {
+		t.Errorf("/readyz with lagging clock = %d %+v, want unavailable", code, p)
+	}
+	clock.Advance(time.Minute)
+
+	s.now = func() time.Time { return time.Unix(0, 0) }
+	if code, _ := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz with clock at 1970 = %d, want unavailable", code)
+	}
+	s.now = time.Now
+
+	s.draining.Store(true)
+	if code, p := probe("/readyz"); code != http.StatusServiceUnavailable || p.Checks["shutdown"] != "draining" {
+		t.Errorf("/readyz while draining = %d %+v, want unavailable", code, p)
+	}
+	if code, _ := probe("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz while draining = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestServeNew(t *testing.T) {
+	clock := kuid.NewManualClock(time.Now())
+	g, _ := kuid.NewGenerator(kuid.WithClock(clock))
+	s := newServer(g, serverConfig{maxBatch: 10, ordered: true, maxClockLag: time.Second})
+
+	mint := func(query string) (int, []string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest("POST", "/v1/new"+query, nil))
+		var resp struct{ IDs []string }
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp.IDs
+	}
+
+	code, ids := mint("?count=10")
+	if code != http.StatusOK || len(ids) != 10 {
+		t.Fatalf("/v1/new?count=10 = %d with %d IDs", code, len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Errorf("ordered IDs %s then %s", ids[i-1], ids[i])
+		}
+	}
+	if code, ids := mint(""); code != http.StatusOK || len(ids) != 1 {
+		t.Errorf("/v1/new = %d with %d IDs, want one", code, len(ids))
+	}
+	for _, q := range []string{"?count=0", "?count=x"} {
+		if code, _ := mint(q); code != http.StatusBadRequest {
+			t.Errorf("/v1/new%s = %d, want %d", q, code, http.StatusBadRequest)
+		}
+	}
+	if code, _ := mint("?count=11"); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("/v1/new?count=11 = %d, want %d", code, http.StatusRequestEntityTooLarge)
+	}
+
+	// A clock that stepped back past the tolerance stops minting
+	clock.Advance(-time.Minute)
+	if code, _ := mint(""); code != http.StatusServiceUnavailable {
+		t.Errorf("/v1/new with lagging clock = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestServeHealthzCannotMint(t *testing.T) {
+	g, _ := kuid.NewGenerator(kuid.WithBlocklist(strings.Split("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", "")...), kuid.WithMaxRetries(0))
+	s := newServer(g, serverConfig{maxBatch: 1})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/healthz for a generator that cannot mint = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
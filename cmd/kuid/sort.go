@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/analysis"
+)
+
+// sortFlags are the flags shared by sort, uniq and join
+type sortFlags struct {
+	opts   analysis.SortOptions
+	format string
+}
+
+func (f *sortFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&f.opts.RunSize, "run-size", 0, "KUIDs sorted in memory before spilling to disk (default 4Mi)")
+	fs.StringVar(&f.opts.TempDir, "tmp", "", "directory for run files (default system temp dir)")
+	fs.StringVar(&f.format, "format", "kuid", "output format: kuid, uuid, hex or base58")
+}
+
+// open sorts the named input and returns its stream with the output
+// formatter
+func (f *sortFlags) open(name string, stdin io.Reader) (*analysis.SortedStream, func(*kuid.KUID) string, error) {
+	render, ok := formats[f.format]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown format %q", f.format)
+	}
+	in, err := openInput(name, stdin)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer in.Close()
+	s, err := analysis.Sorted(in, f.opts)
+	return s, render, err
+}
+
+// reportInvalid notes skipped lines on stderr
+func reportInvalid(stderr io.Writer, name string, s *analysis.SortedStream) {
+	if n := s.Stats().Invalid; n > 0 {
+		if name == "" {
+			name = "stdin"
+		}
+		fmt.Fprintf(stderr, "%s: skipped %d invalid lines\n", name, n)
+	}
+}
+
+func runSort(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("sort", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var sf sortFlags
+	sf.register(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid sort [flags] [file]")
+		fmt.Fprintln(stderr, "\nSorts KUIDs or UUIDs, one per line, by their binary value. Inputs larger than")
+		fmt.Fprintln(stderr, "memory are merge sorted through temporary files.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+
+	s, render, err := sf.open(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	w := bufio.NewWriter(stdout)
+	for s.Next() {
+		id := s.ID()
+		fmt.Fprintln(w, render(&id))
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	reportInvalid(stderr, fs.Arg(0), s)
+	return w.Flush()
+}
+
+func runUniq(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("uniq", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var sf sortFlags
+	sf.register(fs)
+	count := fs.Bool("c", false, "prefix each ID with its number of occurrences")
+	repeated := fs.Bool("d", false, "only print IDs that occur more than once")
+	unique := fs.Bool("u", false, "only print IDs that occur exactly once")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid uniq [flags] [file]")
+		fmt.Fprintln(stderr, "\nPrints each distinct KUID in binary order. Input need not be sorted, and the")
+		fmt.Fprintln(stderr, "same ID written as a KUID and as a UUID counts once.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+	if *repeated && *unique {
+		return errors.New("-d and -u are mutually exclusive")
+	}
+
+	s, render, err := sf.open(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	w := bufio.NewWriter(stdout)
+	emit := func(id kuid.KUID, n int) {
+		if (*repeated && n < 2) || (*unique && n > 1) {
+			return
+		}
+		if *count {
+			fmt.Fprintf(w, "%d ", n)
+		}
+		fmt.Fprintln(w, render(&id))
+	}
+
+	var cur kuid.KUID
+	n := 0
+	for s.Next() {
+		if id := s.ID(); n == 0 || id != cur {
+			if n > 0 {
+				emit(cur, n)
+			}
+			cur, n = id, 0
+		}
+		n++
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if n > 0 {
+		emit(cur, n)
+	}
+	reportInvalid(stderr, fs.Arg(0), s)
+	return w.Flush()
+}
+
+func runJoin(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("join", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var sf sortFlags
+	sf.register(fs)
+	only := fs.Int("v", 0, "print IDs found only in file 1 or only in file 2 instead")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid join [flags] file1 file2")
+		fmt.Fprintln(stderr, "\nPrints, in binary order, each distinct KUID present in both files. One")
+		fmt.Fprintln(stderr, "file may be \"-\" for stdin, and neither need be sorted.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("want two files")
+	}
+	if *only != 0 && *only != 1 && *only != 2 {
+		return fmt.Errorf("-v must be 1 or 2, not %d", *only)
+	}
+	// The second side would find stdin already consumed by the first
+	if fs.Arg(0) == "-" && fs.Arg(1) == "-" {
+		return errors.New("only one file may be \"-\"")
+	}
+
+	left, render, err := sf.open(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer left.Close()
+	right, _, err := sf.open(fs.Arg(1), stdin)
+	if err != nil {
+		return err
+	}
+	defer right.Close()
+
+	w := bufio.NewWriter(stdout)
+	emit := func(id kuid.KUID, side int) {
+		if side == *only {
+			fmt.Fprintln(w, render(&id))
+		}
+	}
+
+	// Merge join over the two sorted streams, skipping repeats on each side
+	var last kuid.KUID
+	started := false
+	lok, rok := left.Next(), right.Next()
+	for lok || rok {
+		var id kuid.KUID
+		side := 0
+		switch {
+		case !rok:
+			id, side = left.ID(), 1
+		case !lok:
+			id, side = right.ID(), 2
+		default:
+			l, r := left.ID(), right.ID()
+			switch c := bytes.Compare(l.Bytes(), r.Bytes()); {
+			case c < 0:
+				id, side = l, 1
+			case c > 0:
+				id, side = r, 2
+			default:
+				id = l
+			}
+		}
+		if !started || id != last {
+			emit(id, side)
+			last, started = id, true
+		}
+		if side != 2 {
+			lok = left.Next()
+		}
+		if side != 1 {
+			rok = right.Next()
+		}
+	}
+	if err := errors.Join(left.Err(), right.Err()); err != nil {
+		return err
+	}
+	reportInvalid(stderr, fs.Arg(0), left)
+	reportInvalid(stderr, fs.Arg(1), right)
+	return w.Flush()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+// fromHex returns the KUID with the given 32 hex digit value
+func fromHex(t *testing.T, s string) *kuid.KUID {
+	t.Helper()
+	k, err := kuid.Parse(s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestSort(t *testing.T) {
+	// As strings, the UUID of b sorts first; in binary, a does
+	a := fromHex(t, "10000000000000000000000000000000")
+	b := fromHex(t, "f0000000000000000000000000000000")
+	c := fromHex(t, "20000000000000000000000000000000")
+	input := strings.Join([]string{b.ToUUID(), a.String(), c.String(), "bad", a.ToUUID()}, "\n")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"sort", "-run-size", "2", "-tmp", t.TempDir(), "-format", "uuid"}
+	if code := run(args, strings.NewReader(input), &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d; stderr: %s", code, stderr.String())
+	}
+	want := strings.Join([]string{a.ToUUID(), a.ToUUID(), c.ToUUID(), b.ToUUID()}, "\n") + "\n"
+	if stdout.String() != want {
+		t.Errorf("output = %q, want %q", stdout.String(), want)
+	}
+	if !strings.Contains(stderr.String(), "skipped 1 invalid") {
+		t.Errorf("stderr = %q, want invalid line report", stderr.String())
+	}
+}
+
+func TestUniq(t *testing.T) {
+	a := fromHex(t, "10000000000000000000000000000000")
+	b := fromHex(t, "20000000000000000000000000000000")
+	input := strings.Join([]string{b.String(), a.String(), a.ToUUID(), b.String(), b.String(), a.String()}, "\n")
+	c := fromHex(t, "30000000000000000000000000000000")
+	input += "\n" + c.String()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{a.String(), b.String(), c.String()}},
+		{[]string{"-c"}, []string{"3 " + a.String(), "3 " + b.String(), "1 " + c.String()}},
+		{[]string{"-d"}, []string{a.String(), b.String()}},
+		{[]string{"-u"}, []string{c.String()}},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(append([]string{"uniq"}, tt.args...), strings.NewReader(input), &stdout, &stderr); code != 0 {
+			t.Fatalf("uniq %v exit code = %d; stderr: %s", tt.args, code, stderr.String())
+		}
+		if got := strings.Split(strings.TrimSpace(stdout.String()), "\n"); !slices.Equal(got, tt.want) {
+			t.Errorf("uniq %v = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	ids := make([]*kuid.KUID, 5)
+	for i := range ids {
+		ids[i] = fromHex(t, strings.Repeat(string(rune('1'+i)), 32))
+	}
+	dir := t.TempDir()
+	file1 := filepath.Join(dir, "1.txt")
+	file2 := filepath.Join(dir, "2.txt")
+	// file1 holds 0-3, file2 holds 2-4, with repeats and mixed formats
+	os.WriteFile(file1, []byte(strings.Join([]string{ids[3].String(), ids[0].String(), ids[2].ToUUID(), ids[1].String(), ids[3].String()}, "\n")), 0o644)
+	os.WriteFile(file2, []byte(strings.Join([]string{ids[4].String(), ids[2].String(), ids[3].ToUUID(), ids[2].String()}, "\n")), 0o644)
+
+	tests := []struct {
+		args []string
+		want []*kuid.KUID
+	}{
+		{nil, []*kuid.KUID{ids[2], ids[3]}},
+		{[]string{"-v", "1"}, []*kuid.KUID{ids[0], ids[1]}},
+		{[]string{"-v", "2"}, []*kuid.KUID{ids[4]}},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		args := append(append([]string{"join"}, tt.args...), file1, file2)
+		if code := run(args, nil, &stdout, &stderr); code != 0 {
+			t.Fatalf("join %v exit code = %d; stderr: %s", tt.args, code, stderr.String())
+		}
+		var want []string
+		for _, k := range tt.want {
+			want = append(want, k.String())
+		}
+		if got := strings.Fields(stdout.String()); !slices.Equal(got, want) {
+			t.Errorf("join %v = %q, want %q", tt.args, got, want)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"join", file1}, nil, &stdout, &stderr); code != 1 {
+		t.Errorf("join with one file exit code = %d, want 1", code)
+	}
+	stderr.Reset()
+	if code := run([]string{"join", "-", "-"}, strings.NewReader(ids[0].String()), &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), `only one file may be "-"`) {
+		t.Errorf("join - - exit code = %d, stderr %q; want 1 and an error", code, stderr.String())
+	}
+}
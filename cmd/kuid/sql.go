@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/alphabatem/kuid/sqlgen"
+)
+
+func runSQL(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("sql", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dialect := fs.String("dialect", "postgres", "SQL dialect: postgres or mysql")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kuid sql [-dialect d]")
+		fmt.Fprintln(stderr, "\nPrints SQL creating functions that convert between KUIDs and UUIDs")
+		fmt.Fprintln(stderr, "exactly as this package does. PostgreSQL also gets kuid_generate().")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+	d, err := sqlgen.ParseDialect(*dialect)
+	if err != nil {
+		return err
+	}
+
+	stmts := sqlgen.Functions(d)
+	if len(stmts) == 0 {
+		return fmt.Errorf("%s has no stored functions", d)
+	}
+	m := sqlgen.Migration{Up: stmts}
+	_, err = io.WriteString(stdout, m.UpScript())
+	return err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{"postgres", []string{"--dialect", "postgres"}, 0, []string{
+			"FUNCTION uuid_to_kuid(id uuid)", "FUNCTION kuid_to_uuid(id text)", "FUNCTION kuid_generate()",
+		}},
+		{"mysql", []string{"-dialect", "mysql"}, 0, []string{"FUNCTION uuid_to_kuid(id BINARY(16))"}},
+		{"sqlite", []string{"-dialect", "sqlite"}, 1, nil},
+		{"arguments", []string{"extra"}, 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"sql"}, tt.args...), nil, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output has no %q", want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Package codes generates short, human-typable codes for coupons, invites
+// and similar redemption flows.
+//
+// Codes are 8 to 12 characters of Crockford base32, which leaves out I, L,
+// O and U, with a Luhn mod 32 check character at the end. The check
+// character catches every single mistyped character and most swapped
+// neighbours, so typos are rejected before any storage lookup. Normalize
+// folds the usual misreadings back: lowercase to uppercase, I and L to 1,
+// O to 0, and drops hyphens and spaces.
+//
+// The payload is taken from the top bits of a 64-bit value: a SHA-256 hash
+// of a whole KUID for New and FromKUID, so fixed region, tenant, type and
+// version bits do not leak into codes, or any integer for FromUint64. A
+// 10-character code carries 45 bits, so codes must be stored and looked
+// up, never decoded.
+package codes
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+)
+
+const (
+	alphabet      = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	minLength     = 8
+	maxLength     = 12
+	defaultLength = 10
+	maxRetries    = 100
+)
+
+var (
+	ErrInvalidCode = errors.New("invalid code")
+	ErrChecksum    = errors.New("code checksum mismatch")
+	ErrExhausted   = errors.New("no unused code found")
+)
+
+// DefaultBlocklist holds words that are never spelled by a code, in
+// normalized form. It is used when Options.Blocklist is nil.
+var DefaultBlocklist = []string{
+	"ASS", "CNT", "DCK", "FAG", "FCK", "FVCK", "KKK", "NGR", "NAZ", "SEX", "SHT", "XXX",
+}
+
+// Options configures a Generator
+type Options struct {
+	// Length is the number of characters including the check character,
+	// between 8 and 12; 10 if zero
+	Length int
+	// GroupSize splits codes into hyphenated groups in Format, such as
+	// "7K3M-9QXT-2B" for 4; 0 keeps them whole
+	GroupSize int
+	// Blocklist lists words codes must not contain; DefaultBlocklist if nil
+	Blocklist []string
+	// Generator supplies random bits; a default Generator if nil
+	Generator *kuid.Generator
+}
+
+// Generator mints codes
+type Generator struct {
+	length    int
+	groupSize int
+	blocklist []string
+	gen       *kuid.Generator
+}
+
+// NewGenerator creates a Generator
+func NewGenerator(opts Options) (*Generator, error) {
+	if opts.Length == 0 {
+		opts.Length = defaultLength
+	}
+	if opts.Length < minLength || opts.Length > maxLength {
+		return nil, errors.New("code length must be between 8 and 12")
+	}
+	if opts.GroupSize < 0 {
+		return nil, errors.New("group size must not be negative")
+	}
+	if opts.Blocklist == nil {
+		opts.Blocklist = DefaultBlocklist
+	}
+	if opts.Generator == nil {
+		var err error
+		if opts.Generator, err = kuid.NewGenerator(); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &Generator{length: opts.Length, groupSize: opts.GroupSize, gen: opts.Generator}
+	for _, w := range opts.Blocklist {
+		if w = Normalize(w); w != "" {
+			g.blocklist = append(g.blocklist, w)
+		}
+	}
+	return g, nil
+}
+
+// New returns a random code free of blocked words
+func (g *Generator) New() (string, error) {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		k, err := g.gen.NewValue()
+		if err != nil {
+			return "", err
+		}
+		if code, err := g.FromKUID(&k); err != kuid.ErrBlocked {
+			return code, err
+		}
+	}
+	return "", kuid.ErrBlocked
+}
+
+// FromKUID derives the code for k from a SHA-256 hash of all 16 bytes,
+// since bits such as region, tenant or the UUID variant are fixed in many
+// layouts. It returns kuid.ErrBlocked if the code would contain a blocked
+// word.
+func (g *Generator) FromKUID(k *kuid.KUID) (string, error) {
+	sum := sha256.Sum256(k.Bytes())
+	return g.FromUint64(binary.BigEndian.Uint64(sum[:8]))
+}
+
+// FromUint64 derives the code for n from its top bits. It returns
+// kuid.ErrBlocked if the code would contain a blocked word.
+func (g *Generator) FromUint64(n uint64) (string, error) {
+	code := make([]byte, g.length)
+	for i := 0; i < g.length-1; i++ {
+		code[i] = alphabet[n>>59]
+		n <<= 5
+	}
+	code[g.length-1] = alphabet[checksum(code[:g.length-1])]
+	if g.blocked(string(code)) {
+		return "", kuid.ErrBlocked
+	}
+	return string(code), nil
+}
+
+// Batch returns n distinct codes. exists, if set, reports codes already
+// issued, such as by a database lookup, so they are skipped as well.
+func (g *Generator) Batch(n int, exists func(code string) (bool, error)) ([]string, error) {
+	codes := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+	for len(codes) < n {
+		var code string
+		for attempt := 0; ; attempt++ {
+			if attempt > maxRetries {
+				return nil, ErrExhausted
+			}
+			var err error
+			if code, err = g.New(); err != nil {
+				return nil, err
+			}
+			if seen[code] {
+				continue
+			}
+			if exists != nil {
+				used, err := exists(code)
+				if err != nil {
+					return nil, err
+				}
+				if used {
+					continue
+				}
+			}
+			break
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// Format splits code into hyphenated groups for display
+func (g *Generator) Format(code string) string {
+	if g.groupSize == 0 {
+		return code
+	}
+	var b strings.Builder
+	for i := 0; i < len(code); i += g.groupSize {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(code[i:min(i+g.groupSize, len(code))])
+	}
+	return b.String()
+}
+
+// Validate normalizes input and checks its length and check character,
+// returning the code to look up
+func (g *Generator) Validate(input string) (string, error) {
+	code := Normalize(input)
+	if len(code) != g.length {
+		return "", ErrInvalidCode
+	}
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(alphabet, code[i]) < 0 {
+			return "", ErrInvalidCode
+		}
+	}
+	if alphabet[checksum([]byte(code[:len(code)-1]))] != code[len(code)-1] {
+		return "", ErrChecksum
+	}
+	return code, nil
+}
+
+// Normalize uppercases s, maps I and L to 1 and O to 0, and drops hyphens
+// and whitespace
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToUpper(s) {
+		switch r {
+		case '-', ' ', '\t', '\n', '\r':
+		case 'I', 'L':
+			b.WriteByte('1')
+		case 'O':
+			b.WriteByte('0')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// checksum returns the Luhn mod 32 check value of payload
+func checksum(payload []byte) int {
+	const n = len(alphabet)
+	sum, factor := 0, 2
+	for i := len(payload) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(alphabet, payload[i])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return (n - sum%n) % n
+}
+
+// blocked reports whether code contains a blocked word
+func (g *Generator) blocked(code string) bool {
+	for _, w := range g.blocklist {
+		if strings.Contains(code, w) {
+			return true
+		}
+	}
+	return false
+}
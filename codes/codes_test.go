@@ -0,0 +1,162 @@
+package codes
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestGenerator(t *testing.T) {
+	for length := minLength; length <= maxLength; length++ {
+		g, err := NewGenerator(Options{Length: length})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			code, err := g.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(code) != length || strings.Trim(code, alphabet) != "" {
+				t.Errorf("New() = %q", code)
+			}
+			if got, err := g.Validate(code); err != nil || got != code {
+				t.Errorf("Validate(%q) = %q, %v", code, got, err)
+			}
+		}
+	}
+
+	for _, opts := range []Options{{Length: 7}, {Length: 13}, {GroupSize: -1}} {
+		if _, err := NewGenerator(opts); err == nil {
+			t.Errorf("NewGenerator(%+v) expected error", opts)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g, _ := NewGenerator(Options{GroupSize: 4})
+	code, _ := g.FromUint64(0x0123456789abcdef)
+	formatted := g.Format(code)
+	if formatted != code[:4]+"-"+code[4:8]+"-"+code[8:] {
+		t.Errorf("Format(%q) = %q", code, formatted)
+	}
+
+	// Typed back sloppily
+	sloppy := strings.ToLower(formatted)
+	sloppy = strings.ReplaceAll(sloppy, "0", "o")
+	sloppy = strings.ReplaceAll(sloppy, "1", "l")
+	if got, err := g.Validate(" " + sloppy + " "); err != nil || got != code {
+		t.Errorf("Validate(%q) = %q, %v, want %q", sloppy, got, err, code)
+	}
+
+	// Every single-character typo is caught
+	for i := 0; i < len(code); i++ {
+		for j := 0; j < len(alphabet); j++ {
+			if alphabet[j] == code[i] {
+				continue
+			}
+			typo := code[:i] + string(alphabet[j]) + code[i+1:]
+			if _, err := g.Validate(typo); err != ErrChecksum {
+				t.Fatalf("Validate(%q) error = %v, want %v", typo, err, ErrChecksum)
+			}
+		}
+	}
+
+	for _, s := range []string{"", code[:9], code + "0", code[:9] + "U"} {
+		if _, err := g.Validate(s); err != ErrInvalidCode {
+			t.Errorf("Validate(%q) error = %v, want %v", s, err, ErrInvalidCode)
+		}
+	}
+}
+
+func TestFromKUID(t *testing.T) {
+	g, _ := NewGenerator(Options{Blocklist: []string{}})
+	k, _ := kuid.NewKUID()
+	a, err := g.FromKUID(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := g.FromKUID(k); a != b {
+		t.Errorf("FromKUID() not deterministic")
+	}
+	sum := sha256.Sum256(k.Bytes())
+	if b, _ := g.FromUint64(binary.BigEndian.Uint64(sum[:8])); a != b {
+		t.Errorf("FromKUID() = %q, want code of the KUID's hash %q", a, b)
+	}
+
+	// V4 KUIDs from a regional generator share their variant and region
+	// bits, which must not give their codes a common prefix
+	regional, _ := kuid.NewGenerator(kuid.WithRegion(6, 5))
+	prefixes := make(map[byte]bool)
+	for i := 0; i < 200; i++ {
+		k, _ := regional.NewV4()
+		code, _ := g.FromKUID(k)
+		prefixes[code[0]] = true
+	}
+	if len(prefixes) < 16 {
+		t.Errorf("200 regional V4 codes used %d first characters", len(prefixes))
+	}
+}
+
+func TestBlocklist(t *testing.T) {
+	g, _ := NewGenerator(Options{Length: 8, Blocklist: []string{"0"}})
+	// All-zero payload spells the blocked word
+	if _, err := g.FromUint64(0); err != kuid.ErrBlocked {
+		t.Errorf("FromUint64(0) error = %v, want %v", err, kuid.ErrBlocked)
+	}
+	for i := 0; i < 100; i++ {
+		code, err := g.New()
+		if err != nil && err != kuid.ErrBlocked {
+			t.Fatal(err)
+		}
+		if strings.Contains(code, "0") {
+			t.Errorf("New() = %q contains blocked word", code)
+		}
+	}
+
+	// Blocked words are normalized, so misreadings are caught too
+	g, _ = NewGenerator(Options{Blocklist: []string{"sh-it"}})
+	if g.blocklist[0] != "SH1T" {
+		t.Errorf("blocklist = %q", g.blocklist)
+	}
+	if d, _ := NewGenerator(Options{}); len(d.blocklist) != len(DefaultBlocklist) {
+		t.Errorf("DefaultBlocklist not applied")
+	}
+}
+
+func TestBatch(t *testing.T) {
+	g, _ := NewGenerator(Options{})
+	issued := map[string]bool{}
+	first, _ := g.New()
+	issued[first] = true
+
+	codes, err := g.Batch(500, func(code string) (bool, error) {
+		return issued[code], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, c := range codes {
+		if seen[c] || issued[c] {
+			t.Errorf("Batch() repeated %q", c)
+		}
+		seen[c] = true
+	}
+	if len(codes) != 500 {
+		t.Errorf("Batch() returned %d codes", len(codes))
+	}
+
+	// Every code taken
+	if _, err := g.Batch(1, func(string) (bool, error) { return true, nil }); err != ErrExhausted {
+		t.Errorf("Batch() error = %v, want %v", err, ErrExhausted)
+	}
+	lookup := errors.New("db down")
+	if _, err := g.Batch(1, func(string) (bool, error) { return false, lookup }); err != lookup {
+		t.Errorf("Batch() error = %v, want %v", err, lookup)
+	}
+}
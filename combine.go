@@ -0,0 +1,50 @@
+package kuid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// CombineMode selects whether the order of the combined KUIDs matters
+type CombineMode uint8
+
+const (
+	// Ordered combines a with b to a different KUID than b with a, for
+	// directed relations such as "follows"
+	Ordered CombineMode = iota
+	// Commutative combines a with b to the same KUID as b with a, for
+	// symmetric relations such as "friends with"
+	Commutative
+)
+
+// combiner backs the package-level Combine
+var combiner = &Deriver{key: []byte("kuid combine")}
+
+// Combine derives a KUID from a pair, such as a graph edge from its
+// endpoints, without storing a mapping. The same pair and mode always
+// yield the same KUID, and the two modes never yield the same KUID for any
+// pair. Like DeriveChild it uses a fixed public key; use Deriver.Combine
+// to keep combined IDs unguessable.
+func Combine(a, b KUID, mode CombineMode) KUID {
+	return combiner.Combine(a, b, mode)
+}
+
+// Combine derives a KUID from a pair under d's key
+func (d *Deriver) Combine(a, b KUID, mode CombineMode) KUID {
+	if mode == Commutative && (a.msb > b.msb || a.msb == b.msb && a.lsb > b.lsb) {
+		a, b = b, a
+	}
+
+	var buf [33]byte
+	binary.BigEndian.PutUint64(buf[0:8], a.msb)
+	binary.BigEndian.PutUint64(buf[8:16], a.lsb)
+	binary.BigEndian.PutUint64(buf[16:24], b.msb)
+	binary.BigEndian.PutUint64(buf[24:32], b.lsb)
+	buf[32] = byte(mode)
+
+	mac := hmac.New(sha256.New, d.key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+	return KUID{msb: binary.BigEndian.Uint64(sum[0:8]), lsb: binary.BigEndian.Uint64(sum[8:16])}
+}
@@ -0,0 +1,52 @@
+package kuid
+
+import "testing"
+
+func TestCombine(t *testing.T) {
+	a, _ := NewValue()
+	b, _ := NewValue()
+
+	tests := []struct {
+		name string
+		mode CombineMode
+		swap bool // whether swapping a and b keeps the result
+	}{
+		{name: "Ordered", mode: Ordered, swap: false},
+		{name: "Commutative", mode: Commutative, swap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ab := Combine(a, b, tt.mode)
+			if again := Combine(a, b, tt.mode); again != ab {
+				t.Errorf("Combine() not deterministic")
+			}
+			if ba := Combine(b, a, tt.mode); (ba == ab) != tt.swap {
+				t.Errorf("Combine(b, a) == Combine(a, b) is %v, want %v", ba == ab, tt.swap)
+			}
+			if ab == a || ab == b {
+				t.Errorf("Combine() returned an input")
+			}
+		})
+	}
+
+	if Combine(a, b, Ordered) == Combine(a, b, Commutative) || Combine(b, a, Ordered) == Combine(a, b, Commutative) {
+		t.Errorf("modes collide")
+	}
+	if Combine(a, a, Ordered) == Combine(a, a, Commutative) {
+		t.Errorf("modes collide for a self-pair")
+	}
+}
+
+func TestDeriverCombine(t *testing.T) {
+	d, _ := NewDeriver([]byte("0123456789abcdef"))
+	a, _ := NewValue()
+	b, _ := NewValue()
+
+	if d.Combine(a, b, Commutative) != d.Combine(b, a, Commutative) {
+		t.Errorf("Deriver.Combine() not commutative")
+	}
+	if d.Combine(a, b, Ordered) == Combine(a, b, Ordered) {
+		t.Errorf("Deriver.Combine() ignores its key")
+	}
+}
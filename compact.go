@@ -0,0 +1,157 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	compactChars   = "0123456789abcdefghijklmnopqrstuv" // base32hex, sorts like the bytes
+	compactDigits  = 20
+	compactMachine = 40 // machine ID bits
+	compactCounter = 24 // counter bits
+)
+
+var ErrNotCompact = errors.New("KUID has no compact form")
+
+// compactValues maps each byte to its base32hex value, or 0xff
+var compactValues = func() (t [256]byte) {
+	for i := range t {
+		t[i] = 0xff
+	}
+	for i := 0; i < len(compactChars); i++ {
+		t[compactChars[i]] = byte(i)
+	}
+	return t
+}()
+
+// CompactID is a 96-bit ID in the style of xid and MongoDB ObjectIDs, for
+// high-volume records where 16 bytes per key matters:
+//
+//	32-bit Unix seconds | 40-bit machine | 24-bit counter
+//
+// Its string form is 20 lowercase base32hex characters, and both forms
+// sort by time.
+type CompactID [12]byte
+
+// CompactGenerator mints CompactIDs for one machine. Each machine needs its
+// own ID, such as a node ID leased from a Coordinator. The counter starts
+// at a random value and wraps after 2^24 IDs, so a machine must not mint
+// more than 16 million IDs per second.
+type CompactGenerator struct {
+	machine uint64
+	counter atomic.Uint32
+	now     func() time.Time
+}
+
+// NewCompactGenerator creates a CompactGenerator for a 40-bit machine ID
+func NewCompactGenerator(machine uint64) (*CompactGenerator, error) {
+	if machine>>compactMachine != 0 {
+		return nil, errors.New("machine ID must fit in 40 bits")
+	}
+	start, _, err := defaultGenerator.readWith(defaultGenerator.config())
+	if err != nil {
+		return nil, err
+	}
+	g := &CompactGenerator{machine: machine, now: time.Now}
+	g.counter.Store(uint32(start))
+	return g, nil
+}
+
+// New mints a CompactID
+func (g *CompactGenerator) New() CompactID {
+	var id CompactID
+	binary.BigEndian.PutUint32(id[0:4], uint32(g.now().Unix()))
+	binary.BigEndian.PutUint64(id[4:12], g.machine<<compactCounter|uint64(g.counter.Add(1)&(1<<compactCounter-1)))
+	return id
+}
+
+// Time returns the second the ID was minted in
+func (id CompactID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(id[0:4])), 0)
+}
+
+// Machine returns the ID of the machine that minted the ID
+func (id CompactID) Machine() uint64 {
+	return binary.BigEndian.Uint64(id[4:12]) >> compactCounter
+}
+
+// Counter returns the counter value of the ID
+func (id CompactID) Counter() uint32 {
+	return uint32(binary.BigEndian.Uint64(id[4:12]) & (1<<compactCounter - 1))
+}
+
+// String returns the 20-character base32hex form
+func (id CompactID) String() string {
+	var dst [compactDigits]byte
+	// 100 bits of output for 96 bits of input, leaving four zero bits at
+	// the top
+	hi, lo := uint64(binary.BigEndian.Uint32(id[0:4])), binary.BigEndian.Uint64(id[4:12])
+	for i := compactDigits - 1; i >= 0; i-- {
+		dst[i] = compactChars[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(dst[:])
+}
+
+// ParseCompact parses the string form of a CompactID
+func ParseCompact(s string) (CompactID, error) {
+	if len(s) != compactDigits {
+		return CompactID{}, ErrInvalidLength
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		digit := compactValues[s[i]]
+		if digit == 0xff {
+			return CompactID{}, ErrInvalidChar
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(digit)
+	}
+	if hi>>32 != 0 {
+		return CompactID{}, ErrOverflow
+	}
+	var id CompactID
+	binary.BigEndian.PutUint32(id[0:4], uint32(hi))
+	binary.BigEndian.PutUint64(id[4:12], lo)
+	return id, nil
+}
+
+// KUID returns the ID as an ordered KUID: the time as its timestamp, a zero
+// sequence, and the machine and counter as the lower half. It sorts by time
+// among KUIDs from NewOrdered.
+func (id CompactID) KUID() *KUID {
+	ms := int64(binary.BigEndian.Uint32(id[0:4])) * 1000
+	return &KUID{msb: uint64(ms) << sequenceBits, lsb: binary.BigEndian.Uint64(id[4:12])}
+}
+
+// CompactFromKUID reverses CompactID.KUID. Other KUIDs have a sub-second
+// timestamp or a sequence and return ErrNotCompact.
+func CompactFromKUID(k *KUID) (CompactID, error) {
+	ms := k.msb >> sequenceBits
+	if k.Sequence() != 0 || ms%1000 != 0 || ms/1000 > 1<<32-1 {
+		return CompactID{}, ErrNotCompact
+	}
+	var id CompactID
+	binary.BigEndian.PutUint32(id[0:4], uint32(ms/1000))
+	binary.BigEndian.PutUint64(id[4:12], k.lsb)
+	return id, nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (id CompactID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *CompactID) UnmarshalText(text []byte) error {
+	parsed, err := ParseCompact(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
@@ -0,0 +1,109 @@
+package kuid
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCompactGenerator(t *testing.T) {
+	if _, err := NewCompactGenerator(1 << 40); err == nil {
+		t.Errorf("NewCompactGenerator() accepted a 41-bit machine ID")
+	}
+
+	g, err := NewCompactGenerator(0xabcdef0123)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	g.now = func() time.Time { return now }
+
+	a, b := g.New(), g.New()
+	if a.Machine() != 0xabcdef0123 || !a.Time().Equal(now) {
+		t.Errorf("Machine() = %x, Time() = %v", a.Machine(), a.Time())
+	}
+	if b.Counter() != (a.Counter()+1)&(1<<24-1) {
+		t.Errorf("Counter() = %d after %d", b.Counter(), a.Counter())
+	}
+
+	// Counter wrap-around keeps the machine bits intact
+	g.counter.Store(1<<24 - 1)
+	if id := g.New(); id.Counter() != 0 || id.Machine() != 0xabcdef0123 {
+		t.Errorf("after wrap Counter() = %d, Machine() = %x", id.Counter(), id.Machine())
+	}
+}
+
+func TestCompactString(t *testing.T) {
+	g, _ := NewCompactGenerator(7)
+	var ids []CompactID
+	var strs []string
+	for i := 0; i < 50; i++ {
+		id := g.New()
+		ids = append(ids, id)
+		strs = append(strs, id.String())
+
+		s := id.String()
+		if len(s) != 20 {
+			t.Errorf("String() = %q, want 20 characters", s)
+		}
+		parsed, err := ParseCompact(s)
+		if err != nil || parsed != id {
+			t.Errorf("ParseCompact(%q) = %v, %v, want %v", s, parsed, err, id)
+		}
+	}
+	if !sort.StringsAreSorted(strs) {
+		t.Errorf("string forms do not sort")
+	}
+
+	var max CompactID
+	for i := range max {
+		max[i] = 0xff
+	}
+	if got := max.String(); got != "1vvvvvvvvvvvvvvvvvvv" {
+		t.Errorf("String() = %q for the largest ID", got)
+	}
+
+	for s, want := range map[string]error{
+		"2vvvvvvvvvvvvvvvvvvv":  ErrOverflow,
+		"0000000000000000000w":  ErrInvalidChar,
+		"0000000000000000000A":  ErrInvalidChar,
+		"000000000000000000000": ErrInvalidLength,
+	} {
+		if _, err := ParseCompact(s); err != want {
+			t.Errorf("ParseCompact(%q) error = %v, want %v", s, err, want)
+		}
+	}
+}
+
+func TestCompactKUID(t *testing.T) {
+	g, _ := NewCompactGenerator(42)
+	id := g.New()
+	k := id.KUID()
+	if !k.Timestamp().Equal(id.Time()) {
+		t.Errorf("KUID timestamp = %v, want %v", k.Timestamp(), id.Time())
+	}
+	back, err := CompactFromKUID(k)
+	if err != nil || back != id {
+		t.Errorf("CompactFromKUID() = %v, %v, want %v", back, err, id)
+	}
+
+	// A sequence or sub-second timestamp has no compact form
+	ordered := &KUID{msb: k.msb | 1, lsb: k.lsb}
+	if _, err := CompactFromKUID(ordered); err != ErrNotCompact {
+		t.Errorf("CompactFromKUID() with sequence error = %v, want %v", err, ErrNotCompact)
+	}
+	if _, err := CompactFromKUID(&KUID{msb: (k.msb>>16 + 1) << 16}); err != ErrNotCompact {
+		t.Errorf("CompactFromKUID() with milliseconds error = %v, want %v", err, ErrNotCompact)
+	}
+}
+
+func TestCompactJSON(t *testing.T) {
+	g, _ := NewCompactGenerator(1)
+	want := g.New()
+	data, _ := json.Marshal(want)
+	var got CompactID
+	if err := json.Unmarshal(data, &got); err != nil || got != want {
+		t.Errorf("round trip = %v, %v, want %v", got, err, want)
+	}
+}
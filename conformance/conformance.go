@@ -0,0 +1,161 @@
+// Package conformance verifies KUID encoders and decoders against the
+// cross-language test vectors in vectors.json. Ports to other languages
+// can read the same file; Go implementations, such as alternate encodings
+// of the same 128-bit values, can run Verify directly.
+package conformance
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/alphabatem/kuid"
+)
+
+//go:embed vectors.json
+var vectorsJSON []byte
+
+// Vectors is a set of test vectors
+type Vectors struct {
+	Version int             `json:"version"`
+	Valid   []ValidVector   `json:"valid"`
+	Invalid []InvalidVector `json:"invalid"`
+}
+
+// ValidVector pairs a 128-bit value with its encodings
+type ValidVector struct {
+	Name string `json:"name"`
+	Hex  string `json:"hex"`  // 32 lowercase hex digits, big-endian
+	UUID string `json:"uuid"` // canonical lowercase UUID form
+	KUID string `json:"kuid"` // canonical base62 form
+}
+
+// InvalidVector is a string every decoder must reject
+type InvalidVector struct {
+	Name   string `json:"name"`
+	KUID   string `json:"kuid"`
+	Reason string `json:"reason"` // "length", "char" or "overflow"
+}
+
+// Encoder converts 128-bit values to strings
+type Encoder interface {
+	Encode(id [16]byte) (string, error)
+}
+
+// Decoder converts strings back to 128-bit values
+type Decoder interface {
+	Decode(s string) ([16]byte, error)
+}
+
+// Failure describes a vector an implementation got wrong
+type Failure struct {
+	Vector string
+	Err    error
+}
+
+func (f Failure) Error() string {
+	return f.Vector + ": " + f.Err.Error()
+}
+
+// Default returns the vectors shipped with the package
+func Default() *Vectors {
+	v, err := Load(bytes.NewReader(vectorsJSON))
+	if err != nil {
+		panic("conformance: embedded vectors: " + err.Error())
+	}
+	return v
+}
+
+// Load reads vectors in the vectors.json format
+func Load(r io.Reader) (*Vectors, error) {
+	var v Vectors
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if v.Version != 1 {
+		return nil, fmt.Errorf("unsupported vectors version %d", v.Version)
+	}
+	for _, vec := range v.Valid {
+		if _, err := vec.bytes(); err != nil {
+			return nil, fmt.Errorf("vector %q: %w", vec.Name, err)
+		}
+	}
+	return &v, nil
+}
+
+func (v ValidVector) bytes() ([16]byte, error) {
+	var id [16]byte
+	b, err := hex.DecodeString(v.Hex)
+	if err != nil || len(b) != len(id) {
+		return id, errors.New("hex must be 32 hex digits")
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// Verify runs every vector against enc and dec, either of which may be nil
+// to test only one direction. Each vector's UUID form is also checked
+// against its hex, so a mistyped vector fails rather than misleading ports
+// that test against it. It returns every failure, or none if the
+// implementation conforms.
+func (v *Vectors) Verify(enc Encoder, dec Decoder) []Failure {
+	var failures []Failure
+	fail := func(name string, format string, args ...any) {
+		failures = append(failures, Failure{Vector: name, Err: fmt.Errorf(format, args...)})
+	}
+
+	for _, vec := range v.Valid {
+		id, _ := vec.bytes()
+		if got := kuid.FromArray(id).ToUUID(); got != vec.UUID {
+			fail(vec.Name, "UUID of %s = %q, vector has %q", vec.Hex, got, vec.UUID)
+		}
+		if enc != nil {
+			got, err := enc.Encode(id)
+			if err != nil {
+				fail(vec.Name, "Encode(%s) error = %v", vec.Hex, err)
+			} else if got != vec.KUID {
+				fail(vec.Name, "Encode(%s) = %q, want %q", vec.Hex, got, vec.KUID)
+			}
+		}
+		if dec != nil {
+			got, err := dec.Decode(vec.KUID)
+			if err != nil {
+				fail(vec.Name, "Decode(%q) error = %v", vec.KUID, err)
+			} else if got != id {
+				fail(vec.Name, "Decode(%q) = %x, want %s", vec.KUID, got, vec.Hex)
+			}
+		}
+	}
+
+	if dec != nil {
+		for _, vec := range v.Invalid {
+			if got, err := dec.Decode(vec.KUID); err == nil {
+				fail(vec.Name, "Decode(%q) = %x, want %s error", vec.KUID, got, vec.Reason)
+			}
+		}
+	}
+	return failures
+}
+
+// Reference is this package's own encoder and decoder
+var Reference reference
+
+type reference struct{}
+
+func (reference) Encode(id [16]byte) (string, error) {
+	return kuid.FromArray(id).String(), nil
+}
+
+func (reference) Decode(s string) ([16]byte, error) {
+	k, err := kuid.FromString(s)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return k.Array(), nil
+}
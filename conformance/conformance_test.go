@@ -0,0 +1,150 @@
+package conformance
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+var update = flag.Bool("update", false, "regenerate vectors.json from the reference implementation")
+
+// generateVectors builds the vector set from the reference implementation.
+// Random vectors use a fixed seed so regeneration is reproducible.
+func generateVectors() *Vectors {
+	v := &Vectors{Version: 1}
+	add := func(name string, id [16]byte) {
+		k := kuid.FromArray(id)
+		v.Valid = append(v.Valid, ValidVector{
+			Name: name,
+			Hex:  hex.EncodeToString(id[:]),
+			UUID: k.ToUUID(),
+			KUID: k.String(),
+		})
+	}
+
+	add("zero", [16]byte{})
+	var max [16]byte
+	for i := range max {
+		max[i] = 0xff
+	}
+	add("max", max)
+	add("max msb", [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	add("max lsb", [16]byte{8: 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	add("one", [16]byte{15: 1})
+	add("base", [16]byte{15: 62})
+	for _, u := range []string{
+		"123e4567-e89b-12d3-a456-426614174000",
+		"d9db5cf3-c755-4f76-8746-04120f2644c6",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	} {
+		k, _ := kuid.FromUUID(u)
+		add("uuid "+u, k.Array())
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 16; i++ {
+		var id [16]byte
+		for j := range id {
+			id[j] = byte(rng.Uint32())
+		}
+		add("random "+hex.EncodeToString(id[:2]), id)
+	}
+
+	v.Invalid = []InvalidVector{
+		{Name: "empty", KUID: "", Reason: "length"},
+		{Name: "too short", KUID: strings.Repeat("0", 21), Reason: "length"},
+		{Name: "too long", KUID: strings.Repeat("0", 23), Reason: "length"},
+		{Name: "hyphen", KUID: "00000000000-0000000000", Reason: "char"},
+		{Name: "non-ascii", KUID: "0000000000é0000000000", Reason: "char"},
+		{Name: "padding", KUID: "000000000000000000000=", Reason: "char"},
+		{Name: "msb overflow", KUID: "LygHa16AHYG00000000000", Reason: "overflow"},
+		{Name: "lsb overflow", KUID: "00000000000LygHa16AHYG", Reason: "overflow"},
+		{Name: "non-canonical alias", KUID: "z0000000000" + "00000000000", Reason: "overflow"},
+	}
+	return v
+}
+
+func TestVectorsUpToDate(t *testing.T) {
+	want, err := json.MarshalIndent(generateVectors(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, '\n')
+
+	if *update {
+		if err := os.WriteFile("vectors.json", want, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if string(want) != string(vectorsJSON) {
+		t.Errorf("vectors.json is stale; run go test -update")
+	}
+}
+
+func TestReferenceConforms(t *testing.T) {
+	for _, f := range Default().Verify(Reference, Reference) {
+		t.Error(f)
+	}
+}
+
+// lenientDecoder accepts anything 22 characters long, like a port missing
+// validation
+type lenientDecoder struct{}
+
+func (lenientDecoder) Decode(s string) ([16]byte, error) {
+	if len(s) != 22 {
+		return [16]byte{}, kuid.ErrInvalidLength
+	}
+	k, err := kuid.FromString(s)
+	if err != nil {
+		return [16]byte{}, nil
+	}
+	return k.Array(), nil
+}
+
+// uuidEncoder emits the UUID form instead of base62
+type uuidEncoder struct{}
+
+func (uuidEncoder) Encode(id [16]byte) (string, error) {
+	return kuid.FromArray(id).ToUUID(), nil
+}
+
+func TestVerifyCatchesFaults(t *testing.T) {
+	v := Default()
+
+	failures := v.Verify(nil, lenientDecoder{})
+	if len(failures) != 6 {
+		t.Errorf("Lenient decoder: %d failures, want 6: %v", len(failures), failures)
+	}
+
+	failures = v.Verify(uuidEncoder{}, nil)
+	if len(failures) != len(v.Valid) {
+		t.Errorf("UUID encoder: %d failures, want %d", len(failures), len(v.Valid))
+	}
+
+	// A vector whose UUID disagrees with its hex fails even with a
+	// conforming implementation
+	v.Valid[0].UUID = "00000000-0000-0000-0000-000000000001"
+	failures = v.Verify(Reference, Reference)
+	if len(failures) != 1 || failures[0].Vector != v.Valid[0].Name {
+		t.Errorf("Mistyped UUID: failures = %v, want one for %q", failures, v.Valid[0].Name)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	for _, input := range []string{
+		`{"version": 2}`,
+		`{"version": 1, "valid": [{"name": "x", "hex": "zz"}]}`,
+		`{"version": 1, "extra": true}`,
+	} {
+		if _, err := Load(strings.NewReader(input)); err == nil {
+			t.Errorf("Load(%s) succeeded", input)
+		}
+	}
+}
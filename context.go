@@ -0,0 +1,16 @@
+package kuid
+
+import "context"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying id as the request ID
+func ContextWithRequestID(ctx context.Context, id *KUID) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID attached to ctx, if any
+func RequestIDFromContext(ctx context.Context) (*KUID, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(*KUID)
+	return id, ok && id != nil
+}
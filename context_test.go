@@ -0,0 +1,17 @@
+package kuid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestIDFromContext(t *testing.T) {
+	if _, ok := RequestIDFromContext(context.Background()); ok {
+		t.Errorf("RequestIDFromContext() found ID in empty context")
+	}
+	id, _ := NewKUID()
+	got, ok := RequestIDFromContext(ContextWithRequestID(context.Background(), id))
+	if !ok || !got.Equal(id) {
+		t.Errorf("RequestIDFromContext() = %v, %v, want %v", got, ok, id)
+	}
+}
@@ -0,0 +1,142 @@
+package kuid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+var (
+	ErrNoFreeNode = errors.New("no free node ID in region")
+	ErrLeaseLost  = errors.New("node ID lease lost")
+)
+
+// Coordinator leases node IDs from a store shared by every node of a
+// region, so no two live nodes mint ordered KUIDs with the same node ID.
+// RedisCoordinator, EtcdCoordinator and ConsulCoordinator are reference
+// implementations.
+type Coordinator interface {
+	// Acquire leases a free node ID, releasing any lease already held
+	Acquire(ctx context.Context) (uint64, error)
+	// Renew extends the held lease, returning ErrLeaseLost once it has
+	// lapsed and may have been taken by another node
+	Renew(ctx context.Context) error
+	// Release gives up the held lease, if any
+	Release(ctx context.Context) error
+}
+
+// CoordinateOptions configures Coordinate
+type CoordinateOptions struct {
+	// Topology and Region are the layout and region the Coordinator
+	// leases node IDs from
+	Topology *Topology
+	Region   string
+	// TTL is the Coordinator's lease duration; leases are renewed every
+	// TTL/3
+	TTL time.Duration
+}
+
+// Coordinate leases a node ID from c, configures g to mint ordered KUIDs as
+// that node and keeps the lease renewed until ctx is done, when the lease is
+// released. Each renewal must finish before the lease expires. Failed
+// renewals are retried until the lease would have expired; g then moves to
+// a newly leased node ID, since another node may have claimed the old one.
+// Whenever g holds no lease, including after Coordinate returns, its
+// NewOrdered fails with ErrLeaseLost rather than reuse a node ID another
+// node may own. Coordinate returns an error when no node ID can be leased.
+func Coordinate(ctx context.Context, c Coordinator, g *Generator, opts CoordinateOptions) error {
+	if err := checkLeaseTTL(opts.TTL); err != nil {
+		return err
+	}
+	// Stop minting with the old node ID before looking for a new one
+	revoke := func() { g.Update(withoutNode(ErrLeaseLost)) }
+	assign := func() (time.Time, error) {
+		revoke()
+		start := time.Now()
+		node, err := c.Acquire(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if err := g.Update(WithTopology(opts.Topology, opts.Region, node)); err != nil {
+			c.Release(ctx)
+			return time.Time{}, err
+		}
+		return start.Add(opts.TTL), nil
+	}
+
+	deadline, err := assign()
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(opts.TTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			revoke()
+			release, cancel := context.WithTimeout(context.WithoutCancel(ctx), opts.TTL)
+			defer cancel()
+			c.Release(release)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		// A renewal still hanging when the lease expires is worthless
+		renewed := time.Now()
+		renewCtx, cancel := context.WithDeadline(ctx, deadline)
+		err := c.Renew(renewCtx)
+		cancel()
+		switch {
+		case err == nil:
+			deadline = renewed.Add(opts.TTL)
+		case err == ErrLeaseLost || !time.Now().Add(opts.TTL/3).Before(deadline):
+			if deadline, err = assign(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// coordinatorRegion validates a Coordinator's region and creates its owner
+// token
+func coordinatorRegion(topo *Topology, region string) (RegionAllocation, string, error) {
+	if err := topo.Validate(); err != nil {
+		return RegionAllocation{}, "", err
+	}
+	r, ok := topo.region(region)
+	if !ok {
+		return RegionAllocation{}, "", fmt.Errorf("region %q not in topology", region)
+	}
+	token, err := NewKUID()
+	if err != nil {
+		return RegionAllocation{}, "", err
+	}
+	return r, token.String(), nil
+}
+
+func checkLeaseTTL(ttl time.Duration) error {
+	if ttl < 3*time.Millisecond {
+		return errors.New("lease TTL must be at least 3ms")
+	}
+	return nil
+}
+
+// probeNodes calls claim on each node ID of region, from a random start so
+// nodes starting together rarely contend, until one is claimed
+func probeNodes(region RegionAllocation, claim func(node uint64) (bool, error)) (uint64, error) {
+	size := region.LastNode - region.FirstNode + 1
+	start := rand.Uint64N(size)
+	for i := uint64(0); i < size; i++ {
+		node := region.FirstNode + (start+i)%size
+		ok, err := claim(node)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return node, nil
+		}
+	}
+	return 0, ErrNoFreeNode
+}
@@ -0,0 +1,116 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConsulClient is the subset of Consul used by ConsulCoordinator. An
+// adapter over github.com/hashicorp/consul/api is a few lines per method:
+//
+//	CreateSession:  client.Session().Create(&api.SessionEntry{
+//	                    TTL: ttl.String(), Behavior: api.SessionBehaviorDelete}, opts)
+//	RenewSession:   client.Session().Renew(id, opts), returning ErrLeaseLost
+//	                when it reports no session
+//	DestroySession: client.Session().Destroy(id, opts)
+//	AcquireKey:     client.KV().Acquire(&api.KVPair{Key: key, Value: value,
+//	                    Session: session}, opts)
+type ConsulClient interface {
+	CreateSession(ctx context.Context, ttl time.Duration) (session string, err error)
+	RenewSession(ctx context.Context, session string) error
+	DestroySession(ctx context.Context, session string) error
+	AcquireKey(ctx context.Context, key string, value []byte, session string) (bool, error)
+}
+
+// ConsulCoordinator is a Coordinator locking node IDs as keys
+// "<prefix>/<region>/<node>" with a Consul session. The session is created
+// with the delete behavior, so Consul removes the key when the session
+// expires or is destroyed, returning the ID to the pool.
+type ConsulCoordinator struct {
+	client ConsulClient
+	prefix string
+	topo   *Topology
+	region RegionAllocation
+	ttl    time.Duration
+	token  string
+
+	mu      sync.Mutex
+	session string
+	held    bool
+}
+
+// NewConsulCoordinator creates a ConsulCoordinator leasing IDs from
+// region's allocation in topo. Consul session TTLs range from 10 seconds
+// to 24 hours.
+func NewConsulCoordinator(client ConsulClient, prefix string, topo *Topology, region string, ttl time.Duration) (*ConsulCoordinator, error) {
+	r, token, err := coordinatorRegion(topo, region)
+	if err != nil {
+		return nil, err
+	}
+	if ttl < 10*time.Second || ttl > 24*time.Hour {
+		return nil, fmt.Errorf("consul session TTL must be between 10s and 24h, not %v", ttl)
+	}
+	return &ConsulCoordinator{client: client, prefix: prefix, topo: topo, region: r, ttl: ttl, token: token}, nil
+}
+
+// Acquire implements Coordinator, locking a free node ID with a fresh
+// session
+func (c *ConsulCoordinator) Acquire(ctx context.Context) (uint64, error) {
+	if err := c.Release(ctx); err != nil {
+		return 0, err
+	}
+	session, err := c.client.CreateSession(ctx, c.ttl)
+	if err != nil {
+		return 0, err
+	}
+	node, err := probeNodes(c.region, func(node uint64) (bool, error) {
+		key := c.prefix + "/" + c.region.Name + "/" + strconv.FormatUint(node, 10)
+		return c.client.AcquireKey(ctx, key, []byte(c.token), session)
+	})
+	if err != nil {
+		c.client.DestroySession(ctx, session)
+		return 0, err
+	}
+	c.mu.Lock()
+	c.session, c.held = session, true
+	c.mu.Unlock()
+	return node, nil
+}
+
+// Renew implements Coordinator by renewing the session
+func (c *ConsulCoordinator) Renew(ctx context.Context) error {
+	c.mu.Lock()
+	session, held := c.session, c.held
+	c.mu.Unlock()
+	if !held {
+		return ErrLeaseLost
+	}
+	err := c.client.RenewSession(ctx, session)
+	if err == ErrLeaseLost {
+		c.mu.Lock()
+		c.held = false
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// Release implements Coordinator by destroying the session, which deletes
+// the node key
+func (c *ConsulCoordinator) Release(ctx context.Context) error {
+	c.mu.Lock()
+	session, held := c.session, c.held
+	c.held = false
+	c.mu.Unlock()
+	if !held {
+		return nil
+	}
+	return c.client.DestroySession(ctx, session)
+}
+
+// Run coordinates g's node ID with Coordinate until ctx is done
+func (c *ConsulCoordinator) Run(ctx context.Context, g *Generator) error {
+	return Coordinate(ctx, c, g, CoordinateOptions{Topology: c.topo, Region: c.region.Name, TTL: c.ttl})
+}
@@ -0,0 +1,88 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeConsul locks keys with sessions that never expire on their own
+type fakeConsul struct {
+	mu       sync.Mutex
+	sessions map[string]bool
+	locks    map[string]string // key to session
+	next     int
+}
+
+func newFakeConsul() *fakeConsul {
+	return &fakeConsul{sessions: map[string]bool{}, locks: map[string]string{}}
+}
+
+func (f *fakeConsul) CreateSession(_ context.Context, ttl time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.next++
+	id := "session-" + strconv.Itoa(f.next)
+	f.sessions[id] = true
+	return id, nil
+}
+
+func (f *fakeConsul) RenewSession(_ context.Context, session string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.sessions[session] {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (f *fakeConsul) DestroySession(_ context.Context, session string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.sessions[session] {
+		return fmt.Errorf("session %s not found", session)
+	}
+	delete(f.sessions, session)
+	for k, s := range f.locks {
+		if s == session {
+			delete(f.locks, k)
+		}
+	}
+	return nil
+}
+
+func (f *fakeConsul) AcquireKey(_ context.Context, key string, _ []byte, session string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if holder, ok := f.locks[key]; ok && holder != session {
+		return false, nil
+	}
+	f.locks[key] = session
+	return true, nil
+}
+
+func TestConsulCoordinator(t *testing.T) {
+	client := newFakeConsul()
+	newCoord := func() Coordinator {
+		c, err := NewConsulCoordinator(client, "kuid/nodes", coordinatorTopology, "us", 15*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	testCoordinator(t, newCoord(), newCoord(), newCoord())
+
+	// The session created for a failed search is not left behind
+	if len(client.sessions) != 2 {
+		t.Errorf("%d sessions outstanding, want 2", len(client.sessions))
+	}
+
+	for _, ttl := range []time.Duration{time.Second, 25 * time.Hour} {
+		if _, err := NewConsulCoordinator(client, "kuid", coordinatorTopology, "us", ttl); err == nil {
+			t.Errorf("NewConsulCoordinator() with TTL %v expected error", ttl)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EtcdClient is the subset of etcd v3 used by EtcdCoordinator. An adapter
+// over go.etcd.io/etcd/client/v3 is a few lines per method:
+//
+//	Grant:         client.Grant(ctx, int64(ttl.Seconds()))
+//	KeepAliveOnce: client.KeepAliveOnce(ctx, id), returning ErrLeaseLost
+//	               for rpctypes.ErrLeaseNotFound
+//	Revoke:        client.Revoke(ctx, id)
+//	PutIfAbsent:   client.Txn(ctx).If(clientv3.CreateRevision(key) == 0).
+//	               Then(clientv3.OpPut(key, value, clientv3.WithLease(id))),
+//	               returning the response's Succeeded
+type EtcdClient interface {
+	Grant(ctx context.Context, ttl time.Duration) (lease int64, err error)
+	KeepAliveOnce(ctx context.Context, lease int64) error
+	Revoke(ctx context.Context, lease int64) error
+	PutIfAbsent(ctx context.Context, key, value string, lease int64) (bool, error)
+}
+
+// EtcdCoordinator is a Coordinator keeping node IDs as keys
+// "<prefix>/<region>/<node>" attached to an etcd lease. etcd deletes the
+// key when the lease expires, returning the ID to the pool.
+type EtcdCoordinator struct {
+	client EtcdClient
+	prefix string
+	topo   *Topology
+	region RegionAllocation
+	ttl    time.Duration
+	token  string
+
+	mu    sync.Mutex
+	lease int64
+	held  bool
+}
+
+// NewEtcdCoordinator creates an EtcdCoordinator leasing IDs from region's
+// allocation in topo for ttl at a time. etcd leases have whole-second
+// TTLs, so ttl must be at least 3 seconds.
+func NewEtcdCoordinator(client EtcdClient, prefix string, topo *Topology, region string, ttl time.Duration) (*EtcdCoordinator, error) {
+	r, token, err := coordinatorRegion(topo, region)
+	if err != nil {
+		return nil, err
+	}
+	if ttl < 3*time.Second {
+		return nil, fmt.Errorf("etcd lease TTL must be at least 3s, not %v", ttl)
+	}
+	return &EtcdCoordinator{client: client, prefix: prefix, topo: topo, region: r, ttl: ttl, token: token}, nil
+}
+
+// Acquire implements Coordinator, claiming a free node ID under a fresh
+// etcd lease
+func (c *EtcdCoordinator) Acquire(ctx context.Context) (uint64, error) {
+	if err := c.Release(ctx); err != nil {
+		return 0, err
+	}
+	lease, err := c.client.Grant(ctx, c.ttl)
+	if err != nil {
+		return 0, err
+	}
+	node, err := probeNodes(c.region, func(node uint64) (bool, error) {
+		key := c.prefix + "/" + c.region.Name + "/" + strconv.FormatUint(node, 10)
+		return c.client.PutIfAbsent(ctx, key, c.token, lease)
+	})
+	if err != nil {
+		c.client.Revoke(ctx, lease)
+		return 0, err
+	}
+	c.mu.Lock()
+	c.lease, c.held = lease, true
+	c.mu.Unlock()
+	return node, nil
+}
+
+// Renew implements Coordinator by keeping the etcd lease alive
+func (c *EtcdCoordinator) Renew(ctx context.Context) error {
+	c.mu.Lock()
+	lease, held := c.lease, c.held
+	c.mu.Unlock()
+	if !held {
+		return ErrLeaseLost
+	}
+	err := c.client.KeepAliveOnce(ctx, lease)
+	if err == ErrLeaseLost {
+		c.mu.Lock()
+		c.held = false
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// Release implements Coordinator by revoking the etcd lease, which deletes
+// the node key
+func (c *EtcdCoordinator) Release(ctx context.Context) error {
+	c.mu.Lock()
+	lease, held := c.lease, c.held
+	c.held = false
+	c.mu.Unlock()
+	if !held {
+		return nil
+	}
+	return c.client.Revoke(ctx, lease)
+}
+
+// Run coordinates g's node ID with Coordinate until ctx is done
+func (c *EtcdCoordinator) Run(ctx context.Context, g *Generator) error {
+	return Coordinate(ctx, c, g, CoordinateOptions{Topology: c.topo, Region: c.region.Name, TTL: c.ttl})
+}
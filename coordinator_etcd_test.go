@@ -0,0 +1,87 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEtcd keeps keys attached to leases that never expire on their own
+type fakeEtcd struct {
+	mu     sync.Mutex
+	leases map[int64][]string
+	keys   map[string]string
+	nextID int64
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{leases: map[int64][]string{}, keys: map[string]string{}}
+}
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl time.Duration) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	f.leases[f.nextID] = nil
+	return f.nextID, nil
+}
+
+func (f *fakeEtcd) KeepAliveOnce(_ context.Context, lease int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.leases[lease]; !ok {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, lease int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys, ok := f.leases[lease]
+	if !ok {
+		return fmt.Errorf("lease %d not found", lease)
+	}
+	for _, k := range keys {
+		delete(f.keys, k)
+	}
+	delete(f.leases, lease)
+	return nil
+}
+
+func (f *fakeEtcd) PutIfAbsent(_ context.Context, key, value string, lease int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return false, nil
+	}
+	f.keys[key] = value
+	f.leases[lease] = append(f.leases[lease], key)
+	return true, nil
+}
+
+func TestEtcdCoordinator(t *testing.T) {
+	client := newFakeEtcd()
+	newCoord := func() Coordinator {
+		c, err := NewEtcdCoordinator(client, "/kuid/nodes", coordinatorTopology, "us", 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	testCoordinator(t, newCoord(), newCoord(), newCoord())
+
+	// The lease granted for a failed search is not left behind
+	if len(client.leases) != 2 {
+		t.Errorf("%d leases outstanding, want 2", len(client.leases))
+	}
+	if _, ok := client.keys["/kuid/nodes/us/2"]; !ok {
+		t.Errorf("node key not written under prefix: %v", client.keys)
+	}
+
+	if _, err := NewEtcdCoordinator(client, "/kuid", coordinatorTopology, "us", time.Second); err == nil {
+		t.Errorf("NewEtcdCoordinator() with sub-3s TTL expected error")
+	}
+}
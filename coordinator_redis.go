@@ -0,0 +1,124 @@
+package kuid
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisCoordinator is a Coordinator leasing node IDs from a Topology region
+// in Redis, so autoscaled pods never share a node ID. Each leased
+// ID is a key "<prefix>:<region>:<node>" holding the owner's token with a
+// TTL. Owners renew well before it expires; when a pod dies its key lapses
+// and the ID returns to the pool.
+type RedisCoordinator struct {
+	conn   RedisDoer
+	prefix string
+	topo   *Topology
+	region RegionAllocation
+	ttl    time.Duration
+	token  string
+
+	mu   sync.Mutex
+	node uint64
+	held bool
+}
+
+// NewRedisCoordinator creates a RedisCoordinator leasing IDs from region's
+// allocation in topo for ttl at a time
+func NewRedisCoordinator(conn RedisDoer, prefix string, topo *Topology, region string, ttl time.Duration) (*RedisCoordinator, error) {
+	r, token, err := coordinatorRegion(topo, region)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLeaseTTL(ttl); err != nil {
+		return nil, err
+	}
+	return &RedisCoordinator{
+		conn:   conn,
+		prefix: prefix,
+		topo:   topo,
+		region: r,
+		ttl:    ttl,
+		token:  token,
+	}, nil
+}
+
+func (c *RedisCoordinator) key(node uint64) string {
+	return c.prefix + ":" + c.region.Name + ":" + strconv.FormatUint(node, 10)
+}
+
+// Acquire leases a free node ID, probing the region from a random start so
+// pods starting together rarely contend. Any lease already held is
+// released first.
+func (c *RedisCoordinator) Acquire(ctx context.Context) (uint64, error) {
+	if err := c.Release(ctx); err != nil {
+		return 0, err
+	}
+
+	node, err := probeNodes(c.region, func(node uint64) (bool, error) {
+		reply, err := c.conn.DoContext(ctx, "SET", c.key(node), c.token, "NX", "PX", c.ttl.Milliseconds())
+		return reply != nil, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	c.node, c.held = node, true
+	c.mu.Unlock()
+	return node, nil
+}
+
+// renewScript extends the lease only while this owner still holds it
+const renewScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0`
+
+// releaseScript deletes the lease only while this owner still holds it
+const releaseScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`
+
+// Renew extends the held lease by the TTL. It returns ErrLeaseLost if the
+// lease expired and may have been taken by another pod.
+func (c *RedisCoordinator) Renew(ctx context.Context) error {
+	c.mu.Lock()
+	node, held := c.node, c.held
+	c.mu.Unlock()
+	if !held {
+		return ErrLeaseLost
+	}
+
+	reply, err := c.conn.DoContext(ctx, "EVAL", renewScript, 1, c.key(node), c.token, c.ttl.Milliseconds())
+	if err != nil {
+		return err
+	}
+	if n, _ := reply.(int64); n != 1 {
+		c.mu.Lock()
+		c.held = false
+		c.mu.Unlock()
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Release gives up the held lease, if any
+func (c *RedisCoordinator) Release(ctx context.Context) error {
+	c.mu.Lock()
+	node, held := c.node, c.held
+	c.held = false
+	c.mu.Unlock()
+	if !held {
+		return nil
+	}
+	_, err := c.conn.DoContext(ctx, "EVAL", releaseScript, 1, c.key(node), c.token)
+	return err
+}
+
+// Run coordinates g's node ID with Coordinate until ctx is done
+func (c *RedisCoordinator) Run(ctx context.Context, g *Generator) error {
+	return Coordinate(ctx, c, g, CoordinateOptions{Topology: c.topo, Region: c.region.Name, TTL: c.ttl})
+}
@@ -0,0 +1,147 @@
+package kuid
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLeaseRedis implements the commands used by RedisCoordinator
+type fakeLeaseRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	down    bool
+}
+
+func newFakeLeaseRedis() *fakeLeaseRedis {
+	return &fakeLeaseRedis{values: map[string]string{}, expires: map[string]time.Time{}}
+}
+
+func (f *fakeLeaseRedis) DoContext(_ context.Context, cmd string, args ...any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return nil, fmt.Errorf("connection refused")
+	}
+	now := time.Now()
+	for k, exp := range f.expires {
+		if !now.Before(exp) {
+			delete(f.values, k)
+			delete(f.expires, k)
+		}
+	}
+	str := func(v any) string { return fmt.Sprint(v) }
+	ms := func(v any) time.Duration { return time.Duration(v.(int64)) * time.Millisecond }
+
+	switch {
+	case cmd == "SET" && str(args[2]) == "NX" && str(args[3]) == "PX":
+		key := str(args[0])
+		if _, ok := f.values[key]; ok {
+			return nil, nil
+		}
+		f.values[key] = str(args[1])
+		f.expires[key] = now.Add(ms(args[4]))
+		return "OK", nil
+	case cmd == "EVAL" && (args[0] == renewScript || args[0] == releaseScript):
+		key := str(args[2])
+		if f.values[key] != str(args[3]) {
+			return int64(0), nil
+		}
+		if args[0] == renewScript {
+			f.expires[key] = now.Add(ms(args[4]))
+		} else {
+			delete(f.values, key)
+			delete(f.expires, key)
+		}
+		return int64(1), nil
+	}
+	return nil, fmt.Errorf("unsupported command %s %v", cmd, args)
+}
+
+func (f *fakeLeaseRedis) owner(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.values[key]
+}
+
+var coordinatorTopology = &Topology{NodeBits: 2, SequenceBits: 14, Regions: []RegionAllocation{
+	{Name: "eu", FirstNode: 0, LastNode: 1},
+	{Name: "us", FirstNode: 2, LastNode: 3},
+}}
+
+func TestRedisCoordinator(t *testing.T) {
+	conn := newFakeLeaseRedis()
+	newCoord := func() Coordinator {
+		c, err := NewRedisCoordinator(conn, "kuid", coordinatorTopology, "us", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	testCoordinator(t, newCoord(), newCoord(), newCoord())
+}
+
+func TestRedisCoordinatorRun(t *testing.T) {
+	conn := newFakeLeaseRedis()
+	coord, _ := NewRedisCoordinator(conn, "kuid", coordinatorTopology, "eu", 30*time.Millisecond)
+	g, _ := NewGenerator()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- coord.Run(ctx, g) }()
+
+	// Outlive several TTLs; renewal must keep the lease
+	time.Sleep(100 * time.Millisecond)
+	k, _ := g.NewOrdered()
+	node := coordinatorTopology.Node(k)
+	if node > 1 || conn.owner(coord.key(node)) != coord.token {
+		t.Fatalf("node %d not leased by the running coordinator", node)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if conn.owner(coord.key(node)) != "" {
+		t.Errorf("Run() did not release its lease on exit")
+	}
+}
+
+func TestRedisCoordinatorRunUnavailable(t *testing.T) {
+	conn := newFakeLeaseRedis()
+	coord, _ := NewRedisCoordinator(conn, "kuid", coordinatorTopology, "eu", 30*time.Millisecond)
+	g, _ := NewGenerator()
+
+	done := make(chan error, 1)
+	go func() { done <- coord.Run(context.Background(), g) }()
+	time.Sleep(20 * time.Millisecond)
+
+	// Once the lease can no longer be guaranteed, Run gives up
+	conn.mu.Lock()
+	conn.down = true
+	conn.mu.Unlock()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("Run() returned nil after losing Redis")
+		}
+		if _, err := g.NewOrdered(); err != ErrLeaseLost {
+			t.Errorf("NewOrdered() without a lease error = %v, want %v", err, ErrLeaseLost)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run() kept going without a lease")
+	}
+}
+
+func TestNewRedisCoordinatorInvalid(t *testing.T) {
+	conn := newFakeLeaseRedis()
+	if _, err := NewRedisCoordinator(conn, "kuid", coordinatorTopology, "ap", time.Minute); err == nil {
+		t.Errorf("NewRedisCoordinator() with unknown region expected error")
+	}
+	if _, err := NewRedisCoordinator(conn, "kuid", coordinatorTopology, "eu", 0); err == nil {
+		t.Errorf("NewRedisCoordinator() with zero TTL expected error")
+	}
+}
@@ -0,0 +1,107 @@
+package kuid
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCoordinator checks the Coordinator contract on three coordinators
+// sharing one store and coordinatorTopology's two-node "us" region
+func testCoordinator(t *testing.T, a, b, c Coordinator) {
+	t.Helper()
+	ctx := context.Background()
+
+	na, err := a.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb, err := b.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if na == nb || na < 2 || nb < 2 {
+		t.Fatalf("Acquire() = %d and %d, want distinct nodes in 2-3", na, nb)
+	}
+	if _, err := c.Acquire(ctx); err != ErrNoFreeNode {
+		t.Errorf("Acquire() on full region error = %v, want %v", err, ErrNoFreeNode)
+	}
+
+	// A released node is free again, and a lease cannot be renewed once gone
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if nc, err := c.Acquire(ctx); err != nil || nc != na {
+		t.Errorf("Acquire() after Release = %d, %v, want %d", nc, err, na)
+	}
+	if err := a.Renew(ctx); err != ErrLeaseLost {
+		t.Errorf("Renew() after Release error = %v, want %v", err, ErrLeaseLost)
+	}
+	if err := b.Renew(ctx); err != nil {
+		t.Errorf("Renew() error = %v", err)
+	}
+}
+
+func TestCoordinateInvalidTTL(t *testing.T) {
+	g, _ := NewGenerator()
+	coord, _ := NewRedisCoordinator(newFakeLeaseRedis(), "kuid", coordinatorTopology, "eu", time.Minute)
+	if err := Coordinate(context.Background(), coord, g, CoordinateOptions{Topology: coordinatorTopology, Region: "eu"}); err == nil {
+		t.Errorf("Coordinate() with zero TTL expected error")
+	}
+}
+
+// hangingCoordinator leases node 0 and never answers Renew
+type hangingCoordinator struct {
+	mu        sync.Mutex
+	acquired  int
+	deadlines []bool
+}
+
+func (h *hangingCoordinator) Acquire(context.Context) (uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.acquired++
+	return 0, nil
+}
+
+func (h *hangingCoordinator) Renew(ctx context.Context) error {
+	_, ok := ctx.Deadline()
+	h.mu.Lock()
+	h.deadlines = append(h.deadlines, ok)
+	h.mu.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (h *hangingCoordinator) Release(context.Context) error { return nil }
+
+func TestCoordinateHungRenew(t *testing.T) {
+	h := &hangingCoordinator{}
+	g, _ := NewGenerator()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Coordinate(ctx, h, g, CoordinateOptions{Topology: coordinatorTopology, Region: "eu", TTL: 30 * time.Millisecond})
+	}()
+
+	// A hung renewal must give up at the lease expiry so a new lease is
+	// acquired, not block past it
+	time.Sleep(150 * time.Millisecond)
+	cancel()
+	<-done
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.acquired < 2 {
+		t.Errorf("Acquire() called %d times, want a new lease after the hung renewal", h.acquired)
+	}
+	for i, ok := range h.deadlines {
+		if !ok {
+			t.Errorf("Renew() call %d had no deadline", i)
+		}
+	}
+	if _, err := g.NewOrdered(); err != ErrLeaseLost {
+		t.Errorf("NewOrdered() after Coordinate returned error = %v, want %v", err, ErrLeaseLost)
+	}
+}
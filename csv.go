@@ -0,0 +1,36 @@
+package kuid
+
+// MarshalCSV implements gocarina/gocsv's TypeMarshaller. jszwec/csvutil
+// uses MarshalText instead; both write the base62 form.
+func (k KUID) MarshalCSV() (string, error) {
+	return k.String(), nil
+}
+
+// UnmarshalCSV implements gocarina/gocsv's TypeUnmarshaller, accepting only
+// the canonical base62 form. Use TolerantKUID for files mixing formats.
+func (k *KUID) UnmarshalCSV(s string) error {
+	return k.UnmarshalText([]byte(s))
+}
+
+// TolerantKUID is a KUID that decodes from either its base62 or UUID form,
+// for ingesting exports that mix both formats per cell. It encodes as the
+// base62 form. It works with gocsv, csvutil and any encoder using
+// encoding.TextMarshaler.
+type TolerantKUID struct {
+	KUID
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (t *TolerantKUID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	t.KUID = *parsed
+	return nil
+}
+
+// UnmarshalCSV implements gocarina/gocsv's TypeUnmarshaller
+func (t *TolerantKUID) UnmarshalCSV(s string) error {
+	return t.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,47 @@
+package kuid
+
+import "testing"
+
+func TestCSVMarshaling(t *testing.T) {
+	kuid, _ := NewKUID()
+
+	cell, err := kuid.MarshalCSV()
+	if err != nil || cell != kuid.String() {
+		t.Errorf("MarshalCSV() = %v, %v, want %v", cell, err, kuid.String())
+	}
+
+	var decoded KUID
+	if err := decoded.UnmarshalCSV(cell); err != nil {
+		t.Fatalf("UnmarshalCSV() error = %v", err)
+	}
+	if !decoded.Equal(kuid) {
+		t.Errorf("UnmarshalCSV() = %v, want %v", decoded, kuid)
+	}
+	if err := decoded.UnmarshalCSV(kuid.ToUUID()); err == nil {
+		t.Errorf("UnmarshalCSV() expected error for UUID cell")
+	}
+}
+
+func TestTolerantKUID(t *testing.T) {
+	kuid, _ := NewKUID()
+
+	for _, cell := range []string{kuid.String(), kuid.ToUUID()} {
+		var tolerant TolerantKUID
+		if err := tolerant.UnmarshalCSV(cell); err != nil {
+			t.Fatalf("UnmarshalCSV(%q) error = %v", cell, err)
+		}
+		if !tolerant.Equal(kuid) {
+			t.Errorf("UnmarshalCSV(%q) = %v, want %v", cell, tolerant.KUID, kuid)
+		}
+
+		out, _ := tolerant.MarshalText()
+		if string(out) != kuid.String() {
+			t.Errorf("MarshalText() = %s, want %v", out, kuid.String())
+		}
+	}
+
+	var tolerant TolerantKUID
+	if err := tolerant.UnmarshalText([]byte("bogus")); err == nil {
+		t.Errorf("UnmarshalText() expected error")
+	}
+}
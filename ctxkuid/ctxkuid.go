@@ -0,0 +1,98 @@
+// Package ctxkuid carries correlation IDs through contexts.
+//
+// The current ID is stored under the same key as kuid.ContextWithRequestID,
+// so IDs attached by kuid.RequestIDMiddleware and the kuidgrpc interceptors
+// are visible here and vice versa. Background jobs that never see an HTTP
+// or gRPC request can call Ensure to get one of their own.
+//
+// WithValue chains IDs: the ID it replaces becomes the parent of the new
+// one, so a job fanning out into sub-tasks can log both its own ID and the
+// request that caused it.
+package ctxkuid
+
+import (
+	"context"
+	"slices"
+
+	"github.com/alphabatem/kuid"
+)
+
+// lineageKey holds the chain of IDs replaced by WithValue
+type lineageKey struct{}
+
+// lineage is a link in the chain of replaced IDs, newest first
+type lineage struct {
+	id     *kuid.KUID
+	parent *lineage
+}
+
+// NewContext returns a copy of ctx carrying id as its correlation ID,
+// starting a new lineage
+func NewContext(ctx context.Context, id *kuid.KUID) context.Context {
+	if _, ok := ctx.Value(lineageKey{}).(*lineage); ok {
+		ctx = context.WithValue(ctx, lineageKey{}, (*lineage)(nil))
+	}
+	return kuid.ContextWithRequestID(ctx, id)
+}
+
+// FromContext returns the correlation ID carried by ctx, if any
+func FromContext(ctx context.Context) (*kuid.KUID, bool) {
+	return kuid.RequestIDFromContext(ctx)
+}
+
+// Ensure returns ctx and its correlation ID, generating and attaching one
+// when ctx has none
+func Ensure(ctx context.Context) (context.Context, *kuid.KUID, error) {
+	if id, ok := FromContext(ctx); ok {
+		return ctx, id, nil
+	}
+	id, err := kuid.NewKUID()
+	if err != nil {
+		return ctx, nil, err
+	}
+	return NewContext(ctx, id), id, nil
+}
+
+// WithValue returns a copy of ctx carrying id as its correlation ID, with
+// the ID it replaces, if any, recorded as id's parent
+func WithValue(ctx context.Context, id *kuid.KUID) context.Context {
+	if current, ok := FromContext(ctx); ok {
+		parent, _ := ctx.Value(lineageKey{}).(*lineage)
+		ctx = context.WithValue(ctx, lineageKey{}, &lineage{id: current, parent: parent})
+	}
+	return kuid.ContextWithRequestID(ctx, id)
+}
+
+// NewChild generates a correlation ID and attaches it to ctx with
+// WithValue, making the current ID its parent
+func NewChild(ctx context.Context) (context.Context, *kuid.KUID, error) {
+	id, err := kuid.NewKUID()
+	if err != nil {
+		return ctx, nil, err
+	}
+	return WithValue(ctx, id), id, nil
+}
+
+// Parent returns the ID that the current correlation ID replaced
+func Parent(ctx context.Context) (*kuid.KUID, bool) {
+	l, _ := ctx.Value(lineageKey{}).(*lineage)
+	if l == nil {
+		return nil, false
+	}
+	return l.id, true
+}
+
+// Lineage returns the correlation IDs of ctx from the root of its lineage
+// to the current ID
+func Lineage(ctx context.Context) []*kuid.KUID {
+	current, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	ids := []*kuid.KUID{current}
+	for l, _ := ctx.Value(lineageKey{}).(*lineage); l != nil; l = l.parent {
+		ids = append(ids, l.id)
+	}
+	slices.Reverse(ids)
+	return ids
+}
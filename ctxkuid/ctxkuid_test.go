@@ -0,0 +1,75 @@
+package ctxkuid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestEnsure(t *testing.T) {
+	ctx, id, err := Ensure(context.Background())
+	if err != nil {
+		t.Fatalf("Ensure() error = %v", err)
+	}
+	if got, ok := FromContext(ctx); !ok || !got.Equal(id) {
+		t.Errorf("FromContext() = %v, %v, want %v", got, ok, id)
+	}
+
+	// An existing ID is kept
+	again, same, _ := Ensure(ctx)
+	if again != ctx || !same.Equal(id) {
+		t.Errorf("Ensure() replaced existing ID %v with %v", id, same)
+	}
+}
+
+func TestSharedWithRequestID(t *testing.T) {
+	id, _ := kuid.NewKUID()
+	if got, ok := FromContext(kuid.ContextWithRequestID(context.Background(), id)); !ok || !got.Equal(id) {
+		t.Errorf("FromContext() does not see kuid.ContextWithRequestID")
+	}
+	if got, ok := kuid.RequestIDFromContext(NewContext(context.Background(), id)); !ok || !got.Equal(id) {
+		t.Errorf("kuid.RequestIDFromContext() does not see NewContext")
+	}
+}
+
+func TestLineage(t *testing.T) {
+	if ids := Lineage(context.Background()); ids != nil {
+		t.Errorf("Lineage() of empty context = %v", ids)
+	}
+
+	root, _ := kuid.NewKUID()
+	ctx := NewContext(context.Background(), root)
+	if _, ok := Parent(ctx); ok {
+		t.Errorf("Parent() of root ID found")
+	}
+
+	ctx, child, _ := NewChild(ctx)
+	grandchild, _ := kuid.NewKUID()
+	ctx = WithValue(ctx, grandchild)
+
+	if parent, ok := Parent(ctx); !ok || !parent.Equal(child) {
+		t.Errorf("Parent() = %v, %v, want %v", parent, ok, child)
+	}
+	ids := Lineage(ctx)
+	if len(ids) != 3 || !ids[0].Equal(root) || !ids[1].Equal(child) || !ids[2].Equal(grandchild) {
+		t.Errorf("Lineage() = %v, want [%v %v %v]", ids, root, child, grandchild)
+	}
+
+	// NewContext starts over
+	fresh, _ := kuid.NewKUID()
+	if ids := Lineage(NewContext(ctx, fresh)); len(ids) != 1 || !ids[0].Equal(fresh) {
+		t.Errorf("Lineage() after NewContext = %v, want [%v]", ids, fresh)
+	}
+}
+
+func TestWithValueWithoutCurrent(t *testing.T) {
+	id, _ := kuid.NewKUID()
+	ctx := WithValue(context.Background(), id)
+	if _, ok := Parent(ctx); ok {
+		t.Errorf("Parent() found without a replaced ID")
+	}
+	if ids := Lineage(ctx); len(ids) != 1 {
+		t.Errorf("Lineage() = %v, want just %v", ids, id)
+	}
+}
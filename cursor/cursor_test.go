@@ -0,0 +1,101 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestRoundTrip(t *testing.T) {
+	cd, err := NewCodec(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := kuid.NewValue()
+
+	tests := []Cursor{
+		{ID: id},
+		{ID: id, Direction: Descending},
+		{ID: id, SortKey: "2026-10-17T09:30:00Z"},
+		{ID: id, SortKey: strings.Repeat("k", MaxSortKey), Direction: Descending},
+	}
+	for _, c := range tests {
+		s, err := cd.Encode(c)
+		if err != nil {
+			t.Fatalf("Encode(%+v) error = %v", c, err)
+		}
+		if strings.ContainsAny(s, "+/=") {
+			t.Errorf("Encode(%+v) = %q, not URL-safe", c, s)
+		}
+		if err := cd.Validate(s); err != nil {
+			t.Errorf("Validate(%q) error = %v", s, err)
+		}
+		got, err := cd.Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", s, err)
+		}
+		if got != c {
+			t.Errorf("Parse(Encode(%+v)) = %+v", c, got)
+		}
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	cd, _ := NewCodec(testKey)
+	if _, err := cd.Encode(Cursor{SortKey: strings.Repeat("k", MaxSortKey+1)}); !errors.Is(err, ErrSortKey) {
+		t.Errorf("Encode() long sort key error = %v, want ErrSortKey", err)
+	}
+	if _, err := cd.Encode(Cursor{Direction: 2}); err == nil {
+		t.Error("Encode() accepted an invalid direction")
+	}
+	if _, err := NewCodec([]byte("short")); err == nil {
+		t.Error("NewCodec() accepted a short key")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	cd, _ := NewCodec(testKey)
+	other, _ := NewCodec([]byte("fedcba9876543210fedcba9876543210"))
+	id, _ := kuid.NewValue()
+	valid, _ := cd.Encode(Cursor{ID: id, SortKey: "42"})
+	foreign, _ := other.Encode(Cursor{ID: id, SortKey: "42"})
+
+	raw, _ := base64.RawURLEncoding.DecodeString(valid)
+	raw[3] ^= 1
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"empty", "", ErrMalformed},
+		{"not base64", "!!!!", ErrMalformed},
+		{"padded", valid + "=", ErrMalformed},
+		{"short", valid[:20], ErrMalformed},
+		{"too long", strings.Repeat("A", 1000), ErrMalformed},
+		{"tampered", tampered, ErrSignature},
+		{"other key", foreign, ErrSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := cd.Parse(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+			if err := cd.Validate(tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectionString(t *testing.T) {
+	if Ascending.String() != "asc" || Descending.String() != "desc" || Direction(5).String() != "Direction(5)" {
+		t.Error("unexpected Direction strings")
+	}
+}
@@ -0,0 +1,153 @@
+//go:build !tinygo
+
+package cursor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/sqlgen"
+)
+
+// Keyset pagination over (sort column, ID) selects the rows strictly past
+// the cursor row:
+//
+//	sort > key OR (sort = key AND id > last)
+//
+// with < for descending listings, and ORDER BY both columns in the same
+// direction. The ID breaks ties between rows sharing a sort value, so no
+// row is skipped or repeated across pages. An index on (sort, id) serves
+// both the filter and the order.
+//
+// KUIDs compare correctly as PostgreSQL uuid, BINARY(16) and BLOB values.
+// Base62 CHAR(22) columns need a binary collation such as COLLATE "C" or
+// utf8mb4_bin, since case-insensitive collations misorder them.
+
+// Predicate is a comparison of a column with a bound value
+type Predicate struct {
+	Column string
+	Op     string // "<", ">" or "="
+	Value  any
+}
+
+// Condition is a disjunction of conjunctions: it holds when every
+// predicate of any one of its groups holds
+type Condition [][]Predicate
+
+// Keyset describes the columns a listing is ordered by
+type Keyset struct {
+	// IDColumn holds the KUID tie-breaker
+	IDColumn string
+	// SortColumn is the primary sort column, or empty when listings sort
+	// by ID alone
+	SortColumn string
+	// IDValue converts the cursor's KUID into a query argument; it
+	// defaults to kuid.SQLUUID, suiting PostgreSQL uuid columns
+	IDValue func(kuid.KUID) any
+	// SortValue converts the cursor's sort key into a query argument, such
+	// as parsing a timestamp; by default the string is bound as is
+	SortValue func(string) (any, error)
+}
+
+// After returns the condition selecting the rows after c in its direction,
+// for the next page
+func (k *Keyset) After(c Cursor) (Condition, error) {
+	op := ">"
+	if c.Direction == Descending {
+		op = "<"
+	}
+	return k.condition(c, op)
+}
+
+// Before returns the condition selecting the rows before c in its
+// direction, for the previous page. Query them in the opposite order and
+// reverse the results.
+func (k *Keyset) Before(c Cursor) (Condition, error) {
+	op := "<"
+	if c.Direction == Descending {
+		op = ">"
+	}
+	return k.condition(c, op)
+}
+
+func (k *Keyset) condition(c Cursor, op string) (Condition, error) {
+	if k.IDColumn == "" {
+		return nil, errors.New("keyset has no ID column")
+	}
+	id := k.idValue(c.ID)
+	if k.SortColumn == "" {
+		return Condition{{{k.IDColumn, op, id}}}, nil
+	}
+
+	var sortKey any = c.SortKey
+	if k.SortValue != nil {
+		var err error
+		if sortKey, err = k.SortValue(c.SortKey); err != nil {
+			return nil, fmt.Errorf("cursor sort key: %w", err)
+		}
+	}
+	return Condition{
+		{{k.SortColumn, op, sortKey}},
+		{{k.SortColumn, "=", sortKey}, {k.IDColumn, op, id}},
+	}, nil
+}
+
+func (k *Keyset) idValue(id kuid.KUID) any {
+	if k.IDValue == nil {
+		return kuid.SQLUUID{KUID: id}
+	}
+	return k.IDValue(id)
+}
+
+// OrderBy returns the ORDER BY list for listings sorted in direction d
+func (k *Keyset) OrderBy(d Direction) string {
+	dir := " ASC"
+	if d == Descending {
+		dir = " DESC"
+	}
+	if k.SortColumn == "" {
+		return k.IDColumn + dir
+	}
+	return k.SortColumn + dir + ", " + k.IDColumn + dir
+}
+
+// SQL renders the condition for dialect d with placeholders numbered from
+// next in PostgreSQL, returning the expression and its arguments
+func (c Condition) SQL(d sqlgen.Dialect, next int) (string, []any, error) {
+	if len(c) == 0 {
+		return "", nil, errors.New("empty condition")
+	}
+	var (
+		b    strings.Builder
+		args []any
+	)
+	b.WriteByte('(')
+	for i, group := range c {
+		if i > 0 {
+			b.WriteString(" OR ")
+		}
+		if len(c) > 1 && len(group) > 1 {
+			b.WriteByte('(')
+		}
+		for j, p := range group {
+			if !sqlgen.ValidIdentifier(p.Column) {
+				return "", nil, fmt.Errorf("invalid column %q", p.Column)
+			}
+			if p.Op != "<" && p.Op != ">" && p.Op != "=" {
+				return "", nil, fmt.Errorf("invalid operator %q", p.Op)
+			}
+			if j > 0 {
+				b.WriteString(" AND ")
+			}
+			b.WriteString(p.Column + " " + p.Op + " " + sqlgen.Placeholders(d, 1, next+len(args)))
+			args = append(args, p.Value)
+		}
+		if len(c) > 1 && len(group) > 1 {
+			b.WriteByte(')')
+		}
+	}
+	b.WriteByte(')')
+	return b.String(), args, nil
+}
@@ -0,0 +1,117 @@
+//go:build !tinygo
+
+package cursor
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/sqlgen"
+)
+
+func TestKeysetSQL(t *testing.T) {
+	id, _ := kuid.NewValue()
+	byID := &Keyset{IDColumn: "id"}
+	bySort := &Keyset{IDColumn: "o.id", SortColumn: "o.total"}
+
+	tests := []struct {
+		name   string
+		k      *Keyset
+		before bool
+		c      Cursor
+		d      sqlgen.Dialect
+		want   string
+	}{
+		{"id asc", byID, false, Cursor{ID: id}, sqlgen.Postgres, "(id > $3)"},
+		{"id desc", byID, false, Cursor{ID: id, Direction: Descending}, sqlgen.MySQL, "(id < ?)"},
+		{"id asc before", byID, true, Cursor{ID: id}, sqlgen.Postgres, "(id < $3)"},
+		{"sort asc", bySort, false, Cursor{ID: id, SortKey: "10"}, sqlgen.Postgres,
+			"(o.total > $3 OR (o.total = $4 AND o.id > $5))"},
+		{"sort desc", bySort, false, Cursor{ID: id, SortKey: "10", Direction: Descending}, sqlgen.SQLite,
+			"(o.total < ? OR (o.total = ? AND o.id < ?))"},
+		{"sort desc before", bySort, true, Cursor{ID: id, SortKey: "10", Direction: Descending}, sqlgen.Postgres,
+			"(o.total > $3 OR (o.total = $4 AND o.id > $5))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := tt.k.After(tt.c)
+			if tt.before {
+				cond, err = tt.k.Before(tt.c)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, args, err := cond.SQL(tt.d, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("SQL() = %q, want %q", got, tt.want)
+			}
+			last := args[len(args)-1].(kuid.SQLUUID)
+			if last.KUID != id {
+				t.Errorf("ID argument = %v, want %v", last, id)
+			}
+			if len(args) == 3 && (args[0] != "10" || args[1] != "10") {
+				t.Errorf("sort arguments = %v, want 10", args[:2])
+			}
+		})
+	}
+}
+
+func TestKeysetValues(t *testing.T) {
+	id, _ := kuid.NewValue()
+	k := &Keyset{
+		IDColumn:   "id",
+		SortColumn: "score",
+		IDValue:    func(id kuid.KUID) any { return kuid.SQLBytes{KUID: id} },
+		SortValue:  func(s string) (any, error) { return strconv.ParseInt(s, 10, 64) },
+	}
+	cond, err := k.After(Cursor{ID: id, SortKey: "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cond[0][0].Value != int64(42) || cond[1][1].Value.(kuid.SQLBytes).KUID != id {
+		t.Errorf("After() = %+v", cond)
+	}
+
+	if _, err := k.After(Cursor{ID: id, SortKey: "x"}); err == nil {
+		t.Error("After() accepted a sort key SortValue rejects")
+	}
+	if _, err := (&Keyset{}).After(Cursor{ID: id}); err == nil {
+		t.Error("After() accepted a keyset without ID column")
+	}
+}
+
+func TestConditionSQLInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Condition
+	}{
+		{"empty", nil},
+		{"column", Condition{{{"id; --", ">", 1}}}},
+		{"operator", Condition{{{"id", "<>", 1}}}},
+	}
+	for _, tt := range tests {
+		if _, _, err := tt.c.SQL(sqlgen.Postgres, 1); err == nil {
+			t.Errorf("%s: SQL() succeeded", tt.name)
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	tests := []struct {
+		k    Keyset
+		d    Direction
+		want string
+	}{
+		{Keyset{IDColumn: "id"}, Ascending, "id ASC"},
+		{Keyset{IDColumn: "id", SortColumn: "created_at"}, Descending, "created_at DESC, id DESC"},
+	}
+	for _, tt := range tests {
+		if got := tt.k.OrderBy(tt.d); got != tt.want {
+			t.Errorf("OrderBy(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
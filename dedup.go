@@ -0,0 +1,104 @@
+package kuid
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	dedupShards     = 64
+	dedupSweepEvery = 256 // inserts per shard between expiry sweeps
+)
+
+// Dedup remembers KUIDs for a fixed TTL, for rejecting replayed requests
+// keyed by idempotency key or message ID. Entries are spread over
+// independently locked shards so concurrent callers rarely contend.
+type Dedup struct {
+	ttl    time.Duration
+	now    func() time.Time
+	shards [dedupShards]dedupShard
+}
+
+type dedupShard struct {
+	mu      sync.Mutex
+	seen    map[KUID]time.Time // expiry per KUID
+	inserts int
+}
+
+// NewDedup creates a Dedup remembering KUIDs for ttl
+func NewDedup(ttl time.Duration) *Dedup {
+	d := &Dedup{ttl: ttl, now: time.Now}
+	for i := range d.shards {
+		d.shards[i].seen = make(map[KUID]time.Time)
+	}
+	return d
+}
+
+// Seen reports whether k was already seen within the TTL. If not, k is
+// recorded and false is returned, so exactly one of several concurrent
+// callers with the same KUID gets false.
+func (d *Dedup) Seen(k *KUID) bool {
+	s := d.shard(k)
+	now := d.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expires, ok := s.seen[*k]; ok && now.Before(expires) {
+		return true
+	}
+	s.seen[*k] = now.Add(d.ttl)
+
+	if s.inserts++; s.inserts%dedupSweepEvery == 0 {
+		s.sweep(now)
+	}
+	return false
+}
+
+// Forget removes k, for example when the request it guarded failed and may
+// be retried
+func (d *Dedup) Forget(k *KUID) {
+	s := d.shard(k)
+	s.mu.Lock()
+	delete(s.seen, *k)
+	s.mu.Unlock()
+}
+
+// Len returns the number of remembered KUIDs, including expired ones not
+// yet swept
+func (d *Dedup) Len() int {
+	n := 0
+	for i := range d.shards {
+		s := &d.shards[i]
+		s.mu.Lock()
+		n += len(s.seen)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// Sweep removes all expired entries. Entries are also swept incrementally
+// as new KUIDs are recorded, so calling Sweep is optional.
+func (d *Dedup) Sweep() {
+	now := d.now()
+	for i := range d.shards {
+		s := &d.shards[i]
+		s.mu.Lock()
+		s.sweep(now)
+		s.mu.Unlock()
+	}
+}
+
+// shard returns the shard holding k, chosen by hash64
+func (d *Dedup) shard(k *KUID) *dedupShard {
+	return &d.shards[hash64(k)%dedupShards]
+}
+
+// sweep removes expired entries. Callers must hold s.mu.
+func (s *dedupShard) sweep(now time.Time) {
+	for k, expires := range s.seen {
+		if !now.Before(expires) {
+			delete(s.seen, k)
+		}
+	}
+}
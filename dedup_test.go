@@ -0,0 +1,108 @@
+package kuid
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	d := NewDedup(time.Minute)
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	id, _ := NewKUID()
+	if d.Seen(id) {
+		t.Errorf("Seen() = true on first call")
+	}
+	copied, _ := FromString(id.String())
+	if !d.Seen(copied) {
+		t.Errorf("Seen() = false for repeated KUID")
+	}
+
+	d.Forget(id)
+	if d.Seen(id) {
+		t.Errorf("Seen() = true after Forget")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if d.Seen(id) {
+		t.Errorf("Seen() = true after TTL expired")
+	}
+}
+
+// fixedLowBits returns n typed and v6 KUIDs, whose low bits are all the
+// same, for checking that sharded structures still spread them out
+func fixedLowBits(t *testing.T, n int) []*KUID {
+	t.Helper()
+	types, _ := NewTypeRegistry(16)
+	types.Register("order", 0x1234)
+	typed, err := NewGenerator(WithType(types, "order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v6, _ := NewV1Generator(nil)
+	ids := make([]*KUID, n)
+	for i := range ids {
+		if i%2 == 0 {
+			ids[i], _ = typed.New()
+		} else {
+			ids[i] = v6.NewV6()
+		}
+	}
+	return ids
+}
+
+func TestDedupShardSpread(t *testing.T) {
+	d := NewDedup(time.Minute)
+	used := make(map[*dedupShard]bool)
+	for _, id := range fixedLowBits(t, 1000) {
+		used[d.shard(id)] = true
+	}
+	if len(used) < dedupShards/2 {
+		t.Errorf("1000 typed and v6 KUIDs used %d of %d shards", len(used), dedupShards)
+	}
+}
+
+func TestDedupSweep(t *testing.T) {
+	d := NewDedup(time.Second)
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		id, _ := NewKUID()
+		d.Seen(id)
+	}
+	if d.Len() != 100 {
+		t.Fatalf("Len() = %d, want 100", d.Len())
+	}
+
+	now = now.Add(time.Minute)
+	d.Sweep()
+	if d.Len() != 0 {
+		t.Errorf("Len() = %d after Sweep, want 0", d.Len())
+	}
+}
+
+func TestDedupConcurrent(t *testing.T) {
+	d := NewDedup(time.Minute)
+	id, _ := NewKUID()
+
+	var first atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !d.Seen(id) {
+				first.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if first.Load() != 1 {
+		t.Errorf("%d callers saw the KUID first, want 1", first.Load())
+	}
+}
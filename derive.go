@@ -0,0 +1,81 @@
+package kuid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const minDeriveKey = 16
+
+// Child KUIDs carry a 96-bit keyed hash of their parent and index followed
+// by the 32-bit index:
+//
+//	msb: 64 bits of HMAC-SHA256(key, parent | index)
+//	lsb: next 32 bits of the HMAC | index
+//
+// so VerifyLineage needs only the parent and child, and ChildIndex recovers
+// the index. Children sort by hash, not by index.
+
+// Deriver derives child KUIDs that only holders of its key can produce or
+// verify. Use it when sub-resource IDs are exposed to clients that must
+// not be able to forge a child of someone else's parent.
+type Deriver struct {
+	key []byte
+}
+
+// unkeyed backs the package-level DeriveChild and VerifyLineage
+var unkeyed = &Deriver{key: []byte("kuid derive child")}
+
+// NewDeriver creates a Deriver. The key must be at least 16 bytes and kept
+// secret; changing it changes every child ID.
+func NewDeriver(key []byte) (*Deriver, error) {
+	if len(key) < minDeriveKey {
+		return nil, errors.New("deriver key must be at least 16 bytes")
+	}
+	return &Deriver{key: append([]byte(nil), key...)}, nil
+}
+
+// DeriveChild returns the index'th child of parent, such as the line items
+// of an order. The same parent and index always yield the same child. The
+// hash is keyed with a fixed public key, so anyone can derive and verify
+// children; use a Deriver when that must stay private.
+func DeriveChild(parent KUID, index uint32) KUID {
+	return unkeyed.DeriveChild(parent, index)
+}
+
+// VerifyLineage reports whether child was derived from parent with
+// DeriveChild
+func VerifyLineage(parent, child KUID) bool {
+	return unkeyed.VerifyLineage(parent, child)
+}
+
+// ChildIndex returns the index a child KUID was derived with. It is only
+// meaningful once VerifyLineage has confirmed the child.
+func ChildIndex(child KUID) uint32 {
+	return uint32(child.lsb)
+}
+
+// DeriveChild returns the index'th child of parent under d's key
+func (d *Deriver) DeriveChild(parent KUID, index uint32) KUID {
+	var buf [20]byte
+	binary.BigEndian.PutUint64(buf[0:8], parent.msb)
+	binary.BigEndian.PutUint64(buf[8:16], parent.lsb)
+	binary.BigEndian.PutUint32(buf[16:20], index)
+
+	mac := hmac.New(sha256.New, d.key)
+	mac.Write(buf[:])
+	sum := mac.Sum(nil)
+	return KUID{
+		msb: binary.BigEndian.Uint64(sum[0:8]),
+		lsb: uint64(binary.BigEndian.Uint32(sum[8:12]))<<32 | uint64(index),
+	}
+}
+
+// VerifyLineage reports whether child was derived from parent under d's key
+func (d *Deriver) VerifyLineage(parent, child KUID) bool {
+	want := d.DeriveChild(parent, ChildIndex(child))
+	return subtle.ConstantTimeCompare(want.Bytes(), child.Bytes()) == 1
+}
@@ -0,0 +1,62 @@
+package kuid
+
+import "testing"
+
+func TestDeriveChild(t *testing.T) {
+	order, _ := NewValue()
+	other, _ := NewValue()
+
+	first := DeriveChild(order, 0)
+	if again := DeriveChild(order, 0); again != first {
+		t.Errorf("DeriveChild() not deterministic: %v != %v", first, again)
+	}
+
+	seen := make(map[KUID]bool)
+	for i := uint32(0); i < 100; i++ {
+		child := DeriveChild(order, i)
+		if seen[child] {
+			t.Fatalf("DeriveChild(%d) repeats an earlier child", i)
+		}
+		seen[child] = true
+		if ChildIndex(child) != i {
+			t.Errorf("ChildIndex() = %d, want %d", ChildIndex(child), i)
+		}
+		if !VerifyLineage(order, child) {
+			t.Errorf("VerifyLineage() rejects child %d", i)
+		}
+		if VerifyLineage(other, child) {
+			t.Errorf("VerifyLineage() accepts child %d of another parent", i)
+		}
+	}
+
+	// Changing the index of a valid child does not make another valid child
+	forged := first
+	forged.lsb ^= 1
+	if VerifyLineage(order, forged) {
+		t.Errorf("VerifyLineage() accepts child with altered index")
+	}
+	if VerifyLineage(order, other) {
+		t.Errorf("VerifyLineage() accepts unrelated KUID")
+	}
+}
+
+func TestDeriver(t *testing.T) {
+	if _, err := NewDeriver([]byte("short")); err == nil {
+		t.Errorf("NewDeriver() accepted short key")
+	}
+
+	a, _ := NewDeriver([]byte("0123456789abcdef"))
+	b, _ := NewDeriver([]byte("fedcba9876543210"))
+	parent, _ := NewValue()
+
+	child := a.DeriveChild(parent, 7)
+	if !a.VerifyLineage(parent, child) {
+		t.Errorf("VerifyLineage() rejects own child")
+	}
+	if b.VerifyLineage(parent, child) || VerifyLineage(parent, child) {
+		t.Errorf("child verifies under another key")
+	}
+	if child == b.DeriveChild(parent, 7) || child == DeriveChild(parent, 7) {
+		t.Errorf("keys derive the same child")
+	}
+}
@@ -0,0 +1,80 @@
+package kuid
+
+import (
+	"math/bits"
+)
+
+const (
+	dnsChars  = "0123456789abcdefghijklmnopqrstuvwxyz"
+	dnsBase   = uint64(len(dnsChars))
+	dnsPrefix = 'k'
+	dnsDigits = 25 // base36 digits needed for 128 bits
+	dnsSize   = dnsDigits + 1
+)
+
+// DNSLabel returns a lowercase base36 representation of the KUID that is a
+// valid DNS-1123 label: lowercase alphanumerics only, starting with a letter
+// and 26 characters long. It can be used directly as a Kubernetes resource
+// name or S3 bucket name and converted back with FromDNSLabel.
+func (k *KUID) DNSLabel() string {
+	out := make([]byte, dnsSize)
+	out[0] = dnsPrefix
+
+	hi, lo := k.msb, k.lsb
+	for i := dnsSize - 1; i > 0; i-- {
+		var r uint64
+		hi, r = hi/dnsBase, hi%dnsBase
+		lo, r = bits.Div64(r, lo, dnsBase)
+		out[i] = dnsChars[r]
+	}
+	return string(out)
+}
+
+// FromDNSLabel creates a KUID from its DNS-1123 label representation
+func FromDNSLabel(s string) (*KUID, error) {
+	if len(s) != dnsSize {
+		return nil, ErrInvalidLength
+	}
+	if s[0] != dnsPrefix {
+		return nil, ErrInvalidChar
+	}
+
+	var hi, lo uint64
+	for i := 1; i < len(s); i++ {
+		digit := dnsDigit(s[i])
+		if digit < 0 {
+			return nil, ErrInvalidChar
+		}
+
+		// (hi, lo) = (hi, lo) * 36 + digit, rejecting anything above 128 bits
+		carry, newLo := bits.Mul64(lo, dnsBase)
+		overflow, newHi := bits.Mul64(hi, dnsBase)
+		if overflow != 0 {
+			return nil, ErrOverflow
+		}
+		newHi, c := bits.Add64(newHi, carry, 0)
+		if c != 0 {
+			return nil, ErrOverflow
+		}
+		newLo, c = bits.Add64(newLo, uint64(digit), 0)
+		newHi, c = bits.Add64(newHi, 0, c)
+		if c != 0 {
+			return nil, ErrOverflow
+		}
+		hi, lo = newHi, newLo
+	}
+
+	return &KUID{msb: hi, lsb: lo}, nil
+}
+
+// dnsDigit returns the base36 value of c, or -1 if c is not a lowercase
+// alphanumeric character
+func dnsDigit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	}
+	return -1
+}
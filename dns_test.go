@@ -0,0 +1,127 @@
+package kuid
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var dns1123Label = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+func TestDNSLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		uuid string
+		want string
+	}{
+		{
+			name: "Zero UUID",
+			uuid: "00000000-0000-0000-0000-000000000000",
+			want: "k" + strings.Repeat("0", dnsDigits),
+		},
+		{
+			name: "Max UUID",
+			uuid: "ffffffff-ffff-ffff-ffff-ffffffffffff",
+			want: "kf5lxx1zz5pnorynqglhzmsp33",
+		},
+		{
+			name: "Random UUID",
+			uuid: "123e4567-e89b-12d3-a456-426614174000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kuid, err := FromUUID(tt.uuid)
+			if err != nil {
+				t.Fatalf("FromUUID() error = %v", err)
+			}
+
+			label := kuid.DNSLabel()
+			if tt.want != "" && label != tt.want {
+				t.Errorf("DNSLabel() = %v, want %v", label, tt.want)
+			}
+			if len(label) > 63 || !dns1123Label.MatchString(label) {
+				t.Errorf("DNSLabel() = %v is not a valid DNS-1123 label", label)
+			}
+
+			decoded, err := FromDNSLabel(label)
+			if err != nil {
+				t.Fatalf("FromDNSLabel() error = %v", err)
+			}
+			if !kuid.Equal(decoded) {
+				t.Errorf("Roundtrip failed: got %v, want %v", decoded.ToUUID(), tt.uuid)
+			}
+		})
+	}
+}
+
+func TestDNSLabelRandom(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		kuid, err := NewKUID()
+		if err != nil {
+			t.Fatalf("Failed to generate KUID: %v", err)
+		}
+
+		label := kuid.DNSLabel()
+		if !dns1123Label.MatchString(label) {
+			t.Fatalf("DNSLabel() = %v is not a valid DNS-1123 label", label)
+		}
+
+		decoded, err := FromDNSLabel(label)
+		if err != nil {
+			t.Fatalf("FromDNSLabel() error = %v", err)
+		}
+		if !kuid.Equal(decoded) {
+			t.Fatalf("Roundtrip failed for %v", label)
+		}
+	}
+}
+
+func TestFromDNSLabelInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   string
+		wantErr error
+	}{
+		{
+			name:    "Empty string",
+			label:   "",
+			wantErr: ErrInvalidLength,
+		},
+		{
+			name:    "Missing prefix",
+			label:   strings.Repeat("0", dnsSize),
+			wantErr: ErrInvalidChar,
+		},
+		{
+			name:    "Uppercase characters",
+			label:   "k" + strings.Repeat("A", dnsDigits),
+			wantErr: ErrInvalidChar,
+		},
+		{
+			name:    "Hyphen",
+			label:   "k" + strings.Repeat("0", dnsDigits-1) + "-",
+			wantErr: ErrInvalidChar,
+		},
+		{
+			name:    "Overflow",
+			label:   "k" + strings.Repeat("z", dnsDigits),
+			wantErr: ErrOverflow,
+		},
+		{
+			name:    "Just above max",
+			label:   "kf5lxx1zz5pnorynqglhzmsp34",
+			wantErr: ErrOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromDNSLabel(tt.label)
+			if err != tt.wantErr {
+				t.Errorf("FromDNSLabel() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package kuid
+
+import "errors"
+
+var ErrInvalidEdgeKey = errors.New("invalid edge key")
+
+const (
+	directedSep   = '>'
+	undirectedSep = '-'
+)
+
+// EdgeKey identifies an edge between two KUID nodes in a graph store.
+// Undirected keys are normalized so From sorts before To, and every
+// service computes the same key for an edge whichever end it starts from.
+//
+// The string form joins the endpoints with ">" for directed edges and "-"
+// for undirected ones:
+//
+//	6f1Kx0aN2bXwGmTqYc0LzR>0Qm3bHkT9xJd8WvNcE2PaL
+//
+// so keys of a node's outgoing edges share its 23-character prefix and can
+// be range scanned.
+type EdgeKey struct {
+	From     KUID
+	To       KUID
+	Directed bool
+}
+
+// NewEdgeKey returns the key of the edge from one node to another
+func NewEdgeKey(from, to KUID, directed bool) EdgeKey {
+	if !directed && (from.msb > to.msb || from.msb == to.msb && from.lsb > to.lsb) {
+		from, to = to, from
+	}
+	return EdgeKey{From: from, To: to, Directed: directed}
+}
+
+// ParseEdgeKey parses the string form of an EdgeKey, rejecting undirected
+// keys whose endpoints are not in order
+func ParseEdgeKey(s string) (EdgeKey, error) {
+	if len(s) != 2*size*2+1 {
+		return EdgeKey{}, ErrInvalidEdgeKey
+	}
+	from, err := ParseValue(s[:size*2])
+	if err != nil {
+		return EdgeKey{}, ErrInvalidEdgeKey
+	}
+	to, err := ParseValue(s[size*2+1:])
+	if err != nil {
+		return EdgeKey{}, ErrInvalidEdgeKey
+	}
+
+	var e EdgeKey
+	switch s[size*2] {
+	case directedSep:
+		e = NewEdgeKey(from, to, true)
+	case undirectedSep:
+		e = NewEdgeKey(from, to, false)
+		if e.From != from {
+			return EdgeKey{}, ErrInvalidEdgeKey
+		}
+	default:
+		return EdgeKey{}, ErrInvalidEdgeKey
+	}
+	return e, nil
+}
+
+// String returns the string form of the key
+func (e EdgeKey) String() string {
+	sep := byte(undirectedSep)
+	if e.Directed {
+		sep = directedSep
+	}
+	b := make([]byte, 0, 2*size*2+1)
+	b = e.From.AppendString(b)
+	b = append(b, sep)
+	return string(e.To.AppendString(b))
+}
+
+// ID returns the edge's KUID, the Combine of its endpoints
+func (e EdgeKey) ID() KUID {
+	if e.Directed {
+		return Combine(e.From, e.To, Ordered)
+	}
+	return Combine(e.From, e.To, Commutative)
+}
+
+// Reverse returns the key of the edge in the other direction. Undirected
+// keys are their own reverse.
+func (e EdgeKey) Reverse() EdgeKey {
+	return NewEdgeKey(e.To, e.From, e.Directed)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (e EdgeKey) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (e *EdgeKey) UnmarshalText(text []byte) error {
+	parsed, err := ParseEdgeKey(string(text))
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
@@ -0,0 +1,94 @@
+package kuid
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEdgeKey(t *testing.T) {
+	a := KUID{msb: 1, lsb: 2}
+	b := KUID{msb: 3, lsb: 4}
+
+	tests := []struct {
+		name    string
+		key     EdgeKey
+		reverse EdgeKey
+		sep     string
+	}{
+		{
+			name:    "Directed",
+			key:     NewEdgeKey(b, a, true),
+			reverse: EdgeKey{From: a, To: b, Directed: true},
+			sep:     ">",
+		},
+		{
+			name:    "Undirected",
+			key:     NewEdgeKey(b, a, false),
+			reverse: EdgeKey{From: a, To: b},
+			sep:     "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.Reverse(); got != tt.reverse {
+				t.Errorf("Reverse() = %v, want %v", got, tt.reverse)
+			}
+
+			s := tt.key.String()
+			if !strings.Contains(s, tt.sep) {
+				t.Errorf("String() = %q, want separator %q", s, tt.sep)
+			}
+			parsed, err := ParseEdgeKey(s)
+			if err != nil || parsed != tt.key {
+				t.Errorf("ParseEdgeKey(%q) = %v, %v, want %v", s, parsed, err, tt.key)
+			}
+			if parsed.ID() != tt.key.ID() {
+				t.Errorf("ID() changed through parsing")
+			}
+		})
+	}
+
+	// Undirected keys are the same from either end
+	if NewEdgeKey(a, b, false) != NewEdgeKey(b, a, false) {
+		t.Errorf("undirected keys differ by endpoint order")
+	}
+	if NewEdgeKey(a, b, true).ID() == NewEdgeKey(b, a, true).ID() {
+		t.Errorf("directed IDs ignore direction")
+	}
+}
+
+func TestParseEdgeKeyInvalid(t *testing.T) {
+	a := KUID{msb: 1, lsb: 2}
+	b := KUID{msb: 3, lsb: 4}
+	for _, s := range []string{
+		"",
+		a.String() + ">",
+		a.String() + "+" + b.String(),
+		b.String() + "-" + a.String(), // undirected keys must be in order
+		a.String() + ">" + b.String()[:21] + "!",
+	} {
+		if _, err := ParseEdgeKey(s); err != ErrInvalidEdgeKey {
+			t.Errorf("ParseEdgeKey(%q) error = %v, want %v", s, err, ErrInvalidEdgeKey)
+		}
+	}
+}
+
+func TestEdgeKeyJSON(t *testing.T) {
+	from, _ := NewValue()
+	to, _ := NewValue()
+	want := map[string]EdgeKey{"edge": NewEdgeKey(from, to, true)}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]EdgeKey
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["edge"] != want["edge"] {
+		t.Errorf("round trip = %v, want %v", got["edge"], want["edge"])
+	}
+}
@@ -0,0 +1,74 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	mrand "math/rand/v2"
+)
+
+// Backend selects where a Generator draws its random bits from
+type Backend int
+
+const (
+	// BackendCrypto reads crypto/rand, or the Web Crypto API on js/wasm,
+	// for every KUID (default)
+	BackendCrypto Backend = iota
+	// BackendChaCha20 expands crypto/rand seeds with a ChaCha20 keystream,
+	// reseeding periodically. It is cryptographically strong and avoids a
+	// system call per KUID.
+	BackendChaCha20
+	// BackendUnsafeMath uses math/rand/v2. It is predictable and only
+	// suitable for tests and simulations.
+	BackendUnsafeMath
+)
+
+// String returns the name of the backend
+func (b Backend) String() string {
+	switch b {
+	case BackendCrypto:
+		return "crypto"
+	case BackendChaCha20:
+		return "chacha20"
+	case BackendUnsafeMath:
+		return "unsafe-math"
+	}
+	return fmt.Sprintf("Backend(%d)", int(b))
+}
+
+// newEntropy creates the entropy source for a backend
+func newEntropy(b Backend) (entropy, error) {
+	switch b {
+	case BackendCrypto:
+		return cryptoEntropy{}, nil
+	case BackendChaCha20:
+		return &chachaEntropy{}, nil
+	case BackendUnsafeMath:
+		return mathEntropy{}, nil
+	}
+	return nil, fmt.Errorf("unknown entropy backend %v", b)
+}
+
+// entropy supplies the random bits of generated KUIDs
+type entropy interface {
+	uint128() (msb, lsb uint64, err error)
+}
+
+// cryptoEntropy reads from the system CSPRNG and is the default source
+type cryptoEntropy struct{}
+
+func (cryptoEntropy) uint128() (uint64, uint64, error) {
+	var buf [16]byte
+	if err := readRandom(buf[:]); err != nil {
+		return 0, 0, err
+	}
+	return binary.BigEndian.Uint64(buf[0:8]), binary.BigEndian.Uint64(buf[8:16]), nil
+}
+
+// mathEntropy uses the math/rand/v2 global source. It is fast but
+// predictable and must never be used for identifiers that need to be
+// unguessable.
+type mathEntropy struct{}
+
+func (mathEntropy) uint128() (uint64, uint64, error) {
+	return mrand.Uint64(), mrand.Uint64(), nil
+}
@@ -0,0 +1,110 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidEventID = errors.New("invalid event ID")
+
+const eventIDSize = size*2 + size
+
+// EventID identifies an event in an event store. ID is an ordered KUID
+// giving the event's place in the global order across all streams, and
+// Seq its position within its own stream, so consumers can check a
+// stream for gaps without a global lookup.
+//
+// EventIDs order by ID and then Seq. The string form is the KUID followed
+// by Seq in 11 base62 digits, and the binary form the 16 KUID bytes
+// followed by Seq big-endian; both sort the same way.
+type EventID struct {
+	ID  KUID
+	Seq uint64
+}
+
+// NewEventID returns an EventID for the event at position seq of its
+// stream, with a new ordered KUID
+func NewEventID(seq uint64) (EventID, error) {
+	return defaultGenerator.NewEventID(seq)
+}
+
+// NewEventID is like the package-level NewEventID but uses g
+func (g *Generator) NewEventID(seq uint64) (EventID, error) {
+	k, err := g.NewOrdered()
+	if err != nil {
+		return EventID{}, err
+	}
+	return EventID{ID: *k, Seq: seq}, nil
+}
+
+// Compare returns -1, 0 or +1 as e sorts before, with or after other
+func (e EventID) Compare(other EventID) int {
+	switch {
+	case e.ID.msb != other.ID.msb:
+		return cmpUint64(e.ID.msb, other.ID.msb)
+	case e.ID.lsb != other.ID.lsb:
+		return cmpUint64(e.ID.lsb, other.ID.lsb)
+	}
+	return cmpUint64(e.Seq, other.Seq)
+}
+
+// Less reports whether e sorts before other
+func (e EventID) Less(other EventID) bool {
+	return e.Compare(other) < 0
+}
+
+// String returns the 33-character string form
+func (e EventID) String() string {
+	return e.ID.String() + encodeLong(e.Seq)
+}
+
+// ParseEventID parses the string form of an EventID
+func ParseEventID(s string) (EventID, error) {
+	if len(s) != eventIDSize {
+		return EventID{}, ErrInvalidEventID
+	}
+	id, err := ParseValue(s[:size*2])
+	if err != nil {
+		return EventID{}, ErrInvalidEventID
+	}
+	seq, err := decodeLong(s[size*2:])
+	if err != nil {
+		return EventID{}, ErrInvalidEventID
+	}
+	return EventID{ID: id, Seq: seq}, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, so JSON carries the
+// string form
+func (e EventID) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (e *EventID) UnmarshalText(text []byte) error {
+	parsed, err := ParseEventID(string(text))
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the 24-byte form
+func (e EventID) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 24)
+	binary.BigEndian.PutUint64(b[0:8], e.ID.msb)
+	binary.BigEndian.PutUint64(b[8:16], e.ID.lsb)
+	binary.BigEndian.PutUint64(b[16:24], e.Seq)
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (e *EventID) UnmarshalBinary(b []byte) error {
+	if len(b) != 24 {
+		return ErrInvalidEventID
+	}
+	e.ID = KUID{msb: binary.BigEndian.Uint64(b[0:8]), lsb: binary.BigEndian.Uint64(b[8:16])}
+	e.Seq = binary.BigEndian.Uint64(b[16:24])
+	return nil
+}
@@ -0,0 +1,104 @@
+package kuid
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestEventIDOrder(t *testing.T) {
+	a := EventID{ID: KUID{msb: 1, lsb: 9}, Seq: 7}
+	tests := []struct {
+		b    EventID
+		want int
+	}{
+		{EventID{ID: KUID{msb: 1, lsb: 9}, Seq: 7}, 0},
+		{EventID{ID: KUID{msb: 1, lsb: 9}, Seq: 8}, -1},
+		{EventID{ID: KUID{msb: 1, lsb: 8}, Seq: 100}, 1},
+		{EventID{ID: KUID{msb: 2}, Seq: 0}, -1},
+	}
+	for _, tt := range tests {
+		if got := a.Compare(tt.b); got != tt.want {
+			t.Errorf("Compare(%v) = %d, want %d", tt.b, got, tt.want)
+		}
+		if got := a.Less(tt.b); got != (tt.want < 0) {
+			t.Errorf("Less(%v) = %v", tt.b, got)
+		}
+	}
+}
+
+func TestEventIDEncodingsSort(t *testing.T) {
+	ids := []EventID{
+		{ID: KUID{msb: 1, lsb: 9}, Seq: 61},
+		{ID: KUID{msb: 1, lsb: 9}, Seq: 62},
+		{ID: KUID{msb: 1, lsb: 10}, Seq: 0},
+		{ID: KUID{msb: ^uint64(0)}, Seq: ^uint64(0)},
+	}
+	for i := 1; i < len(ids); i++ {
+		a, b := ids[i-1], ids[i]
+		if !a.Less(b) || a.String() >= b.String() {
+			t.Errorf("%v and %v strings out of order", a, b)
+		}
+		ab, _ := a.MarshalBinary()
+		bb, _ := b.MarshalBinary()
+		if string(ab) >= string(bb) {
+			t.Errorf("%v and %v binary forms out of order", a, b)
+		}
+	}
+}
+
+func TestEventIDRoundTrip(t *testing.T) {
+	g, _ := NewGenerator()
+	e, err := g.NewEventID(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Seq != 42 || e.ID.Timestamp().IsZero() {
+		t.Errorf("NewEventID(42) = %v", e)
+	}
+
+	s := e.String()
+	if len(s) != 33 {
+		t.Errorf("String() = %q, want 33 characters", s)
+	}
+	if got, err := ParseEventID(s); err != nil || got != e {
+		t.Errorf("ParseEventID(%q) = %v, %v", s, got, err)
+	}
+
+	data, _ := json.Marshal(map[string]EventID{"id": e})
+	var decoded map[string]EventID
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["id"] != e {
+		t.Errorf("JSON round trip = %v, %v", decoded, err)
+	}
+
+	b, _ := e.MarshalBinary()
+	var fromBinary EventID
+	if err := fromBinary.UnmarshalBinary(b); err != nil || fromBinary != e {
+		t.Errorf("binary round trip = %v, %v", fromBinary, err)
+	}
+	if err := fromBinary.UnmarshalBinary(b[:23]); !errors.Is(err, ErrInvalidEventID) {
+		t.Errorf("UnmarshalBinary(23 bytes) error = %v", err)
+	}
+}
+
+func TestParseEventIDInvalid(t *testing.T) {
+	valid := EventID{ID: KUID{msb: 1}, Seq: 1}.String()
+	for _, s := range []string{"", valid[:32], valid + "0", valid[:22] + "zzzzzzzzzzz", "!" + valid[1:]} {
+		if _, err := ParseEventID(s); !errors.Is(err, ErrInvalidEventID) {
+			t.Errorf("ParseEventID(%q) error = %v, want ErrInvalidEventID", s, err)
+		}
+	}
+}
+
+func TestEventIDsSortable(t *testing.T) {
+	g, _ := NewGenerator()
+	var ids []EventID
+	for i := 0; i < 100; i++ {
+		e, _ := g.NewEventID(uint64(i))
+		ids = append(ids, e)
+	}
+	if !slices.IsSortedFunc(ids, EventID.Compare) {
+		t.Error("EventIDs from one generator are not in order")
+	}
+}
@@ -0,0 +1,112 @@
+package kuid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var (
+	ErrExpired          = errors.New("KUID has expired")
+	ErrInvalidSignature = errors.New("invalid KUID signature")
+)
+
+// ExpiringKUID is a KUID that carries its own expiry, for download links,
+// invite codes and other tokens that must stop working on their own. The
+// expiry takes the place of the timestamp in the ordered layout:
+//
+//	48-bit expiry in Unix milliseconds | 80 random bits
+//
+// Unsigned tokens are 22 characters. When created with a key, a 128-bit
+// HMAC-SHA256 tag is appended (44 characters in total) so the expiry cannot
+// be extended by editing the token.
+type ExpiringKUID struct {
+	id  KUID
+	tag *KUID // truncated HMAC, nil when unsigned
+}
+
+// NewExpiring creates a token expiring after ttl. If key is non-nil the
+// token is signed with it and must be parsed with the same key.
+func NewExpiring(ttl time.Duration, key []byte) (*ExpiringKUID, error) {
+	msb, lsb, err := defaultGenerator.read()
+	if err != nil {
+		return nil, err
+	}
+
+	expires := uint64(time.Now().Add(ttl).UnixMilli())
+	e := &ExpiringKUID{id: KUID{msb: expires<<16 | msb&0xFFFF, lsb: lsb}}
+	if key != nil {
+		e.tag = e.sign(key)
+	}
+	return e, nil
+}
+
+// ParseExpiring parses a token and rejects it if it has expired at now. A
+// non-nil key requires a valid signature; a nil key requires an unsigned
+// token.
+func ParseExpiring(s string, key []byte, now time.Time) (*ExpiringKUID, error) {
+	var e ExpiringKUID
+	switch {
+	case key == nil && len(s) == size*2:
+	case key != nil && len(s) == size*4:
+	default:
+		return nil, ErrInvalidLength
+	}
+
+	id, err := FromString(s[:size*2])
+	if err != nil {
+		return nil, err
+	}
+	e.id = *id
+
+	if key != nil {
+		tag, err := FromString(s[size*2:])
+		if err != nil {
+			return nil, err
+		}
+		want := e.sign(key)
+		if subtle.ConstantTimeCompare(tag.Bytes(), want.Bytes()) != 1 {
+			return nil, ErrInvalidSignature
+		}
+		e.tag = tag
+	}
+
+	if e.IsExpired(now) {
+		return nil, ErrExpired
+	}
+	return &e, nil
+}
+
+// KUID returns the identifier part of the token
+func (e *ExpiringKUID) KUID() *KUID {
+	id := e.id
+	return &id
+}
+
+// ExpiresAt returns the embedded expiry
+func (e *ExpiringKUID) ExpiresAt() time.Time {
+	return e.id.Timestamp()
+}
+
+// IsExpired reports whether the token has expired at now
+func (e *ExpiringKUID) IsExpired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt())
+}
+
+// String returns the token, including its signature if signed
+func (e *ExpiringKUID) String() string {
+	if e.tag == nil {
+		return e.id.String()
+	}
+	return e.id.String() + e.tag.String()
+}
+
+func (e *ExpiringKUID) sign(key []byte) *KUID {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(e.id.Bytes())
+	sum := mac.Sum(nil)
+	return &KUID{msb: binary.BigEndian.Uint64(sum[0:8]), lsb: binary.BigEndian.Uint64(sum[8:16])}
+}
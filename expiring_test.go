@@ -0,0 +1,71 @@
+package kuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiring(t *testing.T) {
+	key := []byte("secret key")
+
+	tests := []struct {
+		name    string
+		key     []byte
+		wantLen int
+	}{
+		{name: "Unsigned", key: nil, wantLen: size * 2},
+		{name: "Signed", key: key, wantLen: size * 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewExpiring(time.Hour, tt.key)
+			if err != nil {
+				t.Fatalf("NewExpiring() error = %v", err)
+			}
+			str := e.String()
+			if len(str) != tt.wantLen {
+				t.Errorf("String() length = %d, want %d", len(str), tt.wantLen)
+			}
+			if e.IsExpired(time.Now()) || !e.IsExpired(time.Now().Add(2*time.Hour)) {
+				t.Errorf("IsExpired() wrong for expiry %v", e.ExpiresAt())
+			}
+
+			parsed, err := ParseExpiring(str, tt.key, time.Now())
+			if err != nil {
+				t.Fatalf("ParseExpiring() error = %v", err)
+			}
+			if !parsed.KUID().Equal(e.KUID()) {
+				t.Errorf("ParseExpiring() = %v, want %v", parsed, e)
+			}
+
+			if _, err := ParseExpiring(str, tt.key, time.Now().Add(2*time.Hour)); err != ErrExpired {
+				t.Errorf("ParseExpiring() error = %v, want %v", err, ErrExpired)
+			}
+		})
+	}
+}
+
+func TestExpiringTampered(t *testing.T) {
+	key := []byte("secret key")
+	e, _ := NewExpiring(time.Minute, key)
+
+	// Push the expiry out by a year while keeping the old signature
+	extended := *e
+	extended.id.msb += uint64(365*24*time.Hour/time.Millisecond) << 16
+	if _, err := ParseExpiring(extended.String(), key, time.Now()); err != ErrInvalidSignature {
+		t.Errorf("ParseExpiring() error = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	if _, err := ParseExpiring(e.String(), []byte("other key"), time.Now()); err != ErrInvalidSignature {
+		t.Errorf("ParseExpiring() with wrong key error = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	// A signed token cannot be passed off as unsigned, or vice versa
+	if _, err := ParseExpiring(e.String(), nil, time.Now()); err != ErrInvalidLength {
+		t.Errorf("ParseExpiring() without key error = %v, want %v", err, ErrInvalidLength)
+	}
+	if _, err := ParseExpiring(e.KUID().String(), key, time.Now()); err != ErrInvalidLength {
+		t.Errorf("ParseExpiring() of unsigned token error = %v, want %v", err, ErrInvalidLength)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// expvarMu makes the check and publish in RegisterExpvar one step, since
+// expvar.Publish panics on a name already taken
+var expvarMu sync.Mutex
+
+// RegisterExpvar publishes the Generator's Stats and Health as the expvar
+// variable name, so they appear on /debug/vars alongside the runtime's own
+// variables. Registration is opt-in and fails if name is already taken. It
+// is safe to call concurrently, though not against direct expvar.Publish
+// calls for the same name.
+func (g *Generator) RegisterExpvar(name string) error {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("expvar %q already registered", name)
+	}
+	expvar.Publish(name, expvar.Func(g.expvarValue))
+	return nil
+}
+
+// expvarValue returns the JSON-encodable value published by RegisterExpvar
+func (g *Generator) expvarValue() any {
+	stats, health := g.Stats(), g.Health()
+
+	lastError := ""
+	if health.LastError != nil {
+		lastError = health.LastError.Error()
+	}
+
+	return map[string]any{
+		"backend":   health.Backend.String(),
+		"generated": stats.Total(),
+		"modes": map[string]uint64{
+			"random":  stats.Random,
+			"ordered": stats.Ordered,
+			"hlc":     stats.HLC,
+		},
+		"blocked":          stats.Blocked,
+		"entropy_reads":    health.Reads,
+		"entropy_failures": health.Failures,
+		"fallbacks":        health.Fallbacks,
+		"last_error":       lastError,
+	}
+}
@@ -0,0 +1,65 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegisterExpvar(t *testing.T) {
+	g, _ := NewGenerator()
+	// expvar names live for the process, so -count reruns need a fresh one
+	id, _ := NewKUID()
+	name := "kuid_test_generator_" + id.String()
+	if err := g.RegisterExpvar(name); err != nil {
+		t.Fatalf("RegisterExpvar() error = %v", err)
+	}
+	if err := g.RegisterExpvar(name); err == nil {
+		t.Errorf("RegisterExpvar() expected error for duplicate name")
+	}
+
+	g.New()
+	g.NewOrdered()
+
+	var got struct {
+		Backend   string            `json:"backend"`
+		Generated uint64            `json:"generated"`
+		Modes     map[string]uint64 `json:"modes"`
+		LastError string            `json:"last_error"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got.Backend != "crypto" || got.Generated != 2 || got.Modes["ordered"] != 1 || got.LastError != "" {
+		t.Errorf("expvar value = %+v", got)
+	}
+}
+
+func TestRegisterExpvarConcurrent(t *testing.T) {
+	id, _ := NewKUID()
+	name := "kuid_test_concurrent_" + id.String()
+
+	var registered atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 50; i++ {
+		g, _ := NewGenerator()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if g.RegisterExpvar(name) == nil {
+				registered.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if registered.Load() != 1 {
+		t.Errorf("%d concurrent registrations succeeded, want 1", registered.Load())
+	}
+}
@@ -0,0 +1,146 @@
+package kuid
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Health reports entropy statistics for a Generator
+type Health struct {
+	Backend     Backend   // primary entropy backend
+	Reads       uint64    // successful reads from the primary backend
+	Failures    uint64    // failed reads from any backend
+	Fallbacks   uint64    // reads served by the fallback backend
+	LastError   error     // most recent entropy failure, if any
+	LastFailure time.Time // time of the most recent failure
+}
+
+// Healthy reports whether the most recent entropy read succeeded
+func (h Health) Healthy() bool {
+	return h.LastError == nil
+}
+
+// entropyStats tracks entropy reads for Generator.Health
+type entropyStats struct {
+	reads     atomic.Uint64
+	failures  atomic.Uint64
+	fallbacks atomic.Uint64
+	degraded  atomic.Bool // set while lastError is non-nil
+
+	mu          sync.Mutex
+	lastError   error
+	lastFailure time.Time
+}
+
+func (s *entropyStats) recordFailure(err error) {
+	s.failures.Add(1)
+	s.mu.Lock()
+	s.lastError = err
+	s.lastFailure = time.Now()
+	s.degraded.Store(true)
+	s.mu.Unlock()
+}
+
+func (s *entropyStats) recordSuccess() {
+	s.reads.Add(1)
+	if !s.degraded.Load() {
+		return
+	}
+	s.mu.Lock()
+	s.lastError = nil
+	s.degraded.Store(false)
+	s.mu.Unlock()
+}
+
+// WithRetryOnFailure retries failed entropy reads up to attempts times,
+// sleeping backoff before the first retry and doubling it after each one
+func WithRetryOnFailure(attempts int, backoff time.Duration) Option {
+	return func(c *config) error {
+		if attempts < 0 || backoff < 0 {
+			return errors.New("retry attempts and backoff must not be negative")
+		}
+		c.retries = attempts
+		c.retryBackoff = backoff
+		return nil
+	}
+}
+
+// WithFallbackBackend serves reads from a secondary backend once the primary
+// backend has failed and its retries are exhausted
+func WithFallbackBackend(b Backend) Option {
+	return func(c *config) error {
+		e, err := newEntropy(b)
+		if err != nil {
+			return err
+		}
+		c.fallback = e
+		return nil
+	}
+}
+
+// WithPanicOnFailure panics instead of returning an error when no entropy
+// could be read, for daemons where minting without entropy is never
+// acceptable and a crash-restart is preferred
+func WithPanicOnFailure() Option {
+	return func(c *config) error {
+		c.panicOnFailure = true
+		return nil
+	}
+}
+
+// Health returns entropy read statistics for the Generator
+func (g *Generator) Health() Health {
+	g.stats.mu.Lock()
+	defer g.stats.mu.Unlock()
+	return Health{
+		Backend:     g.config().backend,
+		Reads:       g.stats.reads.Load(),
+		Failures:    g.stats.failures.Load(),
+		Fallbacks:   g.stats.fallbacks.Load(),
+		LastError:   g.stats.lastError,
+		LastFailure: g.stats.lastFailure,
+	}
+}
+
+// read draws 128 random bits, applying the configured failure policy
+func (g *Generator) read() (uint64, uint64, error) {
+	return g.readWith(g.config())
+}
+
+// readWith is read using the given configuration
+func (g *Generator) readWith(c *config) (uint64, uint64, error) {
+	var err error
+	backoff := c.retryBackoff
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		var msb, lsb uint64
+		msb, lsb, err = c.entropy.uint128()
+		if err == nil {
+			g.stats.recordSuccess()
+			return msb, lsb, nil
+		}
+		g.stats.recordFailure(err)
+	}
+
+	if c.fallback != nil {
+		msb, lsb, fallbackErr := c.fallback.uint128()
+		if fallbackErr == nil {
+			g.stats.fallbacks.Add(1)
+			return msb, lsb, nil
+		}
+		g.stats.recordFailure(fallbackErr)
+		err = fallbackErr
+	}
+
+	if c.panicOnFailure {
+		panic(fmt.Sprintf("kuid: entropy unavailable: %v", err))
+	}
+	return 0, 0, err
+}
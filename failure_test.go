@@ -0,0 +1,127 @@
+package kuid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errNoEntropy = errors.New("no entropy")
+
+// flakyEntropy fails the first failures reads and then succeeds
+type flakyEntropy struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyEntropy) uint128() (uint64, uint64, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return 0, 0, errNoEntropy
+	}
+	return uint64(f.calls), uint64(f.calls), nil
+}
+
+// withEntropy replaces the Generator's primary entropy source
+func withEntropy(e entropy) Option {
+	return func(c *config) error {
+		c.entropy = e
+		return nil
+	}
+}
+
+func TestGeneratorFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		opts          []Option
+		wantErr       bool
+		wantFailures  uint64
+		wantFallbacks uint64
+	}{
+		{
+			name:         "Return error by default",
+			failures:     1,
+			wantErr:      true,
+			wantFailures: 1,
+		},
+		{
+			name:         "Retry until success",
+			failures:     2,
+			opts:         []Option{WithRetryOnFailure(2, time.Microsecond)},
+			wantFailures: 2,
+		},
+		{
+			name:         "Retries exhausted",
+			failures:     5,
+			opts:         []Option{WithRetryOnFailure(2, time.Microsecond)},
+			wantErr:      true,
+			wantFailures: 3,
+		},
+		{
+			name:          "Fallback backend",
+			failures:      5,
+			opts:          []Option{WithRetryOnFailure(1, 0), WithFallbackBackend(BackendCrypto)},
+			wantFailures:  2,
+			wantFallbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(tt.opts...)
+			if err != nil {
+				t.Fatalf("NewGenerator() error = %v", err)
+			}
+			g.Update(withEntropy(&flakyEntropy{failures: tt.failures}))
+
+			_, err = g.New()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			h := g.Health()
+			if h.Failures != tt.wantFailures {
+				t.Errorf("Health().Failures = %v, want %v", h.Failures, tt.wantFailures)
+			}
+			if h.Fallbacks != tt.wantFallbacks {
+				t.Errorf("Health().Fallbacks = %v, want %v", h.Fallbacks, tt.wantFallbacks)
+			}
+			if tt.wantFailures > 0 && h.LastFailure.IsZero() {
+				t.Errorf("Health().LastFailure not recorded")
+			}
+		})
+	}
+}
+
+func TestGeneratorHealthRecovers(t *testing.T) {
+	g, _ := NewGenerator()
+	g.Update(withEntropy(&flakyEntropy{failures: 1}))
+
+	if _, err := g.New(); err != errNoEntropy {
+		t.Fatalf("New() error = %v, want %v", err, errNoEntropy)
+	}
+	if g.Health().Healthy() {
+		t.Errorf("Healthy() = true after failure")
+	}
+
+	if _, err := g.New(); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	h := g.Health()
+	if !h.Healthy() || h.Reads != 1 || h.Failures != 1 {
+		t.Errorf("Health() = %+v, want healthy with 1 read and 1 failure", h)
+	}
+}
+
+func TestGeneratorPanicOnFailure(t *testing.T) {
+	g, _ := NewGenerator(WithPanicOnFailure())
+	g.Update(withEntropy(&flakyEntropy{failures: 1}))
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("New() expected panic")
+		}
+	}()
+	g.New()
+}
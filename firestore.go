@@ -0,0 +1,26 @@
+package kuid
+
+// The Firestore Go client has no marshaling hooks for custom types, so KUID
+// fields cannot be used in document structs directly. Declare them as
+// string (or []byte) fields and convert at the boundary:
+//
+//	type Order struct {
+//	    ID string `firestore:"id"`
+//	}
+//	doc := Order{ID: id.FirestoreValue().(string)}
+//	id, err := kuid.FromFirestore(snap.Data()["id"])
+
+// FirestoreValue returns the KUID as a Firestore string value
+func (k *KUID) FirestoreValue() any {
+	return k.String()
+}
+
+// FirestoreBytes returns the KUID as a 16-byte Firestore bytes value
+func (k *KUID) FirestoreBytes() any {
+	return k.Bytes()
+}
+
+// FromFirestore converts a Firestore string or bytes value into a KUID
+func FromFirestore(v any) (*KUID, error) {
+	return fromDatabaseValue(v)
+}
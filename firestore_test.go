@@ -0,0 +1,21 @@
+package kuid
+
+import "testing"
+
+func TestFirestore(t *testing.T) {
+	kuid, _ := NewKUID()
+
+	for _, v := range []any{kuid.FirestoreValue(), kuid.FirestoreBytes()} {
+		decoded, err := FromFirestore(v)
+		if err != nil {
+			t.Fatalf("FromFirestore(%T) error = %v", v, err)
+		}
+		if !decoded.Equal(kuid) {
+			t.Errorf("FromFirestore(%T) = %v, want %v", v, decoded, kuid)
+		}
+	}
+
+	if _, err := FromFirestore(nil); err == nil {
+		t.Errorf("FromFirestore() expected error for nil")
+	}
+}
@@ -0,0 +1,242 @@
+package kuid
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultMaxRetries = 100
+
+var ErrBlocked = errors.New("no KUID free of blocked words found")
+
+// defaultGenerator backs package-level helpers that need random bits
+var defaultGenerator, _ = NewGenerator()
+
+// Generator mints KUIDs with configurable generation behaviour. A Generator
+// is safe for concurrent use once constructed.
+type Generator struct {
+	cfg      atomic.Pointer[config]
+	updateMu sync.Mutex // serialises Update and Swap
+
+	stats  entropyStats
+	counts generatorStats
+
+	// ordered generation state, guarded by mu
+	mu            sync.Mutex
+	lastTimestamp int64
+	sequence      uint16
+	reserved      int64
+	stateLoaded   bool
+	stateEpoch    int // config.stateEpoch the state was loaded for
+}
+
+// config holds the settings applied by Options. A published config is never
+// modified; Update and Swap replace it as a whole, so every call sees one
+// consistent configuration.
+type config struct {
+	backend    Backend
+	entropy    entropy
+	blocklist  []string // lowercased words rejected in the string form
+	maxRetries int
+
+	// entropy failure policy
+	retries        int
+	retryBackoff   time.Duration
+	fallback       entropy
+	panicOnFailure bool
+
+	// fixed bits in the lower half; embedded is derived from the others
+	typeTag  embeddedField
+	region   embeddedField
+	tenant   embeddedField
+	embedded []embeddedField
+
+	// ordered generation
+	clock      Clock
+	topology   *Topology // layout shared across regions
+	node       uint64
+	nodeErr    error // set by Coordinate while no node ID is leased
+	store      Store
+	reserve    time.Duration
+	stateEpoch int // changes whenever the store does
+}
+
+// Option configures a Generator
+type Option func(*config) error
+
+// NewGenerator creates a Generator with the given options applied
+func NewGenerator(opts ...Option) (*Generator, error) {
+	c, err := defaultConfig().with(opts)
+	if err != nil {
+		return nil, err
+	}
+	g := &Generator{}
+	g.cfg.Store(c)
+	return g, nil
+}
+
+func defaultConfig() *config {
+	return &config{
+		entropy:    cryptoEntropy{},
+		maxRetries: defaultMaxRetries,
+		clock:      systemClock{},
+	}
+}
+
+// with returns a copy of c with opts applied
+func (c *config) with(opts []Option) (*config, error) {
+	next := *c
+	next.blocklist = slices.Clone(c.blocklist)
+	for _, opt := range opts {
+		if err := opt(&next); err != nil {
+			return nil, err
+		}
+	}
+	if err := next.placeEmbedded(); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// config returns the configuration currently in effect
+func (g *Generator) config() *config {
+	return g.cfg.Load()
+}
+
+// Update atomically applies opts on top of the Generator's current
+// configuration, for long-lived services that must rotate entropy sources
+// or node IDs without restarting. Calls already in progress finish with the
+// old configuration. If any option fails, nothing changes.
+func (g *Generator) Update(opts ...Option) error {
+	g.updateMu.Lock()
+	defer g.updateMu.Unlock()
+	c, err := g.config().with(opts)
+	if err != nil {
+		return err
+	}
+	g.cfg.Store(c)
+	return nil
+}
+
+// Swap atomically replaces the Generator's configuration with one built
+// from opts alone, as NewGenerator would. Statistics and ordered state are
+// kept, so ordered KUIDs keep sorting after those minted before the swap.
+func (g *Generator) Swap(opts ...Option) error {
+	g.updateMu.Lock()
+	defer g.updateMu.Unlock()
+	fresh := defaultConfig()
+	fresh.stateEpoch = g.config().stateEpoch + 1
+	c, err := fresh.with(opts)
+	if err != nil {
+		return err
+	}
+	g.cfg.Store(c)
+	return nil
+}
+
+// WithBackend selects the entropy backend used by the Generator
+func WithBackend(b Backend) Option {
+	return func(c *config) error {
+		e, err := newEntropy(b)
+		if err != nil {
+			return err
+		}
+		c.backend = b
+		c.entropy = e
+		return nil
+	}
+}
+
+// WithUnsafeMathRand makes the Generator draw its bits from math/rand/v2
+// instead of crypto/rand. This is much faster and does not consume system
+// entropy, but the output is predictable: only use it for tests, load
+// generators and simulations, never for identifiers exposed to users.
+func WithUnsafeMathRand() Option {
+	return WithBackend(BackendUnsafeMath)
+}
+
+// WithBlocklist re-rolls any KUID whose base62 form contains one of the
+// given words. Matching is case-insensitive, so "abc" also rejects "aBC".
+func WithBlocklist(words ...string) Option {
+	return func(c *config) error {
+		for _, w := range words {
+			if w == "" {
+				return errors.New("blocklist words must not be empty")
+			}
+			c.blocklist = append(c.blocklist, strings.ToLower(w))
+		}
+		return nil
+	}
+}
+
+// WithMaxRetries sets how many times a rejected KUID is re-rolled before
+// New gives up with ErrBlocked
+func WithMaxRetries(n int) Option {
+	return func(c *config) error {
+		if n < 0 {
+			return errors.New("max retries must not be negative")
+		}
+		c.maxRetries = n
+		return nil
+	}
+}
+
+// Backend returns the entropy backend in use
+func (g *Generator) Backend() Backend {
+	return g.config().backend
+}
+
+// New generates a new random KUID that passes the configured filters
+func (g *Generator) New() (*KUID, error) {
+	k, err := g.NewValue()
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// NewValue is like New but returns the KUID by value, avoiding a heap
+// allocation when no blocklist is configured
+func (g *Generator) NewValue() (KUID, error) {
+	return g.newRandom(nil)
+}
+
+// newRandom draws random KUIDs, passing each through layout if set, until
+// one passes the blocklist
+func (g *Generator) newRandom(layout func(k KUID) KUID) (KUID, error) {
+	c := g.config()
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		msb, lsb, err := g.readWith(c)
+		if err != nil {
+			return KUID{}, err
+		}
+		k := KUID{msb: msb, lsb: c.embed(lsb)}
+		if layout != nil {
+			k = layout(k)
+		}
+		if len(c.blocklist) == 0 || !c.blocked(k.String()) {
+			g.counts.random.Add(1)
+			return k, nil
+		}
+		g.counts.blocked.Add(1)
+	}
+	return KUID{}, ErrBlocked
+}
+
+// blocked reports whether s contains any word on the blocklist
+func (c *config) blocked(s string) bool {
+	if len(c.blocklist) == 0 {
+		return false
+	}
+	s = strings.ToLower(s)
+	for _, w := range c.blocklist {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package kuid
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGeneratorBlocklist(t *testing.T) {
+	// Single characters are common enough to force frequent re-rolls
+	g, err := NewGenerator(WithBlocklist("a", "B", "7"))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		kuid, err := g.New()
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		str := strings.ToLower(kuid.String())
+		if strings.ContainsAny(str, "ab7") {
+			t.Errorf("Generated KUID contains blocked word: %s", kuid.String())
+		}
+	}
+}
+
+func TestGeneratorBlocklistExhausted(t *testing.T) {
+	// Every base62 string contains at least one of these characters
+	g, err := NewGenerator(
+		WithBlocklist(strings.Split(base62Chars, "")...),
+		WithMaxRetries(3),
+	)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	if _, err := g.New(); err != ErrBlocked {
+		t.Errorf("New() error = %v, want %v", err, ErrBlocked)
+	}
+}
+
+func TestGeneratorInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{
+			name: "Empty blocked word",
+			opt:  WithBlocklist("ok", ""),
+		},
+		{
+			name: "Negative retries",
+			opt:  WithMaxRetries(-1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGenerator(tt.opt); err == nil {
+				t.Errorf("NewGenerator() expected error")
+			}
+		})
+	}
+}
+
+func TestGeneratorUnsafeMathRand(t *testing.T) {
+	g, err := NewGenerator(WithUnsafeMathRand())
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		kuid, err := g.New()
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if seen[kuid.String()] {
+			t.Errorf("Duplicate KUID generated: %s", kuid.String())
+		}
+		seen[kuid.String()] = true
+	}
+}
+
+func TestGeneratorBackends(t *testing.T) {
+	for _, backend := range []Backend{BackendCrypto, BackendChaCha20, BackendUnsafeMath} {
+		t.Run(backend.String(), func(t *testing.T) {
+			g, err := NewGenerator(WithBackend(backend))
+			if err != nil {
+				t.Fatalf("NewGenerator() error = %v", err)
+			}
+			if g.Backend() != backend {
+				t.Errorf("Backend() = %v, want %v", g.Backend(), backend)
+			}
+
+			seen := make(map[string]bool)
+			for i := 0; i < 1000; i++ {
+				kuid, err := g.New()
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+				if seen[kuid.String()] {
+					t.Errorf("Duplicate KUID generated: %s", kuid.String())
+				}
+				seen[kuid.String()] = true
+			}
+		})
+	}
+
+	if _, err := NewGenerator(WithBackend(Backend(42))); err == nil {
+		t.Errorf("NewGenerator() expected error for unknown backend")
+	}
+}
+
+func TestGeneratorUpdate(t *testing.T) {
+	topo := &Topology{NodeBits: 8, SequenceBits: 8, Regions: []RegionAllocation{
+		{Name: "primary", FirstNode: 0, LastNode: 127},
+		{Name: "standby", FirstNode: 128, LastNode: 255},
+	}}
+	g, err := NewGenerator(WithTopology(topo, "primary", 7), WithBlocklist("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := g.NewOrdered()
+
+	// Fail over to the standby region
+	if err := g.Update(WithTopology(topo, "standby", 200), WithBackend(BackendChaCha20)); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := g.NewOrdered()
+	if topo.Node(after) != 200 || g.Backend() != BackendChaCha20 {
+		t.Errorf("Node() = %d, Backend() = %v after Update", topo.Node(after), g.Backend())
+	}
+	if after.msb <= before.msb {
+		t.Errorf("KUID after Update sorts before earlier KUID")
+	}
+	if k, _ := g.New(); strings.ContainsAny(k.String(), "aA") {
+		t.Errorf("Update dropped the blocklist")
+	}
+
+	// A failing option leaves the configuration untouched
+	if err := g.Update(WithBackend(BackendUnsafeMath), WithMaxRetries(-1)); err == nil {
+		t.Fatal("Update() succeeded with invalid option")
+	}
+	if g.Backend() != BackendChaCha20 {
+		t.Errorf("Backend() = %v after failed Update", g.Backend())
+	}
+}
+
+func TestGeneratorSwap(t *testing.T) {
+	g, _ := NewGenerator(WithBlocklist("a"), WithBackend(BackendChaCha20))
+	g.New()
+
+	if err := g.Swap(); err != nil {
+		t.Fatal(err)
+	}
+	if g.Backend() != BackendCrypto || len(g.config().blocklist) != 0 {
+		t.Errorf("Swap() kept old configuration")
+	}
+	if g.Stats().Random != 1 {
+		t.Errorf("Swap() reset statistics")
+	}
+}
+
+func TestGeneratorUpdateStore(t *testing.T) {
+	first, second := &memoryStore{}, &memoryStore{}
+	g, _ := NewGenerator(WithStateStore(first, time.Hour))
+	g.NewOrdered()
+	if first.saves != 1 {
+		t.Fatalf("first store saves = %d, want 1", first.saves)
+	}
+
+	// The new store holds no reservation, so the next KUID must be saved
+	if err := g.Update(WithStateStore(second, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	g.NewOrdered()
+	if second.saves != 1 || second.state.Timestamp < first.state.Timestamp {
+		t.Errorf("second store state = %+v after %d saves", second.state, second.saves)
+	}
+}
+
+func TestGeneratorUpdateConcurrent(t *testing.T) {
+	g, _ := NewGenerator()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				if _, err := g.New(); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := g.NewOrdered(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		backend := Backend(i % 3)
+		if err := g.Update(WithBackend(backend)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
+
+func BenchmarkGenerator(b *testing.B) {
+	crypto, _ := NewGenerator()
+	chacha, _ := NewGenerator(WithBackend(BackendChaCha20))
+	fast, _ := NewGenerator(WithUnsafeMathRand())
+
+	b.Run("Crypto", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := crypto.New(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("ChaCha20", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := chacha.New(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("UnsafeMathRand", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := fast.New(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
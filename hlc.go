@@ -0,0 +1,118 @@
+package kuid
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrClockDrift = errors.New("remote clock too far ahead")
+
+// HLC generates KUIDs stamped with a hybrid logical clock. They share the
+// ordered layout: the 48-bit timestamp holds the HLC physical component in
+// milliseconds and the 16-bit sequence holds the logical counter. Unlike
+// Generator.NewOrdered, an HLC merges timestamps observed from other nodes
+// via Update, so a KUID minted in response to a message always sorts after
+// the message's KUID even when the nodes' clocks disagree.
+type HLC struct {
+	gen      *Generator
+	maxDrift time.Duration
+
+	mu       sync.Mutex
+	physical int64
+	logical  uint16
+}
+
+// NewHLC creates a hybrid logical clock drawing random bits from gen (a
+// default Generator when nil). Update rejects remote timestamps more than
+// maxDrift ahead of the local clock; zero disables the check.
+func NewHLC(gen *Generator, maxDrift time.Duration) (*HLC, error) {
+	if gen == nil {
+		var err error
+		if gen, err = NewGenerator(); err != nil {
+			return nil, err
+		}
+	}
+	return &HLC{gen: gen, maxDrift: maxDrift}, nil
+}
+
+// Now mints a KUID for a local or send event
+func (h *HLC) Now() (*KUID, error) {
+	h.mu.Lock()
+	pt := h.gen.config().clock.Now().UnixMilli()
+	if pt > h.physical {
+		h.physical = pt
+		h.logical = 0
+	} else {
+		h.tick()
+	}
+	physical, logical := h.physical, h.logical
+	h.mu.Unlock()
+
+	return h.stamp(physical, logical)
+}
+
+// Update merges the clock carried by a received KUID and mints a KUID for
+// the receive event that sorts after both remote and every KUID previously
+// minted by h
+func (h *HLC) Update(remote *KUID) (*KUID, error) {
+	rp, rl := int64(remote.msb>>sequenceBits), remote.HLCLogical()
+
+	h.mu.Lock()
+	pt := h.gen.config().clock.Now().UnixMilli()
+	if h.maxDrift > 0 && rp-pt > h.maxDrift.Milliseconds() {
+		h.mu.Unlock()
+		return nil, ErrClockDrift
+	}
+
+	switch {
+	case pt > h.physical && pt > rp:
+		h.physical = pt
+		h.logical = 0
+	case rp > h.physical:
+		h.physical = rp
+		h.logical = rl
+		h.tick()
+	case rp == h.physical:
+		h.logical = max(h.logical, rl)
+		h.tick()
+	default:
+		h.tick()
+	}
+	physical, logical := h.physical, h.logical
+	h.mu.Unlock()
+
+	return h.stamp(physical, logical)
+}
+
+// tick advances the logical counter, carrying into the physical component
+// when it overflows. Callers must hold h.mu.
+func (h *HLC) tick() {
+	if h.logical == maxSequence {
+		h.physical++
+		h.logical = 0
+		return
+	}
+	h.logical++
+}
+
+func (h *HLC) stamp(physical int64, logical uint16) (*KUID, error) {
+	c := h.gen.config()
+	_, lsb, err := h.gen.readWith(c)
+	if err != nil {
+		return nil, err
+	}
+	h.gen.counts.hlc.Add(1)
+	msb := uint64(physical)<<sequenceBits | uint64(logical)
+	return &KUID{msb: msb, lsb: c.embed(lsb)}, nil
+}
+
+// HLCPhysical returns the physical component of an HLC KUID
+func (k *KUID) HLCPhysical() time.Time {
+	return k.Timestamp()
+}
+
+// HLCLogical returns the logical counter of an HLC KUID
+func (k *KUID) HLCLogical() uint16 {
+	return k.Sequence()
+}
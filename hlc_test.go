@@ -0,0 +1,78 @@
+package kuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHLCNow(t *testing.T) {
+	h, err := NewHLC(nil, 0)
+	if err != nil {
+		t.Fatalf("NewHLC() error = %v", err)
+	}
+
+	prev, _ := h.Now()
+	for i := 0; i < 1000; i++ {
+		cur, err := h.Now()
+		if err != nil {
+			t.Fatalf("Now() error = %v", err)
+		}
+		if cur.msb <= prev.msb {
+			t.Fatalf("HLC KUID %d does not sort after its predecessor", i)
+		}
+		prev = cur
+	}
+}
+
+func TestHLCUpdate(t *testing.T) {
+	local, _ := NewHLC(nil, 0)
+	remote, _ := NewHLC(nil, 0)
+
+	// The remote node's clock runs an hour ahead
+	remote.physical = time.Now().Add(time.Hour).UnixMilli()
+	remote.logical = 3
+	msg, err := remote.Now()
+	if err != nil {
+		t.Fatalf("Now() error = %v", err)
+	}
+
+	received, err := local.Update(msg)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if received.msb <= msg.msb {
+		t.Errorf("Receive event does not sort after the remote event")
+	}
+	if !received.HLCPhysical().Equal(msg.HLCPhysical()) || received.HLCLogical() != msg.HLCLogical()+1 {
+		t.Errorf("Update() = (%v, %v), want (%v, %v)",
+			received.HLCPhysical(), received.HLCLogical(), msg.HLCPhysical(), msg.HLCLogical()+1)
+	}
+
+	// Later local events keep sorting after the merged clock
+	next, _ := local.Now()
+	if next.msb <= received.msb {
+		t.Errorf("Local event does not sort after the receive event")
+	}
+}
+
+func TestHLCUpdateFromPast(t *testing.T) {
+	h, _ := NewHLC(nil, 0)
+	old := &KUID{msb: uint64(time.Now().Add(-time.Hour).UnixMilli()) << sequenceBits}
+
+	kuid, err := h.Update(old)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if kuid.HLCLogical() != 0 || time.Since(kuid.HLCPhysical()) > time.Minute {
+		t.Errorf("Update() = (%v, %v), want current physical time", kuid.HLCPhysical(), kuid.HLCLogical())
+	}
+}
+
+func TestHLCMaxDrift(t *testing.T) {
+	h, _ := NewHLC(nil, time.Minute)
+	ahead := &KUID{msb: uint64(time.Now().Add(time.Hour).UnixMilli()) << sequenceBits}
+
+	if _, err := h.Update(ahead); err != ErrClockDrift {
+		t.Errorf("Update() error = %v, want %v", err, ErrClockDrift)
+	}
+}
@@ -0,0 +1,76 @@
+package kuid
+
+import "bytes"
+
+// ID128 is a 128-bit identifier in any scheme. Bytes must return 16 bytes. Storage layers written
+// against it accept KUIDs, UUIDs and ULIDs alike and can keep the 16 bytes
+// as their key; KSUIDs are converted to KUIDs first. Compare orders IDs by
+// their bytes whatever their scheme, so mixed keys sort consistently.
+type ID128 interface {
+	Bytes() []byte
+	String() string
+	Compare(other ID128) int
+}
+
+var (
+	_ ID128 = (*KUID)(nil)
+	_ ID128 = UUID{}
+	_ ID128 = ULID{}
+)
+
+// Compare returns -1, 0 or 1 as k's bytes sort before, equal to or after
+// other's
+func (k *KUID) Compare(other ID128) int {
+	return compareID128(k, other)
+}
+
+// UUID adapts 16 bytes rendered in the hyphenated UUID form to ID128
+type UUID [16]byte
+
+// Bytes returns a copy of the UUID's bytes
+func (u UUID) Bytes() []byte {
+	return append([]byte(nil), u[:]...)
+}
+
+// String returns the hyphenated UUID form
+func (u UUID) String() string {
+	k := AsKUID(u)
+	return k.ToUUID()
+}
+
+// Compare implements ID128
+func (u UUID) Compare(other ID128) int {
+	return compareID128(u, other)
+}
+
+// AsKUID reinterprets the 128 bits of id as a KUID
+func AsKUID(id ID128) KUID {
+	if k, ok := id.(*KUID); ok {
+		return *k
+	}
+	k, _ := FromBytes(id.Bytes())
+	return *k
+}
+
+// compareID128 compares by bytes, avoiding the copy for KUIDs
+func compareID128(a, b ID128) int {
+	ka, aok := a.(*KUID)
+	kb, bok := b.(*KUID)
+	if aok && bok {
+		if ka.msb != kb.msb {
+			return cmpUint64(ka.msb, kb.msb)
+		}
+		return cmpUint64(ka.lsb, kb.lsb)
+	}
+	return bytes.Compare(a.Bytes(), b.Bytes())
+}
+
+func cmpUint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
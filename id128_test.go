@@ -0,0 +1,83 @@
+package kuid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestID128(t *testing.T) {
+	k := &KUID{msb: 0x0123456789abcdef, lsb: 0xfedcba9876543210}
+	var raw [16]byte
+	copy(raw[:], k.Bytes())
+
+	tests := []struct {
+		name string
+		id   ID128
+		want string
+	}{
+		{name: "KUID", id: k, want: k.String()},
+		{name: "UUID", id: UUID(raw), want: "01234567-89ab-cdef-fedc-ba9876543210"},
+		{name: "ULID", id: ULID(raw), want: "014D2PF2DBSQQZXQ5TK1V58CGG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if !slices.Equal(tt.id.Bytes(), raw[:]) {
+				t.Errorf("Bytes() = %x, want %x", tt.id.Bytes(), raw)
+			}
+			if got := AsKUID(tt.id); got != *k {
+				t.Errorf("AsKUID() = %v, want %v", got, k)
+			}
+			for _, other := range tests {
+				if c := tt.id.Compare(other.id); c != 0 {
+					t.Errorf("Compare(%s) = %d, want 0", other.name, c)
+				}
+			}
+		})
+	}
+}
+
+func TestID128Compare(t *testing.T) {
+	ids := []ID128{
+		&KUID{msb: 0, lsb: 1},
+		UUID{0: 0, 15: 2},
+		ULID{15: 3},
+		&KUID{msb: 1, lsb: 0},
+		&KUID{msb: 1, lsb: 1},
+		UUID{0: 0xff},
+	}
+	for i, a := range ids {
+		for j, b := range ids {
+			want := cmpUint64(uint64(i), uint64(j))
+			if got := a.Compare(b); got != want {
+				t.Errorf("ids[%d].Compare(ids[%d]) = %d, want %d", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestULIDString(t *testing.T) {
+	var max ULID
+	for i := range max {
+		max[i] = 0xff
+	}
+	if got := max.String(); got != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("String() = %q for the largest ULID", got)
+	}
+	if got := (ULID{}).String(); got != "00000000000000000000000000" {
+		t.Errorf("String() = %q for the zero ULID", got)
+	}
+
+	// The first 10 characters carry the 48-bit millisecond timestamp
+	var u ULID
+	ms := uint64(1469918176385)
+	for i := 0; i < 6; i++ {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+	if got := u.String()[:10]; got != "01ARYZ6S41" {
+		t.Errorf("timestamp encodes as %q, want %q", got, "01ARYZ6S41")
+	}
+}
@@ -0,0 +1,277 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen KUID identifying a
+	// request that must not be applied twice
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from the store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// CachedResponse is a response recorded by IdempotencyMiddleware
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore keeps the first response for each idempotency key and
+// the claims of requests still running. Get must return ErrNotFound for
+// unknown or expired keys. Shared stores (Redis, SQL) make replays and
+// in-flight conflicts work across instances.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key *KUID) (*CachedResponse, error)
+	// Claim atomically reserves key for one running request, for lease,
+	// and reports whether it did. It reports false while another claim on
+	// key is live or a response for key is recorded.
+	Claim(ctx context.Context, key *KUID, lease time.Duration) (bool, error)
+	// Extend keeps the claim on key live for another lease
+	Extend(ctx context.Context, key *KUID, lease time.Duration) error
+	// Put records resp for ttl and ends the claim on key
+	Put(ctx context.Context, key *KUID, resp *CachedResponse, ttl time.Duration) error
+	// Release ends the claim on key without recording a response
+	Release(ctx context.Context, key *KUID) error
+}
+
+// IdempotencyOptions configures IdempotencyMiddleware
+type IdempotencyOptions struct {
+	// TTL is how long a response is replayed, and the lease on the claim
+	// of a running request, which is extended every TTL/3 until it
+	// finishes. It must be positive.
+	TTL time.Duration
+	// OnStoreError, if set, is called when the store fails to extend a
+	// claim, record a response or release a claim once the handler has
+	// started, since the client's response can no longer report it. A
+	// response that was not recorded has its claim released, so a retry
+	// runs the handler again.
+	OnStoreError func(key *KUID, err error)
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header
+// safe to retry. The first response for a key is recorded in store and
+// replayed for any repeat within opts.TTL, without calling the handler
+// again. While the first request is still running, repeats are rejected
+// with 409 Conflict, on every instance sharing store. Server errors (5xx)
+// are not recorded, so the client may retry. Malformed keys are rejected
+// with 400 and store failures before the handler runs with 503; requests
+// without the header pass through unchanged.
+func IdempotencyMiddleware(store IdempotencyStore, opts IdempotencyOptions) (func(http.Handler) http.Handler, error) {
+	if opts.TTL <= 0 {
+		return nil, errors.New("idempotency TTL must be positive")
+	}
+	ttl := opts.TTL
+	report := func(key *KUID, err error) {
+		if err != nil && opts.OnStoreError != nil {
+			opts.OnStoreError(key, err)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(IdempotencyKeyHeader)
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key, err := Parse(header)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ctx := r.Context()
+			if replayed(ctx, w, store, key) {
+				return
+			}
+			claimed, err := store.Claim(ctx, key, ttl)
+			if err != nil {
+				http.Error(w, "idempotency store unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			if !claimed {
+				// The request holding the key may have finished since the
+				// lookup above
+				if !replayed(ctx, w, store, key) {
+					http.Error(w, "request with this idempotency key is in progress", http.StatusConflict)
+				}
+				return
+			}
+
+			// Bookkeeping after the handler starts must outlive a client
+			// that disconnects
+			ctx = context.WithoutCancel(ctx)
+			recorded := false
+			stop := make(chan struct{})
+			defer func() {
+				close(stop)
+				if !recorded {
+					report(key, store.Release(ctx, key))
+				}
+			}()
+			go func() {
+				tick := time.NewTicker(ttl / 3)
+				defer tick.Stop()
+				for {
+					select {
+					case <-stop:
+						return
+					case <-tick.C:
+						report(key, store.Extend(ctx, key, ttl))
+					}
+				}
+			}()
+
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			if rec.status < http.StatusInternalServerError {
+				err := store.Put(ctx, key, &CachedResponse{
+					Status: rec.status,
+					Header: w.Header().Clone(),
+					Body:   rec.body.Bytes(),
+				}, ttl)
+				recorded = err == nil
+				report(key, err)
+			}
+		})
+	}, nil
+}
+
+// replayed writes the stored response for key, or 503 if the store fails,
+// and reports whether it wrote anything
+func replayed(ctx context.Context, w http.ResponseWriter, store IdempotencyStore, key *KUID) bool {
+	cached, err := store.Get(ctx, key)
+	switch err {
+	case nil:
+		replay(w, cached)
+		return true
+	case ErrNotFound:
+		return false
+	}
+	http.Error(w, "idempotency store unavailable", http.StatusServiceUnavailable)
+	return true
+}
+
+func replay(w http.ResponseWriter, cached *CachedResponse) {
+	for k, v := range cached.Header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(cached.Status)
+	w.Write(cached.Body)
+}
+
+// recordingWriter passes a response through while keeping a copy
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	responses map[KUID]memoryResponse
+	claims    map[KUID]time.Time // claim expiry
+	now       func() time.Time
+}
+
+type memoryResponse struct {
+	resp    *CachedResponse
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty MemoryIdempotencyStore
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		responses: make(map[KUID]memoryResponse),
+		claims:    make(map[KUID]time.Time),
+		now:       time.Now,
+	}
+}
+
+// Get implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Get(_ context.Context, key *KUID) (*CachedResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.responses[*key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !m.now().Before(entry.expires) {
+		delete(m.responses, *key)
+		return nil, ErrNotFound
+	}
+	return entry.resp, nil
+}
+
+// Claim implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Claim(_ context.Context, key *KUID, lease time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if entry, ok := m.responses[*key]; ok && now.Before(entry.expires) {
+		return false, nil
+	}
+	if expires, ok := m.claims[*key]; ok && now.Before(expires) {
+		return false, nil
+	}
+	m.claims[*key] = now.Add(lease)
+	return true, nil
+}
+
+// Extend implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Extend(_ context.Context, key *KUID, lease time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.claims[*key]; !ok {
+		return ErrNotFound
+	}
+	m.claims[*key] = m.now().Add(lease)
+	return nil
+}
+
+// Put implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Put(_ context.Context, key *KUID, resp *CachedResponse, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[*key] = memoryResponse{resp: resp, expires: m.now().Add(ttl)}
+	delete(m.claims, *key)
+	return nil
+}
+
+// Release implements IdempotencyStore
+func (m *MemoryIdempotencyStore) Release(_ context.Context, key *KUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.claims, *key)
+	return nil
+}
@@ -0,0 +1,309 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// idempotent wraps h in an IdempotencyMiddleware over store
+func idempotent(t *testing.T, store IdempotencyStore, opts IdempotencyOptions, h http.HandlerFunc) http.Handler {
+	t.Helper()
+	mw, err := IdempotencyMiddleware(store, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mw(h)
+}
+
+func TestIdempotencyMiddlewareInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second} {
+		if _, err := IdempotencyMiddleware(NewMemoryIdempotencyStore(), IdempotencyOptions{TTL: ttl}); err == nil {
+			t.Errorf("IdempotencyMiddleware(TTL: %v) succeeded, want error", ttl)
+		}
+	}
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	calls := 0
+	handler := idempotent(t, NewMemoryIdempotencyStore(), IdempotencyOptions{TTL: time.Hour},
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("X-Order", fmt.Sprint(calls))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "order %d", calls)
+		})
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	key, _ := NewKUID()
+	first := send(key.String())
+	second := send(key.String())
+
+	if calls != 1 {
+		t.Errorf("Handler called %d times, want 1", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != "order 1" || second.Header().Get("X-Order") != "1" {
+		t.Errorf("Replay = %d %q %v, want first response", second.Code, second.Body.String(), second.Header())
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" || second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Replay header not set correctly")
+	}
+
+	// The UUID form of the same key is the same key
+	send(key.ToUUID())
+	if calls != 1 {
+		t.Errorf("UUID form of key was not replayed")
+	}
+
+	send("")
+	send("")
+	if calls != 3 {
+		t.Errorf("Requests without a key were deduplicated")
+	}
+
+	if rec := send("bogus"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Malformed key status = %d, want 400", rec.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareServerError(t *testing.T) {
+	calls := 0
+	handler := idempotent(t, NewMemoryIdempotencyStore(), IdempotencyOptions{TTL: time.Hour},
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			http.Error(w, "boom", http.StatusInternalServerError)
+		})
+
+	key, _ := NewKUID()
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(IdempotencyKeyHeader, key.String())
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if calls != 2 {
+		t.Errorf("Handler called %d times, want 2 (server errors are retryable)", calls)
+	}
+}
+
+func TestIdempotencyMiddlewareInFlight(t *testing.T) {
+	key, _ := NewKUID()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	handler := idempotent(t, NewMemoryIdempotencyStore(), IdempotencyOptions{TTL: time.Hour},
+		func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		})
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(IdempotencyKeyHeader, key.String())
+		return req
+	}
+
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest())
+		close(done)
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newRequest())
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Concurrent duplicate status = %d, want 409", rec.Code)
+	}
+
+	close(release)
+	<-done
+}
+
+// staleStore misses once on request, as a lookup racing the Put of a
+// request that is just finishing would. It can also fail Put, and records
+// the context error Put saw.
+type staleStore struct {
+	*MemoryIdempotencyStore
+	miss   bool
+	putErr error
+	putCtx error
+}
+
+func (s *staleStore) Get(ctx context.Context, key *KUID) (*CachedResponse, error) {
+	if s.miss {
+		s.miss = false
+		return nil, ErrNotFound
+	}
+	return s.MemoryIdempotencyStore.Get(ctx, key)
+}
+
+func (s *staleStore) Put(ctx context.Context, key *KUID, resp *CachedResponse, ttl time.Duration) error {
+	s.putCtx = ctx.Err()
+	if s.putErr != nil {
+		return s.putErr
+	}
+	return s.MemoryIdempotencyStore.Put(ctx, key, resp, ttl)
+}
+
+func TestIdempotencyMiddlewareRecheck(t *testing.T) {
+	store := &staleStore{MemoryIdempotencyStore: NewMemoryIdempotencyStore()}
+	calls := 0
+	handler := idempotent(t, store, IdempotencyOptions{TTL: time.Hour},
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		})
+
+	key, _ := NewKUID()
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(IdempotencyKeyHeader, key.String())
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	send()
+	// The second request's first lookup predates the first one's Put, so
+	// its claim fails and it must look again
+	store.miss = true
+	rec := send()
+	if calls != 1 {
+		t.Errorf("Handler called %d times, want 1", calls)
+	}
+	if rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Second request was not replayed")
+	}
+}
+
+func TestIdempotencyMiddlewarePutError(t *testing.T) {
+	var reported []error
+	store := &staleStore{MemoryIdempotencyStore: NewMemoryIdempotencyStore(), putErr: errors.New("store down")}
+	calls := 0
+	handler := idempotent(t, store, IdempotencyOptions{
+		TTL:          time.Hour,
+		OnStoreError: func(_ *KUID, err error) { reported = append(reported, err) },
+	}, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	key, _ := NewKUID()
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(IdempotencyKeyHeader, key.String())
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Status = %d, want 201", rec.Code)
+		}
+	}
+	if len(reported) != 2 || reported[0] != store.putErr {
+		t.Errorf("OnStoreError got %v, want the Put error twice", reported)
+	}
+	if calls != 2 {
+		t.Errorf("Handler called %d times, want 2 (unrecorded responses release their claim)", calls)
+	}
+}
+
+func TestIdempotencyMiddlewareClientGone(t *testing.T) {
+	store := &staleStore{MemoryIdempotencyStore: NewMemoryIdempotencyStore()}
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := idempotent(t, store, IdempotencyOptions{TTL: time.Hour},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			cancel() // the client disconnects once the work is done
+		})
+
+	key, _ := NewKUID()
+	req := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx)
+	req.Header.Set(IdempotencyKeyHeader, key.String())
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if store.putCtx != nil {
+		t.Errorf("Put saw context error %v, want a live context", store.putCtx)
+	}
+	if _, err := store.Get(context.Background(), key); err != nil {
+		t.Errorf("Get() after disconnect error = %v, want the recorded response", err)
+	}
+}
+
+func TestIdempotencyMiddlewareSharedStore(t *testing.T) {
+	// Two instances sharing one store, with a lease far shorter than the
+	// first request runs
+	store := NewMemoryIdempotencyStore()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	opts := IdempotencyOptions{TTL: 30 * time.Millisecond}
+	slow := idempotent(t, store, opts, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	calls := 0
+	other := idempotent(t, store, opts, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+
+	key, _ := NewKUID()
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(IdempotencyKeyHeader, key.String())
+		return req
+	}
+
+	done := make(chan struct{})
+	go func() {
+		slow.ServeHTTP(httptest.NewRecorder(), newRequest())
+		close(done)
+	}()
+	<-started
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(opts.TTL / 2)
+		rec := httptest.NewRecorder()
+		other.ServeHTTP(rec, newRequest())
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("Duplicate on another instance after %v = %d, want 409", time.Duration(i+1)*opts.TTL/2, rec.Code)
+		}
+	}
+	close(release)
+	<-done
+
+	rec := httptest.NewRecorder()
+	other.ServeHTTP(rec, newRequest())
+	if calls != 0 || rec.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("Duplicate after the first finished: %d calls, replayed %q", calls, rec.Header().Get(IdempotentReplayedHeader))
+	}
+}
+
+func TestMemoryIdempotencyStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryIdempotencyStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	key, _ := NewKUID()
+	store.Put(ctx, key, &CachedResponse{Status: http.StatusOK}, time.Minute)
+	if _, err := store.Get(ctx, key); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := store.Get(ctx, key); err != ErrNotFound {
+		t.Errorf("Get() error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,125 @@
+// Package invariants checks the properties every KUID implementation must
+// hold. The implementation under test is passed in as Encode, Decode and
+// Compare functions, so forks and alternate encoders can be checked as
+// easily as this module; Encode, Decode and Compare in this package are the
+// reference. Each check returns nil or an error describing the violation,
+// so it can back a property-based test with testing/quick or rapid:
+//
+//	f := func(id [16]byte) bool {
+//		return invariants.CheckRoundTrip(id, myEncode, myDecode) == nil
+//	}
+//	if err := quick.Check(f, nil); err != nil {
+//		t.Error(err)
+//	}
+package invariants
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+)
+
+// EncodeFunc converts a 128-bit value to its base62 string form
+type EncodeFunc func(id [16]byte) (string, error)
+
+// DecodeFunc converts a base62 string back to a 128-bit value
+type DecodeFunc func(s string) ([16]byte, error)
+
+// CompareFunc orders two 128-bit values, returning -1, 0 or +1
+type CompareFunc func(a, b [16]byte) int
+
+// Encode is the reference EncodeFunc, KUID.String
+func Encode(id [16]byte) (string, error) {
+	return kuid.FromArray(id).String(), nil
+}
+
+// Decode is the reference DecodeFunc, FromString
+func Decode(s string) ([16]byte, error) {
+	k, err := kuid.FromString(s)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	return k.Array(), nil
+}
+
+// Compare is the reference CompareFunc, KUID.Compare
+func Compare(a, b [16]byte) int {
+	return kuid.FromArray(a).Compare(kuid.FromArray(b))
+}
+
+// CheckRoundTrip verifies that encode turns id into a 22-character string
+// and decode turns that string back into id
+func CheckRoundTrip(id [16]byte, encode EncodeFunc, decode DecodeFunc) error {
+	s, err := encode(id)
+	if err != nil {
+		return fmt.Errorf("Encode(%x) error = %w", id, err)
+	}
+	if len(s) != 22 {
+		return fmt.Errorf("Encode(%x) = %q, want 22 characters", id, s)
+	}
+	got, err := decode(s)
+	if err != nil {
+		return fmt.Errorf("Decode(%q) error = %w", s, err)
+	}
+	if got != id {
+		return fmt.Errorf("Decode(%q) = %x, want %x", s, got, id)
+	}
+	return nil
+}
+
+// CheckCanonical verifies that s is either rejected by decode or is the one
+// string encode produces for the value it decodes to. Without this two
+// strings could name the same KUID and slip past string-keyed uniqueness
+// checks.
+func CheckCanonical(s string, encode EncodeFunc, decode DecodeFunc) error {
+	id, err := decode(s)
+	if err != nil {
+		return nil
+	}
+	got, err := encode(id)
+	if err != nil {
+		return fmt.Errorf("Encode(%x) error = %w", id, err)
+	}
+	if got != s {
+		return fmt.Errorf("Decode(%q) accepted a non-canonical string for %q", s, got)
+	}
+	return nil
+}
+
+// CheckOrdering verifies that every adjacent pair in ids compares the same
+// way under compare, in binary and in encoded form, so sorting by any
+// representation gives the same order
+func CheckOrdering(ids [][16]byte, encode EncodeFunc, compare CompareFunc) error {
+	for i := 1; i < len(ids); i++ {
+		a, b := ids[i-1], ids[i]
+		binary := bytes.Compare(a[:], b[:])
+		if got := compare(a, b); got != binary {
+			return fmt.Errorf("%x vs %x: Compare() = %d, binary order %d", a, b, got, binary)
+		}
+		sa, err := encode(a)
+		if err != nil {
+			return fmt.Errorf("Encode(%x) error = %w", a, err)
+		}
+		sb, err := encode(b)
+		if err != nil {
+			return fmt.Errorf("Encode(%x) error = %w", b, err)
+		}
+		if str := strings.Compare(sa, sb); str != binary {
+			return fmt.Errorf("%s vs %s: string order %d, binary order %d", sa, sb, str, binary)
+		}
+	}
+	return nil
+}
+
+// CheckIncreasing verifies that ids is strictly increasing under compare,
+// as a stream from one ordered generator must be
+func CheckIncreasing(ids [][16]byte, compare CompareFunc) error {
+	for i := 1; i < len(ids); i++ {
+		if compare(ids[i-1], ids[i]) >= 0 {
+			return fmt.Errorf("position %d: %x does not sort after %x", i, ids[i], ids[i-1])
+		}
+	}
+	return nil
+}
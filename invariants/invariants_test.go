@@ -0,0 +1,137 @@
+package invariants
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/alphabatem/kuid"
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// randomStrings generates 22-character base62 strings, most of which
+// overflow one of the halves
+var randomStrings = &quick.Config{
+	MaxCount: 2000,
+	Values: func(v []reflect.Value, r *rand.Rand) {
+		var sb strings.Builder
+		for i := 0; i < 22; i++ {
+			sb.WriteByte(base62[r.Intn(len(base62))])
+		}
+		v[0] = reflect.ValueOf(sb.String())
+	},
+}
+
+// wrappingDecode is a fork that reduces each half modulo 2^64 instead of
+// rejecting it
+func wrappingDecode(s string) ([16]byte, error) {
+	if len(s) != 22 {
+		return [16]byte{}, kuid.ErrInvalidLength
+	}
+	var halves [2]uint64
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base62, s[i])
+		if digit < 0 {
+			return [16]byte{}, kuid.ErrInvalidChar
+		}
+		halves[i/11] = halves[i/11]*62 + uint64(digit)
+	}
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], halves[0])
+	binary.BigEndian.PutUint64(id[8:], halves[1])
+	return id, nil
+}
+
+// reversedCompare is a fork that orders by the last byte first
+func reversedCompare(a, b [16]byte) int {
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	f := func(id [16]byte) bool {
+		err := CheckRoundTrip(id, Encode, Decode)
+		if err != nil {
+			t.Log(err)
+		}
+		return err == nil
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	failing := func(string) ([16]byte, error) { return [16]byte{}, errors.New("unsupported") }
+	if err := CheckRoundTrip([16]byte{15: 1}, Encode, failing); err == nil {
+		t.Error("CheckRoundTrip() accepted a decoder that always fails")
+	}
+	short := func(id [16]byte) (string, error) { return kuid.FromArray(id).String()[1:], nil }
+	if err := CheckRoundTrip([16]byte{15: 1}, short, Decode); err == nil {
+		t.Error("CheckRoundTrip() accepted a 21-character encoding")
+	}
+}
+
+func TestCheckCanonical(t *testing.T) {
+	f := func(s string) bool {
+		err := CheckCanonical(s, Encode, Decode)
+		if err != nil {
+			t.Log(err)
+		}
+		return err == nil
+	}
+	if err := quick.Check(f, randomStrings); err != nil {
+		t.Error(err)
+	}
+
+	if err := CheckCanonical("z0000000000"+"00000000000", Encode, wrappingDecode); err == nil {
+		t.Error("CheckCanonical() accepted a decoder that wraps overflowing halves")
+	}
+}
+
+func TestCheckOrdering(t *testing.T) {
+	f := func(ids [][16]byte) bool {
+		err := CheckOrdering(ids, Encode, Compare)
+		if err != nil {
+			t.Log(err)
+		}
+		return err == nil
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	ids := [][16]byte{{0: 1}, {15: 1}}
+	if err := CheckOrdering(ids, Encode, reversedCompare); err == nil {
+		t.Error("CheckOrdering() accepted a Compare that disagrees with binary order")
+	}
+}
+
+func TestCheckIncreasing(t *testing.T) {
+	g, _ := kuid.NewGenerator()
+	var ids [][16]byte
+	for k := range g.AllOrdered(context.Background()) {
+		if ids = append(ids, k.Array()); len(ids) == 1000 {
+			break
+		}
+	}
+	if err := CheckIncreasing(ids, Compare); err != nil {
+		t.Error(err)
+	}
+
+	ids[500], ids[501] = ids[501], ids[500]
+	if err := CheckIncreasing(ids, Compare); err == nil {
+		t.Error("CheckIncreasing() accepted a swapped pair")
+	}
+}
@@ -0,0 +1,41 @@
+package kuid
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an endless sequence of random KUIDs for use with range:
+//
+//	for id := range gen.All(ctx) {
+//		...
+//	}
+//
+// KUIDs are generated on demand, so a slow loop body naturally throttles
+// generation. The sequence ends when ctx is done or generation fails; check
+// Health to tell a failure from cancellation.
+func (g *Generator) All(ctx context.Context) iter.Seq[KUID] {
+	return g.all(ctx, g.NewValue)
+}
+
+// AllOrdered is like All but yields ordered KUIDs
+func (g *Generator) AllOrdered(ctx context.Context) iter.Seq[KUID] {
+	return g.all(ctx, func() (KUID, error) {
+		k, err := g.NewOrdered()
+		if err != nil {
+			return KUID{}, err
+		}
+		return *k, nil
+	})
+}
+
+func (g *Generator) all(ctx context.Context, next func() (KUID, error)) iter.Seq[KUID] {
+	return func(yield func(KUID) bool) {
+		for ctx.Err() == nil {
+			k, err := next()
+			if err != nil || !yield(k) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package kuid
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestGeneratorAll(t *testing.T) {
+	g, _ := NewGenerator()
+	seen := map[KUID]bool{}
+	for k := range g.All(context.Background()) {
+		if seen[k] {
+			t.Fatalf("Duplicate KUID %v", k)
+		}
+		seen[k] = true
+		if len(seen) == 100 {
+			break
+		}
+	}
+	if got := g.Stats().Random; got != 100 {
+		t.Errorf("Generated %d KUIDs, want 100", got)
+	}
+}
+
+func TestGeneratorAllCancel(t *testing.T) {
+	g, _ := NewGenerator()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	for range g.All(ctx) {
+		n++
+		if n == 10 {
+			cancel()
+		}
+	}
+	if n != 10 {
+		t.Errorf("Yielded %d KUIDs, want 10", n)
+	}
+}
+
+func TestGeneratorAllFailure(t *testing.T) {
+	g, _ := NewGenerator()
+	g.Update(withEntropy(&flakyEntropy{failures: 1}))
+	for range g.All(context.Background()) {
+		t.Fatal("Yielded a KUID without entropy")
+	}
+	if g.Health().Healthy() {
+		t.Errorf("Failure not visible in Health")
+	}
+}
+
+func TestGeneratorAllOrdered(t *testing.T) {
+	g, _ := NewGenerator()
+	var prev []byte
+	n := 0
+	for k := range g.AllOrdered(context.Background()) {
+		b := k.Bytes()
+		if prev != nil && bytes.Compare(b, prev) <= 0 {
+			t.Fatalf("KUID %d not ordered", n)
+		}
+		prev = b
+		if n++; n == 1000 {
+			break
+		}
+	}
+}
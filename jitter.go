@@ -0,0 +1,63 @@
+package kuid
+
+import "time"
+
+// jitterSeed keeps jitter offsets independent of hash64, which picks
+// partitions and shards
+const jitterSeed = 0x9e3779b97f4a7c15
+
+// Jitter returns a duration in [min, max) derived from k, for spreading
+// retries, cron offsets and cache expiries across entities while keeping
+// each entity's offset stable. It returns min when max <= min.
+//
+// The offset is hash64 of k XOR a fixed seed, mixed again and taken modulo
+// the span, so it is well spread for ordered and random KUIDs alike and
+// uncorrelated with Partition.
+func Jitter(k KUID, min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(mix64(hash64(&k)^jitterSeed)%uint64(max-min))
+}
+
+// Backoff returns the delay before retry attempt (starting at 0) for k:
+// base doubled per attempt and capped at max, then reduced by a jitter of
+// up to half, so delays fall in [d/2, d). Each KUID gets its own stable
+// sequence of delays. It returns 0 unless base and max are positive.
+func Backoff(k KUID, attempt int, base, max time.Duration) time.Duration {
+	if base <= 0 || max <= 0 {
+		return 0
+	}
+	d := base
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max || d < 0 {
+		d = max
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return d - half + time.Duration(mix64(hash64(&k)^jitterSeed^uint64(attempt))%uint64(half))
+}
+
+// hash64 is the hash behind Partition and the shard choice of Dedup,
+// KeyedMutex, SingleFlight and RateLimiter. It mixes msb, then folds in
+// lsb and mixes again, so KUIDs with fixed fields in either half, such as
+// type tags, v6 node IDs or child indexes, still spread evenly. Mixing the
+// halves separately also keeps KUIDs whose halves XOR to the same value
+// from colliding.
+func hash64(k *KUID) uint64 {
+	return mix64(mix64(k.msb) ^ k.lsb)
+}
+
+// mix64 is the SplitMix64 finalizer
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
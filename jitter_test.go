@@ -0,0 +1,88 @@
+package kuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	k, _ := NewValue()
+	min, max := 10*time.Second, 70*time.Second
+	d := Jitter(k, min, max)
+	if d < min || d >= max {
+		t.Errorf("Jitter() = %v, want in [%v, %v)", d, min, max)
+	}
+	if Jitter(k, min, max) != d {
+		t.Error("Jitter() is not stable")
+	}
+	if got := Jitter(k, max, min); got != max {
+		t.Errorf("Jitter(max < min) = %v, want %v", got, max)
+	}
+	if got := Jitter(k, min, min); got != min {
+		t.Errorf("Jitter(min == max) = %v, want %v", got, min)
+	}
+}
+
+func TestJitterSpread(t *testing.T) {
+	// Ordered KUIDs minted together differ only in their low bits and
+	// must still spread over the whole range
+	g, _ := NewGenerator()
+	const buckets = 10
+	var counts [buckets]int
+	for i := 0; i < 5000; i++ {
+		k, _ := g.NewOrdered()
+		counts[Jitter(*k, 0, buckets*time.Minute)/time.Minute]++
+	}
+	for b, c := range counts {
+		if c < 400 || c > 600 {
+			t.Errorf("minute %d has %d of 5000 jitters", b, c)
+		}
+	}
+
+	// Jitter is independent of Partition
+	same := 0
+	for i := 0; i < 1000; i++ {
+		k, _ := NewValue()
+		if k.Partition(10) == int(Jitter(k, 0, 10)) {
+			same++
+		}
+	}
+	if same > 150 {
+		t.Errorf("Jitter matches Partition for %d of 1000 KUIDs", same)
+	}
+
+	// KUIDs whose halves XOR to the same value get their own jitters
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		x := mix64(uint64(i))
+		seen[Jitter(KUID{msb: x, lsb: x ^ 0x1234}, 0, time.Hour)] = true
+	}
+	if len(seen) < 90 {
+		t.Errorf("100 KUIDs sharing msb^lsb got %d distinct jitters", len(seen))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	k, _ := NewValue()
+	base, max := 100*time.Millisecond, 10*time.Second
+	want := []time.Duration{100, 200, 400, 800, 1600, 3200, 6400, 10000, 10000}
+	for attempt, full := range want {
+		full *= time.Millisecond
+		d := Backoff(k, attempt, base, max)
+		if d < full-full/2 || d >= full {
+			t.Errorf("Backoff(attempt %d) = %v, want in [%v, %v)", attempt, d, full-full/2, full)
+		}
+		if Backoff(k, attempt, base, max) != d {
+			t.Errorf("Backoff(attempt %d) is not stable", attempt)
+		}
+	}
+	if d := Backoff(k, 1000, base, max); d < max/2 || d >= max {
+		t.Errorf("Backoff(attempt 1000) = %v", d)
+	}
+	if d := Backoff(k, 3, 0, max); d != 0 {
+		t.Errorf("Backoff(base 0) = %v, want 0", d)
+	}
+	if d := Backoff(k, 0, 1, time.Second); d != 1 {
+		t.Errorf("Backoff(base 1ns) = %v, want 1ns", d)
+	}
+}
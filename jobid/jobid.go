@@ -0,0 +1,129 @@
+// Package jobid derives KUIDs for background jobs and their retries.
+//
+// A job's KUID is a SHA-256 hash of its task type and payload, so
+// enqueueing the same work twice yields the same ID and the queue's own
+// uniqueness check drops the duplicate. Pass ID.String to asynq.TaskID or
+// use it as a machinery Signature UUID.
+//
+// Retries are stamped with the attempt number after the job KUID:
+//
+//	6f1Kx0aN2bXwGmTqYc0LzR       first attempt
+//	6f1Kx0aN2bXwGmTqYc0LzR.2     second retry
+//
+// so any attempt can be parsed back to the job it belongs to. Each retry
+// also has a KUID of its own, derived from the job KUID and attempt, for
+// correlating its logs.
+package jobid
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/ctxkuid"
+)
+
+// ErrInvalid is returned when parsing a malformed job ID
+var ErrInvalid = errors.New("invalid job ID")
+
+const attemptSep = "."
+
+// ID identifies one attempt of a job. Attempt 0 is the first run.
+type ID struct {
+	Job     kuid.KUID
+	Attempt uint32
+}
+
+// Key returns the deterministic KUID for a job of taskType with payload
+func Key(taskType string, payload []byte) *kuid.KUID {
+	h := sha256.New()
+	h.Write([]byte(taskType))
+	h.Write([]byte{0})
+	h.Write(payload)
+	k, _ := kuid.SumKUID(h)
+	return k
+}
+
+// New returns the ID of the first attempt of a job of taskType with payload
+func New(taskType string, payload []byte) ID {
+	return ID{Job: *Key(taskType, payload)}
+}
+
+// Parse parses an ID produced by ID.String
+func Parse(s string) (ID, error) {
+	job, attempt, retry := strings.Cut(s, attemptSep)
+	k, err := kuid.ParseValue(job)
+	if err != nil {
+		return ID{}, ErrInvalid
+	}
+	id := ID{Job: k}
+	if retry {
+		// Attempts are written without sign or leading zeros, so every ID
+		// has exactly one string form
+		n, err := strconv.ParseUint(attempt, 10, 32)
+		if err != nil || n == 0 || attempt[0] == '0' {
+			return ID{}, ErrInvalid
+		}
+		id.Attempt = uint32(n)
+	}
+	return id, nil
+}
+
+// String returns the job KUID, followed by the attempt for retries
+func (id ID) String() string {
+	if id.Attempt == 0 {
+		return id.Job.String()
+	}
+	return id.Job.String() + attemptSep + strconv.FormatUint(uint64(id.Attempt), 10)
+}
+
+// Retry returns the ID of the next attempt
+func (id ID) Retry() ID {
+	id.Attempt++
+	return id
+}
+
+// Parent returns the attempt id retries, if it is a retry
+func (id ID) Parent() (ID, bool) {
+	if id.Attempt == 0 {
+		return ID{}, false
+	}
+	id.Attempt--
+	return id, true
+}
+
+// Lineage returns every attempt up to and including id, first run first
+func (id ID) Lineage() []ID {
+	ids := make([]ID, id.Attempt+1)
+	for i := range ids {
+		ids[i] = ID{Job: id.Job, Attempt: uint32(i)}
+	}
+	return ids
+}
+
+// KUID returns the KUID of this attempt: the job KUID for the first run,
+// and a hash of the job KUID and attempt for retries
+func (id ID) KUID() *kuid.KUID {
+	if id.Attempt == 0 {
+		k := id.Job
+		return &k
+	}
+	sum := sha256.Sum256([]byte(id.String()))
+	k, _ := kuid.FromBytes(sum[:16])
+	return k
+}
+
+// NewContext returns a copy of ctx correlated with the job, and with the
+// attempt for retries. The ID already in ctx, typically the request that
+// enqueued the job, becomes the job's parent in ctxkuid.Lineage.
+func NewContext(ctx context.Context, id ID) context.Context {
+	job := id.Job
+	ctx = ctxkuid.WithValue(ctx, &job)
+	if id.Attempt > 0 {
+		ctx = ctxkuid.WithValue(ctx, id.KUID())
+	}
+	return ctx
+}
@@ -0,0 +1,94 @@
+package jobid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/ctxkuid"
+)
+
+func TestKey(t *testing.T) {
+	a := Key("email:send", []byte(`{"to":"a@example.com"}`))
+	if b := Key("email:send", []byte(`{"to":"a@example.com"}`)); !a.Equal(b) {
+		t.Errorf("Key() not deterministic: %v != %v", a, b)
+	}
+	if b := Key("email:send", []byte(`{"to":"b@example.com"}`)); a.Equal(b) {
+		t.Errorf("Key() ignores payload")
+	}
+	// The separator keeps type and payload from running together
+	if Key("ab", []byte("c")).Equal(Key("a", []byte("bc"))) {
+		t.Errorf("Key() ambiguous between type and payload")
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	id := New("report:build", []byte("42"))
+	for i := 0; i < 3; i++ {
+		got, err := Parse(id.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", id, err)
+		}
+		if got != id {
+			t.Errorf("Parse(%q) = %v", id, got)
+		}
+		id = id.Retry()
+	}
+	if id.Attempt != 3 {
+		t.Errorf("Attempt = %d after three retries", id.Attempt)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	job := New("t", nil).String()
+	for _, s := range []string{
+		"",
+		"short",
+		job + ".",
+		job + ".0",
+		job + ".01",
+		job + ".-1",
+		job + ".+1",
+		job + ".x",
+		job + ".4294967296",
+	} {
+		if _, err := Parse(s); err != ErrInvalid {
+			t.Errorf("Parse(%q) error = %v, want %v", s, err, ErrInvalid)
+		}
+	}
+}
+
+func TestLineage(t *testing.T) {
+	first := New("t", []byte("p"))
+	if _, ok := first.Parent(); ok {
+		t.Errorf("Parent() of first attempt found")
+	}
+
+	second := first.Retry().Retry()
+	if parent, ok := second.Parent(); !ok || parent != first.Retry() {
+		t.Errorf("Parent() = %v, %v", parent, ok)
+	}
+	ids := second.Lineage()
+	if len(ids) != 3 || ids[0] != first || ids[2] != second {
+		t.Errorf("Lineage() = %v", ids)
+	}
+
+	// Retries get distinct KUIDs; the first run keeps the job KUID
+	if !first.KUID().Equal(&first.Job) {
+		t.Errorf("KUID() of first attempt = %v, want %v", first.KUID(), first.Job)
+	}
+	if a, b := first.Retry().KUID(), second.KUID(); a.Equal(b) || a.Equal(&first.Job) {
+		t.Errorf("retry KUIDs collide")
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	request, _ := kuid.NewKUID()
+	ctx := ctxkuid.NewContext(context.Background(), request)
+
+	id := New("t", nil).Retry()
+	ids := ctxkuid.Lineage(NewContext(ctx, id))
+	if len(ids) != 3 || !ids[0].Equal(request) || !ids[1].Equal(&id.Job) || !ids[2].Equal(id.KUID()) {
+		t.Errorf("Lineage() = %v, want [%v %v %v]", ids, request, id.Job, id.KUID())
+	}
+}
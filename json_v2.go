@@ -0,0 +1,42 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package kuid
+
+import (
+	"encoding/json/jsontext"
+	"errors"
+)
+
+// MarshalJSONTo implements json.MarshalerTo, writing the base62 string
+// straight to the encoder without allocating
+func (k KUID) MarshalJSONTo(enc *jsontext.Encoder) error {
+	var buf [2*size + 2]byte
+	b := append(buf[:0], '"')
+	b = k.AppendString(b)
+	b = append(b, '"')
+	return enc.WriteValue(b)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom, accepting the same
+// base62 form as UnmarshalText. A JSON null leaves the KUID unchanged.
+func (k *KUID) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	val, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	switch val.Kind() {
+	case 'n':
+		return nil
+	case '"':
+		// base62 never needs escaping, so the raw value is the string.
+		// Escaped input is rejected as an invalid character.
+		parsed, err := parseValue([]byte(val[1 : len(val)-1]))
+		if err != nil {
+			return err
+		}
+		*k = parsed
+		return nil
+	default:
+		return errors.New("KUID must be a JSON string")
+	}
+}
@@ -0,0 +1,51 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package kuid
+
+import (
+	"encoding/json/v2"
+	"errors"
+	"testing"
+)
+
+type jsonRecord struct {
+	ID  KUID  `json:"id"`
+	Ref *KUID `json:"ref"`
+}
+
+func TestJSONv2(t *testing.T) {
+	id, _ := FromString("7n42DGM5Tfl2CQZcquv8Vb")
+
+	out, err := json.Marshal(jsonRecord{ID: *id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":"7n42DGM5Tfl2CQZcquv8Vb","ref":null}`
+	if string(out) != want {
+		t.Errorf("Marshal() = %s, want %s", out, want)
+	}
+
+	var got jsonRecord
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != *id || got.Ref != nil {
+		t.Errorf("Unmarshal() = %+v", got)
+	}
+
+	tests := []struct {
+		input   string
+		wantErr error
+	}{
+		{`{"id":"short"}`, ErrInvalidLength},
+		{`{"id":"7n42DGM5Tfl2CQZcquv8V!"}`, ErrInvalidChar},
+		{`{"id":"7n42DGM5Tfl2CQZcquv8V\u0062"}`, ErrInvalidLength},
+		{`{"id":42}`, nil},
+	}
+	for _, tt := range tests {
+		err := json.Unmarshal([]byte(tt.input), new(jsonRecord))
+		if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+			t.Errorf("Unmarshal(%s) error = %v, want %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package kuid
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewJTI returns a fresh KUID string for use as a JWT "jti" claim
+func NewJTI() (string, error) {
+	k, err := NewKUID()
+	if err != nil {
+		return "", err
+	}
+	return k.String(), nil
+}
+
+// ParseJTI parses a "jti" claim back into a KUID. Both the KUID and UUID
+// forms are accepted, since many JWT libraries mint UUID identifiers.
+func ParseJTI(jti string) (*KUID, error) {
+	if len(jti) == 36 {
+		return FromUUID(jti)
+	}
+	return FromString(jti)
+}
+
+// ReplayCache records token IDs that have been used so one-time tokens can
+// be rejected when presented again. Implementations may be shared between
+// instances (e.g. backed by Redis SET NX with expiry).
+type ReplayCache interface {
+	// Use marks id as used for ttl and reports whether this is the first
+	// use. It returns false if id was already used and has not expired.
+	Use(ctx context.Context, id *KUID, ttl time.Duration) (bool, error)
+}
+
+const replaySweepEvery = 1024
+
+// MemoryReplayCache is an in-process ReplayCache
+type MemoryReplayCache struct {
+	mu      sync.Mutex
+	used    map[KUID]time.Time
+	inserts int
+	now     func() time.Time
+}
+
+// NewMemoryReplayCache creates an empty MemoryReplayCache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{used: make(map[KUID]time.Time), now: time.Now}
+}
+
+// Use implements ReplayCache
+func (c *MemoryReplayCache) Use(_ context.Context, id *KUID, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if expires, ok := c.used[*id]; ok && now.Before(expires) {
+		return false, nil
+	}
+	c.used[*id] = now.Add(ttl)
+
+	// Periodically drop expired entries so the map does not grow forever
+	if c.inserts++; c.inserts%replaySweepEvery == 0 {
+		for k, expires := range c.used {
+			if !now.Before(expires) {
+				delete(c.used, k)
+			}
+		}
+	}
+	return true, nil
+}
+
+// Len returns the number of tracked IDs, including expired ones not yet
+// swept
+func (c *MemoryReplayCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.used)
+}
@@ -0,0 +1,78 @@
+package kuid
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJTI(t *testing.T) {
+	jti, err := NewJTI()
+	if err != nil {
+		t.Fatalf("NewJTI() error = %v", err)
+	}
+	if len(jti) != size*2 {
+		t.Errorf("NewJTI() length = %d, want %d", len(jti), size*2)
+	}
+
+	kuid, err := ParseJTI(jti)
+	if err != nil {
+		t.Fatalf("ParseJTI() error = %v", err)
+	}
+	if kuid.String() != jti {
+		t.Errorf("ParseJTI() = %v, want %v", kuid.String(), jti)
+	}
+
+	fromUUID, err := ParseJTI(kuid.ToUUID())
+	if err != nil {
+		t.Fatalf("ParseJTI() of UUID error = %v", err)
+	}
+	if !fromUUID.Equal(kuid) {
+		t.Errorf("ParseJTI() of UUID = %v, want %v", fromUUID, kuid)
+	}
+
+	if _, err := ParseJTI("not-a-jti"); err == nil {
+		t.Errorf("ParseJTI() expected error")
+	}
+}
+
+func TestMemoryReplayCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryReplayCache()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	id, _ := NewKUID()
+	if first, _ := c.Use(ctx, id, time.Minute); !first {
+		t.Errorf("Use() = false on first use")
+	}
+	copied, _ := FromString(id.String())
+	if first, _ := c.Use(ctx, copied, time.Minute); first {
+		t.Errorf("Use() = true on replay")
+	}
+
+	// Once the TTL passes the ID may be used again
+	now = now.Add(2 * time.Minute)
+	if first, _ := c.Use(ctx, id, time.Minute); !first {
+		t.Errorf("Use() = false after expiry")
+	}
+}
+
+func TestMemoryReplayCacheSweep(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryReplayCache()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < replaySweepEvery-1; i++ {
+		id, _ := NewKUID()
+		c.Use(ctx, id, time.Second)
+	}
+	now = now.Add(time.Minute)
+	id, _ := NewKUID()
+	c.Use(ctx, id, time.Second)
+
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d after sweep, want 1", c.Len())
+	}
+}
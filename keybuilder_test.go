@@ -0,0 +1,58 @@
+package kuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyBuilder(t *testing.T) {
+	id, _ := NewValue()
+	at := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+	build := func() *KeyBuilder {
+		return NewKeyBuilder().Str("user", "alice").Int("page", 2).Uint("size", 50).
+			Bool("draft", false).Time("at", at).ID("org", id).Bytes("raw", []byte{1})
+	}
+
+	want := build().KUID()
+	if got := build().KUID(); !got.Equal(want) {
+		t.Errorf("KUID() not deterministic")
+	}
+
+	// Same instant in another location
+	b := NewKeyBuilder().Str("user", "alice").Int("page", 2).Uint("size", 50).
+		Bool("draft", false).Time("at", at.In(time.FixedZone("X", 3600))).ID("org", id).Bytes("raw", []byte{1})
+	if !b.KUID().Equal(want) {
+		t.Errorf("Time() depends on location")
+	}
+
+	// KUID does not finalize the builder
+	b.Str("extra", "")
+	if b.KUID().Equal(want) {
+		t.Errorf("adding a part did not change the key")
+	}
+}
+
+func TestKeyBuilderCollisions(t *testing.T) {
+	pairs := []struct {
+		name string
+		a, b *KeyBuilder
+	}{
+		{"Separator in value", NewKeyBuilder().Str("a", "b:c"), NewKeyBuilder().Str("a:b", "c")},
+		{"Split value", NewKeyBuilder().Str("a", "bc").Str("d", ""), NewKeyBuilder().Str("a", "b").Str("cd", "")},
+		{"Kind", NewKeyBuilder().Str("n", "1"), NewKeyBuilder().Int("n", 1)},
+		{"Sign", NewKeyBuilder().Int("n", -1), NewKeyBuilder().Uint("n", 1<<64-1)},
+		{"Bool", NewKeyBuilder().Bool("n", true), NewKeyBuilder().Bool("n", false)},
+		{"Order", NewKeyBuilder().Int("a", 1).Int("b", 2), NewKeyBuilder().Int("b", 2).Int("a", 1)},
+	}
+	for _, tt := range pairs {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a.KUID().Equal(tt.b.KUID()) {
+				t.Errorf("keys collide")
+			}
+		})
+	}
+
+	if !NewKeyBuilder().Int("n", 1).KUID().Equal(NewKeyBuilder().Uint("n", 1).KUID()) {
+		t.Errorf("Int and Uint differ for the same non-negative value")
+	}
+}
@@ -0,0 +1,130 @@
+package kuid
+
+import (
+	"context"
+	"sync"
+)
+
+const mutexShards = 64
+
+// KeyedMutex serializes operations on the same KUID within a process,
+// such as concurrent updates to one entity, without a distributed lock.
+// Locks are created on demand and dropped once no goroutine holds or waits
+// for them, so memory tracks the number of KUIDs in use. They are spread
+// over independently locked shards so unrelated KUIDs rarely contend. The
+// zero value is not usable; create one with NewKeyedMutex.
+type KeyedMutex struct {
+	shards [mutexShards]mutexShard
+}
+
+type mutexShard struct {
+	mu    sync.Mutex
+	locks map[KUID]*keyedLock
+}
+
+// keyedLock is a lock on one KUID. Holding it means having sent into ch;
+// refs counts the holder and waiters so the last one out can drop it.
+type keyedLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// NewKeyedMutex creates an empty KeyedMutex
+func NewKeyedMutex() *KeyedMutex {
+	m := &KeyedMutex{}
+	for i := range m.shards {
+		m.shards[i].locks = make(map[KUID]*keyedLock)
+	}
+	return m
+}
+
+// Lock locks k, waiting until it is free or ctx is done. It returns
+// ctx.Err() without the lock if ctx ends first.
+func (m *KeyedMutex) Lock(ctx context.Context, k *KUID) error {
+	s := m.shard(k)
+	s.mu.Lock()
+	l := s.acquire(*k)
+	s.mu.Unlock()
+
+	select {
+	case l.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.release(*k, l)
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryLock locks k if it is free and reports whether it did
+func (m *KeyedMutex) TryLock(k *KUID) bool {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := s.acquire(*k)
+	select {
+	case l.ch <- struct{}{}:
+		return true
+	default:
+		s.release(*k, l)
+		return false
+	}
+}
+
+// Unlock unlocks k. Like sync.Mutex, it panics if k is not locked, and
+// any goroutine may unlock a KUID another goroutine locked.
+func (m *KeyedMutex) Unlock(k *KUID) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.locks[*k]
+	if !ok {
+		panic("kuid: unlock of unlocked KUID")
+	}
+	select {
+	case <-l.ch:
+	default:
+		panic("kuid: unlock of unlocked KUID")
+	}
+	s.release(*k, l)
+}
+
+// Len returns the number of KUIDs currently locked or waited for
+func (m *KeyedMutex) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		n += len(s.locks)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// shard returns the shard holding k, chosen by hash64
+func (m *KeyedMutex) shard(k *KUID) *mutexShard {
+	return &m.shards[hash64(k)%mutexShards]
+}
+
+// acquire returns the lock for k, creating it, and counts the caller in.
+// Callers must hold s.mu.
+func (s *mutexShard) acquire(k KUID) *keyedLock {
+	l, ok := s.locks[k]
+	if !ok {
+		l = &keyedLock{ch: make(chan struct{}, 1)}
+		s.locks[k] = l
+	}
+	l.refs++
+	return l
+}
+
+// release counts the caller out, dropping the lock once unused. Callers
+// must hold s.mu.
+func (s *mutexShard) release(k KUID, l *keyedLock) {
+	if l.refs--; l.refs == 0 {
+		delete(s.locks, k)
+	}
+}
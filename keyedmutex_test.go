@@ -0,0 +1,110 @@
+package kuid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutexSerializes(t *testing.T) {
+	m := NewKeyedMutex()
+	k, _ := NewKUID()
+	other, _ := NewKUID()
+
+	var wg sync.WaitGroup
+	counter := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.Lock(context.Background(), k); err != nil {
+				t.Error(err)
+				return
+			}
+			c := counter
+			time.Sleep(time.Microsecond)
+			counter = c + 1
+			m.Unlock(k)
+		}()
+	}
+	// Other KUIDs stay available meanwhile
+	if !m.TryLock(other) {
+		t.Error("TryLock() of an unrelated KUID failed")
+	}
+	m.Unlock(other)
+	wg.Wait()
+
+	if counter != 50 {
+		t.Errorf("counter = %d, want 50", counter)
+	}
+	if n := m.Len(); n != 0 {
+		t.Errorf("Len() = %d after all unlocks, want 0", n)
+	}
+}
+
+func TestKeyedMutexTryLock(t *testing.T) {
+	m := NewKeyedMutex()
+	k, _ := NewKUID()
+	if !m.TryLock(k) {
+		t.Fatal("TryLock() of a free KUID failed")
+	}
+	if m.TryLock(k) {
+		t.Error("TryLock() of a held KUID succeeded")
+	}
+	if n := m.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+	m.Unlock(k)
+	if !m.TryLock(k) {
+		t.Error("TryLock() after Unlock() failed")
+	}
+	m.Unlock(k)
+}
+
+func TestKeyedMutexContext(t *testing.T) {
+	m := NewKeyedMutex()
+	k, _ := NewKUID()
+	m.TryLock(k)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Lock(ctx, k); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock() error = %v, want DeadlineExceeded", err)
+	}
+
+	done := make(chan error)
+	go func() { done <- m.Lock(context.Background(), k) }()
+	time.Sleep(5 * time.Millisecond)
+	m.Unlock(k)
+	if err := <-done; err != nil {
+		t.Errorf("waiting Lock() error = %v", err)
+	}
+	m.Unlock(k)
+	if n := m.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+}
+
+func TestKeyedMutexUnlockUnlocked(t *testing.T) {
+	m := NewKeyedMutex()
+	k, _ := NewKUID()
+	defer func() {
+		if recover() == nil {
+			t.Error("Unlock() of an unlocked KUID did not panic")
+		}
+	}()
+	m.Unlock(k)
+}
+
+func TestKeyedMutexShardSpread(t *testing.T) {
+	m := NewKeyedMutex()
+	used := make(map[*mutexShard]bool)
+	for _, id := range fixedLowBits(t, 1000) {
+		used[m.shard(id)] = true
+	}
+	if len(used) < mutexShards/2 {
+		t.Errorf("1000 typed and v6 KUIDs used %d of %d shards", len(used), mutexShards)
+	}
+}
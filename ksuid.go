@@ -0,0 +1,79 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const (
+	ksuidDigits = 27
+	ksuidEpoch  = 1400000000 // KSUID timestamps count seconds from here
+)
+
+var ErrKSUIDRange = errors.New("KUID timestamp outside the KSUID range")
+
+// maxKSUID is the largest 160-bit value
+var maxKSUID = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// FromKSUID converts a 27-character KSUID to an ordered KUID. The KSUID's
+// second-precision timestamp becomes the KUID timestamp, so the result
+// sorts by time among KUIDs from NewOrdered. A KSUID carries 128 payload
+// bits but only 80 fit next to the timestamp: the first 16 fill the
+// sequence and the next 64 the random half. The last 48 are dropped.
+//
+// Distinct KSUIDs minted in the same second thus collide only if their
+// first 80 payload bits match, which random payloads make negligible.
+func FromKSUID(s string) (*KUID, error) {
+	if len(s) != ksuidDigits {
+		return nil, ErrInvalidLength
+	}
+	n := new(big.Int)
+	b := big.NewInt(int64(base))
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base62Chars, s[i])
+		if digit < 0 {
+			return nil, ErrInvalidChar
+		}
+		n.Mul(n, b).Add(n, big.NewInt(int64(digit)))
+	}
+	if n.Cmp(maxKSUID) > 0 {
+		return nil, ErrOverflow
+	}
+
+	var raw [20]byte
+	n.FillBytes(raw[:])
+	ms := (int64(binary.BigEndian.Uint32(raw[0:4])) + ksuidEpoch) * 1000
+	return &KUID{
+		msb: uint64(ms)<<sequenceBits | uint64(binary.BigEndian.Uint16(raw[4:6])),
+		lsb: binary.BigEndian.Uint64(raw[6:14]),
+	}, nil
+}
+
+// ToKSUID converts an ordered KUID to a KSUID. It is the inverse of
+// FromKSUID only for KUIDs that came from a KSUID: the timestamp is
+// truncated to the second and the 48 payload bits FromKSUID dropped come
+// back as zeros, so the original KSUID is not recovered. KUIDs whose
+// timestamp falls outside 2014-05-13 to 2150-06-19 return ErrKSUIDRange.
+func (k *KUID) ToKSUID() (string, error) {
+	secs := k.Timestamp().Unix() - ksuidEpoch
+	if secs < 0 || secs > 1<<32-1 {
+		return "", ErrKSUIDRange
+	}
+
+	var raw [20]byte
+	binary.BigEndian.PutUint32(raw[0:4], uint32(secs))
+	binary.BigEndian.PutUint16(raw[4:6], k.Sequence())
+	binary.BigEndian.PutUint64(raw[6:14], k.lsb)
+
+	// big.Int.Text(62) puts lowercase before uppercase, unlike KSUIDs
+	n := new(big.Int).SetBytes(raw[:])
+	b, digit := big.NewInt(int64(base)), new(big.Int)
+	out := make([]byte, ksuidDigits)
+	for i := ksuidDigits - 1; i >= 0; i-- {
+		n.DivMod(n, b, digit)
+		out[i] = base62Chars[digit.Int64()]
+	}
+	return string(out), nil
+}
@@ -0,0 +1,75 @@
+package kuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromKSUID(t *testing.T) {
+	// Example from the segmentio/ksuid README
+	k, err := FromKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := k.Timestamp(), time.Unix(107608047+ksuidEpoch, 0); !got.Equal(want) {
+		t.Errorf("Timestamp() = %v, want %v", got, want)
+	}
+	// Payload B5A1CD34B5F99D1154FB6853345C9735: the first 80 bits are kept
+	if k.Sequence() != 0xb5a1 || k.lsb != 0xcd34b5f99d1154fb {
+		t.Errorf("payload = %04x %016x", k.Sequence(), k.lsb)
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		wantErr error
+	}{
+		{name: "Max", in: "aWgEPTl1tmebfsQzFP4bxwgy80V"},
+		{name: "Zero", in: "000000000000000000000000000"},
+		{name: "Overflow", in: "aWgEPTl1tmebfsQzFP4bxwgy80W", wantErr: ErrOverflow},
+		{name: "Short", in: "0ujtsYcgvSTl8PAuAdqWYSMnLO", wantErr: ErrInvalidLength},
+		{name: "Bad char", in: "0ujtsYcgvSTl8PAuAdqWYSMnLO-", wantErr: ErrInvalidChar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromKSUID(tt.in); err != tt.wantErr {
+				t.Errorf("FromKSUID(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToKSUID(t *testing.T) {
+	k, _ := FromKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	s, err := k.ToKSUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lossy: the last 48 payload bits come back as zeros
+	back, err := FromKSUID(s)
+	if err != nil || !back.Equal(k) {
+		t.Errorf("FromKSUID(ToKSUID()) = %v, %v, want %v", back, err, k)
+	}
+	if s == "0ujtsYcgvSTl8PAuAdqWYSMnLOv" {
+		t.Errorf("ToKSUID() restored dropped payload bits")
+	}
+	if s[:5] != "0ujts" {
+		t.Errorf("ToKSUID() = %q, want the timestamp prefix of the original", s)
+	}
+
+	// Ordered KUIDs a second apart sort the same way as KSUIDs
+	ms := uint64(time.Now().UnixMilli())
+	a := &KUID{msb: ms<<sequenceBits | 0xffff, lsb: 1<<64 - 1}
+	b := &KUID{msb: (ms + 1000) << sequenceBits}
+	sa, _ := a.ToKSUID()
+	sb, _ := b.ToKSUID()
+	if sa >= sb {
+		t.Errorf("ToKSUID() order %q >= %q", sa, sb)
+	}
+
+	for _, k := range []*KUID{{}, {msb: 1<<64 - 1}} {
+		if _, err := k.ToKSUID(); err != ErrKSUIDRange {
+			t.Errorf("ToKSUID(%v) error = %v, want %v", k, err, ErrKSUIDRange)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package kuid
 
 import (
-	"crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"math/bits"
 	"strings"
 )
 
@@ -24,13 +24,14 @@ var (
 	ErrInvalidLength = errors.New("invalid KUID string length")
 	ErrInvalidChar   = errors.New("invalid character in KUID string")
 	ErrInvalidUUID   = errors.New("invalid UUID format")
+	ErrOverflow      = errors.New("value overflows 128 bits")
+	ErrNotFound      = errors.New("KUID not found")
 )
 
 // NewKUID generates a new random KUID
 func NewKUID() (*KUID, error) {
 	var buf [16]byte
-	_, err := rand.Read(buf[:])
-	if err != nil {
+	if err := readRandom(buf[:]); err != nil {
 		return nil, err
 	}
 
@@ -94,8 +95,9 @@ func encodeLong(value uint64) string {
 	return string(bytes)
 }
 
-// decodeLong decodes a base62 string back to uint64
-func decodeLong(s string) (uint64, error) {
+// decodeLong decodes a base62 string back to uint64, rejecting strings
+// above 2^64-1 so that every accepted string is the canonical encoding
+func decodeLong[T string | []byte](s T) (uint64, error) {
 	if len(s) != size {
 		return 0, ErrInvalidLength
 	}
@@ -106,7 +108,12 @@ func decodeLong(s string) (uint64, error) {
 		if digit < 0 {
 			return 0, ErrInvalidChar
 		}
-		value = value*base + uint64(digit)
+		hi, lo := bits.Mul64(value, base)
+		lo, carry := bits.Add64(lo, uint64(digit), 0)
+		if hi != 0 || carry != 0 {
+			return 0, ErrOverflow
+		}
+		value = lo
 	}
 	return value, nil
 }
@@ -116,23 +123,15 @@ func (k KUID) String() string {
 	return encodeLong(k.msb) + encodeLong(k.lsb)
 }
 
-// FromString creates a KUID from its string representation
+// FromString creates a KUID from its string representation. Each 11-character
+// half must encode at most 2^64-1, "LygHa16AHYF"; larger halves return
+// ErrOverflow rather than wrapping, so every accepted string is canonical.
 func FromString(s string) (*KUID, error) {
-	if len(s) != size*2 {
-		return nil, ErrInvalidLength
-	}
-
-	msb, err := decodeLong(s[:size])
-	if err != nil {
-		return nil, err
-	}
-
-	lsb, err := decodeLong(s[size:])
+	k, err := ParseValue(s)
 	if err != nil {
 		return nil, err
 	}
-
-	return &KUID{msb: msb, lsb: lsb}, nil
+	return &k, nil
 }
 
 // Bytes returns the KUID as a 16-byte slice
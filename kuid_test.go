@@ -119,6 +119,21 @@ func TestFromString(t *testing.T) {
 			str:     "",
 			wantErr: true,
 		},
+		{
+			name:    "Maximum value",
+			str:     "LygHa16AHYFLygHa16AHYF",
+			wantErr: false,
+		},
+		{
+			name:    "Half overflows 64 bits",
+			str:     "LygHa16AHYG00000000000",
+			wantErr: true,
+		},
+		{
+			name:    "Non-canonical alias",
+			str:     "z0000000000" + "00000000000",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
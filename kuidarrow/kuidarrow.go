@@ -0,0 +1,65 @@
+// Package kuidarrow converts KUID slices to and from Apache Arrow
+// FixedSizeBinary(16) arrays, so ID columns can be processed by vectorized
+// analytics engines without materializing strings. Values are stored in
+// UUID (big-endian) byte order.
+//
+// It lives in its own module so the core kuid package stays free of the
+// Arrow dependency.
+package kuidarrow
+
+import (
+	"fmt"
+
+	"github.com/alphabatem/kuid"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Type is the Arrow data type of KUID columns
+var Type = &arrow.FixedSizeBinaryType{ByteWidth: 16}
+
+// Append appends ids to a FixedSizeBinary(16) builder. Nil entries are
+// appended as nulls.
+func Append(b *array.FixedSizeBinaryBuilder, ids []*kuid.KUID) {
+	b.Reserve(len(ids))
+	for _, id := range ids {
+		if id == nil {
+			b.AppendNull()
+			continue
+		}
+		a := id.Array()
+		b.Append(a[:])
+	}
+}
+
+// NewArray builds a FixedSizeBinary(16) array from ids using mem (the Go
+// allocator when nil). Nil entries become nulls. The caller must Release
+// the returned array.
+func NewArray(mem memory.Allocator, ids []*kuid.KUID) *array.FixedSizeBinary {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	b := array.NewFixedSizeBinaryBuilder(mem, Type)
+	defer b.Release()
+
+	Append(b, ids)
+	return b.NewFixedSizeBinaryArray()
+}
+
+// FromArray reads KUIDs from a FixedSizeBinary(16) array. Nulls are
+// returned as nil entries.
+func FromArray(arr *array.FixedSizeBinary) ([]*kuid.KUID, error) {
+	if width := arr.DataType().(*arrow.FixedSizeBinaryType).ByteWidth; width != 16 {
+		return nil, fmt.Errorf("KUID column must be FixedSizeBinary(16), got width %d", width)
+	}
+
+	ids := make([]*kuid.KUID, arr.Len())
+	for i := range ids {
+		if arr.IsNull(i) {
+			continue
+		}
+		ids[i] = kuid.FromArray([16]byte(arr.Value(i)))
+	}
+	return ids, nil
+}
@@ -0,0 +1,49 @@
+package kuidarrow
+
+import (
+	"testing"
+
+	"github.com/alphabatem/kuid"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestRoundtrip(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	a, _ := kuid.NewKUID()
+	b, _ := kuid.NewKUID()
+	ids := []*kuid.KUID{a, nil, b}
+
+	arr := NewArray(mem, ids)
+	defer arr.Release()
+
+	if arr.Len() != 3 || arr.NullN() != 1 {
+		t.Fatalf("NewArray() len = %d, nulls = %d, want 3 and 1", arr.Len(), arr.NullN())
+	}
+	if string(arr.Value(0)) != string(a.Bytes()) {
+		t.Errorf("Value(0) = %x, want %x", arr.Value(0), a.Bytes())
+	}
+
+	got, err := FromArray(arr)
+	if err != nil {
+		t.Fatalf("FromArray() error = %v", err)
+	}
+	if !got[0].Equal(a) || got[1] != nil || !got[2].Equal(b) {
+		t.Errorf("FromArray() = %v, want %v", got, ids)
+	}
+}
+
+func TestFromArrayWrongWidth(t *testing.T) {
+	b := array.NewFixedSizeBinaryBuilder(memory.DefaultAllocator, &arrow.FixedSizeBinaryType{ByteWidth: 8})
+	defer b.Release()
+	b.Append(make([]byte, 8))
+	arr := b.NewFixedSizeBinaryArray()
+	defer arr.Release()
+
+	if _, err := FromArray(arr); err == nil {
+		t.Errorf("FromArray() expected error for width 8")
+	}
+}
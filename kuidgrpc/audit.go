@@ -0,0 +1,209 @@
+package kuidgrpc
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/kuidgrpc/kuidpb"
+)
+
+const (
+	defaultMaxSkew  = time.Second
+	defaultRemember = 1 << 20
+)
+
+// AuditOptions configures an Auditor
+type AuditOptions struct {
+	// Nodes are the generator services to sample, by name
+	Nodes map[string]kuidpb.GeneratorClient
+	// SampleSize is the number of recent IDs requested from each node per
+	// audit; zero asks for all the node keeps
+	SampleSize int
+	// MaxSkew is the largest tolerated difference between a node's clock
+	// and the auditor's; zero means one second
+	MaxSkew time.Duration
+	// Remember is the number of sampled IDs kept to catch duplicates
+	// minted in different audit rounds; zero means 1Mi
+	Remember int
+}
+
+// Auditor continuously checks that participating nodes never mint the same
+// ID and that their clocks agree. Run a single Auditor per fleet, such as
+// on the elected leader, so its view spans every node.
+type Auditor struct {
+	opts AuditOptions
+	now  func() time.Time
+
+	mu    sync.Mutex
+	seen  map[kuid.KUID]string // ID to the node that first reported it
+	order []kuid.KUID          // seen in insertion order, for eviction
+}
+
+// NewAuditor creates an Auditor
+func NewAuditor(opts AuditOptions) *Auditor {
+	if opts.MaxSkew <= 0 {
+		opts.MaxSkew = defaultMaxSkew
+	}
+	if opts.Remember <= 0 {
+		opts.Remember = defaultRemember
+	}
+	return &Auditor{opts: opts, now: time.Now, seen: make(map[kuid.KUID]string)}
+}
+
+// AuditReport is the outcome of one audit round
+type AuditReport struct {
+	Time       time.Time
+	Nodes      map[string]NodeAudit
+	Duplicates []AuditDuplicate
+}
+
+// NodeAudit is one node's part of an AuditReport
+type NodeAudit struct {
+	Sampled int           // IDs returned by the node
+	Skew    time.Duration // node clock minus the auditor's, estimated at the midpoint of the call
+	Skewed  bool          // Skew exceeds MaxSkew
+	Err     error         // why the node could not be sampled
+}
+
+// AuditDuplicate is an ID reported by more than one node
+type AuditDuplicate struct {
+	ID    kuid.KUID
+	Nodes []string
+}
+
+// OK reports whether every node was sampled, no duplicates were found and
+// no clock is skewed
+func (r AuditReport) OK() bool {
+	if len(r.Duplicates) > 0 {
+		return false
+	}
+	for _, n := range r.Nodes {
+		if n.Err != nil || n.Skewed {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeSample is what one node returned
+type nodeSample struct {
+	name  string
+	ids   []kuid.KUID
+	audit NodeAudit
+}
+
+// Audit samples every node once and cross-checks the results against each
+// other and against earlier rounds
+func (a *Auditor) Audit(ctx context.Context) AuditReport {
+	samples := make([]nodeSample, 0, len(a.opts.Nodes))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, client := range a.opts.Nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := a.sample(ctx, name, client)
+			mu.Lock()
+			samples = append(samples, s)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	// Deterministic order, so duplicates list nodes the same way each round
+	slices.SortFunc(samples, func(x, y nodeSample) int {
+		switch {
+		case x.name < y.name:
+			return -1
+		case x.name > y.name:
+			return 1
+		}
+		return 0
+	})
+
+	report := AuditReport{Time: a.now(), Nodes: make(map[string]NodeAudit, len(samples))}
+	dups := make(map[kuid.KUID][]string)
+	a.mu.Lock()
+	for _, s := range samples {
+		report.Nodes[s.name] = s.audit
+		// A node's ring returns the same IDs in successive rounds, so only
+		// a repeat within one sample or from another node is a duplicate
+		inSample := make(map[kuid.KUID]bool, len(s.ids))
+		for _, id := range s.ids {
+			first, ok := a.seen[id]
+			switch {
+			case !ok:
+				a.remember(id, s.name)
+			case first == s.name && !inSample[id]:
+			default:
+				if len(dups[id]) == 0 {
+					dups[id] = []string{first}
+				}
+				dups[id] = append(dups[id], s.name)
+			}
+			inSample[id] = true
+		}
+	}
+	a.mu.Unlock()
+
+	for id, nodes := range dups {
+		report.Duplicates = append(report.Duplicates, AuditDuplicate{ID: id, Nodes: nodes})
+	}
+	slices.SortFunc(report.Duplicates, func(x, y AuditDuplicate) int {
+		return slices.Compare(x.ID.Bytes(), y.ID.Bytes())
+	})
+	return report
+}
+
+// sample fetches one node's recent IDs and estimates its clock skew
+func (a *Auditor) sample(ctx context.Context, name string, client kuidpb.GeneratorClient) nodeSample {
+	s := nodeSample{name: name}
+	sent := a.now()
+	resp, err := client.Sample(ctx, &kuidpb.SampleRequest{Limit: uint32(a.opts.SampleSize)})
+	received := a.now()
+	if err != nil {
+		s.audit.Err = err
+		return s
+	}
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	s.audit.Skew = resp.GetNow().AsTime().Sub(midpoint)
+	s.audit.Skewed = s.audit.Skew > a.opts.MaxSkew || s.audit.Skew < -a.opts.MaxSkew
+
+	ids, err := IDs(&kuidpb.GenerateResponse{Ids: resp.GetIds()})
+	if err != nil {
+		s.audit.Err = err
+		return s
+	}
+	s.ids = ids
+	s.audit.Sampled = len(ids)
+	return s
+}
+
+// remember records the node that first reported id, forgetting the oldest
+// ID once Remember are held. The caller must hold a.mu.
+func (a *Auditor) remember(id kuid.KUID, node string) {
+	if len(a.order) >= a.opts.Remember {
+		delete(a.seen, a.order[0])
+		a.order = a.order[1:]
+	}
+	a.seen[id] = node
+	a.order = append(a.order, id)
+}
+
+// Run audits every interval until ctx is done, passing each report to fn
+func (a *Auditor) Run(ctx context.Context, interval time.Duration, fn func(AuditReport)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fn(a.Audit(ctx))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package kuidgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/kuidgrpc/kuidpb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeSampler is a GeneratorClient answering Sample with fixed IDs
+type fakeSampler struct {
+	kuidpb.GeneratorClient
+	ids  []kuid.KUID
+	skew time.Duration
+	err  error
+}
+
+func (f *fakeSampler) Sample(ctx context.Context, req *kuidpb.SampleRequest, _ ...grpc.CallOption) (*kuidpb.SampleResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &kuidpb.SampleResponse{Now: timestamppb.New(time.Now().Add(f.skew))}
+	for _, id := range f.ids {
+		resp.Ids = append(resp.Ids, id.Bytes())
+	}
+	return resp, nil
+}
+
+func TestAuditor(t *testing.T) {
+	ids := make([]kuid.KUID, 4)
+	for i := range ids {
+		ids[i], _ = kuid.NewValue()
+	}
+	a := &fakeSampler{ids: ids[:2]}
+	b := &fakeSampler{ids: ids[2:3]}
+	auditor := NewAuditor(AuditOptions{Nodes: map[string]kuidpb.GeneratorClient{"a": a, "b": b}})
+
+	// Nodes return overlapping samples round after round without alarm
+	for i := 0; i < 2; i++ {
+		if r := auditor.Audit(context.Background()); !r.OK() {
+			t.Fatalf("round %d: report = %+v, want OK", i, r)
+		}
+	}
+
+	// b mints an ID a minted in an earlier round
+	b.ids = []kuid.KUID{ids[3], ids[0]}
+	r := auditor.Audit(context.Background())
+	if r.OK() || len(r.Duplicates) != 1 {
+		t.Fatalf("report = %+v, want one duplicate", r)
+	}
+	if d := r.Duplicates[0]; d.ID != ids[0] || len(d.Nodes) != 2 || d.Nodes[0] != "a" || d.Nodes[1] != "b" {
+		t.Errorf("duplicate = %+v, want %v from a and b", d, ids[0])
+	}
+	if r.Nodes["b"].Sampled != 2 {
+		t.Errorf("b sampled %d IDs, want 2", r.Nodes["b"].Sampled)
+	}
+}
+
+func TestAuditorSameNodeDuplicate(t *testing.T) {
+	id, _ := kuid.NewValue()
+	a := &fakeSampler{ids: []kuid.KUID{id, id}}
+	r := NewAuditor(AuditOptions{Nodes: map[string]kuidpb.GeneratorClient{"a": a}}).Audit(context.Background())
+	if len(r.Duplicates) != 1 {
+		t.Errorf("report = %+v, want a duplicate within one sample", r)
+	}
+}
+
+func TestAuditorSkewAndErrors(t *testing.T) {
+	auditor := NewAuditor(AuditOptions{
+		Nodes: map[string]kuidpb.GeneratorClient{
+			"fast": &fakeSampler{skew: time.Minute},
+			"down": &fakeSampler{err: errors.New("unavailable")},
+			"ok":   &fakeSampler{skew: 10 * time.Millisecond},
+		},
+		MaxSkew: 500 * time.Millisecond,
+	})
+	r := auditor.Audit(context.Background())
+	if r.OK() {
+		t.Fatal("report OK with a skewed and an unreachable node")
+	}
+	if n := r.Nodes["fast"]; !n.Skewed || n.Skew < 59*time.Second {
+		t.Errorf("fast node = %+v, want about a minute of skew", n)
+	}
+	if r.Nodes["down"].Err == nil {
+		t.Errorf("down node reported no error")
+	}
+	if n := r.Nodes["ok"]; n.Skewed || n.Err != nil {
+		t.Errorf("ok node = %+v", n)
+	}
+}
+
+func TestAuditorRemember(t *testing.T) {
+	ids := make([]kuid.KUID, 3)
+	for i := range ids {
+		ids[i], _ = kuid.NewValue()
+	}
+	a := &fakeSampler{ids: ids}
+	b := &fakeSampler{}
+	auditor := NewAuditor(AuditOptions{Nodes: map[string]kuidpb.GeneratorClient{"a": a, "b": b}, Remember: 2})
+	auditor.Audit(context.Background())
+
+	// Only the two most recent IDs are remembered
+	a.ids = nil
+	b.ids = ids[:1]
+	if r := auditor.Audit(context.Background()); !r.OK() {
+		t.Errorf("forgotten ID reported as duplicate: %+v", r)
+	}
+	b.ids = ids[2:]
+	if r := auditor.Audit(context.Background()); len(r.Duplicates) != 1 {
+		t.Errorf("remembered ID not reported as duplicate: %+v", r)
+	}
+}
+
+func TestSample(t *testing.T) {
+	client := dialGenerator(t, ServerOptions{SampleSize: 4})
+	stream, _ := client.Generate(context.Background(), &kuidpb.GenerateRequest{Count: 6})
+	ids, _, err := receive(t, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Sample(context.Background(), &kuidpb.SampleRequest{Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampled, _ := IDs(&kuidpb.GenerateResponse{Ids: resp.GetIds()})
+	if len(sampled) != 3 || sampled[0] != ids[5] || sampled[2] != ids[3] {
+		t.Errorf("Sample() = %v, want the newest 3 of %v", sampled, ids)
+	}
+	if time.Since(resp.GetNow().AsTime()) > time.Minute {
+		t.Errorf("Sample() Now = %v", resp.GetNow().AsTime())
+	}
+
+	all, _ := client.Sample(context.Background(), &kuidpb.SampleRequest{})
+	if len(all.GetIds()) != 4 {
+		t.Errorf("Sample() without limit returned %d IDs, want 4", len(all.GetIds()))
+	}
+}
@@ -0,0 +1,249 @@
+package kuidgrpc
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/kuidgrpc/kuidpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// defaultMaxBatchSize caps IDs per GenerateResponse when
+	// ServerOptions.MaxBatchSize is zero
+	defaultMaxBatchSize = 1000
+	// defaultSampleSize is the number of recent IDs kept for Sample when
+	// ServerOptions.SampleSize is zero
+	defaultSampleSize = 1024
+)
+
+// ServerOptions configures a GeneratorServer
+type ServerOptions struct {
+	// MaxRate caps the IDs per second sent on one stream. Zero means no cap,
+	// leaving gRPC flow control to pace fast streams.
+	MaxRate float64
+	// MaxBatchSize caps IDs per response message; zero means 1000
+	MaxBatchSize int
+	// Blocks serves AllocateBlock; the RPC is unimplemented when nil
+	Blocks *kuid.BlockAllocator
+	// SampleSize is the number of recently minted IDs kept for Sample;
+	// zero means 1024
+	SampleSize int
+}
+
+// GeneratorServer implements the kuid.v1.Generator service on a
+// kuid.Generator. Register it with kuidpb.RegisterGeneratorServer.
+type GeneratorServer struct {
+	kuidpb.UnimplementedGeneratorServer
+	gen    *kuid.Generator
+	opts   ServerOptions
+	recent *recentIDs
+}
+
+// NewGeneratorServer creates a GeneratorServer minting IDs from gen
+func NewGeneratorServer(gen *kuid.Generator, opts ServerOptions) *GeneratorServer {
+	if opts.MaxBatchSize <= 0 {
+		opts.MaxBatchSize = defaultMaxBatchSize
+	}
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = defaultSampleSize
+	}
+	return &GeneratorServer{gen: gen, opts: opts, recent: newRecentIDs(opts.SampleSize)}
+}
+
+// Generate streams IDs at the lower of the requested rate and MaxRate
+func (s *GeneratorServer) Generate(req *kuidpb.GenerateRequest, stream kuidpb.Generator_GenerateServer) error {
+	if req.GetRate() < 0 || math.IsNaN(req.GetRate()) {
+		return status.Error(codes.InvalidArgument, "rate must not be negative")
+	}
+	var next func() (*kuid.KUID, error)
+	switch req.GetKind() {
+	case kuidpb.Kind_KIND_RANDOM:
+		next = s.gen.New
+	case kuidpb.Kind_KIND_ORDERED:
+		next = s.gen.NewOrdered
+	case kuidpb.Kind_KIND_V4:
+		next = s.gen.NewV4
+	default:
+		return status.Errorf(codes.InvalidArgument, "unknown kind %v", req.GetKind())
+	}
+
+	rate := s.grantRate(req.GetRate())
+	batch := min(max(int(req.GetBatchSize()), 1), s.opts.MaxBatchSize)
+	if rate > 0 {
+		// Never batch more than a second's worth, so slow streams stay live
+		batch = min(batch, max(int(rate), 1))
+	}
+
+	// Pace by batch: each one is sent no earlier than its share of the rate
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(batch) / rate * float64(time.Second))
+	}
+	ctx := stream.Context()
+	start := time.Now()
+	sent := uint64(0)
+	for batches := 0; req.GetCount() == 0 || sent < req.GetCount(); batches++ {
+		if interval > 0 {
+			wait := time.Until(start.Add(time.Duration(batches) * interval))
+			if wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return status.FromContextError(ctx.Err()).Err()
+				case <-t.C:
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		n := uint64(batch)
+		if req.GetCount() > 0 {
+			n = min(n, req.GetCount()-sent)
+		}
+		resp := &kuidpb.GenerateResponse{Ids: make([][]byte, n), GrantedRate: rate}
+		minted := make([]kuid.KUID, n)
+		for i := range resp.Ids {
+			k, err := next()
+			if err != nil {
+				return status.Errorf(codes.Unavailable, "generate: %v", err)
+			}
+			resp.Ids[i] = k.Bytes()
+			minted[i] = *k
+		}
+		s.recent.add(minted...)
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		sent += n
+	}
+	return nil
+}
+
+// AllocateBlock reserves a sequence range or a batch of IDs from the
+// server's BlockAllocator
+func (s *GeneratorServer) AllocateBlock(ctx context.Context, req *kuidpb.AllocateBlockRequest) (*kuidpb.AllocateBlockResponse, error) {
+	if s.opts.Blocks == nil {
+		return nil, status.Error(codes.Unimplemented, "block allocation is not enabled")
+	}
+	var (
+		b   kuid.Block
+		err error
+	)
+	switch req.GetMode() {
+	case kuidpb.BlockMode_BLOCK_MODE_SEQUENCE:
+		b, err = s.opts.Blocks.AllocateBlock(req.GetCount())
+	case kuidpb.BlockMode_BLOCK_MODE_BATCH:
+		b, err = s.opts.Blocks.AllocateBatch(req.GetCount())
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown mode %v", req.GetMode())
+	}
+	switch {
+	case err == kuid.ErrBlockSize:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err == kuid.ErrSequenceExhausted:
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Unavailable, "allocate: %v", err)
+	}
+
+	resp := &kuidpb.AllocateBlockResponse{
+		Lease:   b.Lease.Bytes(),
+		First:   b.First,
+		Count:   b.Count,
+		Expires: timestamppb.New(b.Expires),
+	}
+	s.recent.add(b.IDs...)
+	if b.IDs == nil {
+		resp.SequenceBits = uint32(s.opts.Blocks.SequenceBits())
+	}
+	for _, id := range b.IDs {
+		resp.Ids = append(resp.Ids, id.Bytes())
+	}
+	return resp, nil
+}
+
+// Sample returns the newest IDs this server minted, with its clock
+func (s *GeneratorServer) Sample(ctx context.Context, req *kuidpb.SampleRequest) (*kuidpb.SampleResponse, error) {
+	ids := s.recent.newest(int(req.GetLimit()))
+	resp := &kuidpb.SampleResponse{Ids: make([][]byte, len(ids)), Now: timestamppb.Now()}
+	for i, id := range ids {
+		resp.Ids[i] = id.Bytes()
+	}
+	return resp, nil
+}
+
+// grantRate negotiates a stream's rate; zero means unlimited
+func (s *GeneratorServer) grantRate(requested float64) float64 {
+	switch {
+	case s.opts.MaxRate <= 0:
+		return requested
+	case requested == 0:
+		return s.opts.MaxRate
+	}
+	return min(requested, s.opts.MaxRate)
+}
+
+// IDs decodes the IDs of a GenerateResponse
+func IDs(resp *kuidpb.GenerateResponse) ([]kuid.KUID, error) {
+	ids := make([]kuid.KUID, len(resp.GetIds()))
+	for i, b := range resp.GetIds() {
+		k, err := kuid.FromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = *k
+	}
+	return ids, nil
+}
+
+// recentIDs is a ring of the IDs a server minted most recently
+type recentIDs struct {
+	mu   sync.Mutex
+	ids  []kuid.KUID
+	next int
+	full bool
+}
+
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{ids: make([]kuid.KUID, size)}
+}
+
+func (r *recentIDs) add(ids ...kuid.KUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		r.ids[r.next] = id
+		r.next = (r.next + 1) % len(r.ids)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+}
+
+// newest returns up to limit IDs, newest first; all of them when limit is
+// zero
+func (r *recentIDs) newest(limit int) []kuid.KUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.ids)
+	}
+	if limit > 0 {
+		n = min(n, limit)
+	}
+	out := make([]kuid.KUID, n)
+	for i := range out {
+		out[i] = r.ids[(r.next-1-i+len(r.ids))%len(r.ids)]
+	}
+	return out
+}
@@ -0,0 +1,186 @@
+package kuidgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/kuidgrpc/kuidpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGenerator serves a GeneratorServer in memory and returns a client
+func dialGenerator(t *testing.T, opts ServerOptions) kuidpb.GeneratorClient {
+	t.Helper()
+	gen, _ := kuid.NewGenerator()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	kuidpb.RegisterGeneratorServer(srv, NewGeneratorServer(gen, opts))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return kuidpb.NewGeneratorClient(conn)
+}
+
+// receive drains a Generate stream
+func receive(t *testing.T, stream kuidpb.Generator_GenerateClient) ([]kuid.KUID, []*kuidpb.GenerateResponse, error) {
+	t.Helper()
+	var ids []kuid.KUID
+	var resps []*kuidpb.GenerateResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return ids, resps, nil
+		}
+		if err != nil {
+			return ids, resps, err
+		}
+		batch, err := IDs(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, batch...)
+		resps = append(resps, resp)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	client := dialGenerator(t, ServerOptions{MaxBatchSize: 8})
+	stream, err := client.Generate(context.Background(), &kuidpb.GenerateRequest{
+		Kind:      kuidpb.Kind_KIND_ORDERED,
+		Count:     50,
+		BatchSize: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, resps, err := receive(t, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 50 || len(resps) != 7 {
+		t.Fatalf("got %d IDs in %d messages, want 50 in 7", len(ids), len(resps))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i].String() <= ids[i-1].String() {
+			t.Fatalf("ordered IDs out of order at %d", i)
+		}
+	}
+}
+
+func TestGenerateRate(t *testing.T) {
+	client := dialGenerator(t, ServerOptions{MaxRate: 100})
+	start := time.Now()
+	stream, err := client.Generate(context.Background(), &kuidpb.GenerateRequest{
+		Kind:      kuidpb.Kind_KIND_V4,
+		Rate:      1000,
+		Count:     20,
+		BatchSize: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, resps, err := receive(t, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Four batches of five at 100/s: the last is due 150ms in
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("20 IDs at 100/s took %v, want at least 150ms", elapsed)
+	}
+	if resps[0].GetGrantedRate() != 100 {
+		t.Errorf("GrantedRate = %v, want 100", resps[0].GetGrantedRate())
+	}
+	for _, id := range ids {
+		if id.Version() != 4 {
+			t.Fatalf("Version() = %d, want 4", id.Version())
+		}
+	}
+}
+
+func TestGenerateCancel(t *testing.T) {
+	client := dialGenerator(t, ServerOptions{MaxRate: 10})
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Generate(ctx, &kuidpb.GenerateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, _, err := receive(t, stream); status.Code(err) != codes.Canceled {
+		t.Errorf("Recv() after cancel error = %v, want Canceled", err)
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	client := dialGenerator(t, ServerOptions{})
+	for _, req := range []*kuidpb.GenerateRequest{
+		{Rate: -1},
+		{Kind: kuidpb.Kind(42)},
+	} {
+		stream, err := client.Generate(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := receive(t, stream); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Generate(%v) error = %v, want InvalidArgument", req, err)
+		}
+	}
+}
+
+func TestAllocateBlock(t *testing.T) {
+	blocks, _ := kuid.NewBlockAllocator(kuid.BlockOptions{Sequence: kuid.SequenceOptions{Bits: 48}, MaxBlockSize: 100})
+	client := dialGenerator(t, ServerOptions{Blocks: blocks})
+	ctx := context.Background()
+
+	seq, err := client.AllocateBlock(ctx, &kuidpb.AllocateBlockRequest{Count: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq.GetFirst() != 0 || seq.GetCount() != 50 || seq.GetSequenceBits() != 48 || len(seq.GetIds()) != 0 {
+		t.Errorf("sequence block = %v", seq)
+	}
+	// An ID minted offline from the block traces back to its lease
+	opts := kuid.SequenceOptions{Bits: int(seq.GetSequenceBits())}
+	id, _ := kuid.FromSequence(seq.GetFirst()+49, opts)
+	n, _ := id.ToSequence(opts)
+	owner, ok := blocks.SequenceOwner(n)
+	if lease, _ := kuid.FromBytes(seq.GetLease()); !ok || !owner.Lease.Equal(lease) {
+		t.Errorf("Owner() = %+v, %v", owner, ok)
+	}
+
+	batch, err := client.AllocateBlock(ctx, &kuidpb.AllocateBlockRequest{Mode: kuidpb.BlockMode_BLOCK_MODE_BATCH, Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.GetIds()) != 3 || batch.GetExpires().AsTime().Before(time.Now()) {
+		t.Errorf("batch block = %v", batch)
+	}
+
+	if _, err := client.AllocateBlock(ctx, &kuidpb.AllocateBlockRequest{Count: 101}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("oversized AllocateBlock() error = %v, want InvalidArgument", err)
+	}
+
+	disabled := dialGenerator(t, ServerOptions{})
+	if _, err := disabled.AllocateBlock(ctx, &kuidpb.AllocateBlockRequest{Count: 1}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("AllocateBlock() without allocator error = %v, want Unimplemented", err)
+	}
+}
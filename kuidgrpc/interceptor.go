@@ -0,0 +1,116 @@
+// Package kuidgrpc provides gRPC interceptors propagating KUID request IDs,
+// and a server for the kuid.v1.Generator service defined in kuidpb.
+//
+// The interceptors mirror kuid.RequestIDMiddleware: server interceptors take
+// the request ID from the incoming "x-request-id" metadata, generate one when
+// it is missing or malformed, and attach it to the handler's context; client
+// interceptors forward the ID found in the outgoing context, generating one
+// when there is none. IDs therefore line up across HTTP and gRPC hops.
+//
+// It lives in its own module so the core kuid package stays free of the
+// gRPC dependency.
+package kuidgrpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/ctxkuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the gRPC metadata key carrying request IDs
+var MetadataKey = strings.ToLower(kuid.RequestIDHeader)
+
+// UnaryServerInterceptor attaches a request ID to unary handler contexts
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := serverContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor attaches a request ID to stream handler contexts
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := serverContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor sends the context's request ID with unary calls
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := clientContext(ctx)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor sends the context's request ID with streams
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := clientContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// serverContext resolves the incoming request ID, generating one if needed,
+// attaches it to ctx and echoes it in the response header
+func serverContext(ctx context.Context) (context.Context, error) {
+	var id *kuid.KUID
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			id, _ = kuid.Parse(values[0])
+		}
+	}
+	if id == nil {
+		var err error
+		if id, err = kuid.NewKUID(); err != nil {
+			return nil, status.Error(codes.Internal, "failed to generate request ID")
+		}
+	}
+
+	// Echoing the ID is best effort: it fails if headers were already sent
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id.String()))
+	return ctxkuid.NewContext(ctx, id), nil
+}
+
+// clientContext adds the request ID from ctx, or a new one, to the outgoing
+// metadata unless the caller already set it
+func clientContext(ctx context.Context) (context.Context, error) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(MetadataKey)) > 0 {
+		return ctx, nil
+	}
+
+	ctx, id, err := ctxkuid.Ensure(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, id.String()), nil
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,117 @@
+package kuidgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	existing, _ := kuid.NewKUID()
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want *kuid.KUID
+	}{
+		{name: "No metadata"},
+		{name: "Malformed ID", md: metadata.Pairs(MetadataKey, "bogus")},
+		{name: "Existing ID", md: metadata.Pairs(MetadataKey, existing.String()), want: existing},
+	}
+
+	interceptor := UnaryServerInterceptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var seen *kuid.KUID
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				seen, _ = kuid.RequestIDFromContext(ctx)
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatalf("interceptor error = %v", err)
+			}
+			if seen == nil {
+				t.Fatalf("No request ID in handler context")
+			}
+			if tt.want != nil && !seen.Equal(tt.want) {
+				t.Errorf("Request ID = %v, want %v", seen, tt.want)
+			}
+		})
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	existing, _ := kuid.NewKUID()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, existing.ToUUID()))
+
+	var seen *kuid.KUID
+	err := StreamServerInterceptor()(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		seen, _ = kuid.RequestIDFromContext(ss.Context())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor error = %v", err)
+	}
+	if !seen.Equal(existing) {
+		t.Errorf("Request ID = %v, want %v", seen, existing)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	sent := func(ctx context.Context) string {
+		var got string
+		interceptor(ctx, "/svc/Method", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if values := md.Get(MetadataKey); len(values) == 1 {
+				got = values[0]
+			}
+			return nil
+		})
+		return got
+	}
+
+	id, _ := kuid.NewKUID()
+	if got := sent(kuid.ContextWithRequestID(context.Background(), id)); got != id.String() {
+		t.Errorf("Sent request ID = %v, want %v", got, id.String())
+	}
+	if got := sent(context.Background()); got == "" {
+		t.Errorf("No request ID generated for call without one")
+	}
+
+	explicit := metadata.AppendToOutgoingContext(context.Background(), MetadataKey, "caller-set")
+	if got := sent(explicit); got != "caller-set" {
+		t.Errorf("Sent request ID = %v, want caller-set", got)
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	id, _ := kuid.NewKUID()
+	ctx := kuid.ContextWithRequestID(context.Background(), id)
+
+	var got []string
+	StreamClientInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/svc/Stream", func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get(MetadataKey)
+		return nil, nil
+	})
+	if len(got) != 1 || got[0] != id.String() {
+		t.Errorf("Sent request ID = %v, want %v", got, id.String())
+	}
+}
@@ -0,0 +1,5 @@
+// Package kuidpb holds the protobuf messages and gRPC stubs of the KUID
+// generator service. The server implementation lives in kuidgrpc.
+package kuidpb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative kuidpb/kuid.proto
@@ -0,0 +1,601 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: kuidpb/kuid.proto
+
+package kuidpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Kind selects how IDs are generated
+type Kind int32
+
+const (
+	// 128 random bits
+	Kind_KIND_RANDOM Kind = 0
+	// Time-ordered, sorting by creation time
+	Kind_KIND_ORDERED Kind = 1
+	// RFC 9562 version 4 UUIDs
+	Kind_KIND_V4 Kind = 2
+)
+
+// Enum value maps for Kind.
+var (
+	Kind_name = map[int32]string{
+		0: "KIND_RANDOM",
+		1: "KIND_ORDERED",
+		2: "KIND_V4",
+	}
+	Kind_value = map[string]int32{
+		"KIND_RANDOM":  0,
+		"KIND_ORDERED": 1,
+		"KIND_V4":      2,
+	}
+)
+
+func (x Kind) Enum() *Kind {
+	p := new(Kind)
+	*p = x
+	return p
+}
+
+func (x Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_kuidpb_kuid_proto_enumTypes[0].Descriptor()
+}
+
+func (Kind) Type() protoreflect.EnumType {
+	return &file_kuidpb_kuid_proto_enumTypes[0]
+}
+
+func (x Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Kind.Descriptor instead.
+func (Kind) EnumDescriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{0}
+}
+
+// BlockMode selects how a block of IDs is reserved
+type BlockMode int32
+
+const (
+	// A contiguous sequence range the client turns into IDs with
+	// kuid.FromSequence
+	BlockMode_BLOCK_MODE_SEQUENCE BlockMode = 0
+	// A batch of IDs minted by the server
+	BlockMode_BLOCK_MODE_BATCH BlockMode = 1
+)
+
+// Enum value maps for BlockMode.
+var (
+	BlockMode_name = map[int32]string{
+		0: "BLOCK_MODE_SEQUENCE",
+		1: "BLOCK_MODE_BATCH",
+	}
+	BlockMode_value = map[string]int32{
+		"BLOCK_MODE_SEQUENCE": 0,
+		"BLOCK_MODE_BATCH":    1,
+	}
+)
+
+func (x BlockMode) Enum() *BlockMode {
+	p := new(BlockMode)
+	*p = x
+	return p
+}
+
+func (x BlockMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BlockMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_kuidpb_kuid_proto_enumTypes[1].Descriptor()
+}
+
+func (BlockMode) Type() protoreflect.EnumType {
+	return &file_kuidpb_kuid_proto_enumTypes[1]
+}
+
+func (x BlockMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BlockMode.Descriptor instead.
+func (BlockMode) EnumDescriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{1}
+}
+
+type GenerateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  Kind                   `protobuf:"varint,1,opt,name=kind,proto3,enum=kuid.v1.Kind" json:"kind,omitempty"`
+	// IDs per second wanted; 0 asks for the server's maximum. The server may
+	// grant less, and reports what it granted in every response.
+	Rate float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Total IDs to send; 0 streams until the client cancels
+	Count uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// IDs per response message; 0 means 1. Capped by the server.
+	BatchSize     uint32 `protobuf:"varint,4,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_kuidpb_kuid_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kuidpb_kuid_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateRequest) GetKind() Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Kind_KIND_RANDOM
+}
+
+func (x *GenerateRequest) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetBatchSize() uint32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+type GenerateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 16-byte big-endian IDs
+	Ids [][]byte `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// IDs per second granted; 0 means unlimited
+	GrantedRate   float64 `protobuf:"fixed64,2,opt,name=granted_rate,json=grantedRate,proto3" json:"granted_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_kuidpb_kuid_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kuidpb_kuid_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateResponse) GetIds() [][]byte {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *GenerateResponse) GetGrantedRate() float64 {
+	if x != nil {
+		return x.GrantedRate
+	}
+	return 0
+}
+
+type AllocateBlockRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Mode  BlockMode              `protobuf:"varint,1,opt,name=mode,proto3,enum=kuid.v1.BlockMode" json:"mode,omitempty"`
+	// IDs to reserve
+	Count         uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllocateBlockRequest) Reset() {
+	*x = AllocateBlockRequest{}
+	mi := &file_kuidpb_kuid_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocateBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocateBlockRequest) ProtoMessage() {}
+
+func (x *AllocateBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kuidpb_kuid_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocateBlockRequest.ProtoReflect.Descriptor instead.
+func (*AllocateBlockRequest) Descriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AllocateBlockRequest) GetMode() BlockMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BlockMode_BLOCK_MODE_SEQUENCE
+}
+
+func (x *AllocateBlockRequest) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AllocateBlockResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 16-byte lease ID, for bookkeeping
+	Lease []byte `protobuf:"bytes,1,opt,name=lease,proto3" json:"lease,omitempty"`
+	// Sequence blocks: the range first to first+count-1
+	First uint64 `protobuf:"varint,2,opt,name=first,proto3" json:"first,omitempty"`
+	Count uint64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// Sequence blocks: width of the sequence, to pass to kuid.FromSequence
+	SequenceBits uint32 `protobuf:"varint,4,opt,name=sequence_bits,json=sequenceBits,proto3" json:"sequence_bits,omitempty"`
+	// Batch blocks: 16-byte big-endian IDs
+	Ids           [][]byte               `protobuf:"bytes,5,rep,name=ids,proto3" json:"ids,omitempty"`
+	Expires       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires,proto3" json:"expires,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllocateBlockResponse) Reset() {
+	*x = AllocateBlockResponse{}
+	mi := &file_kuidpb_kuid_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocateBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocateBlockResponse) ProtoMessage() {}
+
+func (x *AllocateBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kuidpb_kuid_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocateBlockResponse.ProtoReflect.Descriptor instead.
+func (*AllocateBlockResponse) Descriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AllocateBlockResponse) GetLease() []byte {
+	if x != nil {
+		return x.Lease
+	}
+	return nil
+}
+
+func (x *AllocateBlockResponse) GetFirst() uint64 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *AllocateBlockResponse) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AllocateBlockResponse) GetSequenceBits() uint32 {
+	if x != nil {
+		return x.SequenceBits
+	}
+	return 0
+}
+
+func (x *AllocateBlockResponse) GetIds() [][]byte {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *AllocateBlockResponse) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+type SampleRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Most IDs to return, newest first; 0 returns all the server keeps
+	Limit         uint32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SampleRequest) Reset() {
+	*x = SampleRequest{}
+	mi := &file_kuidpb_kuid_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SampleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SampleRequest) ProtoMessage() {}
+
+func (x *SampleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kuidpb_kuid_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SampleRequest.ProtoReflect.Descriptor instead.
+func (*SampleRequest) Descriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SampleRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SampleResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 16-byte big-endian IDs, newest first
+	Ids [][]byte `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// The server's clock when the sample was taken
+	Now           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=now,proto3" json:"now,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SampleResponse) Reset() {
+	*x = SampleResponse{}
+	mi := &file_kuidpb_kuid_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SampleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SampleResponse) ProtoMessage() {}
+
+func (x *SampleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kuidpb_kuid_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SampleResponse.ProtoReflect.Descriptor instead.
+func (*SampleResponse) Descriptor() ([]byte, []int) {
+	return file_kuidpb_kuid_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SampleResponse) GetIds() [][]byte {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *SampleResponse) GetNow() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Now
+	}
+	return nil
+}
+
+var File_kuidpb_kuid_proto protoreflect.FileDescriptor
+
+const file_kuidpb_kuid_proto_rawDesc = "" +
+	"\n" +
+	"\x11kuidpb/kuid.proto\x12\akuid.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"}\n" +
+	"\x0fGenerateRequest\x12!\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\r.kuid.v1.KindR\x04kind\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x04 \x01(\rR\tbatchSize\"G\n" +
+	"\x10GenerateResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\fR\x03ids\x12!\n" +
+	"\fgranted_rate\x18\x02 \x01(\x01R\vgrantedRate\"T\n" +
+	"\x14AllocateBlockRequest\x12&\n" +
+	"\x04mode\x18\x01 \x01(\x0e2\x12.kuid.v1.BlockModeR\x04mode\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x04R\x05count\"\xc6\x01\n" +
+	"\x15AllocateBlockResponse\x12\x14\n" +
+	"\x05lease\x18\x01 \x01(\fR\x05lease\x12\x14\n" +
+	"\x05first\x18\x02 \x01(\x04R\x05first\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\x12#\n" +
+	"\rsequence_bits\x18\x04 \x01(\rR\fsequenceBits\x12\x10\n" +
+	"\x03ids\x18\x05 \x03(\fR\x03ids\x124\n" +
+	"\aexpires\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\"%\n" +
+	"\rSampleRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\rR\x05limit\"P\n" +
+	"\x0eSampleResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\fR\x03ids\x12,\n" +
+	"\x03now\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03now*6\n" +
+	"\x04Kind\x12\x0f\n" +
+	"\vKIND_RANDOM\x10\x00\x12\x10\n" +
+	"\fKIND_ORDERED\x10\x01\x12\v\n" +
+	"\aKIND_V4\x10\x02*:\n" +
+	"\tBlockMode\x12\x17\n" +
+	"\x13BLOCK_MODE_SEQUENCE\x10\x00\x12\x14\n" +
+	"\x10BLOCK_MODE_BATCH\x10\x012\xd9\x01\n" +
+	"\tGenerator\x12A\n" +
+	"\bGenerate\x12\x18.kuid.v1.GenerateRequest\x1a\x19.kuid.v1.GenerateResponse0\x01\x12N\n" +
+	"\rAllocateBlock\x12\x1d.kuid.v1.AllocateBlockRequest\x1a\x1e.kuid.v1.AllocateBlockResponse\x129\n" +
+	"\x06Sample\x12\x16.kuid.v1.SampleRequest\x1a\x17.kuid.v1.SampleResponseB,Z*github.com/alphabatem/kuid/kuidgrpc/kuidpbb\x06proto3"
+
+var (
+	file_kuidpb_kuid_proto_rawDescOnce sync.Once
+	file_kuidpb_kuid_proto_rawDescData []byte
+)
+
+func file_kuidpb_kuid_proto_rawDescGZIP() []byte {
+	file_kuidpb_kuid_proto_rawDescOnce.Do(func() {
+		file_kuidpb_kuid_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_kuidpb_kuid_proto_rawDesc), len(file_kuidpb_kuid_proto_rawDesc)))
+	})
+	return file_kuidpb_kuid_proto_rawDescData
+}
+
+var file_kuidpb_kuid_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_kuidpb_kuid_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_kuidpb_kuid_proto_goTypes = []any{
+	(Kind)(0),                     // 0: kuid.v1.Kind
+	(BlockMode)(0),                // 1: kuid.v1.BlockMode
+	(*GenerateRequest)(nil),       // 2: kuid.v1.GenerateRequest
+	(*GenerateResponse)(nil),      // 3: kuid.v1.GenerateResponse
+	(*AllocateBlockRequest)(nil),  // 4: kuid.v1.AllocateBlockRequest
+	(*AllocateBlockResponse)(nil), // 5: kuid.v1.AllocateBlockResponse
+	(*SampleRequest)(nil),         // 6: kuid.v1.SampleRequest
+	(*SampleResponse)(nil),        // 7: kuid.v1.SampleResponse
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_kuidpb_kuid_proto_depIdxs = []int32{
+	0, // 0: kuid.v1.GenerateRequest.kind:type_name -> kuid.v1.Kind
+	1, // 1: kuid.v1.AllocateBlockRequest.mode:type_name -> kuid.v1.BlockMode
+	8, // 2: kuid.v1.AllocateBlockResponse.expires:type_name -> google.protobuf.Timestamp
+	8, // 3: kuid.v1.SampleResponse.now:type_name -> google.protobuf.Timestamp
+	2, // 4: kuid.v1.Generator.Generate:input_type -> kuid.v1.GenerateRequest
+	4, // 5: kuid.v1.Generator.AllocateBlock:input_type -> kuid.v1.AllocateBlockRequest
+	6, // 6: kuid.v1.Generator.Sample:input_type -> kuid.v1.SampleRequest
+	3, // 7: kuid.v1.Generator.Generate:output_type -> kuid.v1.GenerateResponse
+	5, // 8: kuid.v1.Generator.AllocateBlock:output_type -> kuid.v1.AllocateBlockResponse
+	7, // 9: kuid.v1.Generator.Sample:output_type -> kuid.v1.SampleResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_kuidpb_kuid_proto_init() }
+func file_kuidpb_kuid_proto_init() {
+	if File_kuidpb_kuid_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kuidpb_kuid_proto_rawDesc), len(file_kuidpb_kuid_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_kuidpb_kuid_proto_goTypes,
+		DependencyIndexes: file_kuidpb_kuid_proto_depIdxs,
+		EnumInfos:         file_kuidpb_kuid_proto_enumTypes,
+		MessageInfos:      file_kuidpb_kuid_proto_msgTypes,
+	}.Build()
+	File_kuidpb_kuid_proto = out.File
+	file_kuidpb_kuid_proto_goTypes = nil
+	file_kuidpb_kuid_proto_depIdxs = nil
+}
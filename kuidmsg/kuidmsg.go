@@ -0,0 +1,93 @@
+// Package kuidmsg stamps broker messages with KUID message IDs and reads
+// them back, for deduplicating on message ID across Kafka (IBM/sarama and
+// segmentio/kafka-go) and NATS.
+//
+// Stamping sets the kuid.MessageIDHeader header and, for Kafka, the message
+// key (see kuid.KUID.PartitionKey) so the broker keeps all messages for one
+// ID on a single partition. For NATS the JetStream "Nats-Msg-Id" header is
+// set as well, so JetStream's own duplicate window drops redeliveries.
+//
+// It lives in its own module so the core kuid package stays free of broker
+// client dependencies.
+package kuidmsg
+
+import (
+	"errors"
+
+	"github.com/IBM/sarama"
+	"github.com/alphabatem/kuid"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+// ErrNoMessageID is returned when a message carries no KUID message ID
+var ErrNoMessageID = errors.New("message has no KUID message ID")
+
+// StampSarama sets the message ID header and key of a sarama message
+func StampSarama(msg *sarama.ProducerMessage, id *kuid.KUID) {
+	msg.Key = sarama.ByteEncoder(id.PartitionKey())
+	for i, h := range msg.Headers {
+		if string(h.Key) == kuid.MessageIDHeader {
+			msg.Headers[i].Value = []byte(id.String())
+			return
+		}
+	}
+	msg.Headers = append(msg.Headers, sarama.RecordHeader{
+		Key:   []byte(kuid.MessageIDHeader),
+		Value: []byte(id.String()),
+	})
+}
+
+// FromSarama returns the message ID of a consumed sarama message
+func FromSarama(msg *sarama.ConsumerMessage) (*kuid.KUID, error) {
+	for _, h := range msg.Headers {
+		if h != nil && string(h.Key) == kuid.MessageIDHeader {
+			return kuid.Parse(string(h.Value))
+		}
+	}
+	return nil, ErrNoMessageID
+}
+
+// StampKafkaGo sets the message ID header and key of a kafka-go message
+func StampKafkaGo(msg *kafka.Message, id *kuid.KUID) {
+	msg.Key = id.PartitionKey()
+	for i, h := range msg.Headers {
+		if h.Key == kuid.MessageIDHeader {
+			msg.Headers[i].Value = []byte(id.String())
+			return
+		}
+	}
+	msg.Headers = append(msg.Headers, kafka.Header{
+		Key:   kuid.MessageIDHeader,
+		Value: []byte(id.String()),
+	})
+}
+
+// FromKafkaGo returns the message ID of a kafka-go message
+func FromKafkaGo(msg kafka.Message) (*kuid.KUID, error) {
+	for _, h := range msg.Headers {
+		if h.Key == kuid.MessageIDHeader {
+			return kuid.Parse(string(h.Value))
+		}
+	}
+	return nil, ErrNoMessageID
+}
+
+// StampNATS sets the message ID header and the JetStream deduplication
+// header of a NATS message
+func StampNATS(msg *nats.Msg, id *kuid.KUID) {
+	if msg.Header == nil {
+		msg.Header = nats.Header{}
+	}
+	msg.Header.Set(kuid.MessageIDHeader, id.String())
+	msg.Header.Set(nats.MsgIdHdr, id.String())
+}
+
+// FromNATS returns the message ID of a NATS message
+func FromNATS(msg *nats.Msg) (*kuid.KUID, error) {
+	v := msg.Header.Get(kuid.MessageIDHeader)
+	if v == "" {
+		return nil, ErrNoMessageID
+	}
+	return kuid.Parse(v)
+}
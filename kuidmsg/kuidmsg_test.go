@@ -0,0 +1,80 @@
+package kuidmsg
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/alphabatem/kuid"
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestSarama(t *testing.T) {
+	id, _ := kuid.NewKUID()
+	msg := &sarama.ProducerMessage{Topic: "events"}
+	StampSarama(msg, id)
+	StampSarama(msg, id) // stamping twice must not duplicate the header
+
+	if len(msg.Headers) != 1 {
+		t.Fatalf("Headers = %v, want exactly one", msg.Headers)
+	}
+	key, _ := msg.Key.Encode()
+	if string(key) != string(id.PartitionKey()) {
+		t.Errorf("Key = %x, want %x", key, id.PartitionKey())
+	}
+
+	consumed := &sarama.ConsumerMessage{Headers: []*sarama.RecordHeader{&msg.Headers[0]}}
+	got, err := FromSarama(consumed)
+	if err != nil {
+		t.Fatalf("FromSarama() error = %v", err)
+	}
+	if !got.Equal(id) {
+		t.Errorf("FromSarama() = %v, want %v", got, id)
+	}
+
+	if _, err := FromSarama(&sarama.ConsumerMessage{}); err != ErrNoMessageID {
+		t.Errorf("FromSarama() error = %v, want %v", err, ErrNoMessageID)
+	}
+}
+
+func TestKafkaGo(t *testing.T) {
+	id, _ := kuid.NewKUID()
+	var msg kafka.Message
+	StampKafkaGo(&msg, id)
+
+	got, err := FromKafkaGo(msg)
+	if err != nil {
+		t.Fatalf("FromKafkaGo() error = %v", err)
+	}
+	if !got.Equal(id) {
+		t.Errorf("FromKafkaGo() = %v, want %v", got, id)
+	}
+	if string(msg.Key) != string(id.PartitionKey()) {
+		t.Errorf("Key = %x, want %x", msg.Key, id.PartitionKey())
+	}
+
+	if _, err := FromKafkaGo(kafka.Message{}); err != ErrNoMessageID {
+		t.Errorf("FromKafkaGo() error = %v, want %v", err, ErrNoMessageID)
+	}
+}
+
+func TestNATS(t *testing.T) {
+	id, _ := kuid.NewKUID()
+	msg := nats.NewMsg("events")
+	StampNATS(msg, id)
+
+	got, err := FromNATS(msg)
+	if err != nil {
+		t.Fatalf("FromNATS() error = %v", err)
+	}
+	if !got.Equal(id) {
+		t.Errorf("FromNATS() = %v, want %v", got, id)
+	}
+	if msg.Header.Get(nats.MsgIdHdr) != id.String() {
+		t.Errorf("JetStream dedup header = %v, want %v", msg.Header.Get(nats.MsgIdHdr), id.String())
+	}
+
+	if _, err := FromNATS(nats.NewMsg("events")); err != ErrNoMessageID {
+		t.Errorf("FromNATS() error = %v, want %v", err, ErrNoMessageID)
+	}
+}
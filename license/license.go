@@ -0,0 +1,154 @@
+// Package license issues and verifies offline license keys around KUIDs.
+//
+// A key packs the license's KUID, 64 feature bits and expiry with an
+// Ed25519 signature and a CRC-32, encoded as Crockford base32 in groups of
+// six characters:
+//
+//	04A2F8-Q0WZ3C-...-7K3M9Q
+//
+// The vendor signs keys with a private key; applications embed only the
+// public key and verify keys without calling home. The checksum catches
+// typos before the signature is checked, so users get "mistyped" rather
+// than "forged" for an honest mistake.
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+const (
+	version     = 1
+	payloadSize = 1 + 16 + 8 + 8 // version, ID, features, expiry
+	keySize     = payloadSize + ed25519.SignatureSize + 4
+	groupSize   = 6
+)
+
+var (
+	ErrMalformed = errors.New("malformed license key")
+	ErrChecksum  = errors.New("license key checksum mismatch")
+	ErrSignature = errors.New("license key signature invalid")
+	ErrExpired   = errors.New("license expired")
+)
+
+var encoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// License is the content of a license key
+type License struct {
+	ID       kuid.KUID
+	Features uint64    // bit i set grants feature i
+	Expires  time.Time // zero for perpetual licenses, second precision
+}
+
+// Has reports whether the license grants feature, numbered 0 to 63
+func (l *License) Has(feature uint) bool {
+	return feature < 64 && l.Features&(1<<feature) != 0
+}
+
+// Signer issues license keys
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner creates a Signer with the vendor's private key
+func NewSigner(key ed25519.PrivateKey) (*Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid Ed25519 private key")
+	}
+	return &Signer{key: key}, nil
+}
+
+// Sign returns the key string for l
+func (s *Signer) Sign(l *License) string {
+	buf := make([]byte, payloadSize, keySize)
+	buf[0] = version
+	copy(buf[1:17], l.ID.Bytes())
+	binary.BigEndian.PutUint64(buf[17:25], l.Features)
+	if !l.Expires.IsZero() {
+		binary.BigEndian.PutUint64(buf[25:33], uint64(l.Expires.Unix()))
+	}
+	buf = append(buf, ed25519.Sign(s.key, buf)...)
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+
+	text := encoding.EncodeToString(buf)
+	var b strings.Builder
+	for i := 0; i < len(text); i += groupSize {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(text[i:min(i+groupSize, len(text))])
+	}
+	return b.String()
+}
+
+// Verifier checks license keys offline
+type Verifier struct {
+	key ed25519.PublicKey
+	now func() time.Time
+}
+
+// NewVerifier creates a Verifier with the vendor's public key
+func NewVerifier(key ed25519.PublicKey) (*Verifier, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid Ed25519 public key")
+	}
+	return &Verifier{key: key, now: time.Now}, nil
+}
+
+// Verify checks a key's checksum, signature and expiry and returns its
+// license. Case, grouping and the misreadings I, L and O are forgiven. An
+// expired key returns its license along with ErrExpired, so applications
+// can name what ran out.
+func (v *Verifier) Verify(key string) (*License, error) {
+	buf, err := encoding.DecodeString(normalize(key))
+	if err != nil || len(buf) != keySize {
+		return nil, ErrMalformed
+	}
+	body, sum := buf[:keySize-4], binary.BigEndian.Uint32(buf[keySize-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return nil, ErrChecksum
+	}
+	payload, sig := body[:payloadSize], body[payloadSize:]
+	if payload[0] != version {
+		return nil, ErrMalformed
+	}
+	if !ed25519.Verify(v.key, payload, sig) {
+		return nil, ErrSignature
+	}
+
+	id, _ := kuid.FromBytes(payload[1:17])
+	l := &License{ID: *id, Features: binary.BigEndian.Uint64(payload[17:25])}
+	if expires := binary.BigEndian.Uint64(payload[25:33]); expires != 0 {
+		l.Expires = time.Unix(int64(expires), 0)
+		if !v.now().Before(l.Expires) {
+			return l, ErrExpired
+		}
+	}
+	return l, nil
+}
+
+// normalize uppercases s, maps I and L to 1 and O to 0, and drops hyphens
+// and whitespace
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t', '\n', '\r':
+			return -1
+		case 'i', 'I', 'l', 'L':
+			return '1'
+		case 'o', 'O':
+			return '0'
+		}
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,110 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+func testKeys(t *testing.T) (*Signer, *Verifier) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVerifier(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, v
+}
+
+func TestSignVerify(t *testing.T) {
+	s, v := testKeys(t)
+	id, _ := kuid.NewKUID()
+	want := &License{ID: *id, Features: 1<<0 | 1<<5 | 1<<63, Expires: time.Now().Add(time.Hour).Truncate(time.Second)}
+
+	key := s.Sign(want)
+	for _, group := range strings.Split(key, "-") {
+		if len(group) > groupSize {
+			t.Fatalf("Sign() = %q, group %q too long", key, group)
+		}
+	}
+
+	for _, input := range []string{key, strings.ToLower(key), strings.ReplaceAll(key, "-", " "), strings.ReplaceAll(key, "0", "O")} {
+		got, err := v.Verify(input)
+		if err != nil {
+			t.Fatalf("Verify(%q) error = %v", input, err)
+		}
+		if got.ID != want.ID || got.Features != want.Features || !got.Expires.Equal(want.Expires) {
+			t.Errorf("Verify() = %+v, want %+v", got, want)
+		}
+	}
+
+	got, _ := v.Verify(key)
+	if !got.Has(0) || !got.Has(5) || !got.Has(63) || got.Has(1) || got.Has(64) {
+		t.Errorf("Has() wrong for features %b", got.Features)
+	}
+}
+
+func TestVerifyPerpetual(t *testing.T) {
+	s, v := testKeys(t)
+	key := s.Sign(&License{Features: 1})
+	v.now = func() time.Time { return time.Now().AddDate(100, 0, 0) }
+	if l, err := v.Verify(key); err != nil || !l.Expires.IsZero() {
+		t.Errorf("Verify() = %+v, %v", l, err)
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	s, v := testKeys(t)
+	other, _ := testKeys(t)
+	expires := time.Now().Add(time.Hour)
+	key := s.Sign(&License{Features: 3, Expires: expires})
+
+	// Swapping one character breaks the checksum
+	typo := []byte(key)
+	if typo[0] == '0' {
+		typo[0] = '1'
+	} else {
+		typo[0] = '0'
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want error
+	}{
+		{name: "Empty", key: "", want: ErrMalformed},
+		{name: "Truncated", key: key[:len(key)-7], want: ErrMalformed},
+		{name: "Bad character", key: "U" + key[1:], want: ErrMalformed},
+		{name: "Typo", key: string(typo), want: ErrChecksum},
+		{name: "Other vendor", key: other.Sign(&License{Features: 3, Expires: expires}), want: ErrSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(tt.key); err != tt.want {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	v.now = func() time.Time { return expires.Add(time.Second) }
+	if l, err := v.Verify(key); err != ErrExpired || l == nil || l.Features != 3 {
+		t.Errorf("Verify() = %+v, %v, want license with %v", l, err, ErrExpired)
+	}
+
+	if _, err := NewSigner(nil); err == nil {
+		t.Errorf("NewSigner(nil) expected error")
+	}
+	if _, err := NewVerifier(nil); err == nil {
+		t.Errorf("NewVerifier(nil) expected error")
+	}
+}
@@ -0,0 +1,110 @@
+package kuid
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	base62Pattern   = `[0-9A-Za-z]{22}`
+	prefixPattern   = `[a-z](?:[a-z_]{0,61}[a-z])?`
+	uuidPattern     = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+	wordBoundary    = `\b`
+	prefixSeparator = "_"
+)
+
+// Regexps matching the textual forms of KUIDs as whole words. They check
+// the shape only: a 22-character base62 match may still overflow 128 bits,
+// which Matcher filters out.
+var (
+	// Base62Regexp matches the canonical 22-character form
+	Base62Regexp = regexp.MustCompile(wordBoundary + base62Pattern + wordBoundary)
+	// PrefixedRegexp matches the TypeID-style "prefix_<base62>" form made by
+	// kuid new -prefix, capturing the prefix and the KUID
+	PrefixedRegexp = regexp.MustCompile(wordBoundary + `(` + prefixPattern + `)` + prefixSeparator + `(` + base62Pattern + `)` + wordBoundary)
+	// UUIDRegexp matches the hyphenated UUID form in either case
+	UUIDRegexp = regexp.MustCompile(wordBoundary + uuidPattern + wordBoundary)
+)
+
+// IDForm is the textual form of a KUID found by a Matcher
+type IDForm int
+
+const (
+	FormBase62 IDForm = iota
+	FormPrefixed
+	FormUUID
+)
+
+// IDMatch is a KUID found in text. Start and End are byte offsets of the
+// whole match, including any prefix.
+type IDMatch struct {
+	Start, End int
+	ID         KUID
+	Form       IDForm
+	Prefix     string // set for FormPrefixed
+}
+
+// Matcher finds KUIDs in arbitrary text, such as log lines to scrub
+type Matcher struct {
+	re *regexp.Regexp
+}
+
+// matcherRegexp captures a prefixed KUID in groups 1 and 2, a bare KUID in
+// group 3 and a UUID in group 4
+var matcherRegexp = regexp.MustCompile(wordBoundary + `(?:(` + prefixPattern + `)` + prefixSeparator + `(` + base62Pattern + `)|(` + base62Pattern + `)|(` + uuidPattern + `))` + wordBoundary)
+
+// NewMatcher returns a Matcher for all three forms
+func NewMatcher() *Matcher {
+	return &Matcher{re: matcherRegexp}
+}
+
+// FindAll returns every KUID in text in order. Matches of the right shape
+// that do not decode, such as base62 halves above 2^64, are skipped.
+func (m *Matcher) FindAll(text string) []IDMatch {
+	var matches []IDMatch
+	for _, loc := range m.re.FindAllStringSubmatchIndex(text, -1) {
+		if match, ok := decodeMatch(text, loc); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// ReplaceAll returns text with every KUID replaced by the result of repl,
+// for redacting identifiers
+func (m *Matcher) ReplaceAll(text string, repl func(IDMatch) string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range m.FindAll(text) {
+		b.WriteString(text[last:match.Start])
+		b.WriteString(repl(match))
+		last = match.End
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// decodeMatch turns a submatch index from matcherRegexp into an IDMatch
+func decodeMatch(text string, loc []int) (IDMatch, bool) {
+	match := IDMatch{Start: loc[0], End: loc[1]}
+	var err error
+	switch {
+	case loc[2] >= 0:
+		match.Form = FormPrefixed
+		match.Prefix = text[loc[2]:loc[3]]
+		match.ID, err = ParseValue(text[loc[4]:loc[5]])
+	case loc[6] >= 0:
+		match.Form = FormBase62
+		match.ID, err = ParseValue(text[loc[6]:loc[7]])
+	default:
+		match.Form = FormUUID
+		var k *KUID
+		if k, err = FromUUID(text[loc[8]:loc[9]]); err == nil {
+			match.ID = *k
+		}
+	}
+	return match, err == nil
+}
@@ -0,0 +1,91 @@
+package kuid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegexps(t *testing.T) {
+	k, _ := NewKUID()
+	tests := []struct {
+		name  string
+		re    interface{ MatchString(string) bool }
+		match []string
+		miss  []string
+	}{
+		{
+			name:  "Base62",
+			re:    Base62Regexp,
+			match: []string{k.String(), "id=" + k.String() + ","},
+			miss:  []string{k.String()[:21], k.String() + "x", "x" + k.String()},
+		},
+		{
+			name:  "Prefixed",
+			re:    PrefixedRegexp,
+			match: []string{"user_" + k.String(), "api_key_" + k.String()},
+			miss:  []string{"_" + k.String(), "User_" + k.String(), "user__" + k.String()[:21]},
+		},
+		{
+			name:  "UUID",
+			re:    UUIDRegexp,
+			match: []string{k.ToUUID(), strings.ToUpper(k.ToUUID())},
+			miss:  []string{strings.ReplaceAll(k.ToUUID(), "-", ""), k.ToUUID()[1:]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range tt.match {
+				if !tt.re.MatchString(s) {
+					t.Errorf("no match for %q", s)
+				}
+			}
+			for _, s := range tt.miss {
+				if tt.re.MatchString(s) {
+					t.Errorf("unexpected match for %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	c, _ := NewKUID()
+	overflow := strings.Repeat("z", 22)
+	text := "order " + a.String() + " by user_" + b.String() + " trace=" + c.ToUUID() + " junk " + overflow
+
+	m := NewMatcher()
+	matches := m.FindAll(text)
+	if len(matches) != 3 {
+		t.Fatalf("FindAll() = %d matches, want 3: %+v", len(matches), matches)
+	}
+
+	want := []struct {
+		id     *KUID
+		form   IDForm
+		prefix string
+		text   string
+	}{
+		{a, FormBase62, "", a.String()},
+		{b, FormPrefixed, "user", "user_" + b.String()},
+		{c, FormUUID, "", c.ToUUID()},
+	}
+	for i, w := range want {
+		got := matches[i]
+		if got.ID != *w.id || got.Form != w.form || got.Prefix != w.prefix || text[got.Start:got.End] != w.text {
+			t.Errorf("match %d = %+v (%q), want %v %v %q", i, got, text[got.Start:got.End], w.id, w.form, w.text)
+		}
+	}
+
+	scrubbed := m.ReplaceAll(text, func(match IDMatch) string { return match.ID.Redacted() })
+	if strings.Contains(scrubbed, a.String()) || strings.Contains(scrubbed, b.String()) || strings.Contains(scrubbed, c.ToUUID()) {
+		t.Errorf("ReplaceAll() left IDs in %q", scrubbed)
+	}
+	if !strings.HasSuffix(scrubbed, " junk "+overflow) {
+		t.Errorf("ReplaceAll() = %q, want the tail kept", scrubbed)
+	}
+	if got := m.ReplaceAll("nothing here", nil); got != "nothing here" {
+		t.Errorf("ReplaceAll() without matches = %q", got)
+	}
+}
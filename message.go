@@ -0,0 +1,22 @@
+package kuid
+
+// MessageIDHeader is the message header carrying KUID message IDs in
+// Kafka, NATS and other brokers
+const MessageIDHeader = "kuid-message-id"
+
+// PartitionKey returns the KUID's 16 bytes for use as a message key, so a
+// broker's key-hashing partitioner keeps all messages for an ID on one
+// partition
+func (k *KUID) PartitionKey() []byte {
+	return k.Bytes()
+}
+
+// Partition maps the KUID to a partition in [0, n) for manual partitioners.
+// It hashes both halves, so ordered, typed, v6 and derived KUIDs all spread
+// evenly. It returns 0 when n < 1.
+func (k *KUID) Partition(n int) int {
+	if n < 1 {
+		return 0
+	}
+	return int(hash64(k) % uint64(n))
+}
@@ -0,0 +1,59 @@
+package kuid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPartition(t *testing.T) {
+	const partitions = 8
+
+	ordered, _ := NewGenerator()
+	types, _ := NewTypeRegistry(8)
+	types.Register("order", 7)
+	typed, _ := NewGenerator(WithType(types, "order"))
+	v6, _ := NewV1Generator(nil)
+	parent, _ := NewKUID()
+
+	// Each source fixes some bits of every KUID it mints, but all must
+	// still spread out
+	sources := []struct {
+		name string
+		next func(i int) *KUID
+	}{
+		{"ordered", func(int) *KUID { k, _ := ordered.NewOrdered(); return k }},
+		{"typed", func(int) *KUID { k, _ := typed.New(); return k }},
+		{"v6", func(int) *KUID { return v6.NewV6() }},
+		{"derived", func(i int) *KUID { k := DeriveChild(*parent, uint32(i)); return &k }},
+		{"same msb^lsb", func(i int) *KUID { x := mix64(uint64(i)); return &KUID{msb: x, lsb: x ^ 0x1234} }},
+	}
+	for _, src := range sources {
+		t.Run(src.name, func(t *testing.T) {
+			counts := make([]int, partitions)
+			for i := 0; i < 8000; i++ {
+				kuid := src.next(i)
+				p := kuid.Partition(partitions)
+				if p < 0 || p >= partitions {
+					t.Fatalf("Partition() = %d, out of range", p)
+				}
+				if p != kuid.Partition(partitions) {
+					t.Fatalf("Partition() is not deterministic")
+				}
+				counts[p]++
+			}
+			for p, n := range counts {
+				if n < 800 || n > 1200 {
+					t.Errorf("Partition %d received %d of 8000 KUIDs", p, n)
+				}
+			}
+		})
+	}
+
+	kuid, _ := NewKUID()
+	if kuid.Partition(0) != 0 {
+		t.Errorf("Partition(0) = %d, want 0", kuid.Partition(0))
+	}
+	if !bytes.Equal(kuid.PartitionKey(), kuid.Bytes()) {
+		t.Errorf("PartitionKey() = %x, want %x", kuid.PartitionKey(), kuid.Bytes())
+	}
+}
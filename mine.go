@@ -0,0 +1,133 @@
+package kuid
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrPrefixUnreachable = errors.New("prefix cannot occur in a KUID string")
+
+// MineProgress reports the state of a running prefix search
+type MineProgress struct {
+	Attempts uint64        // KUIDs tested so far
+	Elapsed  time.Duration // time since the search started
+}
+
+// MineWithPrefix searches random KUIDs until one is found whose base62 form
+// begins with prefix. The search runs on workers goroutines (GOMAXPROCS when
+// workers < 1) and stops when ctx is done. Each extra prefix character makes
+// the search roughly 62 times longer.
+func MineWithPrefix(ctx context.Context, prefix string, workers int) (*KUID, error) {
+	return MineWithPrefixProgress(ctx, prefix, workers, 0, nil)
+}
+
+// MineWithPrefixProgress behaves like MineWithPrefix and additionally calls
+// progress every interval while the search is running
+func MineWithPrefixProgress(ctx context.Context, prefix string, workers int, interval time.Duration, progress func(MineProgress)) (*KUID, error) {
+	if err := validateMinePrefix(prefix); err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	searchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var attempts atomic.Uint64
+	found := make(chan *KUID, 1)
+	failed := make(chan error, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for searchCtx.Err() == nil {
+				k, err := NewKUID()
+				if err != nil {
+					select {
+					case failed <- err:
+					default:
+					}
+					cancel()
+					return
+				}
+				if attempts.Add(1)%1024 == 0 {
+					// Targets without preemption, such as js/wasm,
+					// would otherwise never run the timers that end
+					// the search
+					runtime.Gosched()
+				}
+				if strings.HasPrefix(k.String(), prefix) {
+					select {
+					case found <- k:
+					default:
+					}
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var tick <-chan time.Time
+	if progress != nil && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	start := time.Now()
+	for {
+		select {
+		case <-tick:
+			progress(MineProgress{Attempts: attempts.Load(), Elapsed: time.Since(start)})
+		case <-done:
+			select {
+			case k := <-found:
+				return k, nil
+			case err := <-failed:
+				return nil, err
+			default:
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// validateMinePrefix checks that prefix only uses base62 characters and can
+// appear at the start of a KUID string
+func validateMinePrefix(prefix string) error {
+	if len(prefix) > size*2 {
+		return ErrInvalidLength
+	}
+	for i := 0; i < len(prefix); i++ {
+		if strings.IndexByte(base62Chars, prefix[i]) < 0 {
+			return ErrInvalidChar
+		}
+	}
+
+	// Each half encodes a uint64, so its smallest completion must not exceed
+	// the encoding of the largest uint64
+	maxLong := encodeLong(math.MaxUint64)
+	for half := 0; half < len(prefix); half += size {
+		part := prefix[half:min(half+size, len(prefix))]
+		if part+strings.Repeat("0", size-len(part)) > maxLong {
+			return ErrPrefixUnreachable
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package kuid
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMineWithPrefix(t *testing.T) {
+	for _, prefix := range []string{"", "A", "3x"} {
+		t.Run(prefix, func(t *testing.T) {
+			kuid, err := MineWithPrefix(context.Background(), prefix, 4)
+			if err != nil {
+				t.Fatalf("MineWithPrefix() error = %v", err)
+			}
+			if !strings.HasPrefix(kuid.String(), prefix) {
+				t.Errorf("MineWithPrefix() = %v, want prefix %v", kuid.String(), prefix)
+			}
+		})
+	}
+}
+
+func TestMineWithPrefixProgress(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var calls atomic.Int32
+	_, err := MineWithPrefixProgress(ctx, "LygHa16AHYF", 2, 5*time.Millisecond, func(p MineProgress) {
+		calls.Add(1)
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("MineWithPrefixProgress() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if calls.Load() == 0 {
+		t.Errorf("Expected progress to be reported")
+	}
+}
+
+func TestMineWithPrefixInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		wantErr error
+	}{
+		{
+			name:    "Invalid characters",
+			prefix:  "A-B",
+			wantErr: ErrInvalidChar,
+		},
+		{
+			name:    "Too long",
+			prefix:  strings.Repeat("0", size*2+1),
+			wantErr: ErrInvalidLength,
+		},
+		{
+			name:    "Above max first character",
+			prefix:  "Z",
+			wantErr: ErrPrefixUnreachable,
+		},
+		{
+			name:    "Above max second half",
+			prefix:  strings.Repeat("0", size) + "M",
+			wantErr: ErrPrefixUnreachable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MineWithPrefix(context.Background(), tt.prefix, 1)
+			if err != tt.wantErr {
+				t.Errorf("MineWithPrefix() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
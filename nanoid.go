@@ -0,0 +1,108 @@
+package kuid
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// Alphabets for NanoIDGenerator
+const (
+	AlphabetURL       = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_-"
+	AlphabetBase62    = base62Chars
+	AlphabetBase58    = base58Chars
+	AlphabetCrockford = crockfordChars
+	AlphabetNumeric   = "0123456789"
+)
+
+// NanoIDGenerator mints NanoID-style random strings of any length and
+// alphabet, for short-lived codes such as email verification tokens that
+// need not be 128-bit KUIDs. It draws from a Generator's entropy source
+// and honours its blocklist and entropy failure policy.
+//
+// Characters are picked by rejection sampling, so every character of the
+// alphabet is equally likely whatever its size.
+type NanoIDGenerator struct {
+	gen      *Generator
+	alphabet string
+	length   int
+	bits     int // random bits consumed per character
+}
+
+// NewNanoIDGenerator creates a NanoIDGenerator for strings of length
+// characters from alphabet, which must hold 2 to 256 distinct bytes. gen
+// supplies the random bits; a default Generator is used when nil.
+func NewNanoIDGenerator(gen *Generator, alphabet string, length int) (*NanoIDGenerator, error) {
+	if gen == nil {
+		gen = defaultGenerator
+	}
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		return nil, errors.New("alphabet must have between 2 and 256 characters")
+	}
+	var seen [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		if seen[alphabet[i]] {
+			return nil, errors.New("alphabet has repeated characters")
+		}
+		seen[alphabet[i]] = true
+	}
+	if length < 1 {
+		return nil, errors.New("length must be positive")
+	}
+	return &NanoIDGenerator{
+		gen:      gen,
+		alphabet: alphabet,
+		length:   length,
+		bits:     bits.Len(uint(len(alphabet) - 1)),
+	}, nil
+}
+
+// Entropy returns the random bits carried by each string. Short-lived
+// tokens checked with rate limiting are fine at 40 or more; anything
+// long-lived or guessable offline wants 128, like a KUID.
+func (n *NanoIDGenerator) Entropy() float64 {
+	return float64(n.length) * math.Log2(float64(len(n.alphabet)))
+}
+
+// New returns a random string that passes the Generator's blocklist
+func (n *NanoIDGenerator) New() (string, error) {
+	c := n.gen.config()
+	buf := make([]byte, n.length)
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if err := n.fill(c, buf); err != nil {
+			return "", err
+		}
+		if !c.blocked(string(buf)) {
+			n.gen.counts.random.Add(1)
+			return string(buf), nil
+		}
+		n.gen.counts.blocked.Add(1)
+	}
+	return "", ErrBlocked
+}
+
+// fill picks characters from 128-bit draws, n.bits at a time, rejecting
+// values past the end of the alphabet
+func (n *NanoIDGenerator) fill(c *config, buf []byte) error {
+	mask := uint64(1)<<n.bits - 1
+	var msb, lsb uint64
+	left := 0 // unused bits in msb and lsb
+	for i := 0; i < len(buf); {
+		if left < n.bits {
+			var err error
+			if msb, lsb, err = n.gen.readWith(c); err != nil {
+				return err
+			}
+			left = 128
+		}
+		v := lsb & mask
+		lsb = lsb>>n.bits | msb<<(64-n.bits)
+		msb >>= n.bits
+		left -= n.bits
+		if v < uint64(len(n.alphabet)) {
+			buf[i] = n.alphabet[v]
+			i++
+		}
+	}
+	return nil
+}
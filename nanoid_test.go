@@ -0,0 +1,91 @@
+package kuid
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNanoIDGenerator(t *testing.T) {
+	for _, alphabet := range []string{AlphabetURL, AlphabetBase62, AlphabetBase58, AlphabetCrockford, AlphabetNumeric, "ab"} {
+		n, err := NewNanoIDGenerator(nil, alphabet, 21)
+		if err != nil {
+			t.Fatalf("NewNanoIDGenerator(%q) error = %v", alphabet, err)
+		}
+		seen := make(map[string]bool)
+		for i := 0; i < 100; i++ {
+			id, err := n.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(id) != 21 {
+				t.Errorf("New() = %q, want 21 characters", id)
+			}
+			if strings.Trim(id, alphabet) != "" {
+				t.Errorf("New() = %q, outside alphabet %q", id, alphabet)
+			}
+			if seen[id] && len(alphabet) > 2 {
+				t.Errorf("New() repeated %q", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestNanoIDGeneratorUniform(t *testing.T) {
+	// Ten characters need 4 bits each, so 6 of 16 values are rejected;
+	// without rejection the first six digits would come up twice as often
+	n, _ := NewNanoIDGenerator(nil, AlphabetNumeric, 1000)
+	counts := make(map[rune]int)
+	for i := 0; i < 20; i++ {
+		id, _ := n.New()
+		for _, c := range id {
+			counts[c]++
+		}
+	}
+	for c, count := range counts {
+		if count < 1600 || count > 2400 {
+			t.Errorf("digit %c drawn %d times of 20000", c, count)
+		}
+	}
+}
+
+func TestNanoIDGeneratorInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet string
+		length   int
+	}{
+		{name: "Short alphabet", alphabet: "a", length: 8},
+		{name: "Repeated character", alphabet: "abca", length: 8},
+		{name: "Zero length", alphabet: AlphabetURL, length: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewNanoIDGenerator(nil, tt.alphabet, tt.length); err == nil {
+				t.Errorf("NewNanoIDGenerator() expected error")
+			}
+		})
+	}
+}
+
+func TestNanoIDGeneratorShared(t *testing.T) {
+	g, _ := NewGenerator(WithBlocklist("a"), WithBackend(BackendChaCha20))
+	n, _ := NewNanoIDGenerator(g, "abc", 4)
+	for i := 0; i < 50; i++ {
+		id, err := n.New()
+		if err != nil && err != ErrBlocked {
+			t.Fatal(err)
+		}
+		if strings.Contains(id, "a") {
+			t.Errorf("New() = %q ignores the blocklist", id)
+		}
+	}
+	if g.Stats().Random == 0 {
+		t.Errorf("NanoIDs not counted in Generator stats")
+	}
+
+	if got := n.Entropy(); math.Abs(got-4*math.Log2(3)) > 1e-9 {
+		t.Errorf("Entropy() = %v", got)
+	}
+}
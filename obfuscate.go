@@ -0,0 +1,67 @@
+package kuid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	feistelRounds   = 8
+	minObfuscateKey = 16
+)
+
+var ErrNotObfuscated = errors.New("KUID was not produced by this obfuscator")
+
+// Obfuscator maps integers such as auto-increment row IDs to KUIDs that do
+// not reveal the integer or its neighbours, and back again. It is a keyed
+// Feistel network over the full 128 bits with the integer in the low half
+// and zeros in the high half; decoding checks the zeros, so KUIDs that were
+// not produced with the same key are rejected rather than mapped to a
+// random integer.
+type Obfuscator struct {
+	key []byte
+}
+
+// NewObfuscator creates an Obfuscator. The key must be at least 16 bytes
+// and kept secret; changing it changes every mapping.
+func NewObfuscator(key []byte) (*Obfuscator, error) {
+	if len(key) < minObfuscateKey {
+		return nil, errors.New("obfuscator key must be at least 16 bytes")
+	}
+	return &Obfuscator{key: append([]byte(nil), key...)}, nil
+}
+
+// Encode maps n to its obfuscated KUID
+func (o *Obfuscator) Encode(n uint64) *KUID {
+	l, r := uint64(0), n
+	for i := 0; i < feistelRounds; i++ {
+		l, r = r, l^o.round(i, r)
+	}
+	return &KUID{msb: l, lsb: r}
+}
+
+// Decode recovers the integer encoded in k
+func (o *Obfuscator) Decode(k *KUID) (uint64, error) {
+	l, r := k.msb, k.lsb
+	for i := feistelRounds - 1; i >= 0; i-- {
+		l, r = r^o.round(i, l), l
+	}
+	if l != 0 {
+		return 0, ErrNotObfuscated
+	}
+	return r, nil
+}
+
+// round is the Feistel round function: a truncated HMAC-SHA256 of the
+// round number and half-block
+func (o *Obfuscator) round(i int, half uint64) uint64 {
+	var buf [9]byte
+	buf[0] = byte(i)
+	binary.BigEndian.PutUint64(buf[1:], half)
+
+	mac := hmac.New(sha256.New, o.key)
+	mac.Write(buf[:])
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
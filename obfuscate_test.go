@@ -0,0 +1,58 @@
+package kuid
+
+import (
+	"math"
+	"testing"
+)
+
+var testObfuscateKey = []byte("0123456789abcdef")
+
+func TestObfuscator(t *testing.T) {
+	o, err := NewObfuscator(testObfuscateKey)
+	if err != nil {
+		t.Fatalf("NewObfuscator() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, n := range []uint64{0, 1, 2, 3, 1000, math.MaxUint64} {
+		kuid := o.Encode(n)
+		if seen[kuid.String()] {
+			t.Errorf("Encode(%d) collides with an earlier value", n)
+		}
+		seen[kuid.String()] = true
+
+		got, err := o.Decode(kuid)
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if got != n {
+			t.Errorf("Decode(Encode(%d)) = %v", n, got)
+		}
+	}
+
+	// Sequential integers must not produce sequential-looking KUIDs
+	a, b := o.Encode(1), o.Encode(2)
+	if a.msb == b.msb {
+		t.Errorf("Encode(1) and Encode(2) share their high bits")
+	}
+}
+
+func TestObfuscatorRejectsForeign(t *testing.T) {
+	o, _ := NewObfuscator(testObfuscateKey)
+	other, _ := NewObfuscator([]byte("fedcba9876543210"))
+
+	if _, err := other.Decode(o.Encode(42)); err != ErrNotObfuscated {
+		t.Errorf("Decode() with wrong key error = %v, want %v", err, ErrNotObfuscated)
+	}
+
+	random, _ := NewKUID()
+	if _, err := o.Decode(random); err != ErrNotObfuscated {
+		t.Errorf("Decode() of random KUID error = %v, want %v", err, ErrNotObfuscated)
+	}
+}
+
+func TestNewObfuscatorShortKey(t *testing.T) {
+	if _, err := NewObfuscator([]byte("short")); err == nil {
+		t.Errorf("NewObfuscator() expected error for short key")
+	}
+}
@@ -0,0 +1,91 @@
+package kuid
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNewOrdered(t *testing.T) {
+	g, err := NewGenerator()
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	before := time.Now().Truncate(time.Millisecond)
+	var ids []*KUID
+	var strs []string
+	for i := 0; i < 10000; i++ {
+		kuid, err := g.NewOrdered()
+		if err != nil {
+			t.Fatalf("NewOrdered() error = %v", err)
+		}
+		ids = append(ids, kuid)
+		strs = append(strs, kuid.String())
+	}
+	after := time.Now()
+
+	for i := 1; i < len(ids); i++ {
+		prev, cur := ids[i-1], ids[i]
+		if cur.msb <= prev.msb {
+			t.Fatalf("KUID %d does not sort after its predecessor", i)
+		}
+	}
+	if !sort.StringsAreSorted(strs) {
+		t.Errorf("String forms of ordered KUIDs are not sorted")
+	}
+
+	ts := ids[0].Timestamp()
+	if ts.Before(before) || ts.After(after) {
+		t.Errorf("Timestamp() = %v, want between %v and %v", ts, before, after)
+	}
+}
+
+func TestNewOrderedClockRegression(t *testing.T) {
+	g, _ := NewGenerator()
+
+	// Pretend the last KUID was minted in the future
+	future := time.Now().Add(time.Hour).UnixMilli()
+	g.lastTimestamp = future
+	g.sequence = 7
+
+	kuid, err := g.NewOrdered()
+	if err != nil {
+		t.Fatalf("NewOrdered() error = %v", err)
+	}
+	if kuid.Timestamp().UnixMilli() != future || kuid.Sequence() != 8 {
+		t.Errorf("NewOrdered() = (%v, %v), want (%v, %v)",
+			kuid.Timestamp().UnixMilli(), kuid.Sequence(), future, 8)
+	}
+
+	// Exhausting the sequence moves on to the next millisecond
+	g.sequence = maxSequence
+	kuid, err = g.NewOrdered()
+	if err != nil {
+		t.Fatalf("NewOrdered() error = %v", err)
+	}
+	if kuid.Timestamp().UnixMilli() != future+1 || kuid.Sequence() != 0 {
+		t.Errorf("NewOrdered() = (%v, %v), want (%v, %v)",
+			kuid.Timestamp().UnixMilli(), kuid.Sequence(), future+1, 0)
+	}
+}
+
+func TestClockLag(t *testing.T) {
+	start := time.UnixMilli(1700000000000)
+	clock := NewManualClock(start)
+	g, _ := NewGenerator(WithClock(clock))
+	if lag := g.ClockLag(); lag != 0 {
+		t.Errorf("ClockLag() before any KUID = %v, want 0", lag)
+	}
+
+	g.NewOrdered()
+	clock.Set(start.Add(-2 * time.Second))
+	if lag := g.ClockLag(); lag != 2*time.Second {
+		t.Errorf("ClockLag() after clock stepped back = %v, want 2s", lag)
+	}
+
+	clock.Set(start.Add(time.Second))
+	if lag := g.ClockLag(); lag != 0 {
+		t.Errorf("ClockLag() after clock caught up = %v, want 0", lag)
+	}
+}
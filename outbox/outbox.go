@@ -0,0 +1,222 @@
+// Package outbox standardizes transactional outbox and consumer dedup
+// records on KUID message IDs.
+//
+// A service writes Records to its outbox table in the same transaction as
+// the state change they announce, and a relay publishes pending records
+// in ID order and marks them published. Message IDs are ordered KUIDs, so
+// the relay's scan is an index range and consumers can dedup on the ID
+// carried in the kuid.MessageIDHeader header. Consumers remember the IDs
+// they have processed as DedupRecords that expire once redelivery is no
+// longer possible.
+//
+// Schema and DedupSchema emit matching tables for each SQL dialect.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+var ErrNotFound = errors.New("outbox record not found")
+
+// Record is a message waiting in the outbox
+type Record struct {
+	ID      kuid.KUID
+	Topic   string
+	Key     []byte // partition key; nil to let the broker choose
+	Payload []byte
+	Headers map[string]string
+	// PublishedAt is zero until the relay has published the record
+	PublishedAt time.Time
+}
+
+// generator mints message IDs for NewRecord calls without a Generator
+var generator, _ = kuid.NewGenerator()
+
+// NewRecord creates a record for payload with a new ordered message ID
+// from g, or from a package generator if g is nil. The ID is also carried
+// in the kuid.MessageIDHeader header.
+func NewRecord(g *kuid.Generator, topic string, payload []byte) (*Record, error) {
+	if g == nil {
+		g = generator
+	}
+	if topic == "" {
+		return nil, errors.New("outbox record needs a topic")
+	}
+	id, err := g.NewOrdered()
+	if err != nil {
+		return nil, err
+	}
+	return &Record{
+		ID:      *id,
+		Topic:   topic,
+		Payload: payload,
+		Headers: map[string]string{kuid.MessageIDHeader: id.String()},
+	}, nil
+}
+
+// CreatedAt returns when the record was created, from its ID
+func (r *Record) CreatedAt() time.Time {
+	return r.ID.Timestamp()
+}
+
+// Store is an outbox table. Add must use the transaction of the state
+// change being announced, typically by binding the store to it; Pending
+// returns unpublished records oldest first.
+type Store interface {
+	Add(ctx context.Context, records ...*Record) error
+	Pending(ctx context.Context, limit int) ([]*Record, error)
+	MarkPublished(ctx context.Context, at time.Time, ids ...kuid.KUID) error
+}
+
+// Relay publishes up to limit pending records in order, marking each batch
+// of successes published. It stops at the first failure so later records
+// are not published ahead of it, and returns the number published.
+// Delivery is at least once: a crash between publishing and marking
+// republishes, which consumers absorb with a DedupStore.
+func Relay(ctx context.Context, s Store, limit int, publish func(context.Context, *Record) error) (int, error) {
+	records, err := s.Pending(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var published []kuid.KUID
+	var pubErr error
+	for _, r := range records {
+		if pubErr = publish(ctx, r); pubErr != nil {
+			break
+		}
+		published = append(published, r.ID)
+	}
+	if len(published) > 0 {
+		if err := s.MarkPublished(ctx, time.Now(), published...); err != nil {
+			return 0, err
+		}
+	}
+	return len(published), pubErr
+}
+
+// DedupRecord notes that a consumer processed a message
+type DedupRecord struct {
+	Consumer  string
+	MessageID kuid.KUID
+	ExpiresAt time.Time
+}
+
+// DedupStore remembers processed messages. Claim records r and reports
+// true, or reports false if the consumer already holds an unexpired
+// record for the message; it must be atomic so exactly one of several
+// concurrent claims succeeds.
+type DedupStore interface {
+	Claim(ctx context.Context, r DedupRecord) (bool, error)
+	Purge(ctx context.Context, before time.Time) (int, error)
+}
+
+// MemoryStore is an in-process Store, useful for tests
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[kuid.KUID]*Record
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[kuid.KUID]*Record)}
+}
+
+// Add implements Store
+func (m *MemoryStore) Add(_ context.Context, records ...*Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range records {
+		if _, ok := m.records[r.ID]; ok {
+			return errors.New("duplicate outbox record " + r.ID.String())
+		}
+	}
+	for _, r := range records {
+		c := *r
+		m.records[r.ID] = &c
+	}
+	return nil
+}
+
+// Pending implements Store
+func (m *MemoryStore) Pending(_ context.Context, limit int) ([]*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pending []*Record
+	for _, r := range m.records {
+		if r.PublishedAt.IsZero() {
+			c := *r
+			pending = append(pending, &c)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ID.Compare(&pending[j].ID) < 0
+	})
+	if limit > 0 && len(pending) > limit {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+// MarkPublished implements Store
+func (m *MemoryStore) MarkPublished(_ context.Context, at time.Time, ids ...kuid.KUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		r, ok := m.records[id]
+		if !ok {
+			return ErrNotFound
+		}
+		r.PublishedAt = at
+	}
+	return nil
+}
+
+// MemoryDedupStore is an in-process DedupStore
+type MemoryDedupStore struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	records map[dedupKey]time.Time
+}
+
+type dedupKey struct {
+	consumer string
+	id       kuid.KUID
+}
+
+// NewMemoryDedupStore creates an empty MemoryDedupStore
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{now: time.Now, records: make(map[dedupKey]time.Time)}
+}
+
+// Claim implements DedupStore
+func (m *MemoryDedupStore) Claim(_ context.Context, r DedupRecord) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := dedupKey{r.Consumer, r.MessageID}
+	if expires, ok := m.records[key]; ok && m.now().Before(expires) {
+		return false, nil
+	}
+	m.records[key] = r.ExpiresAt
+	return true, nil
+}
+
+// Purge implements DedupStore
+func (m *MemoryDedupStore) Purge(_ context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for key, expires := range m.records {
+		if expires.Before(before) {
+			delete(m.records, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestNewRecord(t *testing.T) {
+	r, err := NewRecord(nil, "orders", []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Headers[kuid.MessageIDHeader] != r.ID.String() {
+		t.Errorf("headers = %v, want message ID %v", r.Headers, r.ID)
+	}
+	if time.Since(r.CreatedAt()) > time.Minute {
+		t.Errorf("CreatedAt() = %v", r.CreatedAt())
+	}
+	if _, err := NewRecord(nil, "", nil); err == nil {
+		t.Error("NewRecord() accepted an empty topic")
+	}
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+	g, _ := kuid.NewGenerator()
+	s := NewMemoryStore()
+	var records []*Record
+	for i := 0; i < 5; i++ {
+		r, _ := NewRecord(g, "orders", []byte{byte(i)})
+		records = append(records, r)
+	}
+	// Added out of order; Pending must still return creation order
+	if err := s.Add(ctx, records[3], records[4], records[0], records[1], records[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(ctx, records[0]); err == nil {
+		t.Error("Add() accepted a duplicate ID")
+	}
+
+	var got []byte
+	failAt := byte(3)
+	publish := func(_ context.Context, r *Record) error {
+		if r.Payload[0] == failAt {
+			return errors.New("broker down")
+		}
+		got = append(got, r.Payload[0])
+		return nil
+	}
+
+	n, err := Relay(ctx, s, 10, publish)
+	if err == nil || n != 3 || string(got) != "\x00\x01\x02" {
+		t.Fatalf("Relay() = %d, %v; published %v", n, err, got)
+	}
+
+	failAt = 255
+	if n, err := Relay(ctx, s, 1, publish); err != nil || n != 1 {
+		t.Fatalf("Relay(limit 1) = %d, %v", n, err)
+	}
+	if n, err := Relay(ctx, s, 10, publish); err != nil || n != 1 {
+		t.Fatalf("Relay() = %d, %v", n, err)
+	}
+	if string(got) != "\x00\x01\x02\x03\x04" {
+		t.Errorf("published %v, want every record once in order", got)
+	}
+	if pending, _ := s.Pending(ctx, 0); len(pending) != 0 {
+		t.Errorf("%d records still pending", len(pending))
+	}
+	if err := s.MarkPublished(ctx, time.Now(), kuid.KUID{}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("MarkPublished(unknown) error = %v", err)
+	}
+}
+
+func TestMemoryDedupStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryDedupStore()
+	s.now = func() time.Time { return now }
+
+	id, _ := kuid.NewValue()
+	r := DedupRecord{Consumer: "billing", MessageID: id, ExpiresAt: now.Add(time.Hour)}
+	if ok, _ := s.Claim(ctx, r); !ok {
+		t.Fatal("first Claim() = false")
+	}
+	if ok, _ := s.Claim(ctx, r); ok {
+		t.Error("repeated Claim() = true")
+	}
+	other := r
+	other.Consumer = "shipping"
+	if ok, _ := s.Claim(ctx, other); !ok {
+		t.Error("Claim() by another consumer = false")
+	}
+
+	now = now.Add(2 * time.Hour)
+	r.ExpiresAt = now.Add(time.Hour)
+	if ok, _ := s.Claim(ctx, r); !ok {
+		t.Error("Claim() after expiry = false")
+	}
+	if n, _ := s.Purge(ctx, now); n != 1 {
+		t.Errorf("Purge() = %d, want the expired shipping record", n)
+	}
+}
@@ -0,0 +1,76 @@
+package outbox
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alphabatem/kuid/sqlgen"
+)
+
+// column types per dialect: message ID, text, bytes, JSON headers, time
+var columnTypes = map[sqlgen.Dialect][5]string{
+	sqlgen.Postgres: {"uuid", "text", "bytea", "jsonb", "timestamptz"},
+	sqlgen.MySQL:    {"BINARY(16)", "VARCHAR(255)", "LONGBLOB", "JSON", "DATETIME(6)"},
+	sqlgen.SQLite:   {"BLOB", "TEXT", "BLOB", "TEXT", "TEXT"},
+}
+
+// Schema returns the migration creating an outbox table for Records. IDs
+// use the dialect's 16-byte storage, as bound by kuid.SQLUUID in
+// PostgreSQL and kuid.SQLBytes elsewhere, so the pending index scans in
+// creation order.
+func Schema(d sqlgen.Dialect, table string) (*sqlgen.Migration, error) {
+	types, ok := columnTypes[d]
+	if !ok || !sqlgen.ValidIdentifier(table) {
+		return nil, fmt.Errorf("invalid outbox table %q for dialect %v", table, d)
+	}
+	id, text, bytes, headers, ts := types[0], types[1], types[2], types[3], types[4]
+
+	create := fmt.Sprintf(`CREATE TABLE %s (
+	id %s NOT NULL PRIMARY KEY,
+	topic %s NOT NULL,
+	message_key %s,
+	payload %s NOT NULL,
+	headers %s,
+	published_at %s
+)`, table, id, text, bytes, bytes, headers, ts)
+
+	// MySQL has no partial indexes
+	index := fmt.Sprintf("CREATE INDEX %s_pending ON %s (id) WHERE published_at IS NULL", indexName(table), table)
+	if d == sqlgen.MySQL {
+		index = fmt.Sprintf("CREATE INDEX %s_pending ON %s (published_at, id)", indexName(table), table)
+	}
+	return &sqlgen.Migration{
+		Up:   []string{create, index},
+		Down: []string{"DROP TABLE " + table},
+	}, nil
+}
+
+// DedupSchema returns the migration creating a table for DedupRecords,
+// keyed by consumer and message ID, with an index for purging expired
+// records
+func DedupSchema(d sqlgen.Dialect, table string) (*sqlgen.Migration, error) {
+	types, ok := columnTypes[d]
+	if !ok || !sqlgen.ValidIdentifier(table) {
+		return nil, fmt.Errorf("invalid dedup table %q for dialect %v", table, d)
+	}
+	id, text, ts := types[0], types[1], types[4]
+
+	create := fmt.Sprintf(`CREATE TABLE %s (
+	consumer %s NOT NULL,
+	message_id %s NOT NULL,
+	expires_at %s NOT NULL,
+	PRIMARY KEY (consumer, message_id)
+)`, table, text, id, ts)
+	index := fmt.Sprintf("CREATE INDEX %s_expiry ON %s (expires_at)", indexName(table), table)
+	return &sqlgen.Migration{
+		Up:   []string{create, index},
+		Down: []string{"DROP TABLE " + table},
+	}, nil
+}
+
+// indexName derives index names from the table name without its schema,
+// since PostgreSQL rejects qualified index names and creates indexes in
+// their table's schema
+func indexName(table string) string {
+	return table[strings.LastIndexByte(table, '.')+1:]
+}
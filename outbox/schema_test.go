@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid/sqlgen"
+)
+
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		d     sqlgen.Dialect
+		table string
+		want  []string
+	}{
+		{sqlgen.Postgres, "app.outbox", []string{"CREATE TABLE app.outbox (", "id uuid NOT NULL PRIMARY KEY", "headers jsonb",
+			"CREATE INDEX outbox_pending ON app.outbox (id) WHERE published_at IS NULL"}},
+		{sqlgen.MySQL, "outbox", []string{"id BINARY(16) NOT NULL PRIMARY KEY", "published_at DATETIME(6)",
+			"CREATE INDEX outbox_pending ON outbox (published_at, id)"}},
+		{sqlgen.SQLite, "outbox", []string{"id BLOB NOT NULL PRIMARY KEY", "WHERE published_at IS NULL"}},
+	}
+	for _, tt := range tests {
+		m, err := Schema(tt.d, tt.table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up := m.UpScript()
+		for _, want := range tt.want {
+			if !strings.Contains(up, want) {
+				t.Errorf("%v schema has no %q:\n%s", tt.d, want, up)
+			}
+		}
+		if m.DownScript() != "DROP TABLE "+tt.table+";\n\n" {
+			t.Errorf("%v down = %q", tt.d, m.DownScript())
+		}
+	}
+
+	if _, err := Schema(sqlgen.Postgres, "outbox; DROP TABLE users"); err == nil {
+		t.Error("Schema() accepted an invalid table")
+	}
+	if _, err := Schema(sqlgen.Dialect(9), "outbox"); err == nil {
+		t.Error("Schema() accepted an unknown dialect")
+	}
+}
+
+func TestDedupSchema(t *testing.T) {
+	m, err := DedupSchema(sqlgen.MySQL, "processed_messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"message_id BINARY(16) NOT NULL", "PRIMARY KEY (consumer, message_id)",
+		"CREATE INDEX processed_messages_expiry ON processed_messages (expires_at)"} {
+		if !strings.Contains(m.UpScript(), want) {
+			t.Errorf("schema has no %q", want)
+		}
+	}
+	if _, err := DedupSchema(sqlgen.SQLite, ""); err == nil {
+		t.Error("DedupSchema() accepted an empty table")
+	}
+}
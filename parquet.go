@@ -0,0 +1,26 @@
+package kuid
+
+import "fmt"
+
+// ParquetSchema returns the Parquet message-type definition of a KUID
+// column: FIXED_LEN_BYTE_ARRAY(16) annotated with the UUID logical type.
+// Parquet stores UUIDs in big-endian byte order, which matches Bytes and
+// Array, so query engines display KUID columns as their UUID form.
+//
+// With parquet-go, declare the field as a [16]byte using Array:
+//
+//	type Event struct {
+//	    ID [16]byte `parquet:"id,uuid"`
+//	}
+func ParquetSchema(column string, optional bool) string {
+	repetition := "required"
+	if optional {
+		repetition = "optional"
+	}
+	return fmt.Sprintf("%s fixed_len_byte_array(16) %s (UUID);", repetition, column)
+}
+
+// FromParquet reads a KUID from a Parquet FIXED_LEN_BYTE_ARRAY(16) value
+func FromParquet(b []byte) (*KUID, error) {
+	return FromBytes(b)
+}
@@ -0,0 +1,23 @@
+package kuid
+
+import "testing"
+
+func TestParquetSchema(t *testing.T) {
+	if got := ParquetSchema("id", false); got != "required fixed_len_byte_array(16) id (UUID);" {
+		t.Errorf("ParquetSchema() = %v", got)
+	}
+	if got := ParquetSchema("parent_id", true); got != "optional fixed_len_byte_array(16) parent_id (UUID);" {
+		t.Errorf("ParquetSchema() = %v", got)
+	}
+}
+
+func TestFromParquet(t *testing.T) {
+	kuid, _ := NewKUID()
+	decoded, err := FromParquet(kuid.Bytes())
+	if err != nil {
+		t.Fatalf("FromParquet() error = %v", err)
+	}
+	if !decoded.Equal(kuid) {
+		t.Errorf("FromParquet() = %v, want %v", decoded, kuid)
+	}
+}
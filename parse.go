@@ -0,0 +1,35 @@
+package kuid
+
+import "fmt"
+
+// ParseError reports a string that is neither a KUID nor a UUID
+type ParseError struct {
+	Input string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid KUID %q: %v", e.Input, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse creates a KUID from either its 22-character base62 form or a
+// hyphenated UUID. Failures are returned as *ParseError.
+func Parse(s string) (*KUID, error) {
+	var (
+		k   *KUID
+		err error
+	)
+	if len(s) == 36 {
+		k, err = FromUUID(s)
+	} else {
+		k, err = FromString(s)
+	}
+	if err != nil {
+		return nil, &ParseError{Input: s, Err: err}
+	}
+	return k, nil
+}
@@ -0,0 +1,40 @@
+package kuid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	want, _ := FromUUID("d9db5cf3-c755-4f76-8746-04120f2644c6")
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "KUID", input: want.String()},
+		{name: "UUID", input: want.ToUUID()},
+		{name: "Invalid length", input: "abc", wantErr: ErrInvalidLength},
+		{name: "Invalid UUID", input: "d9db5cf3-c755-4f76-8746-04120f2644cg", wantErr: ErrInvalidUUID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != nil {
+				var perr *ParseError
+				if !errors.As(err, &perr) || perr.Input != tt.input || !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse() error = %v, want ParseError wrapping %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("Parse() = %v, want %v", got, want)
+			}
+		})
+	}
+}
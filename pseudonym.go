@@ -0,0 +1,96 @@
+package kuid
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+const minPseudonymKey = 16
+
+// PseudonymStore keeps the reverse mapping from pseudonyms to the external
+// IDs they were derived from. Get must return ErrNotFound for unknown IDs.
+type PseudonymStore interface {
+	Put(ctx context.Context, id *KUID, externalID string) error
+	Get(ctx context.Context, id *KUID) (string, error)
+}
+
+// Pseudonymizer derives stable KUIDs from external identifiers such as
+// customer emails or account numbers with HMAC-SHA256, so data sets can be
+// joined on the pseudonym without shipping the raw identifier. The same key
+// always yields the same KUID for the same input; without the key the
+// mapping cannot be reproduced or reversed.
+type Pseudonymizer struct {
+	key   []byte
+	store PseudonymStore
+}
+
+// NewPseudonymizer creates a Pseudonymizer. The key must be at least 16
+// bytes. store is optional and only needed for Record and Reverse.
+func NewPseudonymizer(key []byte, store PseudonymStore) (*Pseudonymizer, error) {
+	if len(key) < minPseudonymKey {
+		return nil, errors.New("pseudonymizer key must be at least 16 bytes")
+	}
+	return &Pseudonymizer{key: append([]byte(nil), key...), store: store}, nil
+}
+
+// Pseudonymize returns the KUID for externalID
+func (p *Pseudonymizer) Pseudonymize(externalID string) *KUID {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(externalID))
+	sum := mac.Sum(nil)
+	return &KUID{msb: binary.BigEndian.Uint64(sum[0:8]), lsb: binary.BigEndian.Uint64(sum[8:16])}
+}
+
+// Record pseudonymizes externalID and saves the reverse mapping in the store
+func (p *Pseudonymizer) Record(ctx context.Context, externalID string) (*KUID, error) {
+	if p.store == nil {
+		return nil, errors.New("pseudonymizer has no store")
+	}
+	id := p.Pseudonymize(externalID)
+	if err := p.store.Put(ctx, id, externalID); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// Reverse looks up the external ID a pseudonym was recorded for
+func (p *Pseudonymizer) Reverse(ctx context.Context, id *KUID) (string, error) {
+	if p.store == nil {
+		return "", errors.New("pseudonymizer has no store")
+	}
+	return p.store.Get(ctx, id)
+}
+
+// MemoryPseudonymStore is an in-process PseudonymStore
+type MemoryPseudonymStore struct {
+	mu       sync.RWMutex
+	external map[KUID]string
+}
+
+// NewMemoryPseudonymStore creates an empty MemoryPseudonymStore
+func NewMemoryPseudonymStore() *MemoryPseudonymStore {
+	return &MemoryPseudonymStore{external: make(map[KUID]string)}
+}
+
+// Put implements PseudonymStore
+func (m *MemoryPseudonymStore) Put(_ context.Context, id *KUID, externalID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.external[*id] = externalID
+	return nil
+}
+
+// Get implements PseudonymStore
+func (m *MemoryPseudonymStore) Get(_ context.Context, id *KUID) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	externalID, ok := m.external[*id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return externalID, nil
+}
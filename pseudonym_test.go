@@ -0,0 +1,56 @@
+package kuid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPseudonymize(t *testing.T) {
+	p, err := NewPseudonymizer([]byte("0123456789abcdef"), nil)
+	if err != nil {
+		t.Fatalf("NewPseudonymizer() error = %v", err)
+	}
+	other, _ := NewPseudonymizer([]byte("fedcba9876543210"), nil)
+
+	a := p.Pseudonymize("alice@example.com")
+	if !a.Equal(p.Pseudonymize("alice@example.com")) {
+		t.Errorf("Pseudonymize() is not deterministic")
+	}
+	if a.Equal(p.Pseudonymize("bob@example.com")) {
+		t.Errorf("Pseudonymize() maps different IDs to the same KUID")
+	}
+	if a.Equal(other.Pseudonymize("alice@example.com")) {
+		t.Errorf("Pseudonymize() does not depend on the key")
+	}
+
+	if _, err := p.Reverse(context.Background(), a); err == nil {
+		t.Errorf("Reverse() without store expected error")
+	}
+}
+
+func TestPseudonymizerReverse(t *testing.T) {
+	ctx := context.Background()
+	p, _ := NewPseudonymizer([]byte("0123456789abcdef"), NewMemoryPseudonymStore())
+
+	id, err := p.Record(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	got, err := p.Reverse(ctx, id)
+	if err != nil {
+		t.Fatalf("Reverse() error = %v", err)
+	}
+	if got != "alice@example.com" {
+		t.Errorf("Reverse() = %v, want alice@example.com", got)
+	}
+
+	if _, err := p.Reverse(ctx, p.Pseudonymize("unrecorded")); err != ErrNotFound {
+		t.Errorf("Reverse() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestNewPseudonymizerShortKey(t *testing.T) {
+	if _, err := NewPseudonymizer([]byte("short"), nil); err == nil {
+		t.Errorf("NewPseudonymizer() expected error for short key")
+	}
+}
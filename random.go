@@ -0,0 +1,11 @@
+//go:build !(js && wasm)
+
+package kuid
+
+import "crypto/rand"
+
+// readRandom fills b with cryptographically secure random bytes
+func readRandom(b []byte) error {
+	_, err := rand.Read(b)
+	return err
+}
@@ -0,0 +1,35 @@
+//go:build js && wasm
+
+package kuid
+
+import (
+	"errors"
+	"syscall/js"
+)
+
+// maxGetRandomValues is the most the Web Crypto API fills per call
+const maxGetRandomValues = 65536
+
+// readRandom fills b from the host's Web Crypto API. Calling it directly
+// rather than through crypto/rand works the same under TinyGo, whose
+// crypto/rand support on js/wasm varies between releases.
+func readRandom(b []byte) (err error) {
+	crypto := js.Global().Get("crypto")
+	if crypto.IsUndefined() {
+		return errors.New("kuid: Web Crypto API unavailable")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("kuid: crypto.getRandomValues failed")
+		}
+	}()
+
+	for len(b) > 0 {
+		n := min(len(b), maxGetRandomValues)
+		arr := js.Global().Get("Uint8Array").New(n)
+		crypto.Call("getRandomValues", arr)
+		js.CopyBytesToGo(b[:n], arr)
+		b = b[n:]
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build js && wasm
+
+package kuid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadRandomWebCrypto(t *testing.T) {
+	// Larger than one getRandomValues call allows
+	b := make([]byte, maxGetRandomValues+100)
+	if err := readRandom(b); err != nil {
+		t.Fatal(err)
+	}
+	tail := b[maxGetRandomValues:]
+	if bytes.Equal(tail, make([]byte, len(tail))) {
+		t.Errorf("Bytes past the first chunk were not filled")
+	}
+}
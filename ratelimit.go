@@ -0,0 +1,194 @@
+package kuid
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	limiterShards     = 64
+	limiterSweepEvery = 256 // new buckets per shard between idle sweeps
+)
+
+// RateLimitOptions configures a RateLimiter
+type RateLimitOptions struct {
+	// Rate is the number of events allowed per second per KUID
+	Rate float64
+	// Burst is the most events a KUID may spend at once; a KUID seen for
+	// the first time starts with a full bucket
+	Burst int
+	// TTL is how long a KUID's bucket is kept after its last event. Zero,
+	// or anything shorter than the time an empty bucket takes to refill,
+	// means that refill time, so evicting a bucket never forgives its debt.
+	TTL time.Duration
+	// OnDecision, if set, is called after every AllowN with its outcome,
+	// for counting allowed and limited requests per tenant
+	OnDecision func(k *KUID, allowed bool)
+	// OnEvict, if set, is called with the number of idle buckets removed
+	// by each sweep that removed any
+	OnEvict func(n int)
+}
+
+// RateLimiter is a token-bucket limiter with one bucket per KUID, for
+// per-user or per-tenant limits. Buckets are 16-byte map keys spread over
+// independently locked shards, created on first use and evicted once idle
+// for the TTL. The zero value is not usable; create one with
+// NewRateLimiter.
+type RateLimiter struct {
+	rate   float64
+	burst  float64
+	ttl    time.Duration
+	now    func() time.Time
+	shards [limiterShards]limiterShard
+
+	onDecision func(k *KUID, allowed bool)
+	onEvict    func(n int)
+}
+
+type limiterShard struct {
+	mu      sync.Mutex
+	buckets map[KUID]tokenBucket
+	inserts int
+}
+
+// tokenBucket holds the tokens left as of last
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing opts.Rate events per
+// second per KUID, in bursts of up to opts.Burst
+func NewRateLimiter(opts RateLimitOptions) (*RateLimiter, error) {
+	if !(opts.Rate > 0) || math.IsInf(opts.Rate, 1) {
+		return nil, errors.New("rate limit must be positive and finite")
+	}
+	if opts.Burst < 1 {
+		return nil, errors.New("rate limit burst must be at least 1")
+	}
+	refill := time.Duration(math.MaxInt64)
+	if d := math.Ceil(float64(opts.Burst) / opts.Rate * float64(time.Second)); d < float64(math.MaxInt64) {
+		refill = time.Duration(d)
+	}
+	l := &RateLimiter{
+		rate:  opts.Rate,
+		burst: float64(opts.Burst),
+		ttl:   max(opts.TTL, refill),
+		now:   time.Now,
+
+		onDecision: opts.OnDecision,
+		onEvict:    opts.OnEvict,
+	}
+	for i := range l.shards {
+		l.shards[i].buckets = make(map[KUID]tokenBucket)
+	}
+	return l, nil
+}
+
+// Allow reports whether an event for k may happen now, spending a token
+// if so
+func (l *RateLimiter) Allow(k *KUID) bool {
+	return l.AllowN(k, 1)
+}
+
+// AllowN reports whether n events for k may happen now, spending n tokens
+// if so. A denied call spends nothing, and n above the burst is never
+// allowed.
+func (l *RateLimiter) AllowN(k *KUID, n int) bool {
+	s := l.shard(k)
+	now := l.now()
+	evicted := 0
+
+	s.mu.Lock()
+	b, ok := s.buckets[*k]
+	if ok {
+		b.tokens = l.refill(b, now)
+	} else {
+		b.tokens = l.burst
+		if s.inserts++; s.inserts%limiterSweepEvery == 0 {
+			evicted = l.sweep(s, now)
+		}
+	}
+	b.last = now
+	allowed := float64(n) <= b.tokens
+	if allowed {
+		b.tokens -= float64(n)
+	}
+	s.buckets[*k] = b
+	s.mu.Unlock()
+
+	// Hooks run unlocked so they may call back into the limiter
+	if evicted > 0 && l.onEvict != nil {
+		l.onEvict(evicted)
+	}
+	if l.onDecision != nil {
+		l.onDecision(k, allowed)
+	}
+	return allowed
+}
+
+// Forget drops k's bucket, so its next event starts with a full burst
+func (l *RateLimiter) Forget(k *KUID) {
+	s := l.shard(k)
+	s.mu.Lock()
+	delete(s.buckets, *k)
+	s.mu.Unlock()
+}
+
+// Len returns the number of buckets held, including idle ones not yet
+// swept
+func (l *RateLimiter) Len() int {
+	n := 0
+	for i := range l.shards {
+		s := &l.shards[i]
+		s.mu.Lock()
+		n += len(s.buckets)
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// Sweep removes every bucket idle for the TTL. Buckets are also swept
+// incrementally as new KUIDs arrive, so calling Sweep is optional.
+func (l *RateLimiter) Sweep() {
+	now := l.now()
+	evicted := 0
+	for i := range l.shards {
+		s := &l.shards[i]
+		s.mu.Lock()
+		evicted += l.sweep(s, now)
+		s.mu.Unlock()
+	}
+	if evicted > 0 && l.onEvict != nil {
+		l.onEvict(evicted)
+	}
+}
+
+// shard returns the shard holding k, chosen by hash64
+func (l *RateLimiter) shard(k *KUID) *limiterShard {
+	return &l.shards[hash64(k)%limiterShards]
+}
+
+// refill returns b's tokens as of now, capped at the burst
+func (l *RateLimiter) refill(b tokenBucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return min(l.burst, b.tokens+elapsed.Seconds()*l.rate)
+}
+
+// sweep removes idle buckets from s and returns how many. Callers must
+// hold s.mu.
+func (l *RateLimiter) sweep(s *limiterShard, now time.Time) int {
+	n := 0
+	for k, b := range s.buckets {
+		if now.Sub(b.last) >= l.ttl {
+			delete(s.buckets, k)
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,215 @@
+package kuid
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiterInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RateLimitOptions
+	}{
+		{"zero rate", RateLimitOptions{Rate: 0, Burst: 1}},
+		{"negative rate", RateLimitOptions{Rate: -1, Burst: 1}},
+		{"NaN rate", RateLimitOptions{Rate: math.NaN(), Burst: 1}},
+		{"infinite rate", RateLimitOptions{Rate: math.Inf(1), Burst: 1}},
+		{"zero burst", RateLimitOptions{Rate: 1, Burst: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRateLimiter(tt.opts); err == nil {
+				t.Errorf("NewRateLimiter(%+v) succeeded, want error", tt.opts)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l, err := NewRateLimiter(RateLimitOptions{Rate: 2, Burst: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	id, _ := NewKUID()
+	for i := 0; i < 3; i++ {
+		if !l.Allow(id) {
+			t.Fatalf("Allow() #%d = false within burst", i)
+		}
+	}
+	copied, _ := FromString(id.String())
+	if l.Allow(copied) {
+		t.Fatalf("Allow() = true after burst spent")
+	}
+
+	other, _ := NewKUID()
+	if !l.Allow(other) {
+		t.Errorf("Allow() = false for a different KUID")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow(id) {
+		t.Errorf("Allow() = false after one token refilled")
+	}
+	if l.Allow(id) {
+		t.Errorf("Allow() = true with no tokens left")
+	}
+
+	now = now.Add(time.Hour)
+	if !l.AllowN(id, 3) {
+		t.Errorf("AllowN(3) = false after full refill")
+	}
+	now = now.Add(time.Hour)
+	if l.AllowN(id, 4) {
+		t.Errorf("AllowN(4) = true above burst")
+	}
+	if !l.AllowN(id, 3) {
+		t.Errorf("AllowN(3) = false after a denied call")
+	}
+
+	l.Forget(id)
+	if !l.AllowN(id, 3) {
+		t.Errorf("AllowN(3) = false after Forget")
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	var evicted atomic.Int64
+	l, err := NewRateLimiter(RateLimitOptions{
+		Rate:    10,
+		Burst:   10,
+		TTL:     time.Minute,
+		OnEvict: func(n int) { evicted.Add(int64(n)) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		id, _ := NewKUID()
+		l.Allow(id)
+	}
+	if l.Len() != 100 {
+		t.Fatalf("Len() = %d, want 100", l.Len())
+	}
+
+	now = now.Add(59 * time.Second)
+	l.Sweep()
+	if l.Len() != 100 {
+		t.Errorf("Len() = %d after Sweep within TTL, want 100", l.Len())
+	}
+
+	now = now.Add(time.Second)
+	l.Sweep()
+	if l.Len() != 0 {
+		t.Errorf("Len() = %d after Sweep, want 0", l.Len())
+	}
+	if evicted.Load() != 100 {
+		t.Errorf("OnEvict total = %d, want 100", evicted.Load())
+	}
+}
+
+func TestRateLimiterTTLAtLeastRefill(t *testing.T) {
+	// An empty bucket takes 10s to refill, so a 1s TTL must not evict it
+	l, err := NewRateLimiter(RateLimitOptions{Rate: 1, Burst: 10, TTL: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	id, _ := NewKUID()
+	l.AllowN(id, 10)
+	now = now.Add(5 * time.Second)
+	l.Sweep()
+	if l.AllowN(id, 6) {
+		t.Errorf("AllowN(6) = true; bucket was evicted with debt")
+	}
+}
+
+func TestRateLimiterOnDecision(t *testing.T) {
+	var allowed, limited atomic.Int64
+	var l *RateLimiter
+	l, err := NewRateLimiter(RateLimitOptions{
+		Rate:  1,
+		Burst: 2,
+		OnDecision: func(k *KUID, ok bool) {
+			if ok {
+				allowed.Add(1)
+			} else {
+				limited.Add(1)
+			}
+			l.Len() // hooks may call back into the limiter
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.now = func() time.Time { return time.Unix(0, 0) }
+
+	id, _ := NewKUID()
+	for i := 0; i < 5; i++ {
+		l.Allow(id)
+	}
+	if allowed.Load() != 2 || limited.Load() != 3 {
+		t.Errorf("allowed, limited = %d, %d; want 2, 3", allowed.Load(), limited.Load())
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l, err := NewRateLimiter(RateLimitOptions{Rate: 1e-3, Burst: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := NewKUID()
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l.Allow(id) {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed.Load() != 50 {
+		t.Errorf("%d concurrent calls allowed, want 50", allowed.Load())
+	}
+}
+
+func TestRateLimiterShardSpread(t *testing.T) {
+	l, _ := NewRateLimiter(RateLimitOptions{Rate: 1, Burst: 1})
+	used := make(map[*limiterShard]bool)
+	for _, id := range fixedLowBits(t, 1000) {
+		used[l.shard(id)] = true
+	}
+	if len(used) < limiterShards/2 {
+		t.Errorf("1000 typed and v6 KUIDs used %d of %d shards", len(used), limiterShards)
+	}
+}
+
+func BenchmarkRateLimiterAllow(b *testing.B) {
+	l, _ := NewRateLimiter(RateLimitOptions{Rate: 1e6, Burst: 1e6})
+	ids := make([]*KUID, 1024)
+	for i := range ids {
+		ids[i], _ = NewKUID()
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			l.Allow(ids[i%len(ids)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package kuid
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+const (
+	redactHead = 6
+	redactTail = 4
+)
+
+// logRedaction controls whether KUIDs are redacted when logged
+var logRedaction atomic.Bool
+
+// Redacted returns a shortened form of the KUID that is recognisable to
+// humans but does not reproduce the full identifier, e.g. "4FqyMN…44c6"
+func (k *KUID) Redacted() string {
+	s := k.String()
+	return s[:redactHead] + "…" + s[len(s)-redactTail:]
+}
+
+// SetLogRedaction makes LogValue and LogString return redacted KUIDs, so
+// identifiers tied to personal data are not reproduced in full by log
+// aggregation systems. It is off by default.
+func SetLogRedaction(enabled bool) {
+	logRedaction.Store(enabled)
+}
+
+// LogRedaction reports whether log redaction is enabled
+func LogRedaction() bool {
+	return logRedaction.Load()
+}
+
+// LogString returns the string to log for the KUID, honouring
+// SetLogRedaction. Use it with loggers such as zap:
+//
+//	logger.Info("order placed", zap.String("order", id.LogString()))
+func (k KUID) LogString() string {
+	if logRedaction.Load() {
+		return k.Redacted()
+	}
+	return k.String()
+}
+
+// LogValue implements slog.LogValuer, honouring SetLogRedaction
+func (k KUID) LogValue() slog.Value {
+	return slog.StringValue(k.LogString())
+}
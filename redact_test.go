@@ -0,0 +1,48 @@
+package kuid
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	kuid, _ := FromUUID("d9db5cf3-c755-4f76-8746-04120f2644c6")
+	str := kuid.String()
+
+	got := kuid.Redacted()
+	want := str[:6] + "…" + str[len(str)-4:]
+	if got != want {
+		t.Errorf("Redacted() = %v, want %v", got, want)
+	}
+	if strings.Contains(got, str[6:len(str)-4]) {
+		t.Errorf("Redacted() = %v reveals the middle of %v", got, str)
+	}
+}
+
+func TestLogRedaction(t *testing.T) {
+	kuid, _ := NewKUID()
+	defer SetLogRedaction(false)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	logger.Info("full", "id", kuid)
+	if !strings.Contains(buf.String(), "id="+kuid.String()) {
+		t.Errorf("Log output %q does not contain full KUID", buf.String())
+	}
+
+	buf.Reset()
+	SetLogRedaction(true)
+	if !LogRedaction() {
+		t.Errorf("LogRedaction() = false after enabling")
+	}
+	logger.Info("redacted", "id", kuid)
+	if strings.Contains(buf.String(), kuid.String()) || !strings.Contains(buf.String(), kuid.Redacted()) {
+		t.Errorf("Log output %q is not redacted", buf.String())
+	}
+	if kuid.LogString() != kuid.Redacted() {
+		t.Errorf("LogString() = %v, want %v", kuid.LogString(), kuid.Redacted())
+	}
+}
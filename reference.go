@@ -0,0 +1,206 @@
+package kuid
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	minReferenceLength     = 6
+	defaultReferenceLength = 10
+	maxReferenceAttempts   = 16
+)
+
+var ErrReferenceExhausted = errors.New("no free reference number found")
+
+// ReferenceOptions configures a ReferenceFormatter
+type ReferenceOptions struct {
+	// Length is the number of characters, excluding prefix and hyphens: 6
+	// to 12, or 6 to 19 with Digits; 10 if zero
+	Length int
+	// Digits restricts references to 0-9 for providers that only take
+	// numeric references; otherwise Crockford base32 is used
+	Digits bool
+	// Prefix is prepended as is, such as "ORD-"
+	Prefix string
+	// GroupSize splits the reference into hyphenated groups; 0 keeps it whole
+	GroupSize int
+}
+
+// ReferenceStore records which KUID each reference number belongs to.
+// Claim must be atomic: it links ref to id unless ref already belongs to
+// another KUID, and returns the owner either way. A SQL store can use a
+// unique column with INSERT ... ON CONFLICT DO NOTHING followed by a
+// SELECT. Lookup returns ErrNotFound for unknown references.
+type ReferenceStore interface {
+	Claim(ctx context.Context, ref string, id *KUID) (*KUID, error)
+	Lookup(ctx context.Context, ref string) (*KUID, error)
+}
+
+// ReferenceFormatter maps KUIDs to short reference numbers for payment
+// providers and customer support, which want something numeric-ish and
+// typable rather than a 22-character KUID.
+//
+// A reference is a SHA-256 hash of the whole KUID and an attempt number,
+// truncated to Length base10 or base32 digits. Hashing keeps the region,
+// tenant and version bits that many layouts fix out of references.
+// Truncation makes collisions possible: a 10-digit reference collides with
+// one of a million others with probability about 10^-4. When two KUIDs
+// truncate alike, the store tracks the first and the second moves on to
+// its next attempt. References are deterministic given the store's
+// contents, so repeated calls for one KUID return the same reference.
+type ReferenceFormatter struct {
+	opts  ReferenceOptions
+	store ReferenceStore
+}
+
+// NewReferenceFormatter creates a ReferenceFormatter. store may be nil when
+// only Format is used.
+func NewReferenceFormatter(opts ReferenceOptions, store ReferenceStore) (*ReferenceFormatter, error) {
+	if opts.Length == 0 {
+		opts.Length = defaultReferenceLength
+	}
+	maxLength := 12 // 60 of the 64 bits
+	if opts.Digits {
+		maxLength = 19
+	}
+	if opts.Length < minReferenceLength || opts.Length > maxLength {
+		return nil, errors.New("reference length out of range")
+	}
+	if opts.GroupSize < 0 {
+		return nil, errors.New("group size must not be negative")
+	}
+	return &ReferenceFormatter{opts: opts, store: store}, nil
+}
+
+// Format returns id's first-choice reference without consulting the store
+func (f *ReferenceFormatter) Format(id *KUID) string {
+	return f.format(f.candidate(id, 0))
+}
+
+// Reference returns id's reference, claiming it in the store. It returns
+// ErrReferenceExhausted if every candidate is taken, which means Length is
+// too short for the number of KUIDs referenced.
+func (f *ReferenceFormatter) Reference(ctx context.Context, id *KUID) (string, error) {
+	if f.store == nil {
+		return "", errors.New("reference formatter has no store")
+	}
+	for attempt := 0; attempt < maxReferenceAttempts; attempt++ {
+		ref := f.candidate(id, attempt)
+		owner, err := f.store.Claim(ctx, ref, id)
+		if err != nil {
+			return "", err
+		}
+		if owner.Equal(id) {
+			return f.format(ref), nil
+		}
+	}
+	return "", ErrReferenceExhausted
+}
+
+// Lookup returns the KUID a reference belongs to. Input is normalized
+// first, so the prefix, hyphens, spaces and case do not matter.
+func (f *ReferenceFormatter) Lookup(ctx context.Context, input string) (*KUID, error) {
+	if f.store == nil {
+		return nil, errors.New("reference formatter has no store")
+	}
+	return f.store.Lookup(ctx, f.Normalize(input))
+}
+
+// Normalize strips the prefix, hyphens and whitespace from input and
+// uppercases it
+func (f *ReferenceFormatter) Normalize(input string) string {
+	input = strings.TrimSpace(input)
+	if len(input) >= len(f.opts.Prefix) && strings.EqualFold(input[:len(f.opts.Prefix)], f.opts.Prefix) {
+		input = input[len(f.opts.Prefix):]
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ':
+			return -1
+		}
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, input)
+}
+
+// candidate returns the attempt'th reference for id, without formatting.
+// Every attempt, the first included, hashes the whole KUID.
+func (f *ReferenceFormatter) candidate(id *KUID, attempt int) string {
+	var buf [24]byte
+	binary.BigEndian.PutUint64(buf[0:8], id.msb)
+	binary.BigEndian.PutUint64(buf[8:16], id.lsb)
+	binary.BigEndian.PutUint64(buf[16:24], uint64(attempt))
+	sum := sha256.Sum256(buf[:])
+	v := binary.BigEndian.Uint64(sum[:8])
+
+	ref := make([]byte, f.opts.Length)
+	if f.opts.Digits {
+		for i := len(ref) - 1; i >= 0; i-- {
+			ref[i] = byte('0' + v%10)
+			v /= 10
+		}
+	} else {
+		for i := range ref {
+			ref[i] = crockfordChars[v>>59]
+			v <<= 5
+		}
+	}
+	return string(ref)
+}
+
+// format adds the prefix and hyphens
+func (f *ReferenceFormatter) format(ref string) string {
+	if f.opts.GroupSize == 0 {
+		return f.opts.Prefix + ref
+	}
+	var b strings.Builder
+	b.WriteString(f.opts.Prefix)
+	for i := 0; i < len(ref); i += f.opts.GroupSize {
+		if i > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(ref[i:min(i+f.opts.GroupSize, len(ref))])
+	}
+	return b.String()
+}
+
+// MemoryReferenceStore is an in-process ReferenceStore
+type MemoryReferenceStore struct {
+	mu     sync.Mutex
+	owners map[string]KUID
+}
+
+// NewMemoryReferenceStore creates an empty MemoryReferenceStore
+func NewMemoryReferenceStore() *MemoryReferenceStore {
+	return &MemoryReferenceStore{owners: make(map[string]KUID)}
+}
+
+// Claim implements ReferenceStore
+func (m *MemoryReferenceStore) Claim(_ context.Context, ref string, id *KUID) (*KUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	owner, ok := m.owners[ref]
+	if !ok {
+		owner = *id
+		m.owners[ref] = owner
+	}
+	return &owner, nil
+}
+
+// Lookup implements ReferenceStore
+func (m *MemoryReferenceStore) Lookup(_ context.Context, ref string) (*KUID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	owner, ok := m.owners[ref]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &owner, nil
+}
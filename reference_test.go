@@ -0,0 +1,115 @@
+package kuid
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReferenceFormat(t *testing.T) {
+	id := &KUID{msb: 1, lsb: 1234567890123456789}
+	tests := []struct {
+		name string
+		opts ReferenceOptions
+		want *regexp.Regexp
+	}{
+		{name: "Digits", opts: ReferenceOptions{Digits: true}, want: regexp.MustCompile(`^5167099403$`)},
+		{name: "Grouped digits", opts: ReferenceOptions{Digits: true, Length: 12, Prefix: "ORD-", GroupSize: 4}, want: regexp.MustCompile(`^ORD-3951-6709-9403$`)},
+		{name: "Base32", opts: ReferenceOptions{Length: 8}, want: regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{8}$`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewReferenceFormatter(tt.opts, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.Format(id); !tt.want.MatchString(got) {
+				t.Errorf("Format() = %q, want match for %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, opts := range []ReferenceOptions{{Length: 5}, {Length: 13}, {Length: 20, Digits: true}, {GroupSize: -1}} {
+		if _, err := NewReferenceFormatter(opts, nil); err == nil {
+			t.Errorf("NewReferenceFormatter(%+v) expected error", opts)
+		}
+	}
+}
+
+func TestReferenceSpread(t *testing.T) {
+	// V4 KUIDs from a regional generator share their variant and region
+	// bits, which must not give their references a common prefix
+	g, _ := NewGenerator(WithRegion(6, 5))
+	f, _ := NewReferenceFormatter(ReferenceOptions{}, nil)
+	firsts := make(map[byte]bool)
+	for i := 0; i < 200; i++ {
+		k, _ := g.NewV4()
+		firsts[f.Format(k)[0]] = true
+	}
+	if len(firsts) < 16 {
+		t.Errorf("200 regional V4 references used %d first characters", len(firsts))
+	}
+}
+
+func TestReferenceCollisions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryReferenceStore()
+	f, _ := NewReferenceFormatter(ReferenceOptions{Digits: true, Length: 6, Prefix: "PAY-", GroupSize: 3}, store)
+
+	// Find two KUIDs whose hashes truncate to the same six digits
+	var a, b *KUID
+	firsts := make(map[string]*KUID)
+	for i := uint64(0); b == nil; i++ {
+		k := &KUID{msb: i, lsb: 1}
+		ref := f.Format(k)
+		if other, ok := firsts[ref]; ok {
+			a, b = other, k
+		}
+		firsts[ref] = k
+	}
+	refA, err := f.Reference(ctx, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refA != f.Format(a) {
+		t.Errorf("Reference(a) = %q, want %q", refA, f.Format(a))
+	}
+	refB, err := f.Reference(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refB == refA {
+		t.Fatalf("Reference(b) collides with a: %q", refB)
+	}
+
+	// Stable on repeat, and resolvable however it is typed
+	if again, _ := f.Reference(ctx, b); again != refB {
+		t.Errorf("Reference(b) = %q, then %q", refB, again)
+	}
+	typed := "pay-" + strings.ReplaceAll(refA[len("PAY-"):], "-", "")
+	for input, want := range map[string]*KUID{refA: a, typed: a, " " + refB + " ": b} {
+		if got, err := f.Lookup(ctx, input); err != nil || !got.Equal(want) {
+			t.Errorf("Lookup(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := f.Lookup(ctx, "PAY-000-000"); err != ErrNotFound {
+		t.Errorf("Lookup() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestReferenceExhausted(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryReferenceStore()
+	f, _ := NewReferenceFormatter(ReferenceOptions{Digits: true, Length: 6}, store)
+
+	// Claim every candidate of id for someone else
+	id := &KUID{msb: 1, lsb: 2}
+	other := &KUID{msb: 3, lsb: 4}
+	for i := 0; i < maxReferenceAttempts; i++ {
+		store.Claim(ctx, f.candidate(id, i), other)
+	}
+	if _, err := f.Reference(ctx, id); err != ErrReferenceExhausted {
+		t.Errorf("Reference() error = %v, want %v", err, ErrReferenceExhausted)
+	}
+}
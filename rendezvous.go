@@ -0,0 +1,93 @@
+package kuid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RendezvousNode is a node in a Rendezvous set. Weight sets its share of
+// KUIDs relative to the other nodes.
+type RendezvousNode struct {
+	Name   string
+	Weight float64
+}
+
+// Rendezvous assigns KUIDs to nodes by weighted rendezvous (highest random
+// weight) hashing. Every KUID scores every node and the highest score
+// wins, so adding or removing a node only moves the KUIDs it gains or
+// loses, with no ring or virtual nodes to tune. Lookups cost one hash per
+// node, which suits sets of up to a few hundred nodes.
+//
+// Scores follow weighted rendezvous hashing: -Weight / ln(u), where u in
+// (0, 1) comes from the SplitMix64 finalizer of the KUID's halves and a
+// seed taken from the first 8 bytes of SHA-256(Name).
+type Rendezvous struct {
+	nodes []RendezvousNode
+	seeds []uint64
+}
+
+// NewRendezvous creates a Rendezvous over nodes. Names must be unique and
+// weights positive.
+func NewRendezvous(nodes ...RendezvousNode) (*Rendezvous, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("rendezvous needs at least one node")
+	}
+	r := &Rendezvous{nodes: append([]RendezvousNode(nil), nodes...), seeds: make([]uint64, len(nodes))}
+	names := make(map[string]bool, len(nodes))
+	for i, n := range nodes {
+		if n.Name == "" || names[n.Name] {
+			return nil, fmt.Errorf("invalid or duplicate node name %q", n.Name)
+		}
+		if !(n.Weight > 0) || math.IsInf(n.Weight, 1) {
+			return nil, fmt.Errorf("node %q: weight must be positive and finite", n.Name)
+		}
+		names[n.Name] = true
+		sum := sha256.Sum256([]byte(n.Name))
+		r.seeds[i] = binary.BigEndian.Uint64(sum[:8])
+	}
+	return r, nil
+}
+
+// Owner returns the name of the node owning k
+func (r *Rendezvous) Owner(k KUID) string {
+	best, bestScore := 0, math.Inf(-1)
+	for i := range r.nodes {
+		if s := r.score(i, k); s > bestScore {
+			best, bestScore = i, s
+		}
+	}
+	return r.nodes[best].Name
+}
+
+// Owners returns the n nodes with the highest scores for k, best first,
+// for placing replicas. When the owner fails, the next node takes over.
+func (r *Rendezvous) Owners(k KUID, n int) []string {
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	if n < 1 {
+		return nil
+	}
+	idx := make([]int, len(r.nodes))
+	scores := make([]float64, len(r.nodes))
+	for i := range r.nodes {
+		idx[i] = i
+		scores[i] = r.score(i, k)
+	}
+	sort.Slice(idx, func(a, b int) bool { return scores[idx[a]] > scores[idx[b]] })
+	owners := make([]string, n)
+	for i := range owners {
+		owners[i] = r.nodes[idx[i]].Name
+	}
+	return owners
+}
+
+func (r *Rendezvous) score(i int, k KUID) float64 {
+	h := mix64(mix64(k.msb^r.seeds[i]) ^ k.lsb)
+	u := (float64(h>>11) + 0.5) / (1 << 53)
+	return -r.nodes[i].Weight / math.Log(u)
+}
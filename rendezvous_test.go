@@ -0,0 +1,86 @@
+package kuid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRendezvousDistribution(t *testing.T) {
+	r, err := NewRendezvous(
+		RendezvousNode{"a", 1},
+		RendezvousNode{"b", 1},
+		RendezvousNode{"c", 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 20000
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		k, _ := NewValue()
+		owner := r.Owner(k)
+		if r.Owner(k) != owner {
+			t.Fatal("Owner() is not stable")
+		}
+		counts[owner]++
+	}
+	want := map[string]float64{"a": 0.25, "b": 0.25, "c": 0.5}
+	for name, share := range want {
+		if got := float64(counts[name]) / n; math.Abs(got-share) > 0.02 {
+			t.Errorf("node %s owns %.3f of KUIDs, want %.2f", name, got, share)
+		}
+	}
+}
+
+func TestRendezvousMinimalDisruption(t *testing.T) {
+	before, _ := NewRendezvous(RendezvousNode{"a", 1}, RendezvousNode{"b", 1}, RendezvousNode{"c", 1}, RendezvousNode{"d", 1})
+	after, _ := NewRendezvous(RendezvousNode{"a", 1}, RendezvousNode{"b", 1}, RendezvousNode{"d", 1})
+	for i := 0; i < 5000; i++ {
+		k, _ := NewValue()
+		was, is := before.Owner(k), after.Owner(k)
+		if was != "c" && was != is {
+			t.Fatalf("%v moved from %s to %s when c was removed", k, was, is)
+		}
+		if was == "c" && is != before.Owners(k, 2)[1] {
+			t.Fatalf("%v did not fail over to its second choice", k)
+		}
+	}
+}
+
+func TestRendezvousOwners(t *testing.T) {
+	r, _ := NewRendezvous(RendezvousNode{"a", 1}, RendezvousNode{"b", 3}, RendezvousNode{"c", 1})
+	k, _ := NewValue()
+	owners := r.Owners(k, 5)
+	if len(owners) != 3 || owners[0] != r.Owner(k) {
+		t.Errorf("Owners() = %v, owner %s", owners, r.Owner(k))
+	}
+	seen := map[string]bool{}
+	for _, o := range owners {
+		seen[o] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Owners() repeats nodes: %v", owners)
+	}
+	if got := r.Owners(k, 0); got != nil {
+		t.Errorf("Owners(0) = %v", got)
+	}
+}
+
+func TestNewRendezvousInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []RendezvousNode
+	}{
+		{"empty", nil},
+		{"no name", []RendezvousNode{{"", 1}}},
+		{"duplicate", []RendezvousNode{{"a", 1}, {"a", 1}}},
+		{"zero weight", []RendezvousNode{{"a", 0}}},
+		{"NaN weight", []RendezvousNode{{"a", math.NaN()}}},
+		{"infinite weight", []RendezvousNode{{"a", math.Inf(1)}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewRendezvous(tt.nodes...); err == nil {
+			t.Errorf("%s: NewRendezvous() succeeded", tt.name)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"net/http"
+)
+
+// RequestIDHeader is the HTTP header carrying request IDs. gRPC metadata
+// uses the lowercase form of the same name.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware attaches a request ID to every request's context. The
+// ID is taken from the X-Request-ID header when it holds a valid KUID or UUID
+// and generated otherwise, and is echoed in the response header so clients
+// and downstream services can correlate logs.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := Parse(r.Header.Get(RequestIDHeader))
+		if err != nil {
+			if id, err = NewKUID(); err != nil {
+				http.Error(w, "failed to generate request ID", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(RequestIDHeader, id.String())
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
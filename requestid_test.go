@@ -0,0 +1,50 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen *KUID
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = RequestIDFromContext(r.Context())
+	}))
+
+	existing, _ := NewKUID()
+	tests := []struct {
+		name   string
+		header string
+		want   *KUID
+	}{
+		{name: "Missing header"},
+		{name: "Invalid header", header: "not-an-id"},
+		{name: "KUID header", header: existing.String(), want: existing},
+		{name: "UUID header", header: existing.ToUUID(), want: existing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if seen == nil {
+				t.Fatalf("No request ID in handler context")
+			}
+			if tt.want != nil && !seen.Equal(tt.want) {
+				t.Errorf("Request ID = %v, want %v", seen, tt.want)
+			}
+			if got := rec.Header().Get(RequestIDHeader); got != seen.String() {
+				t.Errorf("Response header = %v, want %v", got, seen.String())
+			}
+		})
+	}
+}
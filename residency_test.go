@@ -0,0 +1,100 @@
+package kuid
+
+import "testing"
+
+func TestRegionTenant(t *testing.T) {
+	types, _ := NewTypeRegistry(4)
+	types.Register("order", 9)
+
+	// Tenant given before region: placement must not depend on order
+	g, err := NewGenerator(WithTenant(20, 123456), WithRegion(6, 42), WithType(types, "order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 60; i++ {
+		var k *KUID
+		switch i % 3 {
+		case 0:
+			k, _ = g.New()
+		case 1:
+			k, _ = g.NewOrdered()
+		case 2:
+			// The variant bits must not clobber the region
+			k, _ = g.NewV4()
+			if !k.IsRFC9562() || k.Version() != 4 {
+				t.Fatalf("NewV4() = %s, not a version 4 UUID", k.ToUUID())
+			}
+		}
+		if region, ok := g.Region(k); !ok || region != 42 {
+			t.Fatalf("Region() = %d, %v, want 42", region, ok)
+		}
+		if tenant, ok := g.Tenant(k); !ok || tenant != 123456 {
+			t.Fatalf("Tenant() = %d, %v, want 123456", tenant, ok)
+		}
+		if typ, _ := types.TypeOf(k); typ != "order" {
+			t.Fatalf("TypeOf() = %q, want order", typ)
+		}
+		if k.lsb>>56&0x3f != 42 {
+			t.Fatalf("Region not below the variant bits of the lower half: %064b", k.lsb)
+		}
+	}
+
+	plain, _ := NewGenerator()
+	k, _ := plain.New()
+	if _, ok := plain.Region(k); ok {
+		t.Errorf("Region() reported for Generator without region")
+	}
+}
+
+func TestRegionTenantV4(t *testing.T) {
+	tests := []struct {
+		regionBits, tenantBits int
+		region, tenant         uint64
+	}{
+		{4, 0, 4, 0},
+		{1, 1, 1, 1},
+		{16, 16, 0xffff, 0xffff},
+		{2, 30, 3, 1<<30 - 1},
+	}
+	for _, tt := range tests {
+		opts := []Option{WithRegion(tt.regionBits, tt.region)}
+		if tt.tenantBits > 0 {
+			opts = append(opts, WithTenant(tt.tenantBits, tt.tenant))
+		}
+		g, err := NewGenerator(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, _ := g.NewV4()
+		if region, _ := g.Region(k); region != tt.region {
+			t.Errorf("%d-bit region %d: Region() = %d after NewV4", tt.regionBits, tt.region, region)
+		}
+		if tenant, ok := g.Tenant(k); ok && tenant != tt.tenant {
+			t.Errorf("%d-bit tenant %d: Tenant() = %d after NewV4", tt.tenantBits, tt.tenant, tenant)
+		}
+	}
+}
+
+func TestRegionTenantErrors(t *testing.T) {
+	types, _ := NewTypeRegistry(16)
+	types.Register("user", 1)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"Region too wide", []Option{WithRegion(17, 1)}},
+		{"Region overflows", []Option{WithRegion(4, 16)}},
+		{"Tenant overflows", []Option{WithTenant(8, 256)}},
+		{"Too many bits", []Option{WithRegion(8, 1), WithTenant(16, 1), WithType(types, "user")}},
+		{"Tenant uses whole budget", []Option{WithTenant(32, 1), WithRegion(1, 1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGenerator(tt.opts...); err == nil {
+				t.Errorf("NewGenerator() succeeded")
+			}
+		})
+	}
+}
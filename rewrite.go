@@ -0,0 +1,105 @@
+package kuid
+
+import "io"
+
+// maxRewriteBuffer bounds the text a Rewriter holds back waiting for the
+// end of a token
+const maxRewriteBuffer = 64 << 10
+
+// RewritePolicy returns the replacement for a KUID found by a Rewriter
+type RewritePolicy func(IDMatch) string
+
+// RedactPolicy replaces KUIDs with their Redacted form, keeping any prefix
+func RedactPolicy() RewritePolicy {
+	return func(m IDMatch) string {
+		return withPrefix(m, m.ID.Redacted())
+	}
+}
+
+// PseudonymizePolicy replaces KUIDs with their pseudonym under p, in the
+// same form, so logs stay joinable on ID without exposing the real one
+func PseudonymizePolicy(p *Pseudonymizer) RewritePolicy {
+	return func(m IDMatch) string {
+		pseudonym := p.Pseudonymize(m.ID.String())
+		if m.Form == FormUUID {
+			return pseudonym.ToUUID()
+		}
+		return withPrefix(m, pseudonym.String())
+	}
+}
+
+// NormalizePolicy rewrites UUIDs as canonical base62 KUIDs, so one ID
+// reads the same everywhere in the logs
+func NormalizePolicy() RewritePolicy {
+	return func(m IDMatch) string {
+		return withPrefix(m, m.ID.String())
+	}
+}
+
+// withPrefix restores the prefix of a prefixed match
+func withPrefix(m IDMatch, s string) string {
+	if m.Form == FormPrefixed {
+		return m.Prefix + prefixSeparator + s
+	}
+	return s
+}
+
+// Rewriter is an io.Writer that replaces KUIDs and UUIDs in the text
+// passing through it, for sanitizing logs before they reach third-party
+// aggregators. IDs split across writes are still found: text after the last
+// byte that cannot be part of an ID is held back until more arrives, up to
+// 64 KiB, or until Flush.
+type Rewriter struct {
+	w       io.Writer
+	policy  RewritePolicy
+	matcher *Matcher
+	buf     []byte
+}
+
+// NewRewriter returns a Rewriter writing to w
+func NewRewriter(w io.Writer, policy RewritePolicy) *Rewriter {
+	return &Rewriter{w: w, policy: policy, matcher: NewMatcher()}
+}
+
+// Write implements io.Writer
+func (r *Rewriter) Write(p []byte) (int, error) {
+	r.buf = append(r.buf, p...)
+	cut := 0
+	for i := len(r.buf) - 1; i >= 0; i-- {
+		if c := rune(r.buf[i]); !isScanRune(c) && c != '_' {
+			cut = i + 1
+			break
+		}
+	}
+	if cut == 0 && len(r.buf) > maxRewriteBuffer {
+		cut = len(r.buf) // no boundary in sight: not an ID
+	}
+	if cut == 0 {
+		return len(p), nil
+	}
+	if err := r.emit(r.buf[:cut]); err != nil {
+		return 0, err
+	}
+	r.buf = append(r.buf[:0], r.buf[cut:]...)
+	return len(p), nil
+}
+
+// Flush rewrites and writes any text held back
+func (r *Rewriter) Flush() error {
+	if len(r.buf) == 0 {
+		return nil
+	}
+	err := r.emit(r.buf)
+	r.buf = r.buf[:0]
+	return err
+}
+
+// Close flushes the Rewriter. It does not close the underlying writer.
+func (r *Rewriter) Close() error {
+	return r.Flush()
+}
+
+func (r *Rewriter) emit(text []byte) error {
+	_, err := io.WriteString(r.w, r.matcher.ReplaceAll(string(text), r.policy))
+	return err
+}
@@ -0,0 +1,78 @@
+package kuid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriter(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	p, _ := NewPseudonymizer([]byte("0123456789abcdef"), nil)
+	line := "héllo user_" + a.String() + " trace " + b.ToUUID() + "\n"
+
+	tests := []struct {
+		name   string
+		policy RewritePolicy
+		want   string
+	}{
+		{
+			name:   "Redact",
+			policy: RedactPolicy(),
+			want:   "héllo user_" + a.Redacted() + " trace " + b.Redacted() + "\n",
+		},
+		{
+			name:   "Pseudonymize",
+			policy: PseudonymizePolicy(p),
+			want:   "héllo user_" + p.Pseudonymize(a.String()).String() + " trace " + p.Pseudonymize(b.String()).ToUUID() + "\n",
+		},
+		{
+			name:   "Normalize",
+			policy: NormalizePolicy(),
+			want:   "héllo user_" + a.String() + " trace " + b.String() + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Write byte by byte so every ID straddles writes
+			var out strings.Builder
+			r := NewRewriter(&out, tt.policy)
+			for i := 0; i < len(line); i++ {
+				if n, err := r.Write([]byte{line[i]}); n != 1 || err != nil {
+					t.Fatalf("Write() = %d, %v", n, err)
+				}
+			}
+			if err := r.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriterHoldsBack(t *testing.T) {
+	a, _ := NewKUID()
+	var out strings.Builder
+	r := NewRewriter(&out, RedactPolicy())
+
+	r.Write([]byte("id=" + a.String()[:10]))
+	if out.String() != "id=" {
+		t.Errorf("output before the ID ends = %q, want %q", out.String(), "id=")
+	}
+	r.Write([]byte(a.String()[10:]))
+	r.Flush()
+	if want := "id=" + a.Redacted(); out.String() != want {
+		t.Errorf("output after Flush = %q, want %q", out.String(), want)
+	}
+
+	// A run with no boundary is passed through once it exceeds the limit
+	out.Reset()
+	long := strings.Repeat("x", maxRewriteBuffer+1)
+	r.Write([]byte(long))
+	if out.Len() != len(long) {
+		t.Errorf("held back %d bytes of an unbounded run", len(long)-out.Len())
+	}
+}
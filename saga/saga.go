@@ -0,0 +1,176 @@
+// Package saga derives correlation IDs for the steps of multi-step
+// distributed workflows.
+//
+// A saga starts from a root KUID. Each step is named by a label, and its
+// KUID is derived from its parent's with kuid.DeriveChild, so every hop
+// computes the same step IDs without coordination and any step ID can be
+// verified against its parent. Steps render as breadcrumbs:
+//
+//	6f1Kx0aN2bXwGmTqYc0LzR/reserve/charge
+//
+// A breadcrumb in any hop's logs therefore leads to the saga root, whose
+// KUID finds every other hop, and to the path taken to reach the step.
+package saga
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/ctxkuid"
+)
+
+var (
+	ErrInvalidLabel      = errors.New("invalid saga step label")
+	ErrInvalidBreadcrumb = errors.New("invalid saga breadcrumb")
+)
+
+const (
+	sep = "/"
+
+	// MaxLabel is the longest step label
+	MaxLabel = 64
+	// MaxDepth is the deepest a step may be nested below the root
+	MaxDepth = 32
+)
+
+// Step is a position in a saga: the root KUID and the labels of the steps
+// leading from it. A Step with no labels is the saga itself.
+type Step struct {
+	Root   kuid.KUID
+	Labels []string
+}
+
+// Start returns the root step of a saga with a new random KUID
+func Start() (Step, error) {
+	root, err := kuid.NewValue()
+	if err != nil {
+		return Step{}, err
+	}
+	return Step{Root: root}, nil
+}
+
+// Child returns the step labelled label below s. Labels may contain ASCII
+// letters, digits and "_-.:", such as "charge" or "ship:2".
+func (s Step) Child(label string) (Step, error) {
+	if !validLabel(label) {
+		return Step{}, ErrInvalidLabel
+	}
+	if len(s.Labels) >= MaxDepth {
+		return Step{}, errors.New("saga steps nested too deeply")
+	}
+	labels := make([]string, len(s.Labels)+1)
+	copy(labels, s.Labels)
+	labels[len(s.Labels)] = label
+	return Step{Root: s.Root, Labels: labels}, nil
+}
+
+// Parent returns the step s belongs to, unless s is the root
+func (s Step) Parent() (Step, bool) {
+	if len(s.Labels) == 0 {
+		return Step{}, false
+	}
+	return Step{Root: s.Root, Labels: s.Labels[:len(s.Labels)-1]}, true
+}
+
+// Lineage returns the steps from the root down to s
+func (s Step) Lineage() []Step {
+	steps := make([]Step, len(s.Labels)+1)
+	for i := range steps {
+		steps[i] = Step{Root: s.Root, Labels: s.Labels[:i]}
+	}
+	return steps
+}
+
+// ID returns the step's KUID: the root for the saga itself, and otherwise
+// the child of the parent step's KUID at the label's index
+func (s Step) ID() kuid.KUID {
+	id := s.Root
+	for _, label := range s.Labels {
+		id = kuid.DeriveChild(id, LabelIndex(label))
+	}
+	return id
+}
+
+// LabelIndex returns the kuid.DeriveChild index for a step label, so
+// kuid.VerifyLineage(parent, child) confirms a step ID and
+// kuid.ChildIndex(child) == LabelIndex(label) confirms its label
+func LabelIndex(label string) uint32 {
+	sum := sha256.Sum256([]byte(label))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+// String returns the breadcrumb: the root KUID followed by the labels,
+// separated by "/"
+func (s Step) String() string {
+	if len(s.Labels) == 0 {
+		return s.Root.String()
+	}
+	return s.Root.String() + sep + strings.Join(s.Labels, sep)
+}
+
+// Parse parses a breadcrumb produced by Step.String
+func Parse(breadcrumb string) (Step, error) {
+	parts := strings.Split(breadcrumb, sep)
+	root, err := kuid.ParseValue(parts[0])
+	if err != nil || len(parts)-1 > MaxDepth {
+		return Step{}, ErrInvalidBreadcrumb
+	}
+	s := Step{Root: root}
+	if len(parts) > 1 {
+		s.Labels = parts[1:]
+		for _, label := range s.Labels {
+			if !validLabel(label) {
+				return Step{}, ErrInvalidBreadcrumb
+			}
+		}
+	}
+	return s, nil
+}
+
+// MarshalText implements encoding.TextMarshaler with the breadcrumb
+func (s Step) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (s *Step) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+func validLabel(label string) bool {
+	if label == "" || len(label) > MaxLabel {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("_-.:", c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+type stepKey struct{}
+
+// NewContext returns a copy of ctx carrying step, with the step's KUID as
+// the context's correlation ID for ctxkuid
+func NewContext(ctx context.Context, step Step) context.Context {
+	id := step.ID()
+	ctx = context.WithValue(ctx, stepKey{}, step)
+	return ctxkuid.WithValue(ctx, &id)
+}
+
+// FromContext returns the step stored in ctx by NewContext
+func FromContext(ctx context.Context) (Step, bool) {
+	s, ok := ctx.Value(stepKey{}).(Step)
+	return s, ok
+}
@@ -0,0 +1,131 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+	"github.com/alphabatem/kuid/ctxkuid"
+)
+
+func TestStepIDs(t *testing.T) {
+	root, err := Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reserve, _ := root.Child("reserve")
+	charge, _ := reserve.Child("charge")
+
+	if root.ID() != root.Root {
+		t.Error("root step ID is not the saga root")
+	}
+	if !kuid.VerifyLineage(reserve.ID(), charge.ID()) || !kuid.VerifyLineage(root.ID(), reserve.ID()) {
+		t.Error("step IDs do not verify against their parents")
+	}
+	if kuid.ChildIndex(charge.ID()) != LabelIndex("charge") {
+		t.Error("ChildIndex does not match the label")
+	}
+
+	// Every hop derives the same IDs
+	again, _ := (Step{Root: root.Root}).Child("reserve")
+	again, _ = again.Child("charge")
+	if again.ID() != charge.ID() {
+		t.Error("step ID is not deterministic")
+	}
+	other, _ := reserve.Child("refund")
+	if other.ID() == charge.ID() {
+		t.Error("sibling steps share an ID")
+	}
+
+	parent, ok := charge.Parent()
+	if !ok || parent.ID() != reserve.ID() {
+		t.Errorf("Parent() = %v, %v", parent, ok)
+	}
+	if _, ok := root.Parent(); ok {
+		t.Error("root has a parent")
+	}
+	lineage := charge.Lineage()
+	if len(lineage) != 3 || lineage[0].ID() != root.ID() || lineage[2].ID() != charge.ID() {
+		t.Errorf("Lineage() = %v", lineage)
+	}
+}
+
+func TestChildDoesNotAlias(t *testing.T) {
+	root, _ := Start()
+	a, _ := root.Child("a")
+	ab, _ := a.Child("b")
+	parent, _ := ab.Parent()
+	ac, _ := parent.Child("c")
+	if ab.String() != root.String()+"/a/b" || ac.String() != root.String()+"/a/c" {
+		t.Errorf("children alias each other: %v, %v", ab, ac)
+	}
+}
+
+func TestBreadcrumb(t *testing.T) {
+	root, _ := Start()
+	step, _ := root.Child("ship:2")
+	step, _ = step.Child("notify.email")
+
+	s := step.String()
+	if s != root.Root.String()+"/ship:2/notify.email" {
+		t.Errorf("String() = %q", s)
+	}
+	parsed, err := Parse(s)
+	if err != nil || parsed.ID() != step.ID() {
+		t.Errorf("Parse(%q) = %v, %v", s, parsed, err)
+	}
+	if parsed, err := Parse(root.String()); err != nil || parsed.Labels != nil || parsed.Root != root.Root {
+		t.Errorf("Parse(root) = %v, %v", parsed, err)
+	}
+
+	data, _ := json.Marshal(step)
+	var decoded Step
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.String() != s {
+		t.Errorf("JSON round trip = %v, %v", decoded, err)
+	}
+
+	deep := root.Root.String() + strings.Repeat("/x", MaxDepth+1)
+	for _, bad := range []string{"", "bogus/a", root.Root.String() + "/", root.Root.String() + "//a",
+		root.Root.String() + "/a b", deep} {
+		if _, err := Parse(bad); !errors.Is(err, ErrInvalidBreadcrumb) {
+			t.Errorf("Parse(%q) error = %v", bad, err)
+		}
+	}
+}
+
+func TestInvalidChild(t *testing.T) {
+	root, _ := Start()
+	for _, label := range []string{"", "a/b", "é", strings.Repeat("x", MaxLabel+1)} {
+		if _, err := root.Child(label); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("Child(%q) error = %v", label, err)
+		}
+	}
+	step := root
+	for i := 0; i < MaxDepth; i++ {
+		step, _ = step.Child("x")
+	}
+	if _, err := step.Child("x"); err == nil {
+		t.Error("Child() exceeded MaxDepth")
+	}
+}
+
+func TestContext(t *testing.T) {
+	root, _ := Start()
+	step, _ := root.Child("charge")
+	ctx := NewContext(context.Background(), step)
+
+	got, ok := FromContext(ctx)
+	if !ok || got.String() != step.String() {
+		t.Errorf("FromContext() = %v, %v", got, ok)
+	}
+	id, ok := ctxkuid.FromContext(ctx)
+	if want := step.ID(); !ok || *id != want {
+		t.Errorf("ctxkuid.FromContext() = %v, want %v", id, want)
+	}
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext(empty) ok")
+	}
+}
@@ -0,0 +1,61 @@
+package kuid
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxScanLength is the length of the longest accepted form, a UUID
+const maxScanLength = 36
+
+// Set implements flag.Value, so command-line tools can declare KUID flags
+// with flag.Var. It accepts the base62 and UUID forms, like Parse.
+func (k *KUID) Set(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*k = *parsed
+	return nil
+}
+
+// Scan implements fmt.Scanner for the %v and %s verbs, so fmt.Sscan and
+// fmt.Sscanf can read KUIDs embedded in text such as "id=%v,". It reads
+// the longest run of base62 characters and hyphens, at most 36 or the scan
+// width, and parses it like Parse.
+func (k *KUID) Scan(state fmt.ScanState, verb rune) error {
+	if verb != 'v' && verb != 's' {
+		return fmt.Errorf("kuid: unsupported scan verb %%%c", verb)
+	}
+	state.SkipSpace()
+
+	limit := maxScanLength
+	if w, ok := state.Width(); ok && w < limit {
+		limit = w
+	}
+	buf := make([]byte, 0, limit)
+	for len(buf) < limit {
+		r, _, err := state.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !isScanRune(r) {
+			state.UnreadRune()
+			break
+		}
+		buf = append(buf, byte(r))
+	}
+	if len(buf) == 0 {
+		return errors.New("kuid: expected KUID")
+	}
+	return k.Set(string(buf))
+}
+
+// isScanRune reports whether r can appear in a KUID or UUID
+func isScanRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r == '-'
+}
@@ -0,0 +1,65 @@
+package kuid
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestFlagValue(t *testing.T) {
+	want, _ := NewKUID()
+	var id KUID
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&id, "id", "record ID")
+
+	if err := fs.Parse([]string{"-id", want.ToUUID()}); err != nil {
+		t.Fatal(err)
+	}
+	if !id.Equal(want) {
+		t.Errorf("-id = %v, want %v", id, want)
+	}
+
+	var perr *ParseError
+	if err := fs.Parse([]string{"-id", "nope"}); err == nil || !errors.As(id.Set("nope"), &perr) {
+		t.Errorf("Parse() error = %v, want a ParseError", err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+
+	var x, y KUID
+	var level string
+	n, err := fmt.Sscanf(fmt.Sprintf("level=%s id=%s,parent=%s", "info", a, b.ToUUID()), "level=%s id=%v,parent=%v", &level, &x, &y)
+	if err != nil || n != 3 {
+		t.Fatalf("Sscanf() = %d, %v", n, err)
+	}
+	if !x.Equal(a) || !y.Equal(b) {
+		t.Errorf("Sscanf() = %v, %v, want %v, %v", x, y, a, b)
+	}
+
+	if _, err := fmt.Sscan("  "+a.String()+"\n", &x); err != nil || !x.Equal(a) {
+		t.Errorf("Sscan() = %v, %v, want %v", x, err, a)
+	}
+
+	// The width limits the read, leaving the rest for the next verb
+	var rest string
+	if _, err := fmt.Sscanf(a.String()+"tail", "%22v%s", &x, &rest); err != nil || !x.Equal(a) || rest != "tail" {
+		t.Errorf("Sscanf() with width = %v, %q, %v", x, rest, err)
+	}
+
+	for _, tt := range []struct{ input, format string }{
+		{"", "%v"},
+		{"!!!", "%v"},
+		{a.String()[:21], "%v"},
+		{a.String(), "%d"},
+	} {
+		if _, err := fmt.Sscanf(tt.input, tt.format, &x); err == nil {
+			t.Errorf("Sscanf(%q, %q) expected error", tt.input, tt.format)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package kuid
+
+// Pattern is a regular expression matching exactly the strings FromString
+// accepts: two 11-character base62 halves, each at most "LygHa16AHYF", the
+// encoding of 2^64-1
+const Pattern = `^` + halfPattern + halfPattern + `$`
+
+// halfPattern matches base62 strings up to "LygHa16AHYF". Base62 digits
+// sort in ASCII order, so each alternative takes a prefix of the bound
+// followed by a smaller digit and then anything.
+const halfPattern = `(?:[0-9A-K][0-9A-Za-z]{10}|L(?:[0-9A-Za-x][0-9A-Za-z]{9}|y(?:[0-9A-Za-f][0-9A-Za-z]{8}|g(?:[0-9A-G][0-9A-Za-z]{7}|H(?:[0-9A-Z][0-9A-Za-z]{6}|a(?:0[0-9A-Za-z]{5}|1(?:[0-5][0-9A-Za-z]{4}|6(?:[0-9][0-9A-Za-z]{3}|A(?:[0-9A-G][0-9A-Za-z]{2}|H(?:[0-9A-X][0-9A-Za-z]|Y[0-9A-F]))))))))))`
+
+// SwaggoOverride is a swaggo/swag override rule documenting KUID fields as
+// strings. Add it to the .swaggo file passed to swag init --overridesFile.
+const SwaggoOverride = "replace github.com/alphabatem/kuid.KUID string"
+
+const (
+	schemaDescription = "Compressed UUID: 128 bits encoded as 22 base62 characters"
+	schemaExample     = "6VZ1pUmrfLV4CfPbdQ8ZFm"
+)
+
+// JSONSchema returns the JSON Schema (draft 2020-12, also valid as an
+// OpenAPI 3.1 schema object) describing KUID strings. The map is freshly
+// allocated and may be modified by the caller.
+func JSONSchema() map[string]any {
+	return map[string]any{
+		"type":        "string",
+		"format":      "kuid",
+		"pattern":     Pattern,
+		"minLength":   size * 2,
+		"maxLength":   size * 2,
+		"description": schemaDescription,
+		"examples":    []any{schemaExample},
+	}
+}
+
+// OpenAPISchema returns the OpenAPI 3.0 schema object describing KUID
+// strings. It differs from JSONSchema only in using the singular "example"
+// keyword, which is all OpenAPI 3.0 supports.
+func OpenAPISchema() map[string]any {
+	schema := JSONSchema()
+	delete(schema, "examples")
+	schema["example"] = schemaExample
+	return schema
+}
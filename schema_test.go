@@ -0,0 +1,88 @@
+package kuid
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPattern(t *testing.T) {
+	re := regexp.MustCompile(Pattern)
+
+	for i := 0; i < 1000; i++ {
+		kuid, _ := NewKUID()
+		if !re.MatchString(kuid.String()) {
+			t.Fatalf("Pattern does not match %v", kuid.String())
+		}
+	}
+	max, _ := FromUUID("ffffffff-ffff-ffff-ffff-ffffffffffff")
+	if !re.MatchString(max.String()) {
+		t.Errorf("Pattern does not match max KUID %v", max.String())
+	}
+
+	for _, s := range []string{"", "ABC", strings.Repeat("z", size*2), strings.Repeat("!", size*2)} {
+		if re.MatchString(s) {
+			t.Errorf("Pattern matches invalid string %q", s)
+		}
+	}
+}
+
+func TestPatternAgreesWithFromString(t *testing.T) {
+	re := regexp.MustCompile(Pattern)
+	const bound = "LygHa16AHYF"
+
+	// Strings on both sides of the bound in every position of either half
+	var inputs []string
+	for i := 0; i < size; i++ {
+		for _, d := range []int{-1, 0, 1} {
+			j := strings.IndexByte(base62Chars, bound[i]) + d
+			if j < 0 || j >= len(base62Chars) {
+				continue
+			}
+			for _, rest := range []byte{'0', 'z'} {
+				half := bound[:i] + string(base62Chars[j]) + strings.Repeat(string(rest), size-i-1)
+				inputs = append(inputs, half+bound, bound+half)
+			}
+		}
+	}
+	// Random base62 strings, most of which overflow a half
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		b := make([]byte, size*2)
+		for j := range b {
+			b[j] = base62Chars[r.Intn(len(base62Chars))]
+		}
+		inputs = append(inputs, string(b))
+	}
+
+	for _, s := range inputs {
+		_, err := FromString(s)
+		if matched := re.MatchString(s); matched != (err == nil) {
+			t.Errorf("%q: Pattern matches = %v, FromString error = %v", s, matched, err)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema()
+	if schema["type"] != "string" || schema["pattern"] != Pattern {
+		t.Errorf("JSONSchema() = %v", schema)
+	}
+
+	example := schema["examples"].([]any)[0].(string)
+	if _, err := FromString(example); err != nil {
+		t.Errorf("Schema example %v is not a valid KUID: %v", example, err)
+	}
+	if !regexp.MustCompile(Pattern).MatchString(example) {
+		t.Errorf("Schema example %v does not match the pattern", example)
+	}
+
+	openapi := OpenAPISchema()
+	if _, ok := openapi["examples"]; ok || openapi["example"] != example {
+		t.Errorf("OpenAPISchema() = %v", openapi)
+	}
+	if _, ok := JSONSchema()["examples"]; !ok {
+		t.Errorf("OpenAPISchema() modified the shared schema")
+	}
+}
@@ -0,0 +1,64 @@
+package kuid
+
+import "errors"
+
+// SequenceOptions configures FromSequence and ToSequence
+type SequenceOptions struct {
+	// Bits is the number of most significant bits holding the sequence,
+	// between 1 and 64. Zero means 64.
+	Bits int
+	// Generator supplies the random bits; the default crypto/rand
+	// Generator is used when nil.
+	Generator *Generator
+}
+
+func (o SequenceOptions) bits() (int, error) {
+	switch {
+	case o.Bits == 0:
+		return 64, nil
+	case o.Bits < 1 || o.Bits > 64:
+		return 0, errors.New("sequence bits must be between 1 and 64")
+	}
+	return o.Bits, nil
+}
+
+// FromSequence packs a monotonically increasing sequence (such as a database
+// bigserial) into the most significant bits of a KUID and fills the rest
+// with random bits. KUIDs built from increasing sequences sort in sequence
+// order, keeping index inserts local, while the random tail keeps them
+// unique across systems that share sequence values.
+func FromSequence(seq uint64, opts SequenceOptions) (*KUID, error) {
+	bits, err := opts.bits()
+	if err != nil {
+		return nil, err
+	}
+	if bits < 64 && seq>>bits != 0 {
+		return nil, ErrOverflow
+	}
+
+	gen := opts.Generator
+	if gen == nil {
+		gen = defaultGenerator
+	}
+	msb, lsb, err := gen.read()
+	if err != nil {
+		return nil, err
+	}
+
+	if bits == 64 {
+		msb = seq
+	} else {
+		msb = seq<<(64-bits) | msb>>bits
+	}
+	return &KUID{msb: msb, lsb: lsb}, nil
+}
+
+// ToSequence extracts the sequence packed by FromSequence with the same
+// options
+func (k *KUID) ToSequence(opts SequenceOptions) (uint64, error) {
+	bits, err := opts.bits()
+	if err != nil {
+		return 0, err
+	}
+	return k.msb >> (64 - bits), nil
+}
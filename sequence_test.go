@@ -0,0 +1,64 @@
+package kuid
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestFromSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		seq  uint64
+		bits int
+	}{
+		{name: "Full width", seq: 123456789, bits: 0},
+		{name: "Max full width", seq: math.MaxUint64, bits: 64},
+		{name: "40 bits", seq: 1<<40 - 1, bits: 40},
+		{name: "Single bit", seq: 1, bits: 1},
+		{name: "Zero", seq: 0, bits: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := SequenceOptions{Bits: tt.bits}
+			kuid, err := FromSequence(tt.seq, opts)
+			if err != nil {
+				t.Fatalf("FromSequence() error = %v", err)
+			}
+			got, err := kuid.ToSequence(opts)
+			if err != nil {
+				t.Fatalf("ToSequence() error = %v", err)
+			}
+			if got != tt.seq {
+				t.Errorf("ToSequence() = %v, want %v", got, tt.seq)
+			}
+		})
+	}
+}
+
+func TestFromSequenceOrdering(t *testing.T) {
+	opts := SequenceOptions{Bits: 48}
+	var strs []string
+	for seq := uint64(0); seq < 1000; seq++ {
+		kuid, err := FromSequence(seq, opts)
+		if err != nil {
+			t.Fatalf("FromSequence() error = %v", err)
+		}
+		strs = append(strs, kuid.String())
+	}
+	if !sort.StringsAreSorted(strs) {
+		t.Errorf("KUIDs from increasing sequences are not sorted")
+	}
+}
+
+func TestFromSequenceInvalid(t *testing.T) {
+	if _, err := FromSequence(1<<20, SequenceOptions{Bits: 20}); err != ErrOverflow {
+		t.Errorf("FromSequence() error = %v, want %v", err, ErrOverflow)
+	}
+	for _, bits := range []int{-1, 65} {
+		if _, err := FromSequence(1, SequenceOptions{Bits: bits}); err == nil {
+			t.Errorf("FromSequence() expected error for %d bits", bits)
+		}
+	}
+}
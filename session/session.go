@@ -0,0 +1,187 @@
+// Package session mints and rotates KUID session identifiers.
+//
+// Session IDs use the ordered KUID layout with the expiry time in place of
+// the creation time:
+//
+//	48-bit expiry in Unix milliseconds | 80 random bits
+//
+// so an expired ID can be rejected from the ID alone before any storage
+// lookup, and IDs sort by expiry, which makes sweeping expired sessions a
+// range scan. The 80 random bits exceed the 64 bits of entropy recommended
+// for session identifiers.
+package session
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/alphabatem/kuid"
+)
+
+var (
+	ErrExpired  = errors.New("session expired")
+	ErrNotFound = errors.New("session not found")
+)
+
+// Session is a server-side session
+type Session struct {
+	ID         *kuid.KUID
+	PreviousID *kuid.KUID // ID this session was rotated from, if any
+	ExpiresAt  time.Time
+	Values     map[string]string
+}
+
+// Store persists sessions. Implementations decide where sessions live
+// (memory, Redis, SQL); Load must return ErrNotFound for unknown IDs.
+type Store interface {
+	Save(ctx context.Context, s *Session) error
+	Load(ctx context.Context, id *kuid.KUID) (*Session, error)
+	Delete(ctx context.Context, id *kuid.KUID) error
+}
+
+// Manager creates, resolves and rotates sessions
+type Manager struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewManager creates a Manager issuing sessions valid for ttl
+func NewManager(store Store, ttl time.Duration) (*Manager, error) {
+	if store == nil {
+		return nil, errors.New("session store must not be nil")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("session TTL must be positive")
+	}
+	return &Manager{store: store, ttl: ttl, now: time.Now}, nil
+}
+
+// New creates and stores a new session
+func (m *Manager) New(ctx context.Context) (*Session, error) {
+	s, err := m.newSession()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Save(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get resolves a session from its string ID. Malformed and expired IDs are
+// rejected without touching the store.
+func (m *Manager) Get(ctx context.Context, id string) (*Session, error) {
+	k, err := kuid.FromString(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if !m.now().Before(ExpiresAt(k)) {
+		return nil, ErrExpired
+	}
+	return m.store.Load(ctx, k)
+}
+
+// Rotate replaces a session's ID, for example after login or privilege
+// changes, carrying over its values and extending its expiry. The new
+// session records the old ID in PreviousID and the old session is deleted.
+func (m *Manager) Rotate(ctx context.Context, s *Session) (*Session, error) {
+	rotated, err := m.newSession()
+	if err != nil {
+		return nil, err
+	}
+	rotated.PreviousID = s.ID
+	for k, v := range s.Values {
+		rotated.Values[k] = v
+	}
+	if err := m.store.Save(ctx, rotated); err != nil {
+		return nil, err
+	}
+	if err := m.store.Delete(ctx, s.ID); err != nil {
+		return nil, err
+	}
+	return rotated, nil
+}
+
+// Destroy deletes a session
+func (m *Manager) Destroy(ctx context.Context, s *Session) error {
+	return m.store.Delete(ctx, s.ID)
+}
+
+func (m *Manager) newSession() (*Session, error) {
+	id, err := NewID(m.now().Add(m.ttl))
+	if err != nil {
+		return nil, err
+	}
+	return &Session{ID: id, ExpiresAt: ExpiresAt(id), Values: map[string]string{}}, nil
+}
+
+// NewID mints a session ID embedding the given expiry
+func NewID(expires time.Time) (*kuid.KUID, error) {
+	random, err := kuid.NewKUID()
+	if err != nil {
+		return nil, err
+	}
+
+	b := random.Bytes()
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(expires.UnixMilli()))
+	copy(b[0:6], ts[2:8])
+	return kuid.FromBytes(b)
+}
+
+// ExpiresAt returns the expiry embedded in a session ID
+func ExpiresAt(id *kuid.KUID) time.Time {
+	return id.Timestamp()
+}
+
+// MemoryStore is an in-process Store, useful for tests and single-instance
+// services
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]*Session)}
+}
+
+// Save stores a copy of s
+func (m *MemoryStore) Save(_ context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID.String()] = clone(s)
+	return nil
+}
+
+// Load returns a copy of the stored session
+func (m *MemoryStore) Load(_ context.Context, id *kuid.KUID) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.sessions[id.String()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return clone(s), nil
+}
+
+// Delete removes a session
+func (m *MemoryStore) Delete(_ context.Context, id *kuid.KUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id.String())
+	return nil
+}
+
+func clone(s *Session) *Session {
+	c := *s
+	c.Values = make(map[string]string, len(s.Values))
+	for k, v := range s.Values {
+		c.Values[k] = v
+	}
+	return &c
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewManager(NewMemoryStore(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	s, err := m.New(ctx)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if d := time.Until(s.ExpiresAt); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("ExpiresAt = %v, want about an hour from now", s.ExpiresAt)
+	}
+
+	s.Values["user"] = "alice"
+	if err := m.store.Save(ctx, s); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := m.Get(ctx, s.ID.String())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Values["user"] != "alice" {
+		t.Errorf("Get() values = %v, want user=alice", got.Values)
+	}
+
+	if err := m.Destroy(ctx, got); err != nil {
+		t.Fatalf("Destroy() error = %v", err)
+	}
+	if _, err := m.Get(ctx, s.ID.String()); err != ErrNotFound {
+		t.Errorf("Get() after Destroy error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestManagerRotate(t *testing.T) {
+	ctx := context.Background()
+	m, _ := NewManager(NewMemoryStore(), time.Hour)
+
+	old, _ := m.New(ctx)
+	old.Values["user"] = "alice"
+
+	rotated, err := m.Rotate(ctx, old)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotated.ID.Equal(old.ID) {
+		t.Errorf("Rotate() kept the old ID")
+	}
+	if !rotated.PreviousID.Equal(old.ID) {
+		t.Errorf("PreviousID = %v, want %v", rotated.PreviousID, old.ID)
+	}
+	if rotated.Values["user"] != "alice" {
+		t.Errorf("Rotate() values = %v, want user=alice", rotated.Values)
+	}
+
+	if _, err := m.Get(ctx, old.ID.String()); err != ErrNotFound {
+		t.Errorf("Get() of rotated-out session error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := m.Get(ctx, rotated.ID.String()); err != nil {
+		t.Errorf("Get() of rotated session error = %v", err)
+	}
+}
+
+func TestManagerExpired(t *testing.T) {
+	ctx := context.Background()
+	m, _ := NewManager(NewMemoryStore(), time.Minute)
+
+	s, _ := m.New(ctx)
+	m.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+	if _, err := m.Get(ctx, s.ID.String()); err != ErrExpired {
+		t.Errorf("Get() error = %v, want %v", err, ErrExpired)
+	}
+	if _, err := m.Get(ctx, "not-a-session"); err != ErrNotFound {
+		t.Errorf("Get() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	expires := time.Now().Add(24 * time.Hour).Truncate(time.Millisecond)
+	a, err := NewID(expires)
+	if err != nil {
+		t.Fatalf("NewID() error = %v", err)
+	}
+	b, _ := NewID(expires)
+
+	if !ExpiresAt(a).Equal(expires) {
+		t.Errorf("ExpiresAt() = %v, want %v", ExpiresAt(a), expires)
+	}
+	if a.Equal(b) {
+		t.Errorf("NewID() returned duplicate IDs for the same expiry")
+	}
+}
@@ -0,0 +1,87 @@
+package kuid
+
+import (
+	"errors"
+	"sync"
+)
+
+const flightShards = 64
+
+// ErrFlightPanicked is returned to callers sharing a SingleFlight call
+// whose function panicked. The panic itself propagates in the goroutine
+// that ran the function.
+var ErrFlightPanicked = errors.New("singleflight function panicked")
+
+// SingleFlight collapses concurrent calls for the same KUID into one, so a
+// cache-fill storm for an entity runs its loader once. It is keyed on the
+// 16-byte KUID, so lookups do not allocate a string key, and calls are
+// spread over independently locked shards. The zero value is not usable;
+// create one with NewSingleFlight.
+type SingleFlight[T any] struct {
+	shards [flightShards]flightShard[T]
+}
+
+type flightShard[T any] struct {
+	mu    sync.Mutex
+	calls map[KUID]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+	dups int
+}
+
+// NewSingleFlight creates an empty SingleFlight
+func NewSingleFlight[T any]() *SingleFlight[T] {
+	f := &SingleFlight[T]{}
+	for i := range f.shards {
+		f.shards[i].calls = make(map[KUID]*flightCall[T])
+	}
+	return f
+}
+
+// Do runs fn and returns its results, unless a call for k is already in
+// flight, in which case it waits for that call and returns its results.
+// shared reports whether the results went to more than one caller.
+func (f *SingleFlight[T]) Do(k *KUID, fn func() (T, error)) (v T, err error, shared bool) {
+	s := f.shard(k)
+	s.mu.Lock()
+	if c, ok := s.calls[*k]; ok {
+		c.dups++
+		s.mu.Unlock()
+		<-c.done
+		return c.val, c.err, true
+	}
+	c := &flightCall[T]{done: make(chan struct{})}
+	s.calls[*k] = c
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if s.calls[*k] == c {
+			delete(s.calls, *k)
+		}
+		shared = c.dups > 0
+		s.mu.Unlock()
+		close(c.done)
+	}()
+	c.err = ErrFlightPanicked // replaced unless fn panics
+	c.val, c.err = fn()
+	return c.val, c.err, false
+}
+
+// Forget makes the next Do for k run its function instead of joining the
+// call in flight, for example after the data it loads has changed
+func (f *SingleFlight[T]) Forget(k *KUID) {
+	s := f.shard(k)
+	s.mu.Lock()
+	delete(s.calls, *k)
+	s.mu.Unlock()
+}
+
+// shard returns the shard holding k, chosen by hash64
+func (f *SingleFlight[T]) shard(k *KUID) *flightShard[T] {
+	return &f.shards[hash64(k)%flightShards]
+}
@@ -0,0 +1,119 @@
+package kuid
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleFlightCollapses(t *testing.T) {
+	f := NewSingleFlight[string]()
+	k, _ := NewKUID()
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const n = 20
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	sharedCount := atomic.Int32{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err, shared := f.Do(k, func() (string, error) {
+				calls.Add(1)
+				<-release
+				return "loaded", nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+			results[i] = v
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if c := calls.Load(); c != 1 {
+		t.Errorf("function ran %d times, want 1", c)
+	}
+	for i, v := range results {
+		if v != "loaded" {
+			t.Errorf("caller %d got %q", i, v)
+		}
+	}
+	if sharedCount.Load() != n {
+		t.Errorf("%d callers saw shared results, want %d", sharedCount.Load(), n)
+	}
+
+	// Calls after completion run again
+	v, _, shared := f.Do(k, func() (string, error) { return "fresh", nil })
+	if v != "fresh" || shared {
+		t.Errorf("Do() after completion = %q, shared %v", v, shared)
+	}
+}
+
+func TestSingleFlightErrorAndForget(t *testing.T) {
+	f := NewSingleFlight[int]()
+	k, _ := NewKUID()
+	want := errors.New("load failed")
+	if _, err, _ := f.Do(k, func() (int, error) { return 0, want }); err != want {
+		t.Errorf("Do() error = %v, want %v", err, want)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go f.Do(k, func() (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+	<-started
+	f.Forget(k)
+	v, _, shared := f.Do(k, func() (int, error) { return 2, nil })
+	close(release)
+	if v != 2 || shared {
+		t.Errorf("Do() after Forget() = %d, shared %v; want a fresh call", v, shared)
+	}
+}
+
+func TestSingleFlightPanic(t *testing.T) {
+	f := NewSingleFlight[int]()
+	k, _ := NewKUID()
+	started := make(chan struct{})
+	waiter := make(chan error)
+
+	go func() {
+		defer func() { recover() }()
+		f.Do(k, func() (int, error) {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+			panic("boom")
+		})
+	}()
+	<-started
+	go func() {
+		_, err, _ := f.Do(k, func() (int, error) { return 1, nil })
+		waiter <- err
+	}()
+	if err := <-waiter; err != nil && !errors.Is(err, ErrFlightPanicked) {
+		t.Errorf("waiter error = %v, want ErrFlightPanicked or a fresh call", err)
+	}
+}
+
+func TestSingleFlightShardSpread(t *testing.T) {
+	f := NewSingleFlight[int]()
+	used := make(map[*flightShard[int]]bool)
+	for _, id := range fixedLowBits(t, 1000) {
+		used[f.shard(id)] = true
+	}
+	if len(used) < flightShards/2 {
+		t.Errorf("1000 typed and v6 KUIDs used %d of %d shards", len(used), flightShards)
+	}
+}
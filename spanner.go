@@ -0,0 +1,47 @@
+package kuid
+
+import "fmt"
+
+// EncodeSpanner implements cloud.google.com/go/spanner's Encoder, storing
+// KUIDs in STRING(22) columns. Use SpannerBytes for BYTES(16) columns.
+func (k KUID) EncodeSpanner() (any, error) {
+	return k.String(), nil
+}
+
+// DecodeSpanner implements cloud.google.com/go/spanner's Decoder. It reads
+// both STRING(22) and BYTES(16) columns.
+func (k *KUID) DecodeSpanner(input any) error {
+	parsed, err := fromDatabaseValue(input)
+	if err != nil {
+		return err
+	}
+	*k = *parsed
+	return nil
+}
+
+// SpannerBytes is a KUID stored in a Spanner BYTES(16) column, taking 16
+// bytes instead of 22
+type SpannerBytes struct {
+	KUID
+}
+
+// EncodeSpanner implements cloud.google.com/go/spanner's Encoder
+func (s SpannerBytes) EncodeSpanner() (any, error) {
+	return s.Bytes(), nil
+}
+
+// fromDatabaseValue converts a string (KUID or UUID form) or 16-byte value
+// read from a database client into a KUID
+func fromDatabaseValue(v any) (*KUID, error) {
+	switch v := v.(type) {
+	case string:
+		return Parse(v)
+	case []byte:
+		return FromBytes(v)
+	case *string:
+		if v != nil {
+			return Parse(*v)
+		}
+	}
+	return nil, fmt.Errorf("cannot convert %T to KUID", v)
+}
@@ -0,0 +1,45 @@
+package kuid
+
+import "testing"
+
+// spannerEncoder and spannerDecoder mirror cloud.google.com/go/spanner's
+// Encoder and Decoder interfaces
+type spannerEncoder interface {
+	EncodeSpanner() (any, error)
+}
+
+type spannerDecoder interface {
+	DecodeSpanner(input any) error
+}
+
+func TestSpanner(t *testing.T) {
+	kuid, _ := NewKUID()
+
+	var enc spannerEncoder = *kuid
+	v, err := enc.EncodeSpanner()
+	if err != nil || v != kuid.String() {
+		t.Errorf("EncodeSpanner() = %v, %v, want %v", v, err, kuid.String())
+	}
+
+	enc = SpannerBytes{*kuid}
+	v, _ = enc.EncodeSpanner()
+	if b, ok := v.([]byte); !ok || len(b) != 16 {
+		t.Errorf("SpannerBytes.EncodeSpanner() = %v, want 16 bytes", v)
+	}
+
+	for _, input := range []any{kuid.String(), kuid.Bytes(), kuid.ToUUID()} {
+		var decoded KUID
+		var dec spannerDecoder = &decoded
+		if err := dec.DecodeSpanner(input); err != nil {
+			t.Fatalf("DecodeSpanner(%T) error = %v", input, err)
+		}
+		if !decoded.Equal(kuid) {
+			t.Errorf("DecodeSpanner(%T) = %v, want %v", input, decoded, kuid)
+		}
+	}
+
+	var decoded KUID
+	if err := decoded.DecodeSpanner(int64(1)); err == nil {
+		t.Errorf("DecodeSpanner() expected error for int64")
+	}
+}
@@ -0,0 +1,226 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+)
+
+// KUID cannot implement sql.Scanner itself because its Scan method is
+// fmt.Scanner's. These wrappers implement sql.Scanner and driver.Valuer
+// instead, so they work as struct fields with database/sql, sqlx and bun,
+// and as arguments to sqlx.In and bun.In. Each writes one storage form and
+// scans any of them. Use a pointer field for nullable columns:
+//
+//	type Order struct {
+//	    ID       kuid.SQLUUID  `db:"id" bun:"id,pk"`
+//	    ParentID *kuid.SQLUUID `db:"parent_id" bun:"parent_id"`
+//	}
+
+// ErrNullKUID is returned when scanning NULL into a KUID wrapper
+var ErrNullKUID = errors.New("cannot scan NULL into KUID; use a pointer")
+
+// SQLString is a KUID stored in base62 form, in CHAR(22) or text columns
+type SQLString struct {
+	KUID
+}
+
+// Value implements driver.Valuer
+func (s SQLString) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+
+// Scan implements sql.Scanner
+func (s *SQLString) Scan(src any) error {
+	return scanSQL(&s.KUID, src)
+}
+
+// SQLUUID is a KUID stored as a hyphenated UUID, in PostgreSQL uuid
+// columns or CHAR(36) columns
+type SQLUUID struct {
+	KUID
+}
+
+// Value implements driver.Valuer
+func (s SQLUUID) Value() (driver.Value, error) {
+	return s.ToUUID(), nil
+}
+
+// Scan implements sql.Scanner
+func (s *SQLUUID) Scan(src any) error {
+	return scanSQL(&s.KUID, src)
+}
+
+// SQLBytes is a KUID stored as 16 bytes, in MySQL BINARY(16) columns or
+// SQLite BLOBs
+type SQLBytes struct {
+	KUID
+}
+
+// Value implements driver.Valuer
+func (s SQLBytes) Value() (driver.Value, error) {
+	return s.Bytes(), nil
+}
+
+// Scan implements sql.Scanner
+func (s *SQLBytes) Scan(src any) error {
+	return scanSQL(&s.KUID, src)
+}
+
+// scanSQL sets k from a column value. Drivers return text columns as
+// either string or []byte, so only 16-byte values are taken as binary.
+func scanSQL(k *KUID, src any) error {
+	var (
+		parsed *KUID
+		err    error
+	)
+	switch src := src.(type) {
+	case nil:
+		return ErrNullKUID
+	case []byte:
+		if len(src) == 16 {
+			parsed, err = FromBytes(src)
+		} else {
+			parsed, err = Parse(string(src))
+		}
+	default:
+		parsed, err = fromDatabaseValue(src)
+	}
+	if err != nil {
+		return err
+	}
+	*k = *parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing the 33-character string form,
+// which sorts in event order under a binary collation
+func (e EventID) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements sql.Scanner, accepting the string or 24-byte binary form
+func (e *EventID) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return e.UnmarshalText([]byte(src))
+	case []byte:
+		if len(src) == 24 {
+			return e.UnmarshalBinary(src)
+		}
+		return e.UnmarshalText(src)
+	case nil:
+		return ErrNullKUID
+	}
+	return ErrInvalidEventID
+}
+
+// StringArgs returns ids in base62 form as query arguments, for
+// expanding IN clauses with sqlx.In or bun.In
+func StringArgs(ids []KUID) []any {
+	args := make([]any, len(ids))
+	for i := range ids {
+		args[i] = ids[i].String()
+	}
+	return args
+}
+
+// UUIDArgs returns ids as UUID strings for query arguments
+func UUIDArgs(ids []KUID) []any {
+	args := make([]any, len(ids))
+	for i := range ids {
+		args[i] = ids[i].ToUUID()
+	}
+	return args
+}
+
+// BytesArgs returns ids as 16-byte values for query arguments
+func BytesArgs(ids []KUID) []any {
+	args := make([]any, len(ids))
+	for i := range ids {
+		args[i] = ids[i].Bytes()
+	}
+	return args
+}
+
+// UUIDArray is a PostgreSQL uuid[] of KUIDs, like pq.Array, so a whole
+// slice binds to one parameter:
+//
+//	db.Query("SELECT * FROM orders WHERE id = ANY($1)", kuid.UUIDArray(ids))
+type UUIDArray []KUID
+
+// Value implements driver.Valuer
+func (a UUIDArray) Value() (driver.Value, error) {
+	return arrayLiteral(a, (*KUID).ToUUID), nil
+}
+
+// Scan implements sql.Scanner
+func (a *UUIDArray) Scan(src any) error {
+	return scanArray((*[]KUID)(a), src)
+}
+
+// StringArray is a PostgreSQL text[] of KUIDs in base62 form
+type StringArray []KUID
+
+// Value implements driver.Valuer
+func (a StringArray) Value() (driver.Value, error) {
+	return arrayLiteral(a, func(k *KUID) string { return k.String() }), nil
+}
+
+// Scan implements sql.Scanner
+func (a *StringArray) Scan(src any) error {
+	return scanArray((*[]KUID)(a), src)
+}
+
+// arrayLiteral formats ids as a PostgreSQL array literal. Neither form
+// contains characters that need quoting.
+func arrayLiteral(ids []KUID, format func(*KUID) string) string {
+	if ids == nil {
+		return "{}"
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(format(&ids[i]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// scanArray parses a PostgreSQL array literal of KUIDs or UUIDs
+func scanArray(a *[]KUID, src any) error {
+	var s string
+	switch src := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		s = src
+	case []byte:
+		s = string(src)
+	default:
+		return errors.New("cannot scan non-array value into KUID array")
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return errors.New("invalid PostgreSQL array literal")
+	}
+	s = s[1 : len(s)-1]
+
+	ids := []KUID{}
+	if s != "" {
+		for _, elem := range strings.Split(s, ",") {
+			k, err := Parse(strings.Trim(elem, `"`))
+			if err != nil {
+				return err
+			}
+			ids = append(ids, *k)
+		}
+	}
+	*a = ids
+	return nil
+}
@@ -0,0 +1,218 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSQLValue(t *testing.T) {
+	id, _ := NewKUID()
+	tests := []struct {
+		name  string
+		value driver.Valuer
+		want  driver.Value
+	}{
+		{"string", SQLString{*id}, id.String()},
+		{"uuid", SQLUUID{*id}, id.ToUUID()},
+		{"bytes", &SQLBytes{*id}, id.Bytes()},
+		{"uuid array", UUIDArray{*id, *id}, "{" + id.ToUUID() + "," + id.ToUUID() + "}"},
+		{"string array", StringArray{*id}, "{" + id.String() + "}"},
+		{"nil array", UUIDArray(nil), "{}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !driver.IsValue(got) {
+				t.Errorf("Value() = %T, not a driver value", got)
+			}
+			if b, ok := tt.want.([]byte); ok {
+				if !bytes.Equal(got.([]byte), b) {
+					t.Errorf("Value() = %x, want %x", got, b)
+				}
+			} else if got != tt.want {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	id, _ := NewKUID()
+	for _, src := range []any{id.String(), id.ToUUID(), id.Bytes(), []byte(id.String()), []byte(id.ToUUID())} {
+		var s SQLString
+		var u SQLUUID
+		var b SQLBytes
+		for _, dst := range []sql.Scanner{&s, &u, &b} {
+			if err := dst.Scan(src); err != nil {
+				t.Fatalf("Scan(%v) error = %v", src, err)
+			}
+		}
+		if s.KUID != *id || u.KUID != *id || b.KUID != *id {
+			t.Errorf("Scan(%v) = %v, %v, %v, want %v", src, s, u, b, id)
+		}
+	}
+
+	var s SQLUUID
+	if err := s.Scan(nil); !errors.Is(err, ErrNullKUID) {
+		t.Errorf("Scan(nil) error = %v, want ErrNullKUID", err)
+	}
+	for _, src := range []any{"bogus", []byte{1, 2, 3}, int64(1)} {
+		if err := s.Scan(src); err == nil {
+			t.Errorf("Scan(%v) succeeded", src)
+		}
+	}
+}
+
+func TestSQLArrayScan(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	tests := []struct {
+		src     any
+		want    []KUID
+		wantErr bool
+	}{
+		{"{" + a.ToUUID() + "," + b.ToUUID() + "}", []KUID{*a, *b}, false},
+		{[]byte(`{"` + a.String() + `"}`), []KUID{*a}, false},
+		{"{}", []KUID{}, false},
+		{nil, nil, false},
+		{"{bogus}", nil, true},
+		{a.String(), nil, true},
+		{int64(1), nil, true},
+	}
+	for _, tt := range tests {
+		var arr UUIDArray
+		err := arr.Scan(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Scan(%v) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (len(arr) != len(tt.want) || (arr == nil) != (tt.want == nil)) {
+			t.Errorf("Scan(%v) = %v, want %v", tt.src, arr, tt.want)
+		}
+		for i := range tt.want {
+			if arr[i] != tt.want[i] {
+				t.Errorf("Scan(%v)[%d] = %v, want %v", tt.src, i, arr[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestSQLArgs(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	ids := []KUID{*a, *b}
+	if got := StringArgs(ids); len(got) != 2 || got[1] != b.String() {
+		t.Errorf("StringArgs() = %v", got)
+	}
+	if got := UUIDArgs(ids); len(got) != 2 || got[0] != a.ToUUID() {
+		t.Errorf("UUIDArgs() = %v", got)
+	}
+	if got := BytesArgs(ids); len(got) != 2 || !bytes.Equal(got[0].([]byte), a.Bytes()) {
+		t.Errorf("BytesArgs() = %v", got)
+	}
+	if got := UUIDArgs(nil); len(got) != 0 {
+		t.Errorf("UUIDArgs(nil) = %v", got)
+	}
+}
+
+// TestSQLStructScan scans rows into struct fields the way sqlx and bun do,
+// through database/sql, including NULL into a pointer field
+func TestSQLStructScan(t *testing.T) {
+	id, _ := NewKUID()
+	db := sql.OpenDB(rowsDriver{
+		{id.ToUUID(), nil},
+		{id.Bytes(), []byte(id.String())},
+	})
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id, parent_id FROM orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	type order struct {
+		ID       SQLUUID
+		ParentID *SQLString
+	}
+	var orders []order
+	for rows.Next() {
+		var o order
+		if err := rows.Scan(&o.ID, &o.ParentID); err != nil {
+			t.Fatal(err)
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 || orders[0].ID.KUID != *id || orders[0].ParentID != nil ||
+		orders[1].ParentID == nil || orders[1].ParentID.KUID != *id {
+		t.Errorf("scanned %+v", orders)
+	}
+}
+
+// rowsDriver is a database/sql driver and connector answering every query
+// with its rows
+type rowsDriver [][]driver.Value
+
+func (d rowsDriver) Open(string) (driver.Conn, error)             { return d, nil }
+func (d rowsDriver) Connect(context.Context) (driver.Conn, error) { return d, nil }
+func (d rowsDriver) Driver() driver.Driver                        { return d }
+func (d rowsDriver) Prepare(string) (driver.Stmt, error)          { return d, nil }
+func (d rowsDriver) Close() error                                 { return nil }
+func (d rowsDriver) Begin() (driver.Tx, error)                    { return nil, errors.New("not supported") }
+func (d rowsDriver) NumInput() int                                { return -1 }
+func (d rowsDriver) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (d rowsDriver) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{rows: d}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id", "parent_id"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestEventIDSQL(t *testing.T) {
+	e := EventID{ID: KUID{msb: 5, lsb: 6}, Seq: 7}
+	v, err := e.Value()
+	if err != nil || v != e.String() {
+		t.Errorf("Value() = %v, %v", v, err)
+	}
+	b, _ := e.MarshalBinary()
+	for _, src := range []any{e.String(), []byte(e.String()), b} {
+		var got EventID
+		if err := got.Scan(src); err != nil || got != e {
+			t.Errorf("Scan(%v) = %v, %v", src, got, err)
+		}
+	}
+	var got EventID
+	if err := got.Scan(nil); !errors.Is(err, ErrNullKUID) {
+		t.Errorf("Scan(nil) error = %v", err)
+	}
+	if err := got.Scan(int64(1)); !errors.Is(err, ErrInvalidEventID) {
+		t.Errorf("Scan(int64) error = %v", err)
+	}
+}
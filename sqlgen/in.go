@@ -0,0 +1,87 @@
+//go:build !tinygo
+
+package sqlgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alphabatem/kuid"
+)
+
+// Storage is the form in which a column stores KUIDs
+type Storage int
+
+const (
+	// Native is 16-byte storage as written by Generate: uuid in
+	// PostgreSQL, BINARY(16) in MySQL and BLOBs in SQLite
+	Native Storage = iota
+	// Base62 is the 22-character KUID string, as in CHAR(22) columns
+	Base62
+	// UUIDText is the 36-character hyphenated UUID string
+	UUIDText
+)
+
+// Placeholders returns n comma-separated parameter placeholders for d,
+// numbered from next in PostgreSQL: "$3, $4" or "?, ?"
+func Placeholders(d Dialect, n, next int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if d == Postgres {
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(next + i))
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// In returns a predicate matching column against ids, and its arguments.
+// PostgreSQL binds the whole slice to parameter $next as a typed array, so
+// the query text is the same for any number of IDs:
+//
+//	id = ANY($1::uuid[])
+//
+// MySQL and SQLite expand one placeholder per ID. With no IDs they get a
+// predicate matching nothing, since "IN ()" is a syntax error.
+func In(d Dialect, s Storage, column string, ids []kuid.KUID, next int) (string, []any, error) {
+	if !ValidIdentifier(column) {
+		return "", nil, fmt.Errorf("invalid column %q", column)
+	}
+	if _, ok := dialectNames[d]; !ok {
+		return "", nil, fmt.Errorf("unknown SQL dialect %v", d)
+	}
+
+	if d == Postgres {
+		switch s {
+		case Native:
+			return fmt.Sprintf("%s = ANY($%d::uuid[])", column, next), []any{kuid.UUIDArray(ids)}, nil
+		case Base62:
+			return fmt.Sprintf("%s = ANY($%d::text[])", column, next), []any{kuid.StringArray(ids)}, nil
+		case UUIDText:
+			return fmt.Sprintf("%s = ANY($%d::text[])", column, next), []any{kuid.UUIDArray(ids)}, nil
+		}
+		return "", nil, fmt.Errorf("unknown storage %d", s)
+	}
+
+	var args []any
+	switch s {
+	case Native:
+		args = kuid.BytesArgs(ids)
+	case Base62:
+		args = kuid.StringArgs(ids)
+	case UUIDText:
+		args = kuid.UUIDArgs(ids)
+	default:
+		return "", nil, fmt.Errorf("unknown storage %d", s)
+	}
+	if len(ids) == 0 {
+		return "1 = 0", args, nil
+	}
+	return fmt.Sprintf("%s IN (%s)", column, Placeholders(d, len(ids), next)), args, nil
+}
@@ -0,0 +1,89 @@
+//go:build !tinygo
+
+package sqlgen
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		d       Dialect
+		n, next int
+		want    string
+	}{
+		{Postgres, 3, 1, "$1, $2, $3"},
+		{Postgres, 2, 4, "$4, $5"},
+		{MySQL, 3, 1, "?, ?, ?"},
+		{SQLite, 1, 9, "?"},
+		{MySQL, 0, 1, ""},
+	}
+	for _, tt := range tests {
+		if got := Placeholders(tt.d, tt.n, tt.next); got != tt.want {
+			t.Errorf("Placeholders(%v, %d, %d) = %q, want %q", tt.d, tt.n, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestIn(t *testing.T) {
+	a, _ := kuid.NewKUID()
+	b, _ := kuid.NewKUID()
+	ids := []kuid.KUID{*a, *b}
+
+	tests := []struct {
+		name     string
+		d        Dialect
+		s        Storage
+		ids      []kuid.KUID
+		want     string
+		wantArgs []any
+	}{
+		{"postgres native", Postgres, Native, ids, "o.id = ANY($2::uuid[])", []any{"{" + a.ToUUID() + "," + b.ToUUID() + "}"}},
+		{"postgres base62", Postgres, Base62, ids, "o.id = ANY($2::text[])", []any{"{" + a.String() + "," + b.String() + "}"}},
+		{"postgres uuid text", Postgres, UUIDText, nil, "o.id = ANY($2::text[])", []any{"{}"}},
+		{"mysql native", MySQL, Native, ids, "o.id IN (?, ?)", []any{a.Bytes(), b.Bytes()}},
+		{"sqlite base62", SQLite, Base62, ids, "o.id IN (?, ?)", []any{a.String(), b.String()}},
+		{"mysql uuid text", MySQL, UUIDText, ids[:1], "o.id IN (?)", []any{a.ToUUID()}},
+		{"mysql empty", MySQL, Native, nil, "1 = 0", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := In(tt.d, tt.s, "o.id", tt.ids, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("In() = %q, want %q", got, tt.want)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("In() args = %v, want %v", args, tt.wantArgs)
+			}
+			for i, arg := range args {
+				if v, ok := arg.(driver.Valuer); ok {
+					arg, _ = v.Value()
+				}
+				if want, ok := tt.wantArgs[i].([]byte); ok {
+					if !bytes.Equal(arg.([]byte), want) {
+						t.Errorf("arg %d = %x, want %x", i, arg, want)
+					}
+				} else if arg != tt.wantArgs[i] {
+					t.Errorf("arg %d = %v, want %v", i, arg, tt.wantArgs[i])
+				}
+			}
+		})
+	}
+
+	if _, _, err := In(Postgres, Native, "id; DROP TABLE x", ids, 1); err == nil {
+		t.Error("In() accepted an invalid column")
+	}
+	if _, _, err := In(MySQL, Storage(7), "id", ids, 1); err == nil {
+		t.Error("In() accepted an unknown storage")
+	}
+	if _, _, err := In(Dialect(7), Native, "id", ids, 1); err == nil {
+		t.Error("In() accepted an unknown dialect")
+	}
+}
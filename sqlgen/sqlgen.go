@@ -0,0 +1,312 @@
+// Package sqlgen emits SQL for storing KUIDs in databases: migrations that
+// move UUID text columns to 16-byte storage, functions that render those
+// bytes in KUID base62 form so queries and views agree with the Go
+// package, and IN-clause predicates for querying by many KUIDs.
+//
+// Migrations convert columns in place so indexes, primary keys and unique
+// constraints survive: PostgreSQL columns become uuid, MySQL columns
+// BINARY(16) and SQLite values BLOBs. Each migration comes with a down
+// migration restoring the lowercase hyphenated text form. Foreign keys
+// referencing a converted column must be dropped first and recreated
+// afterwards; the generated SQL does not touch them.
+//
+// PostgreSQL and MySQL get a uuid_to_kuid function, used by the generated
+// views. SQLite has no stored functions, so its views call kuid(), which
+// the application must register on each connection with
+// kuid.RegisterSQLiteFunctions or kuid.SQLiteFunctions.
+package sqlgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dialect is a SQL dialect
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	MySQL
+	SQLite
+)
+
+var dialectNames = map[Dialect]string{Postgres: "postgres", MySQL: "mysql", SQLite: "sqlite"}
+
+// String returns the dialect name accepted by ParseDialect
+func (d Dialect) String() string {
+	if name, ok := dialectNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+// ParseDialect returns the dialect called name: postgres, mysql or sqlite
+func ParseDialect(name string) (Dialect, error) {
+	for d, n := range dialectNames {
+		if n == strings.ToLower(name) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown SQL dialect %q", name)
+}
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Column is an ID column to migrate
+type Column struct {
+	Table string
+	Name  string
+	// NotNull keeps a NOT NULL constraint through MySQL's MODIFY COLUMN,
+	// which otherwise drops it
+	NotNull bool
+	// Converted skips the type conversion for columns already stored as
+	// 16 bytes, such as native PostgreSQL uuid columns, so only the view
+	// is created
+	Converted bool
+	// View names the view adding a <name>_kuid column with the base62
+	// form; <table>_kuid if empty, or no view if "-"
+	View string
+}
+
+// Migration holds the statements of an up and a down migration, without
+// trailing semicolons
+type Migration struct {
+	Up   []string
+	Down []string
+}
+
+// UpScript returns the up migration as a script
+func (m *Migration) UpScript() string {
+	return script(m.Up)
+}
+
+// DownScript returns the down migration as a script
+func (m *Migration) DownScript() string {
+	return script(m.Down)
+}
+
+func script(stmts []string) string {
+	var b strings.Builder
+	for _, s := range stmts {
+		b.WriteString(s)
+		b.WriteString(";\n\n")
+	}
+	return b.String()
+}
+
+// Generate returns the migration converting cols for dialect. The down
+// migration undoes the steps in reverse order, but leaves the uuid_to_kuid
+// function in place since other views may use it.
+func Generate(d Dialect, cols ...Column) (*Migration, error) {
+	if len(cols) == 0 {
+		return nil, errors.New("no columns to migrate")
+	}
+	if _, ok := dialectNames[d]; !ok {
+		return nil, fmt.Errorf("unknown SQL dialect %v", d)
+	}
+
+	m := &Migration{}
+	if d != SQLite {
+		m.Up = append(m.Up, Functions(d)...)
+	}
+	var down [][]string
+	for _, c := range cols {
+		if !ValidIdentifier(c.Table) || !ValidIdentifier(c.Name) || strings.Contains(c.Name, ".") {
+			return nil, fmt.Errorf("invalid column %s.%s", c.Table, c.Name)
+		}
+		if c.View != "" && c.View != "-" && !ValidIdentifier(c.View) {
+			return nil, fmt.Errorf("invalid view name %q", c.View)
+		}
+
+		var colDown []string
+		if !c.Converted {
+			up, down := convert(d, c)
+			m.Up = append(m.Up, up...)
+			colDown = append(colDown, down...)
+		}
+		if view := c.viewName(); view != "" {
+			m.Up = append(m.Up, fmt.Sprintf("CREATE VIEW %s AS SELECT %s.*, %s(%s.%s) AS %s_kuid FROM %s",
+				view, c.Table, encodeFunction(d), c.Table, c.Name, c.Name, c.Table))
+			colDown = append([]string{"DROP VIEW IF EXISTS " + view}, colDown...)
+		}
+		down = append(down, colDown)
+	}
+	for i := len(down) - 1; i >= 0; i-- {
+		m.Down = append(m.Down, down[i]...)
+	}
+	return m, nil
+}
+
+func (c *Column) viewName() string {
+	switch c.View {
+	case "-":
+		return ""
+	case "":
+		return c.Table + "_kuid"
+	}
+	return c.View
+}
+
+// encodeFunction names the function rendering 16 bytes as a KUID
+func encodeFunction(d Dialect) string {
+	if d == SQLite {
+		return "kuid"
+	}
+	return "uuid_to_kuid"
+}
+
+// convert returns the statements converting c to 16-byte storage and back
+func convert(d Dialect, c Column) (up, down []string) {
+	t, col := c.Table, c.Name
+	switch d {
+	case Postgres:
+		up = []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE uuid USING %s::uuid", t, col, col)}
+		down = []string{fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE text USING %s::text", t, col, col)}
+	case MySQL:
+		// Going through VARBINARY keeps the bytes of the text, and MODIFY
+		// keeps the column's indexes
+		null := ""
+		if c.NotNull {
+			null = " NOT NULL"
+		}
+		up = []string{
+			fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s VARBINARY(36)%s", t, col, null),
+			fmt.Sprintf("UPDATE %s SET %s = UNHEX(REPLACE(%s, '-', ''))", t, col, col),
+			fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s BINARY(16)%s", t, col, null),
+		}
+		down = []string{
+			fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s VARBINARY(36)%s", t, col, null),
+			fmt.Sprintf("UPDATE %s SET %s = LOWER(INSERT(INSERT(INSERT(INSERT(HEX(%s), 9, 0, '-'), 14, 0, '-'), 19, 0, '-'), 24, 0, '-'))", t, col, col),
+			fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s CHAR(36)%s", t, col, null),
+		}
+	case SQLite:
+		// Column types are advisory in SQLite, so only the values change.
+		// unhex needs SQLite 3.41 or later.
+		up = []string{fmt.Sprintf("UPDATE %s SET %s = unhex(replace(%s, '-', '')) WHERE typeof(%s) = 'text'", t, col, col, col)}
+		down = []string{fmt.Sprintf("UPDATE %s SET %s = lower(substr(hex(%s), 1, 8) || '-' || substr(hex(%s), 9, 4) || '-' || substr(hex(%s), 13, 4) || '-' || substr(hex(%s), 17, 4) || '-' || substr(hex(%s), 21)) WHERE typeof(%s) = 'blob'",
+			t, col, col, col, col, col, col, col)}
+	}
+	return up, down
+}
+
+// Functions returns the statements creating the dialect's KUID functions.
+//
+// PostgreSQL gets uuid_to_kuid(uuid) and kuid_to_uuid(text), which convert
+// exactly as the Go package does and reject strings that FromString
+// rejects, and kuid_generate(), which returns a random KUID for column
+// defaults. kuid_generate needs PostgreSQL 13 or later for
+// gen_random_uuid, so its KUIDs carry UUID version 4 bits like those of
+// NewV4KUID.
+//
+// MySQL gets uuid_to_kuid(BINARY(16)). SQLite has no stored functions and
+// gets none.
+func Functions(d Dialect) []string {
+	switch d {
+	case Postgres:
+		return []string{`CREATE OR REPLACE FUNCTION uuid_to_kuid(id uuid) RETURNS text
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+	alphabet constant text := '` + alphabet + `';
+	digits text := replace(id::text, '-', '');
+	result text := '';
+	part text;
+	half numeric;
+BEGIN
+	FOR h IN 0..1 LOOP
+		half := 0;
+		FOR i IN 1..16 LOOP
+			half := half * 16 + position(substr(digits, h * 16 + i, 1) IN '0123456789abcdef') - 1;
+		END LOOP;
+		part := '';
+		FOR i IN 1..11 LOOP
+			part := substr(alphabet, (half % 62)::int + 1, 1) || part;
+			half := div(half, 62);
+		END LOOP;
+		result := result || part;
+	END LOOP;
+	RETURN result;
+END
+$$`, `CREATE OR REPLACE FUNCTION kuid_to_uuid(id text) RETURNS uuid
+LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE AS $$
+DECLARE
+	alphabet constant text := '` + alphabet + `';
+	result text := '';
+	part text;
+	half numeric;
+	digit int;
+BEGIN
+	IF length(id) <> 22 THEN
+		RAISE EXCEPTION 'invalid KUID string length: %', id USING ERRCODE = 'invalid_text_representation';
+	END IF;
+	FOR h IN 0..1 LOOP
+		half := 0;
+		FOR i IN 1..11 LOOP
+			digit := strpos(alphabet, substr(id, h * 11 + i, 1)) - 1;
+			IF digit < 0 THEN
+				RAISE EXCEPTION 'invalid character in KUID string: %', id USING ERRCODE = 'invalid_text_representation';
+			END IF;
+			half := half * 62 + digit;
+		END LOOP;
+		IF half > 18446744073709551615 THEN
+			RAISE EXCEPTION 'KUID value overflows 128 bits: %', id USING ERRCODE = 'invalid_text_representation';
+		END IF;
+		part := '';
+		FOR i IN 1..16 LOOP
+			part := substr('0123456789abcdef', (half % 16)::int + 1, 1) || part;
+			half := div(half, 16);
+		END LOOP;
+		result := result || part;
+	END LOOP;
+	RETURN result::uuid;
+END
+$$`, `CREATE OR REPLACE FUNCTION kuid_generate() RETURNS text
+LANGUAGE sql VOLATILE PARALLEL SAFE AS $$
+	SELECT uuid_to_kuid(gen_random_uuid())
+$$`}
+	case MySQL:
+		digits := make([]string, 11)
+		for i := range digits {
+			digits[i] = fmt.Sprintf("SUBSTRING('%s', (v DIV %d) %% 62 + 1, 1)", alphabet, pow62(10-i))
+		}
+		half := func(from int) string {
+			return fmt.Sprintf("kuid_half(CAST(CONV(HEX(SUBSTRING(id, %d, 8)), 16, 10) AS UNSIGNED))", from)
+		}
+		return []string{
+			"DROP FUNCTION IF EXISTS kuid_half",
+			"CREATE FUNCTION kuid_half(v BIGINT UNSIGNED) RETURNS CHAR(11) DETERMINISTIC\nRETURN CONCAT(\n\t" + strings.Join(digits, ",\n\t") + ")",
+			"DROP FUNCTION IF EXISTS uuid_to_kuid",
+			"CREATE FUNCTION uuid_to_kuid(id BINARY(16)) RETURNS CHAR(22) DETERMINISTIC\nRETURN CONCAT(" + half(1) + ", " + half(9) + ")",
+		}
+	}
+	return nil
+}
+
+func pow62(n int) uint64 {
+	p := uint64(1)
+	for i := 0; i < n; i++ {
+		p *= 62
+	}
+	return p
+}
+
+// ValidIdentifier reports whether name is a plain, optionally
+// schema-qualified SQL identifier that is safe to interpolate
+func ValidIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			return false
+		}
+		for i := 0; i < len(part); i++ {
+			c := part[i]
+			letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			if !letter && (i == 0 || c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,193 @@
+package sqlgen
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestParseDialect(t *testing.T) {
+	for _, d := range []Dialect{Postgres, MySQL, SQLite} {
+		got, err := ParseDialect(strings.ToUpper(d.String()))
+		if err != nil || got != d {
+			t.Errorf("ParseDialect(%q) = %v, %v", d, got, err)
+		}
+	}
+	if _, err := ParseDialect("oracle"); err == nil {
+		t.Error("ParseDialect(oracle) succeeded")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  Dialect
+		cols     []Column
+		wantUp   []string
+		wantDown []string
+	}{
+		{
+			name:    "postgres",
+			dialect: Postgres,
+			cols:    []Column{{Table: "users", Name: "id"}},
+			wantUp: []string{
+				"CREATE OR REPLACE FUNCTION uuid_to_kuid",
+				"CREATE OR REPLACE FUNCTION kuid_to_uuid",
+				"CREATE OR REPLACE FUNCTION kuid_generate",
+				"ALTER TABLE users ALTER COLUMN id TYPE uuid USING id::uuid",
+				"CREATE VIEW users_kuid AS SELECT users.*, uuid_to_kuid(users.id) AS id_kuid FROM users",
+			},
+			wantDown: []string{
+				"DROP VIEW IF EXISTS users_kuid",
+				"ALTER TABLE users ALTER COLUMN id TYPE text USING id::text",
+			},
+		},
+		{
+			name:    "postgres converted",
+			dialect: Postgres,
+			cols:    []Column{{Table: "app.users", Name: "id", Converted: true, View: "app.user_ids"}},
+			wantUp: []string{
+				"CREATE OR REPLACE FUNCTION uuid_to_kuid",
+				"CREATE OR REPLACE FUNCTION kuid_to_uuid",
+				"CREATE OR REPLACE FUNCTION kuid_generate",
+				"CREATE VIEW app.user_ids AS SELECT app.users.*, uuid_to_kuid(app.users.id) AS id_kuid FROM app.users",
+			},
+			wantDown: []string{"DROP VIEW IF EXISTS app.user_ids"},
+		},
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			cols:    []Column{{Table: "orders", Name: "id", NotNull: true, View: "-"}},
+			wantUp: []string{
+				"DROP FUNCTION IF EXISTS kuid_half",
+				"CREATE FUNCTION kuid_half",
+				"DROP FUNCTION IF EXISTS uuid_to_kuid",
+				"CREATE FUNCTION uuid_to_kuid",
+				"ALTER TABLE orders MODIFY COLUMN id VARBINARY(36) NOT NULL",
+				"UPDATE orders SET id = UNHEX(REPLACE(id, '-', ''))",
+				"ALTER TABLE orders MODIFY COLUMN id BINARY(16) NOT NULL",
+			},
+			wantDown: []string{
+				"ALTER TABLE orders MODIFY COLUMN id VARBINARY(36) NOT NULL",
+				"UPDATE orders SET id = LOWER(INSERT(",
+				"ALTER TABLE orders MODIFY COLUMN id CHAR(36) NOT NULL",
+			},
+		},
+		{
+			name:    "sqlite two columns",
+			dialect: SQLite,
+			cols:    []Column{{Table: "a", Name: "id"}, {Table: "b", Name: "a_id", View: "-"}},
+			wantUp: []string{
+				"UPDATE a SET id = unhex(replace(id, '-', '')) WHERE typeof(id) = 'text'",
+				"CREATE VIEW a_kuid AS SELECT a.*, kuid(a.id) AS id_kuid FROM a",
+				"UPDATE b SET a_id = unhex(replace(a_id, '-', '')) WHERE typeof(a_id) = 'text'",
+			},
+			wantDown: []string{
+				"UPDATE b SET a_id = lower(",
+				"DROP VIEW IF EXISTS a_kuid",
+				"UPDATE a SET id = lower(",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Generate(tt.dialect, tt.cols...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkStatements(t, "up", m.Up, tt.wantUp)
+			checkStatements(t, "down", m.Down, tt.wantDown)
+		})
+	}
+}
+
+func checkStatements(t *testing.T, name string, got, wantPrefixes []string) {
+	t.Helper()
+	if len(got) != len(wantPrefixes) {
+		t.Fatalf("%s has %d statements, want %d:\n%s", name, len(got), len(wantPrefixes), strings.Join(got, "\n"))
+	}
+	for i, want := range wantPrefixes {
+		if !strings.HasPrefix(got[i], want) {
+			t.Errorf("%s statement %d = %q, want prefix %q", name, i, got[i], want)
+		}
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Dialect
+		cols []Column
+	}{
+		{"no columns", Postgres, nil},
+		{"bad dialect", Dialect(9), []Column{{Table: "t", Name: "id"}}},
+		{"injection", Postgres, []Column{{Table: "t; DROP TABLE x", Name: "id"}}},
+		{"qualified column", Postgres, []Column{{Table: "t", Name: "t.id"}}},
+		{"empty part", MySQL, []Column{{Table: "s..t", Name: "id"}}},
+		{"bad view", SQLite, []Column{{Table: "t", Name: "id", View: "1v"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.d, tt.cols...); err == nil {
+				t.Error("Generate succeeded")
+			}
+		})
+	}
+}
+
+func TestScripts(t *testing.T) {
+	m := &Migration{Up: []string{"A", "B"}, Down: []string{"C"}}
+	if got := m.UpScript(); got != "A;\n\nB;\n\n" {
+		t.Errorf("UpScript() = %q", got)
+	}
+	if got := m.DownScript(); got != "C;\n\n" {
+		t.Errorf("DownScript() = %q", got)
+	}
+}
+
+// TestMySQLDigits evaluates the digit expressions of kuid_half in Go and
+// compares them with the package's encoding
+func TestMySQLDigits(t *testing.T) {
+	fn := Functions(MySQL)[1]
+	for _, v := range []uint64{0, 1, 61, 62, 1<<63 + 12345, ^uint64(0)} {
+		var got []byte
+		for i := 0; i < 11; i++ {
+			p := pow62(10 - i)
+			if !strings.Contains(fn, "(v DIV "+strconv.FormatUint(p, 10)+") % 62 + 1") {
+				t.Fatalf("kuid_half has no digit for 62^%d", 10-i)
+			}
+			got = append(got, alphabet[v/p%62])
+		}
+		var b [16]byte
+		binary.BigEndian.PutUint64(b[:8], v)
+		id, _ := kuid.FromBytes(b[:])
+		if want := id.String()[:11]; string(got) != want {
+			t.Errorf("digits of %d = %s, want %s", v, got, want)
+		}
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	for _, d := range []Dialect{Postgres, MySQL} {
+		for _, stmt := range Functions(d) {
+			if strings.Contains(stmt, "alphabet") && !strings.Contains(stmt, "'"+alphabet+"'") {
+				t.Errorf("%s function does not embed the alphabet: %s", d, stmt)
+			}
+		}
+	}
+	for i := 0; i < len(alphabet); i++ {
+		var b [16]byte
+		b[15] = byte(i)
+		id, _ := kuid.FromBytes(b[:])
+		if got := id.String()[21]; got != alphabet[i] {
+			t.Fatalf("digit %d = %c, package encodes %c", i, alphabet[i], got)
+		}
+	}
+	if got := Functions(SQLite); got != nil {
+		t.Errorf("Functions(SQLite) = %v, want nil", got)
+	}
+}
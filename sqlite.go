@@ -0,0 +1,102 @@
+//go:build !tinygo
+
+package kuid
+
+import "database/sql/driver"
+
+// SQLiteFunction is a SQL function for embedded SQLite databases, in a
+// form any driver can register. Arguments arrive as the driver passes
+// them: TEXT as string and BLOB as []byte.
+//
+// With modernc.org/sqlite, register the functions once at startup:
+//
+//	for _, f := range kuid.SQLiteFunctions() {
+//	    register := sqlite.RegisterScalarFunction
+//	    if f.Deterministic {
+//	        register = sqlite.RegisterDeterministicScalarFunction
+//	    }
+//	    call := f.Func
+//	    err := register(f.Name, int32(f.NArgs), func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+//	        return call(args)
+//	    })
+//	}
+type SQLiteFunction struct {
+	Name          string
+	NArgs         int
+	Deterministic bool
+	Func          func(args []driver.Value) (driver.Value, error)
+}
+
+// SQLiteFunctions returns the KUID functions for SQLite:
+//
+//	kuid(x)          base62 form of a KUID, UUID or 16-byte BLOB
+//	kuid_to_uuid(x)  hyphenated UUID form of the same
+//	kuid_bytes(x)    16-byte BLOB form of the same
+//	kuid_generate()  a new random KUID in base62 form
+//
+// The conversions return NULL for NULL and fail the statement for values
+// that are not IDs. With IDs stored as BLOBs, a query can take either
+// representation through kuid_bytes:
+//
+//	SELECT kuid(id), name FROM users WHERE id = kuid_bytes(?)
+func SQLiteFunctions() []SQLiteFunction {
+	return []SQLiteFunction{
+		{Name: "kuid", NArgs: 1, Deterministic: true, Func: sqliteConvert(func(k *KUID) driver.Value { return k.String() })},
+		{Name: "kuid_to_uuid", NArgs: 1, Deterministic: true, Func: sqliteConvert(func(k *KUID) driver.Value { return k.ToUUID() })},
+		{Name: "kuid_bytes", NArgs: 1, Deterministic: true, Func: sqliteConvert(func(k *KUID) driver.Value { return k.Bytes() })},
+		{Name: "kuid_generate", NArgs: 0, Func: func([]driver.Value) (driver.Value, error) {
+			k, err := NewValue()
+			if err != nil {
+				return nil, err
+			}
+			return k.String(), nil
+		}},
+	}
+}
+
+// sqliteConvert returns a one-argument SQLite function applying conv to
+// the KUID its argument holds
+func sqliteConvert(conv func(*KUID) driver.Value) func([]driver.Value) (driver.Value, error) {
+	return func(args []driver.Value) (driver.Value, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		k, err := fromDatabaseValue(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return conv(k), nil
+	}
+}
+
+// SQLiteFuncRegisterer registers Go functions on a SQLite connection. It
+// is implemented by *sqlite3.SQLiteConn from github.com/mattn/go-sqlite3.
+type SQLiteFuncRegisterer interface {
+	RegisterFunc(name string, impl any, pure bool) error
+}
+
+// RegisterSQLiteFunctions registers SQLiteFunctions on conn. With
+// github.com/mattn/go-sqlite3, call it from a ConnectHook so every
+// connection in the pool has them:
+//
+//	sql.Register("sqlite3_kuid", &sqlite3.SQLiteDriver{
+//	    ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+//	        return kuid.RegisterSQLiteFunctions(conn)
+//	    },
+//	})
+//	db, err := sql.Open("sqlite3_kuid", "app.db")
+func RegisterSQLiteFunctions(conn SQLiteFuncRegisterer) error {
+	for _, f := range SQLiteFunctions() {
+		call := f.Func
+		var impl any
+		if f.NArgs == 0 {
+			impl = func() (any, error) { return call(nil) }
+		} else {
+			impl = func(v any) (any, error) { return call([]driver.Value{v}) }
+		}
+		if err := conn.RegisterFunc(f.Name, impl, f.Deterministic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
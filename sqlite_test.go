@@ -0,0 +1,99 @@
+//go:build !tinygo
+
+package kuid
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestSQLiteFunctions(t *testing.T) {
+	id, _ := FromUUID("0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b")
+	funcs := make(map[string]SQLiteFunction)
+	for _, f := range SQLiteFunctions() {
+		funcs[f.Name] = f
+	}
+
+	tests := []struct {
+		fn      string
+		arg     driver.Value
+		want    driver.Value
+		wantErr bool
+	}{
+		{"kuid", id.Bytes(), id.String(), false},
+		{"kuid", id.ToUUID(), id.String(), false},
+		{"kuid", id.String(), id.String(), false},
+		{"kuid", nil, nil, false},
+		{"kuid", "bogus", nil, true},
+		{"kuid", []byte{1, 2, 3}, nil, true},
+		{"kuid", int64(7), nil, true},
+		{"kuid_to_uuid", id.String(), id.ToUUID(), false},
+		{"kuid_to_uuid", id.Bytes(), id.ToUUID(), false},
+		{"kuid_bytes", id.String(), id.Bytes(), false},
+		{"kuid_bytes", id.ToUUID(), id.Bytes(), false},
+	}
+	for _, tt := range tests {
+		got, err := funcs[tt.fn].Func([]driver.Value{tt.arg})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s(%v) error = %v, wantErr %v", tt.fn, tt.arg, err, tt.wantErr)
+			continue
+		}
+		if b, ok := tt.want.([]byte); ok {
+			if !bytes.Equal(got.([]byte), b) {
+				t.Errorf("%s(%v) = %x, want %x", tt.fn, tt.arg, got, b)
+			}
+		} else if got != tt.want {
+			t.Errorf("%s(%v) = %v, want %v", tt.fn, tt.arg, got, tt.want)
+		}
+	}
+
+	gen := funcs["kuid_generate"]
+	if gen.Deterministic || gen.NArgs != 0 {
+		t.Error("kuid_generate is registered as deterministic or with arguments")
+	}
+	v, err := gen.Func(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromString(v.(string)); err != nil {
+		t.Errorf("kuid_generate() = %v: %v", v, err)
+	}
+}
+
+type fakeRegisterer struct {
+	funcs map[string]any
+	pure  map[string]bool
+	err   error
+}
+
+func (r *fakeRegisterer) RegisterFunc(name string, impl any, pure bool) error {
+	r.funcs[name] = impl
+	r.pure[name] = pure
+	return r.err
+}
+
+func TestRegisterSQLiteFunctions(t *testing.T) {
+	r := &fakeRegisterer{funcs: map[string]any{}, pure: map[string]bool{}}
+	if err := RegisterSQLiteFunctions(r); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.funcs) != 4 || !r.pure["kuid"] || r.pure["kuid_generate"] {
+		t.Fatalf("registered %v, pure %v", r.funcs, r.pure)
+	}
+
+	id, _ := NewKUID()
+	got, err := r.funcs["kuid"].(func(any) (any, error))(id.ToUUID())
+	if err != nil || got != id.String() {
+		t.Errorf("kuid(%s) = %v, %v", id.ToUUID(), got, err)
+	}
+	if _, err := r.funcs["kuid_generate"].(func() (any, error))(); err != nil {
+		t.Error(err)
+	}
+
+	r.err = errors.New("registration failed")
+	if err := RegisterSQLiteFunctions(r); err != r.err {
+		t.Errorf("error = %v, want %v", err, r.err)
+	}
+}
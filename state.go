@@ -0,0 +1,147 @@
+package kuid
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is the ordered-generation state persisted across restarts. It records
+// the highest timestamp and sequence the Generator may have issued.
+type State struct {
+	Timestamp int64  `json:"timestamp"` // Unix milliseconds
+	Sequence  uint16 `json:"sequence"`
+}
+
+// Store persists Generator state. Load must return a zero State and no error
+// when nothing has been saved yet.
+type Store interface {
+	Load() (State, error)
+	Save(State) error
+}
+
+// FileStore is a Store keeping state in a JSON file. Writes go to a
+// temporary file that is synced and renamed over the original, so a crash
+// never leaves a partially written state behind.
+type FileStore struct {
+	Path string
+}
+
+// NewFileStore creates a FileStore writing to path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// Load reads the state file, returning a zero State if it does not exist
+func (f *FileStore) Load() (State, error) {
+	var st State
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// Save atomically replaces the state file
+func (f *FileStore) Save(st State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// WithStateStore persists ordered-generation state so that a restarted
+// Generator never issues KUIDs that sort before, or collide with, those
+// issued before the restart.
+//
+// With a zero reserve every NewOrdered call saves its exact state. A positive
+// reserve instead saves a timestamp that far ahead and only writes again once
+// the clock passes it; after a restart generation resumes at the reserved
+// timestamp. This trades a gap in the timeline for far fewer writes.
+func WithStateStore(store Store, reserve time.Duration) Option {
+	return func(c *config) error {
+		if reserve < 0 {
+			return errors.New("state reserve must not be negative")
+		}
+		c.store = store
+		c.reserve = reserve
+		c.stateEpoch++
+		return nil
+	}
+}
+
+// loadState restores persisted state on first use, and again whenever the
+// store is replaced. State already in memory is kept if it is ahead of the
+// store's. Callers must hold g.mu.
+func (g *Generator) loadState(c *config) error {
+	if g.stateEpoch != c.stateEpoch {
+		g.stateEpoch = c.stateEpoch
+		g.stateLoaded = false
+		g.reserved = 0
+	}
+	if c.store == nil || g.stateLoaded {
+		return nil
+	}
+	st, err := c.store.Load()
+	if err != nil {
+		return err
+	}
+	if st.Timestamp > maxTimestamp || st.Timestamp < 0 {
+		return errors.New("persisted state timestamp out of range")
+	}
+	if st.Timestamp > g.lastTimestamp || (st.Timestamp == g.lastTimestamp && st.Sequence > g.sequence) {
+		g.lastTimestamp = st.Timestamp
+		g.sequence = st.Sequence
+	}
+	g.reserved = st.Timestamp
+	g.stateLoaded = true
+	return nil
+}
+
+// saveState persists the current timestamp and sequence if they are not
+// already covered by a reservation. Callers must hold g.mu.
+func (g *Generator) saveState(c *config) error {
+	if c.store == nil {
+		return nil
+	}
+	if c.reserve == 0 {
+		return c.store.Save(State{Timestamp: g.lastTimestamp, Sequence: g.sequence})
+	}
+	if g.lastTimestamp <= g.reserved {
+		return nil
+	}
+
+	reserved := g.lastTimestamp + c.reserve.Milliseconds()
+	if err := c.store.Save(State{Timestamp: reserved, Sequence: maxSequence}); err != nil {
+		return err
+	}
+	g.reserved = reserved
+	return nil
+}
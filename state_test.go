@@ -0,0 +1,107 @@
+package kuid
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in-memory Store counting saves
+type memoryStore struct {
+	state State
+	saves int
+}
+
+func (m *memoryStore) Load() (State, error) {
+	return m.state, nil
+}
+
+func (m *memoryStore) Save(st State) error {
+	m.state = st
+	m.saves++
+	return nil
+}
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "kuid.state"))
+
+	st, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if st != (State{}) {
+		t.Errorf("Load() = %+v, want zero state", st)
+	}
+
+	want := State{Timestamp: 1700000000000, Sequence: 42}
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	st, err = store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if st != want {
+		t.Errorf("Load() = %+v, want %+v", st, want)
+	}
+}
+
+func TestGeneratorRestart(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "kuid.state"))
+
+	// Simulate a previous run that minted KUIDs ahead of the current clock
+	future := time.Now().Add(time.Hour).UnixMilli()
+	if err := store.Save(State{Timestamp: future, Sequence: 5}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	g, err := NewGenerator(WithStateStore(store, 0))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	kuid, err := g.NewOrdered()
+	if err != nil {
+		t.Fatalf("NewOrdered() error = %v", err)
+	}
+	if kuid.Timestamp().UnixMilli() != future || kuid.Sequence() != 6 {
+		t.Errorf("NewOrdered() = (%v, %v), want (%v, %v)",
+			kuid.Timestamp().UnixMilli(), kuid.Sequence(), future, 6)
+	}
+
+	st, _ := store.Load()
+	if st.Timestamp != future || st.Sequence != 6 {
+		t.Errorf("Persisted state = %+v, want (%v, %v)", st, future, 6)
+	}
+}
+
+func TestGeneratorStateReserve(t *testing.T) {
+	store := &memoryStore{}
+	g, err := NewGenerator(WithStateStore(store, time.Hour))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	var last *KUID
+	for i := 0; i < 1000; i++ {
+		if last, err = g.NewOrdered(); err != nil {
+			t.Fatalf("NewOrdered() error = %v", err)
+		}
+	}
+	if store.saves != 1 {
+		t.Errorf("Store saved %d times, want 1", store.saves)
+	}
+
+	// A restarted generator resumes after the reservation
+	reserved := store.state.Timestamp
+	restarted, _ := NewGenerator(WithStateStore(store, time.Hour))
+	kuid, err := restarted.NewOrdered()
+	if err != nil {
+		t.Fatalf("NewOrdered() error = %v", err)
+	}
+	if kuid.msb <= last.msb {
+		t.Errorf("KUID after restart sorts before KUIDs from the previous run")
+	}
+	if kuid.Timestamp().UnixMilli() != reserved+1 {
+		t.Errorf("Timestamp() after restart = %v, want %v", kuid.Timestamp().UnixMilli(), reserved+1)
+	}
+}
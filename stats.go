@@ -0,0 +1,34 @@
+package kuid
+
+import "sync/atomic"
+
+// Stats counts the KUIDs minted by a Generator in each mode
+type Stats struct {
+	Random  uint64 // KUIDs returned by New
+	Ordered uint64 // KUIDs returned by NewOrdered
+	HLC     uint64 // KUIDs minted by HLCs using the Generator
+	Blocked uint64 // candidates re-rolled because of the blocklist
+}
+
+// Total returns the number of KUIDs minted in all modes
+func (s Stats) Total() uint64 {
+	return s.Random + s.Ordered + s.HLC
+}
+
+// generatorStats tracks Stats for a Generator
+type generatorStats struct {
+	random  atomic.Uint64
+	ordered atomic.Uint64
+	hlc     atomic.Uint64
+	blocked atomic.Uint64
+}
+
+// Stats returns the number of KUIDs minted so far
+func (g *Generator) Stats() Stats {
+	return Stats{
+		Random:  g.counts.random.Load(),
+		Ordered: g.counts.ordered.Load(),
+		HLC:     g.counts.hlc.Load(),
+		Blocked: g.counts.blocked.Load(),
+	}
+}
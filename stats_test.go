@@ -0,0 +1,24 @@
+package kuid
+
+import "testing"
+
+func TestGeneratorStats(t *testing.T) {
+	g, _ := NewGenerator()
+	h, _ := NewHLC(g, 0)
+
+	for i := 0; i < 3; i++ {
+		g.New()
+	}
+	for i := 0; i < 2; i++ {
+		g.NewOrdered()
+	}
+	h.Now()
+
+	want := Stats{Random: 3, Ordered: 2, HLC: 1}
+	if got := g.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got := g.Stats().Total(); got != 6 {
+		t.Errorf("Stats().Total() = %v, want 6", got)
+	}
+}
@@ -0,0 +1,99 @@
+package kuid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ScanKUIDs is a bufio.SplitFunc returning the tokens of a stream delimited
+// by whitespace and commas, such as ad-hoc ID dumps. Tokens are not
+// validated; pass them to Parse.
+func ScanKUIDs(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) {
+		r, width := utf8.DecodeRune(data[start:])
+		if !isDelimiter(r) {
+			break
+		}
+		start += width
+	}
+	for i := start; i < len(data); {
+		r, width := utf8.DecodeRune(data[i:])
+		if isDelimiter(r) {
+			return i + width, data[start:i], nil
+		}
+		i += width
+	}
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+func isDelimiter(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// DecodeError reports a token that is not a KUID, with its byte offset in
+// the stream
+type DecodeError struct {
+	Offset int64
+	Token  string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("offset %d: invalid KUID %q: %v", e.Offset, e.Token, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads KUIDs, in base62 or UUID form, from a stream delimited by
+// whitespace and commas
+type Decoder struct {
+	scanner *bufio.Scanner
+	offset  int64 // bytes consumed by the scanner
+	start   int64 // offset of the last token
+}
+
+// NewDecoder returns a Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	d := &Decoder{scanner: bufio.NewScanner(r)}
+	d.scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := ScanKUIDs(data, atEOF)
+		if token != nil {
+			d.start = d.offset + int64(cap(data)-cap(token))
+		}
+		d.offset += int64(advance)
+		return advance, token, err
+	})
+	return d
+}
+
+// Decode returns the next KUID, or io.EOF at the end of the stream. A
+// malformed token is returned as a *DecodeError; decoding can continue with
+// the next token.
+func (d *Decoder) Decode() (KUID, error) {
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			return KUID{}, err
+		}
+		return KUID{}, io.EOF
+	}
+	token := d.scanner.Text()
+	k, err := Parse(token)
+	if err != nil {
+		return KUID{}, &DecodeError{Offset: d.start, Token: token, Err: err.(*ParseError).Err}
+	}
+	return *k, nil
+}
+
+// InputOffset returns the byte offset of the token last decoded
+func (d *Decoder) InputOffset() int64 {
+	return d.start
+}
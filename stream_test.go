@@ -0,0 +1,67 @@
+package kuid
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanKUIDs(t *testing.T) {
+	s := bufio.NewScanner(strings.NewReader(" a,b\tc\n\n,,d e f,"))
+	s.Split(ScanKUIDs)
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if want := []string{"a", "b", "c", "d", "e", "f"}; !slices.Equal(got, want) {
+		t.Errorf("tokens = %q, want %q", got, want)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	a, _ := NewKUID()
+	b, _ := NewKUID()
+	input := a.String() + ",\n  " + b.ToUUID() + ", bogus\n" + a.String()
+
+	// One byte at a time exercises tokens split across reads
+	d := NewDecoder(iotest.OneByteReader(strings.NewReader(input)))
+
+	if k, err := d.Decode(); err != nil || k != *a {
+		t.Fatalf("Decode() = %v, %v, want %v", k, err, a)
+	}
+	if k, err := d.Decode(); err != nil || k != *b {
+		t.Fatalf("Decode() = %v, %v, want %v", k, err, b)
+	}
+	if d.InputOffset() != 26 {
+		t.Errorf("InputOffset() = %d, want 26", d.InputOffset())
+	}
+
+	_, err := d.Decode()
+	var derr *DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("Decode() error = %v, want a DecodeError", err)
+	}
+	if want := int64(strings.Index(input, "bogus")); derr.Offset != want || derr.Token != "bogus" || !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("DecodeError = %+v, want offset %d", derr, want)
+	}
+
+	// Decoding continues past the bad token
+	if k, err := d.Decode(); err != nil || k != *a {
+		t.Fatalf("Decode() after error = %v, %v, want %v", k, err, a)
+	}
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Decode() at end error = %v, want EOF", err)
+	}
+}
+
+func TestDecoderReadError(t *testing.T) {
+	boom := errors.New("boom")
+	d := NewDecoder(iotest.ErrReader(boom))
+	if _, err := d.Decode(); err != boom {
+		t.Errorf("Decode() error = %v, want %v", err, boom)
+	}
+}
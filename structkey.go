@@ -0,0 +1,212 @@
+package kuid
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// StructOptions controls how FromStruct serializes values
+type StructOptions struct {
+	// Tag is the struct tag naming fields, "json" if empty. Fields tagged
+	// "-" are skipped.
+	Tag string
+	// OmitZero skips zero-valued fields, so adding a field to a struct
+	// leaves the KUIDs of values that do not set it unchanged
+	OmitZero bool
+}
+
+// FromStruct returns a deterministic KUID for v, for content-addressed
+// config and cache keys. v is serialized with fields in name order, map
+// entries in key order and every value tagged with its kind and length, so
+// equal values always hash alike and different values cannot run into each
+// other. Signed and unsigned integers hash by value, not by Go type, so
+// widening a field keeps its keys. Types implementing
+// encoding.TextMarshaler, such as KUID and time.Time, hash their text
+// form. Unexported fields are skipped, and embedded structs are flattened
+// as in encoding/json. Channels, functions, complex numbers and NaN are
+// rejected.
+func FromStruct(v any, opts StructOptions) (*KUID, error) {
+	if opts.Tag == "" {
+		opts.Tag = "json"
+	}
+	h := sha256.New()
+	if err := opts.write(h, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return SumKUID(h)
+}
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// write serializes v to h
+func (o *StructOptions) write(w io.Writer, v reflect.Value) error {
+	if !v.IsValid() {
+		w.Write([]byte{'n'})
+		return nil
+	}
+	if v.Type().Implements(textMarshalerType) && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		writeTagged(w, 't', text)
+		return nil
+	}
+
+	var num [8]byte
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			w.Write([]byte{'n'})
+			return nil
+		}
+		return o.write(w, v.Elem())
+	case reflect.Bool:
+		b := byte('F')
+		if v.Bool() {
+			b = 'T'
+		}
+		w.Write([]byte{b})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Negative values get their own tag so they never match a uint
+		tag := byte('i')
+		if v.Int() < 0 {
+			tag = '-'
+		}
+		binary.BigEndian.PutUint64(num[:], uint64(v.Int()))
+		writeTagged(w, tag, num[:])
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.BigEndian.PutUint64(num[:], v.Uint())
+		writeTagged(w, 'i', num[:])
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) {
+			return fmt.Errorf("cannot hash NaN")
+		}
+		if f == 0 {
+			f = 0 // -0 hashes as 0
+		}
+		binary.BigEndian.PutUint64(num[:], math.Float64bits(f))
+		writeTagged(w, 'f', num[:])
+	case reflect.String:
+		writeTagged(w, 's', []byte(v.String()))
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			writeTagged(w, 's', v.Bytes())
+			break
+		}
+		writeLen(w, 'l', v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := o.write(w, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		return o.writeMap(w, v)
+	case reflect.Struct:
+		return o.writeStruct(w, v)
+	default:
+		return fmt.Errorf("cannot hash %s", v.Type())
+	}
+	return nil
+}
+
+// writeMap serializes map entries sorted by their serialized keys
+func (o *StructOptions) writeMap(w io.Writer, v reflect.Value) error {
+	type entry struct{ key, value []byte }
+	entries := make([]entry, 0, v.Len())
+	for it := v.MapRange(); it.Next(); {
+		var key, value bytes.Buffer
+		if err := o.write(&key, it.Key()); err != nil {
+			return err
+		}
+		if err := o.write(&value, it.Value()); err != nil {
+			return err
+		}
+		entries = append(entries, entry{key.Bytes(), value.Bytes()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	writeLen(w, 'm', len(entries))
+	for _, e := range entries {
+		w.Write(e.key)
+		w.Write(e.value)
+	}
+	return nil
+}
+
+// writeStruct serializes fields sorted by name
+func (o *StructOptions) writeStruct(w io.Writer, v reflect.Value) error {
+	type field struct {
+		name  string
+		value reflect.Value
+	}
+	var fields []field
+	var collect func(v reflect.Value)
+	collect = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			name, _, _ := strings.Cut(sf.Tag.Get(o.Tag), ",")
+			if name == "-" {
+				continue
+			}
+			fv := v.Field(i)
+			if sf.Anonymous && name == "" {
+				if fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				if fv.Kind() == reflect.Struct && !fv.Type().Implements(textMarshalerType) {
+					collect(fv)
+					continue
+				}
+			}
+			if !sf.IsExported() || o.OmitZero && fv.IsZero() {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fields = append(fields, field{name, fv})
+		}
+	}
+	collect(v)
+	slices.SortStableFunc(fields, func(a, b field) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	writeLen(w, 'S', len(fields))
+	for _, f := range fields {
+		writeTagged(w, 's', []byte(f.name))
+		if err := o.write(w, f.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTagged writes a kind tag, length and data
+func writeTagged(w io.Writer, tag byte, data []byte) {
+	writeLen(w, tag, len(data))
+	w.Write(data)
+}
+
+// writeLen writes a kind tag and length
+func writeLen(w io.Writer, tag byte, n int) {
+	var buf [9]byte
+	buf[0] = tag
+	binary.BigEndian.PutUint64(buf[1:], uint64(n))
+	w.Write(buf[:])
+}
@@ -0,0 +1,115 @@
+package kuid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type structKeyBase struct {
+	Region string `json:"region"`
+}
+
+type structKeyParams struct {
+	structKeyBase
+	User    string            `json:"user"`
+	Page    int               `json:"page"`
+	Filters map[string]string `json:"filters"`
+	Since   time.Time         `json:"since"`
+	Debug   bool              `json:"-"`
+	secret  string
+}
+
+func TestFromStruct(t *testing.T) {
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	base := structKeyParams{
+		structKeyBase: structKeyBase{Region: "eu"},
+		User:          "alice",
+		Page:          2,
+		Filters:       map[string]string{"a": "1", "b": "2", "c": "3"},
+		Since:         since,
+	}
+	want, err := FromStruct(base, StructOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same := []any{
+		&base,
+		func() structKeyParams { p := base; p.Debug = true; p.secret = "x"; return p }(),
+		// Fields hash by name, not declaration order or Go type
+		struct {
+			Since   time.Time         `json:"since"`
+			Filters map[string]string `json:"filters"`
+			Page    int64             `json:"page"`
+			User    string            `json:"user"`
+			Region  string            `json:"region"`
+		}{since, map[string]string{"c": "3", "b": "2", "a": "1"}, 2, "alice", "eu"},
+	}
+	for i, v := range same {
+		if got, err := FromStruct(v, StructOptions{}); err != nil || !got.Equal(want) {
+			t.Errorf("value %d: FromStruct() = %v, %v, want %v", i, got, err, want)
+		}
+	}
+
+	different := []structKeyParams{
+		{structKeyBase: base.structKeyBase, User: "alice", Page: 3, Filters: base.Filters, Since: since},
+		{structKeyBase: structKeyBase{Region: "us"}, User: "alice", Page: 2, Filters: base.Filters, Since: since},
+		{structKeyBase: base.structKeyBase, User: "alice", Page: 2, Filters: map[string]string{"a": "12"}, Since: since},
+		{structKeyBase: base.structKeyBase, User: "alice", Page: 2, Filters: map[string]string{"a1": "2"}, Since: since},
+	}
+	for i, v := range different {
+		if got, _ := FromStruct(v, StructOptions{}); got.Equal(want) {
+			t.Errorf("value %d: FromStruct() collides", i)
+		}
+	}
+}
+
+func TestFromStructOptions(t *testing.T) {
+	type v1 struct {
+		Name string `key:"n"`
+	}
+	type v2 struct {
+		Name  string `key:"n"`
+		Limit int    `key:"l"`
+	}
+
+	a, _ := FromStruct(v1{Name: "x"}, StructOptions{Tag: "key", OmitZero: true})
+	b, _ := FromStruct(v2{Name: "x"}, StructOptions{Tag: "key", OmitZero: true})
+	if !a.Equal(b) {
+		t.Errorf("OmitZero: adding a zero field changed the KUID")
+	}
+	c, _ := FromStruct(v2{Name: "x"}, StructOptions{Tag: "key"})
+	if a.Equal(c) {
+		t.Errorf("zero field ignored without OmitZero")
+	}
+}
+
+func TestFromStructValues(t *testing.T) {
+	pairs := [][2]any{
+		{int8(-1), uint64(math.MaxUint64)},
+		{"ab", []string{"a", "b"}},
+		{[]string{"ab", "c"}, []string{"a", "bc"}},
+		{nil, ""},
+		{0.0, 0},
+		{true, 1},
+	}
+	for _, p := range pairs {
+		a, _ := FromStruct(p[0], StructOptions{})
+		b, _ := FromStruct(p[1], StructOptions{})
+		if a.Equal(b) {
+			t.Errorf("FromStruct(%#v) == FromStruct(%#v)", p[0], p[1])
+		}
+	}
+
+	zero, _ := FromStruct(0.0, StructOptions{})
+	if negZero, _ := FromStruct(math.Copysign(0, -1), StructOptions{}); !negZero.Equal(zero) {
+		t.Errorf("-0 and 0 hash differently")
+	}
+
+	for _, v := range []any{make(chan int), func() {}, complex(1, 2), math.NaN(), map[string]any{"f": func() {}}} {
+		if _, err := FromStruct(v, StructOptions{}); err == nil {
+			t.Errorf("FromStruct(%T) expected error", v)
+		}
+	}
+}
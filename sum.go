@@ -0,0 +1,25 @@
+package kuid
+
+import (
+	"errors"
+	"hash"
+)
+
+var ErrShortHash = errors.New("hash sum shorter than 16 bytes")
+
+// SumKUID finalizes h into a KUID made of the first 16 bytes of its sum.
+// It does not reset h.
+//
+// Truncating a cryptographic hash such as SHA-256 or BLAKE2b to 128 bits
+// leaves about 2^64 work to find a collision, ample for fingerprints and
+// dedup keys but not for signatures. Non-cryptographic 128-bit hashes such
+// as FNV-128 are fine for trusted input only, since collisions can be
+// crafted. Hashes with sums under 16 bytes, such as FNV-64 or CRC-32, are
+// rejected with ErrShortHash rather than padded.
+func SumKUID(h hash.Hash) (*KUID, error) {
+	if h.Size() < 16 {
+		return nil, ErrShortHash
+	}
+	var buf [64]byte
+	return FromBytes(h.Sum(buf[:0])[:16])
+}
@@ -0,0 +1,38 @@
+package kuid
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash/fnv"
+	"testing"
+)
+
+func TestSumKUID(t *testing.T) {
+	h := sha256.New()
+	h.Write([]byte("fingerprint"))
+	k, err := SumKUID(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("fingerprint"))
+	want, _ := FromBytes(sum[:16])
+	if !k.Equal(want) {
+		t.Errorf("SumKUID() = %v, want %v", k, want)
+	}
+
+	// The hash keeps its state
+	h.Write([]byte("more"))
+	if again, _ := SumKUID(h); again.Equal(k) {
+		t.Errorf("SumKUID() reset the hash")
+	}
+
+	if _, err := SumKUID(sha512.New()); err != nil {
+		t.Errorf("SumKUID(sha512) error = %v", err)
+	}
+	if _, err := SumKUID(fnv.New128a()); err != nil {
+		t.Errorf("SumKUID(fnv128a) error = %v", err)
+	}
+	if _, err := SumKUID(fnv.New64a()); err != ErrShortHash {
+		t.Errorf("SumKUID(fnv64a) error = %v, want %v", err, ErrShortHash)
+	}
+}
@@ -0,0 +1,62 @@
+package kuid
+
+import "fmt"
+
+// shortLength is the length of kuidShort output, like a short git hash
+const shortLength = 8
+
+// TemplateFuncs returns functions for text/template and html/template:
+//
+//	kuidNew        a new random KUID
+//	kuidShort      the first 8 characters, for display only
+//	kuidToUUID     the hyphenated UUID form
+//	kuidRedact     the Redacted form
+//
+// The formatting functions take a KUID, *KUID or string in either form:
+//
+//	tmpl := template.New("email").Funcs(kuid.TemplateFuncs())
+//	{{ .OrderID | kuidShort }}
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"kuidNew": func() (KUID, error) {
+			return NewValue()
+		},
+		"kuidShort": func(v any) (string, error) {
+			k, err := templateKUID(v)
+			if err != nil {
+				return "", err
+			}
+			return k.String()[:shortLength], nil
+		},
+		"kuidToUUID": func(v any) (string, error) {
+			k, err := templateKUID(v)
+			if err != nil {
+				return "", err
+			}
+			return k.ToUUID(), nil
+		},
+		"kuidRedact": func(v any) (string, error) {
+			k, err := templateKUID(v)
+			if err != nil {
+				return "", err
+			}
+			return k.Redacted(), nil
+		},
+	}
+}
+
+// templateKUID converts a template argument to a KUID
+func templateKUID(v any) (*KUID, error) {
+	switch v := v.(type) {
+	case KUID:
+		return &v, nil
+	case *KUID:
+		if v == nil {
+			return nil, fmt.Errorf("kuid: nil KUID")
+		}
+		return v, nil
+	case string:
+		return Parse(v)
+	}
+	return nil, fmt.Errorf("kuid: cannot format %T as a KUID", v)
+}
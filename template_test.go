@@ -0,0 +1,62 @@
+package kuid
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	k, _ := NewKUID()
+	tests := []struct {
+		name string
+		tmpl string
+		data any
+		want string
+	}{
+		{name: "Short", tmpl: `{{ kuidShort . }}`, data: *k, want: k.String()[:8]},
+		{name: "UUID from pointer", tmpl: `{{ kuidToUUID . }}`, data: k, want: k.ToUUID()},
+		{name: "Redact from string", tmpl: `{{ . | kuidRedact }}`, data: k.ToUUID(), want: k.Redacted()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(tt.tmpl))
+			if err := tmpl.Execute(&out, tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+
+	var out strings.Builder
+	tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(`{{ kuidNew }}`))
+	if err := tmpl.Execute(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromString(out.String()); err != nil {
+		t.Errorf("kuidNew = %q: %v", out.String(), err)
+	}
+
+	for _, data := range []any{"not an id", 42, (*KUID)(nil)} {
+		tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(`{{ kuidShort . }}`))
+		if err := tmpl.Execute(&out, data); err == nil {
+			t.Errorf("kuidShort %#v expected error", data)
+		}
+	}
+}
+
+func TestTemplateFuncsHTML(t *testing.T) {
+	k, _ := NewKUID()
+	var out strings.Builder
+	tmpl := htmltemplate.Must(htmltemplate.New("t").Funcs(TemplateFuncs()).Parse(`<a href="/orders/{{ kuidToUUID . }}">{{ kuidShort . }}</a>`))
+	if err := tmpl.Execute(&out, k); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<a href="/orders/` + k.ToUUID() + `">` + k.String()[:8] + `</a>`; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
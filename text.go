@@ -0,0 +1,19 @@
+package kuid
+
+// MarshalText implements encoding.TextMarshaler, encoding KUIDs as their
+// base62 string. This also makes encoding/json and other text-based
+// encoders use the string form.
+func (k KUID) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting only the
+// canonical base62 form
+func (k *KUID) UnmarshalText(text []byte) error {
+	parsed, err := FromString(string(text))
+	if err != nil {
+		return err
+	}
+	*k = *parsed
+	return nil
+}
@@ -0,0 +1,36 @@
+package kuid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTextMarshaling(t *testing.T) {
+	kuid, _ := NewKUID()
+
+	type record struct {
+		ID  KUID  `json:"id"`
+		Ptr *KUID `json:"ptr"`
+	}
+	data, err := json.Marshal(record{ID: *kuid, Ptr: kuid})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"id":"` + kuid.String() + `","ptr":"` + kuid.String() + `"}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded record
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !decoded.ID.Equal(kuid) || !decoded.Ptr.Equal(kuid) {
+		t.Errorf("Unmarshal() = %+v, want %v", decoded, kuid)
+	}
+
+	// UUIDs are not accepted by the strict text form
+	if err := json.Unmarshal([]byte(`{"id":"`+kuid.ToUUID()+`"}`), &decoded); err == nil {
+		t.Errorf("Unmarshal() expected error for UUID form")
+	}
+}
@@ -0,0 +1,175 @@
+package kuid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Topology describes how ordered KUIDs are minted across a multi-region
+// deployment. Every region loads the same Topology so their KUIDs share a
+// layout and sort together:
+//
+//	msb: 48-bit milliseconds since Epoch | node | sequence
+//	lsb: 64 random bits
+//
+// NodeBits and SequenceBits split the 16 bits below the timestamp. Each
+// region owns a disjoint range of node IDs, so two nodes can never mint
+// the same timestamp, node and sequence. A zero Epoch means the Unix epoch.
+type Topology struct {
+	Epoch        time.Time          `json:"epoch"`
+	NodeBits     int                `json:"node_bits"`
+	SequenceBits int                `json:"sequence_bits"`
+	Regions      []RegionAllocation `json:"regions"`
+}
+
+// RegionAllocation assigns the node IDs FirstNode to LastNode inclusive to
+// a region
+type RegionAllocation struct {
+	Name      string `json:"name"`
+	FirstNode uint64 `json:"first_node"`
+	LastNode  uint64 `json:"last_node"`
+}
+
+// LoadTopology decodes and validates a JSON Topology
+func LoadTopology(r io.Reader) (*Topology, error) {
+	var t Topology
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Validate checks that the layout fits and that no two regions share node
+// IDs
+func (t *Topology) Validate() error {
+	if t.NodeBits < 1 || t.SequenceBits < 1 || t.NodeBits+t.SequenceBits != sequenceBits {
+		return fmt.Errorf("node and sequence bits must both be positive and total %d", sequenceBits)
+	}
+	if t.Epoch.After(time.Now()) {
+		return errors.New("epoch is in the future")
+	}
+	if t.epochMilli() < 0 {
+		return errors.New("epoch is before 1970")
+	}
+
+	names := make(map[string]bool, len(t.Regions))
+	for i, r := range t.Regions {
+		if r.Name == "" {
+			return fmt.Errorf("region %d has no name", i)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("region %q allocated twice", r.Name)
+		}
+		names[r.Name] = true
+		if r.FirstNode > r.LastNode || r.LastNode>>t.NodeBits != 0 {
+			return fmt.Errorf("region %q: nodes %d-%d do not fit in %d bits", r.Name, r.FirstNode, r.LastNode, t.NodeBits)
+		}
+		for _, other := range t.Regions[:i] {
+			if r.FirstNode <= other.LastNode && other.FirstNode <= r.LastNode {
+				return fmt.Errorf("region %q: nodes %d-%d overlap region %q", r.Name, r.FirstNode, r.LastNode, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// epochMilli returns the epoch in Unix milliseconds
+func (t *Topology) epochMilli() int64 {
+	if t.Epoch.IsZero() {
+		return 0
+	}
+	return t.Epoch.UnixMilli()
+}
+
+// region returns the allocation for name
+func (t *Topology) region(name string) (RegionAllocation, bool) {
+	for _, r := range t.Regions {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return RegionAllocation{}, false
+}
+
+// WithTopology makes NewOrdered mint KUIDs in t's layout as the given node,
+// which must lie in region's allocation
+func WithTopology(t *Topology, region string, node uint64) Option {
+	return func(c *config) error {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		r, ok := t.region(region)
+		if !ok {
+			return fmt.Errorf("region %q not in topology", region)
+		}
+		if node < r.FirstNode || node > r.LastNode {
+			return fmt.Errorf("node %d outside region %q (%d-%d)", node, region, r.FirstNode, r.LastNode)
+		}
+		c.topology = t
+		c.node = node
+		c.nodeErr = nil
+		return nil
+	}
+}
+
+// withoutNode makes NewOrdered fail with err until a node ID is assigned
+// again with WithTopology
+func withoutNode(err error) Option {
+	return func(c *config) error {
+		c.nodeErr = err
+		return nil
+	}
+}
+
+// maxSequence returns the largest per-millisecond sequence NewOrdered
+// may use
+func (c *config) maxSequence() uint16 {
+	if c.topology == nil {
+		return maxSequence
+	}
+	return 1<<c.topology.SequenceBits - 1
+}
+
+// orderedMSB lays out a Unix millisecond timestamp and sequence
+func (c *config) orderedMSB(unixMilli int64, seq uint16) uint64 {
+	t := c.topology
+	if t == nil {
+		return uint64(unixMilli)<<sequenceBits | uint64(seq)
+	}
+	since := uint64(unixMilli - t.epochMilli())
+	return since<<sequenceBits | c.node<<t.SequenceBits | uint64(seq)
+}
+
+// Timestamp returns the creation time of a KUID minted with t
+func (t *Topology) Timestamp(k *KUID) time.Time {
+	return time.UnixMilli(t.epochMilli() + int64(k.msb>>sequenceBits))
+}
+
+// Node returns the node that minted a KUID with t
+func (t *Topology) Node(k *KUID) uint64 {
+	return k.msb & (1<<sequenceBits - 1) >> t.SequenceBits
+}
+
+// Sequence returns the per-millisecond sequence of a KUID minted with t
+func (t *Topology) Sequence(k *KUID) uint16 {
+	return uint16(k.msb & (1<<t.SequenceBits - 1))
+}
+
+// Region returns the region whose nodes minted a KUID with t
+func (t *Topology) Region(k *KUID) (string, bool) {
+	node := t.Node(k)
+	for _, r := range t.Regions {
+		if node >= r.FirstNode && node <= r.LastNode {
+			return r.Name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,103 @@
+package kuid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTopology = `{
+	"epoch": "2024-01-01T00:00:00Z",
+	"node_bits": 6,
+	"sequence_bits": 10,
+	"regions": [
+		{"name": "eu-west", "first_node": 0, "last_node": 15},
+		{"name": "us-east", "first_node": 16, "last_node": 31}
+	]
+}`
+
+func TestTopology(t *testing.T) {
+	topo, err := LoadTopology(strings.NewReader(testTopology))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eu, err := NewGenerator(WithTopology(topo, "eu-west", 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	us, _ := NewGenerator(WithTopology(topo, "us-east", 20))
+
+	before := time.Now().Truncate(time.Millisecond)
+	var prev *KUID
+	for i := 0; i < 3000; i++ {
+		k, err := eu.NewOrdered()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != nil && bytes.Compare(k.Bytes(), prev.Bytes()) <= 0 {
+			t.Fatalf("KUID %d not ordered", i)
+		}
+		if topo.Node(k) != 3 {
+			t.Fatalf("Node() = %d, want 3", topo.Node(k))
+		}
+		prev = k
+	}
+
+	k, _ := us.NewOrdered()
+	if region, ok := topo.Region(k); region != "us-east" || !ok {
+		t.Errorf("Region() = %q, %v, want us-east", region, ok)
+	}
+	if ts := topo.Timestamp(k); ts.Before(before) || ts.After(time.Now().Add(time.Second)) {
+		t.Errorf("Timestamp() = %v, want about %v", ts, before)
+	}
+	// KUID.Timestamp counts from the Unix epoch, so under this 2024 epoch
+	// it is off by exactly the epoch
+	if got, want := k.Timestamp(), topo.Timestamp(k).Add(-time.Duration(topo.Epoch.UnixMilli())*time.Millisecond); !got.Equal(want) {
+		t.Errorf("KUID.Timestamp() = %v, want %v", got, want)
+	}
+	if topo.Sequence(k) != 0 {
+		t.Errorf("Sequence() = %d, want 0 for first KUID", topo.Sequence(k))
+	}
+}
+
+func TestTopologyValidate(t *testing.T) {
+	regions := func(r ...RegionAllocation) []RegionAllocation { return r }
+	tests := []struct {
+		name string
+		topo Topology
+	}{
+		{"Bits do not total 16", Topology{NodeBits: 4, SequenceBits: 4}},
+		{"No node bits", Topology{NodeBits: 0, SequenceBits: 16}},
+		{"Future epoch", Topology{Epoch: time.Now().Add(time.Hour), NodeBits: 8, SequenceBits: 8}},
+		{"Nodes overflow", Topology{NodeBits: 4, SequenceBits: 12, Regions: regions(
+			RegionAllocation{Name: "a", FirstNode: 0, LastNode: 16})}},
+		{"Overlap", Topology{NodeBits: 4, SequenceBits: 12, Regions: regions(
+			RegionAllocation{Name: "a", FirstNode: 0, LastNode: 7},
+			RegionAllocation{Name: "b", FirstNode: 7, LastNode: 15})}},
+		{"Duplicate name", Topology{NodeBits: 4, SequenceBits: 12, Regions: regions(
+			RegionAllocation{Name: "a", FirstNode: 0, LastNode: 7},
+			RegionAllocation{Name: "a", FirstNode: 8, LastNode: 15})}},
+		{"Reversed range", Topology{NodeBits: 4, SequenceBits: 12, Regions: regions(
+			RegionAllocation{Name: "a", FirstNode: 5, LastNode: 2})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.topo.Validate(); err == nil {
+				t.Errorf("Validate() succeeded")
+			}
+		})
+	}
+
+	topo, _ := LoadTopology(strings.NewReader(testTopology))
+	if _, err := NewGenerator(WithTopology(topo, "eu-west", 16)); err == nil {
+		t.Errorf("Node outside region accepted")
+	}
+	if _, err := NewGenerator(WithTopology(topo, "ap-south", 0)); err == nil {
+		t.Errorf("Unknown region accepted")
+	}
+	if _, err := LoadTopology(strings.NewReader(`{"node_bits": 8, "sequence_bits": 8, "nodes": 3}`)); err == nil {
+		t.Errorf("Unknown JSON field accepted")
+	}
+}
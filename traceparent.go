@@ -0,0 +1,92 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+// TraceparentHeader is the W3C Trace Context header
+const TraceparentHeader = "traceparent"
+
+// TraceFlagSampled is the W3C trace flag marking a sampled trace
+const TraceFlagSampled byte = 0x01
+
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+// traceparentSize is the length of a version 00 traceparent:
+// "00-" + 32 hex trace ID + "-" + 16 hex span ID + "-" + 2 hex flags
+const traceparentSize = 55
+
+// BuildTraceparent formats a W3C traceparent header value using k as the
+// trace ID, so a request's KUID and its trace line up in logs and tracing
+// backends. A zero KUID or span ID yields a header receivers will reject.
+func BuildTraceparent(k KUID, spanID [8]byte, flags byte) string {
+	b := make([]byte, 0, traceparentSize)
+	b = append(b, "00-"...)
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], k.msb)
+	binary.BigEndian.PutUint64(id[8:], k.lsb)
+	b = hex.AppendEncode(b, id[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, spanID[:])
+	b = append(b, '-')
+	b = hex.AppendEncode(b, []byte{flags})
+	return string(b)
+}
+
+// ParseTraceparent returns the trace ID, as a KUID, the parent span ID and
+// the flags of a W3C traceparent header value. Headers from future versions
+// are accepted as long as they start with the version 00 fields.
+func ParseTraceparent(s string) (KUID, [8]byte, byte, error) {
+	var span [8]byte
+	if len(s) < traceparentSize || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return KUID{}, span, 0, ErrInvalidTraceparent
+	}
+	version, ok := lowerHex(s[:2])
+	if !ok || version[0] == 0xff || (version[0] == 0 && len(s) != traceparentSize) ||
+		(len(s) > traceparentSize && s[traceparentSize] != '-') {
+		return KUID{}, span, 0, ErrInvalidTraceparent
+	}
+	trace, ok := lowerHex(s[3:35])
+	if !ok {
+		return KUID{}, span, 0, ErrInvalidTraceparent
+	}
+	parent, ok := lowerHex(s[36:52])
+	if !ok {
+		return KUID{}, span, 0, ErrInvalidTraceparent
+	}
+	flags, ok := lowerHex(s[53:55])
+	if !ok {
+		return KUID{}, span, 0, ErrInvalidTraceparent
+	}
+
+	k := KUID{msb: binary.BigEndian.Uint64(trace[:8]), lsb: binary.BigEndian.Uint64(trace[8:])}
+	copy(span[:], parent)
+	if k == (KUID{}) || span == ([8]byte{}) {
+		return KUID{}, [8]byte{}, 0, ErrInvalidTraceparent
+	}
+	return k, span, flags[0], nil
+}
+
+// lowerHex decodes s, which the W3C format requires to be lowercase
+func lowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// NewSpanID returns a random, non-zero span ID
+func NewSpanID() ([8]byte, error) {
+	var id [8]byte
+	for id == ([8]byte{}) {
+		if err := readRandom(id[:]); err != nil {
+			return id, err
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,68 @@
+package kuid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildTraceparent(t *testing.T) {
+	k, _ := FromUUID("4bf92f35-77b3-4da6-a3ce-929d0e0e4736")
+	span := [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+
+	got := BuildTraceparent(*k, span, TraceFlagSampled)
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got != want {
+		t.Fatalf("BuildTraceparent() = %v, want %v", got, want)
+	}
+
+	trace, parent, flags, err := ParseTraceparent(got)
+	if err != nil {
+		t.Fatalf("ParseTraceparent() error = %v", err)
+	}
+	if trace != *k || parent != span || flags != TraceFlagSampled {
+		t.Errorf("ParseTraceparent() = %v, %x, %x", trace.ToUUID(), parent, flags)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	valid := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"Valid", valid, false},
+		{"Future version with extra fields", "01" + valid[2:] + "-what-the-future-holds", false},
+		{"Version 00 with extra fields", valid + "-extra", true},
+		{"Forbidden version", "ff" + valid[2:], true},
+		{"Uppercase hex", strings.ToUpper(valid), true},
+		{"Zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", true},
+		{"Zero span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", true},
+		{"Bad separator", strings.Replace(valid, "-", "_", 1), true},
+		{"Too short", valid[:54], true},
+		{"Empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := ParseTraceparent(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTraceparent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err != ErrInvalidTraceparent {
+				t.Errorf("ParseTraceparent() error = %v, want %v", err, ErrInvalidTraceparent)
+			}
+		})
+	}
+}
+
+func TestNewSpanID(t *testing.T) {
+	a, err := NewSpanID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewSpanID()
+	if a == ([8]byte{}) || a == b {
+		t.Errorf("NewSpanID() = %x, %x", a, b)
+	}
+}
@@ -0,0 +1,142 @@
+package kuid
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrUnknownType = errors.New("unknown KUID type")
+
+// defaultTypes is the registry consulted by KUID.Type
+var defaultTypes atomic.Pointer[TypeRegistry]
+
+// TypeRegistry maps entity type tags to names. Tags are stored in the
+// least significant bits of a KUID, so any KUID minted by a Generator
+// configured with WithType can be classified from its value alone. Those
+// bits are then the same for every KUID of a type; Partition and the
+// sharded helpers hash both halves, so typed KUIDs still spread evenly.
+type TypeRegistry struct {
+	bits int
+
+	mu    sync.RWMutex
+	names map[uint64]string
+	tags  map[string]uint64
+}
+
+// NewTypeRegistry creates a registry for tags of the given width, between
+// 1 and 16 bits. Each tag bit halves the randomness left in a KUID's
+// lower half.
+func NewTypeRegistry(bits int) (*TypeRegistry, error) {
+	if bits < 1 || bits > 16 {
+		return nil, errors.New("type tag bits must be between 1 and 16")
+	}
+	return &TypeRegistry{
+		bits:  bits,
+		names: make(map[uint64]string),
+		tags:  make(map[string]uint64),
+	}, nil
+}
+
+// Register assigns tag to the named type. Neither may already be in use.
+func (r *TypeRegistry) Register(name string, tag uint64) error {
+	if tag>>r.bits != 0 {
+		return fmt.Errorf("type %q: tag %d: %w", name, tag, ErrOverflow)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if other, ok := r.names[tag]; ok {
+		return fmt.Errorf("type %q: tag %d already used by %q", name, tag, other)
+	}
+	if _, ok := r.tags[name]; ok {
+		return fmt.Errorf("type %q already registered", name)
+	}
+	r.names[tag] = name
+	r.tags[name] = tag
+	return nil
+}
+
+// Bits returns the tag width
+func (r *TypeRegistry) Bits() int {
+	return r.bits
+}
+
+// Tag returns the raw type tag of a KUID
+func (r *TypeRegistry) Tag(k *KUID) uint64 {
+	return k.lsb & (1<<r.bits - 1)
+}
+
+// TypeOf returns the registered name of a KUID's type tag
+func (r *TypeRegistry) TypeOf(k *KUID) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.names[r.Tag(k)]
+	if !ok {
+		return "", ErrUnknownType
+	}
+	return name, nil
+}
+
+// SetTypeRegistry sets the registry used by KUID.Type
+func SetTypeRegistry(r *TypeRegistry) {
+	defaultTypes.Store(r)
+}
+
+// Type returns the entity type of the KUID according to the registry set
+// with SetTypeRegistry. A KUID minted without a type tag yields an
+// arbitrary registered type or ErrUnknownType.
+func (k *KUID) Type() (string, error) {
+	r := defaultTypes.Load()
+	if r == nil {
+		return "", ErrUnknownType
+	}
+	return r.TypeOf(k)
+}
+
+// WithType stamps every KUID the Generator mints with the tag registered
+// for name in r. Use one Generator per entity type.
+func WithType(r *TypeRegistry, name string) Option {
+	return func(c *config) error {
+		r.mu.RLock()
+		tag, ok := r.tags[name]
+		r.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("type %q: %w", name, ErrUnknownType)
+		}
+		c.typeTag = embeddedField{name: "type", shift: 0, bits: r.bits, value: tag}
+		return nil
+	}
+}
+
+// embeddedField is a fixed value the Generator writes into the least
+// significant half of every KUID it mints
+type embeddedField struct {
+	name  string
+	shift int
+	bits  int
+	value uint64
+}
+
+func (f embeddedField) mask() uint64 {
+	return (1<<f.bits - 1) << f.shift
+}
+
+// addEmbedded adds f to the embedded fields, rejecting overlaps
+func (c *config) addEmbedded(f embeddedField) error {
+	for _, other := range c.embedded {
+		if other.mask()&f.mask() != 0 {
+			return fmt.Errorf("%s bits overlap %s bits", f.name, other.name)
+		}
+	}
+	c.embedded = append(c.embedded, f)
+	return nil
+}
+
+// embed writes the embedded fields into lsb
+func (c *config) embed(lsb uint64) uint64 {
+	for _, f := range c.embedded {
+		lsb = lsb&^f.mask() | f.value<<f.shift
+	}
+	return lsb
+}
@@ -0,0 +1,77 @@
+package kuid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypeRegistry(t *testing.T) {
+	r, err := NewTypeRegistry(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("user", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("order", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		tag  uint64
+	}{
+		{"user", 1},   // name taken
+		{"refund", 2}, // tag taken
+		{"big", 16},   // tag too wide
+	}
+	for _, tt := range tests {
+		if err := r.Register(tt.name, tt.tag); err == nil {
+			t.Errorf("Register(%q, %d) succeeded", tt.name, tt.tag)
+		}
+	}
+
+	users, _ := NewGenerator(WithType(r, "user"))
+	orders, _ := NewGenerator(WithType(r, "order"))
+	SetTypeRegistry(r)
+	defer SetTypeRegistry(nil)
+
+	for i := 0; i < 20; i++ {
+		u, _ := users.New()
+		o, _ := orders.NewOrdered()
+		if typ, err := u.Type(); typ != "user" || err != nil {
+			t.Fatalf("User KUID Type() = %q, %v", typ, err)
+		}
+		if typ, err := o.Type(); typ != "order" || err != nil {
+			t.Fatalf("Order KUID Type() = %q, %v", typ, err)
+		}
+	}
+
+	if _, err := NewGenerator(WithType(r, "refund")); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("WithType(refund) error = %v, want %v", err, ErrUnknownType)
+	}
+}
+
+func TestTypeWithoutRegistry(t *testing.T) {
+	k, _ := NewKUID()
+	if _, err := k.Type(); err != ErrUnknownType {
+		t.Errorf("Type() error = %v, want %v", err, ErrUnknownType)
+	}
+}
+
+func TestTypedKUIDsSpread(t *testing.T) {
+	types, _ := NewTypeRegistry(8)
+	types.Register("order", 7)
+	g, err := NewGenerator(WithType(types, "order"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	partitions := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		k, _ := g.New()
+		partitions[k.Partition(16)] = true
+	}
+	if len(partitions) != 16 {
+		t.Errorf("1000 typed KUIDs used %d of 16 partitions", len(partitions))
+	}
+}
@@ -0,0 +1,78 @@
+package kuid
+
+const (
+	// crockfordChars is the Crockford base32 alphabet used by ULIDs
+	crockfordChars = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	ulidDigits     = 26
+)
+
+// crockfordValues maps each byte to its base32 value, or 0xff if it is not
+// in the alphabet. Lowercase letters are accepted as the ULID spec asks.
+var crockfordValues = func() (t [256]byte) {
+	for i := range t {
+		t[i] = 0xff
+	}
+	for i := 0; i < len(crockfordChars); i++ {
+		t[crockfordChars[i]] = byte(i)
+		t[crockfordChars[i]|0x20] = byte(i)
+	}
+	return t
+}()
+
+// ULID adapts 16 bytes rendered in the 26-character ULID form to ID128
+type ULID [16]byte
+
+// Bytes returns a copy of the ULID's bytes
+func (u ULID) Bytes() []byte {
+	return append([]byte(nil), u[:]...)
+}
+
+// String returns the ULID form: 26 Crockford base32 characters, the first
+// carrying only the top 3 bits
+func (u ULID) String() string {
+	k := AsKUID(u)
+	return k.ToULID()
+}
+
+// Compare implements ID128
+func (u ULID) Compare(other ID128) int {
+	return compareID128(u, other)
+}
+
+// ToULID returns the KUID's 128 bits in the ULID string form. Ordered KUIDs
+// keep their millisecond timestamp in the first 48 bits, so they read as
+// ULIDs with the right time when minted without a Topology epoch.
+func (k *KUID) ToULID() string {
+	var dst [ulidDigits]byte
+	// 130 bits of output for 128 bits of input: work from the end, 5 bits
+	// at a time, leaving two zero bits at the top
+	hi, lo := k.msb, k.lsb
+	for i := ulidDigits - 1; i >= 0; i-- {
+		dst[i] = crockfordChars[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(dst[:])
+}
+
+// FromULID creates a KUID holding the 128 bits of a ULID. Lowercase is
+// accepted; I, L, O and U are not, and a first character above '7' is
+// rejected with ErrOverflow since it would need more than 128 bits.
+func FromULID(s string) (*KUID, error) {
+	if len(s) != ulidDigits {
+		return nil, ErrInvalidLength
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		digit := crockfordValues[s[i]]
+		if digit == 0xff {
+			return nil, ErrInvalidChar
+		}
+		if i == 0 && digit > 7 {
+			return nil, ErrOverflow
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(digit)
+	}
+	return &KUID{msb: hi, lsb: lo}, nil
+}
@@ -0,0 +1,60 @@
+package kuid
+
+import "testing"
+
+func TestULIDRoundTrip(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		k, _ := NewKUID()
+		s := k.ToULID()
+		back, err := FromULID(s)
+		if err != nil {
+			t.Fatalf("FromULID(%q) error = %v", s, err)
+		}
+		if !back.Equal(k) {
+			t.Errorf("FromULID(%q) = %v, want %v", s, back, k)
+		}
+	}
+}
+
+func TestFromULID(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    *KUID
+		wantErr error
+	}{
+		{name: "Zero", in: "00000000000000000000000000", want: &KUID{}},
+		{name: "Max", in: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", want: &KUID{msb: 1<<64 - 1, lsb: 1<<64 - 1}},
+		{name: "Lowercase", in: "014d2pf2dbsqqzxq5tk1v58cgg", want: &KUID{msb: 0x0123456789abcdef, lsb: 0xfedcba9876543210}},
+		{name: "Overflow", in: "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", wantErr: ErrOverflow},
+		{name: "Short", in: "01ARZ3NDEKTSV4RRFFQ69G5FA", wantErr: ErrInvalidLength},
+		{name: "Excluded letter", in: "01ARZ3NDEKTSV4RRFFQ69G5FAI", wantErr: ErrInvalidChar},
+		{name: "Symbol", in: "01ARZ3NDEKTSV4RRFFQ69G5FA-", wantErr: ErrInvalidChar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromULID(tt.in)
+			if err != tt.wantErr {
+				t.Fatalf("FromULID(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			if tt.want != nil && !got.Equal(tt.want) {
+				t.Errorf("FromULID(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestULIDOrderedTimestamp(t *testing.T) {
+	g, _ := NewGenerator()
+	k, _ := g.NewOrdered()
+
+	// A ULID's first 10 characters are its millisecond timestamp
+	var ms int64
+	for _, c := range []byte(k.ToULID()[:10]) {
+		ms = ms<<5 | int64(crockfordValues[c])
+	}
+	if want := k.Timestamp().UnixMilli(); ms != want {
+		t.Errorf("ULID timestamp = %d, want %d", ms, want)
+	}
+}
@@ -0,0 +1,73 @@
+package kuid
+
+import (
+	"crypto/sha256"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// URLOptions controls how FromURL canonicalizes URLs
+type URLOptions struct {
+	// KeepFragment keeps the #fragment, for single-page apps that route on it
+	KeepFragment bool
+	// IgnoreParams lists query parameters to drop, such as tracking
+	// parameters. A trailing "*" matches any suffix, as in "utm_*".
+	IgnoreParams []string
+}
+
+// FromURL returns a deterministic KUID for u, so URLs that differ only in
+// spelling map to the same KUID. See CanonicalURL for the rules.
+func FromURL(u *url.URL, opts URLOptions) *KUID {
+	h := sha256.New()
+	h.Write([]byte(CanonicalURL(u, opts)))
+	k, _ := SumKUID(h)
+	return k
+}
+
+// CanonicalURL returns the form of u that FromURL hashes: the scheme and
+// host are lowercased, default ports and user info are removed, the path
+// is re-escaped consistently and defaults to "/", query parameters are
+// sorted by name then value with ignored ones dropped, and the fragment is
+// removed unless opts.KeepFragment is set.
+func CanonicalURL(u *url.URL, opts URLOptions) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80" || scheme == "https" && port == "443") {
+		host += ":" + port
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+
+	c := url.URL{Scheme: scheme, Host: host, Path: u.Path}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	if u.Opaque != "" {
+		c = url.URL{Scheme: scheme, Opaque: u.Opaque}
+	}
+
+	query := u.Query()
+	for name, values := range query {
+		if ignoredParam(name, opts.IgnoreParams) {
+			delete(query, name)
+			continue
+		}
+		slices.Sort(values)
+	}
+	c.RawQuery = query.Encode() // sorts by name
+	if opts.KeepFragment {
+		c.Fragment = u.Fragment
+	}
+	return c.String()
+}
+
+// ignoredParam reports whether name matches one of patterns
+func ignoredParam(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(name, prefix) || p == name {
+			return true
+		}
+	}
+	return false
+}
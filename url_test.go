@@ -0,0 +1,55 @@
+package kuid
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	opts := URLOptions{IgnoreParams: []string{"utm_*", "ref"}}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+		opts URLOptions
+	}{
+		{name: "Lowercase", in: "HTTPS://Example.COM/Path", want: "https://example.com/Path"},
+		{name: "Default port", in: "https://example.com:443/", want: "https://example.com/"},
+		{name: "Other port", in: "http://example.com:8080/", want: "http://example.com:8080/"},
+		{name: "Empty path", in: "https://example.com", want: "https://example.com/"},
+		{name: "User info", in: "https://user:pw@example.com/", want: "https://example.com/"},
+		{name: "IPv6", in: "http://[::1]:80/a", want: "http://[::1]/a"},
+		{name: "Escaping", in: "https://example.com/a%20b/%7Euser", want: "https://example.com/a%20b/~user"},
+		{name: "Sorted query", in: "https://example.com/?b=2&a=3&a=1", want: "https://example.com/?a=1&a=3&b=2"},
+		{name: "Ignored params", in: "https://example.com/?utm_source=x&ref=y&id=1&referrer=z", want: "https://example.com/?id=1&referrer=z", opts: opts},
+		{name: "Fragment", in: "https://example.com/#top", want: "https://example.com/"},
+		{name: "Kept fragment", in: "https://example.com/#/inbox", want: "https://example.com/#/inbox", opts: URLOptions{KeepFragment: true}},
+		{name: "Opaque", in: "mailto:Someone@example.com", want: "mailto:Someone@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := CanonicalURL(u, tt.opts); got != tt.want {
+				t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromURL(t *testing.T) {
+	a, _ := url.Parse("https://Example.com:443/page?b=2&a=1#section")
+	b, _ := url.Parse("https://example.com/page?a=1&b=2")
+	c, _ := url.Parse("https://example.com/page?a=1&b=3")
+
+	if !FromURL(a, URLOptions{}).Equal(FromURL(b, URLOptions{})) {
+		t.Errorf("FromURL() differs for equivalent URLs")
+	}
+	if FromURL(b, URLOptions{}).Equal(FromURL(c, URLOptions{})) {
+		t.Errorf("FromURL() equal for different URLs")
+	}
+}
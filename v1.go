@@ -0,0 +1,166 @@
+package kuid
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotTimeBased = errors.New("KUID is not a version 1 or 6 UUID")
+	ErrInvalidNode  = errors.New("node ID must be 6 bytes")
+)
+
+// gregorianOffset is the number of 100ns intervals between the Gregorian
+// epoch (1582-10-15) used by UUIDv1 and v6 and the Unix epoch
+const gregorianOffset = 0x01b21dd213814000
+
+// defaultV1Generator backs NewV1KUID and NewV6KUID. It is created on first
+// use so a failure to draw its random node ID surfaces as an error.
+var defaultV1Generator = sync.OnceValues(func() (*V1Generator, error) {
+	return NewV1Generator(nil)
+})
+
+// V1Generator mints Gregorian-timestamp UUIDs (versions 1 and 6) for
+// interoperating with systems that read timestamps out of their IDs. It is
+// safe for concurrent use.
+type V1Generator struct {
+	node [6]byte
+
+	mu        sync.Mutex
+	clockSeq  uint16
+	lastClock uint64 // last clock reading
+	lastTicks uint64 // last timestamp handed out, at or after lastClock
+	now       func() time.Time
+}
+
+// NewV1Generator creates a V1Generator with the given 6-byte node ID,
+// traditionally a MAC address. A nil node picks a random one with the
+// multicast bit set, as RFC 9562 recommends, so it cannot clash with a real
+// MAC address. The clock sequence starts at a random value.
+func NewV1Generator(node []byte) (*V1Generator, error) {
+	g := &V1Generator{now: time.Now}
+	if node == nil {
+		if err := readRandom(g.node[:]); err != nil {
+			return nil, err
+		}
+		g.node[0] |= 0x01
+	} else if len(node) != len(g.node) {
+		return nil, ErrInvalidNode
+	} else {
+		copy(g.node[:], node)
+	}
+
+	var seq [2]byte
+	if err := readRandom(seq[:]); err != nil {
+		return nil, err
+	}
+	g.clockSeq = binary.BigEndian.Uint16(seq[:]) & 0x3fff
+	return g, nil
+}
+
+// NewV1KUID mints a version 1 UUID as a KUID using a random node ID
+func NewV1KUID() (*KUID, error) {
+	g, err := defaultV1Generator()
+	if err != nil {
+		return nil, err
+	}
+	return g.NewV1(), nil
+}
+
+// NewV6KUID mints a version 6 UUID as a KUID using a random node ID
+func NewV6KUID() (*KUID, error) {
+	g, err := defaultV1Generator()
+	if err != nil {
+		return nil, err
+	}
+	return g.NewV6(), nil
+}
+
+// next returns the timestamp and clock sequence for the next UUID. Within
+// one 100ns tick the timestamp is advanced artificially; if the clock goes
+// backwards the clock sequence is incremented instead, as RFC 9562 requires.
+func (g *V1Generator) next() (ticks uint64, clockSeq uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	clock := uint64(g.now().UnixNano()/100) + gregorianOffset
+	if clock < g.lastClock {
+		// Only a real regression bumps the clock sequence, not the clock
+		// catching up with timestamps advanced within one tick
+		g.clockSeq = (g.clockSeq + 1) & 0x3fff
+		g.lastTicks = clock - 1
+	}
+	g.lastClock = clock
+	ticks = max(clock, g.lastTicks+1)
+	g.lastTicks = ticks
+	return ticks, g.clockSeq
+}
+
+// lsb builds the variant, clock sequence and node half shared by v1 and v6
+func (g *V1Generator) lsb(clockSeq uint16) uint64 {
+	var b [8]byte
+	binary.BigEndian.PutUint16(b[:2], clockSeq)
+	copy(b[2:], g.node[:])
+	return binary.BigEndian.Uint64(b[:])&^variantMask | variantRFC
+}
+
+// NewV1 mints a version 1 UUID: the timestamp is split low-field first, so
+// v1 UUIDs do not sort by time
+func (g *V1Generator) NewV1() *KUID {
+	ticks, seq := g.next()
+	timeLow := ticks & 0xffffffff
+	timeMid := ticks >> 32 & 0xffff
+	timeHi := ticks >> 48 & 0x0fff
+	msb := timeLow<<32 | timeMid<<16 | 1<<versionShift | timeHi
+	return &KUID{msb: msb, lsb: g.lsb(seq)}
+}
+
+// NewV6 mints a version 6 UUID: the v1 fields reordered most significant
+// first, so v6 UUIDs sort by time
+func (g *V1Generator) NewV6() *KUID {
+	ticks, seq := g.next()
+	msb := ticks>>12<<16 | 6<<versionShift | ticks&0x0fff
+	return &KUID{msb: msb, lsb: g.lsb(seq)}
+}
+
+// gregorianTicks returns the 60-bit timestamp of a v1 or v6 KUID
+func (k *KUID) gregorianTicks() (uint64, error) {
+	if !k.IsRFC9562() {
+		return 0, ErrNotTimeBased
+	}
+	switch k.Version() {
+	case 1:
+		timeLow := k.msb >> 32
+		timeMid := k.msb >> 16 & 0xffff
+		timeHi := k.msb & 0x0fff
+		return timeHi<<48 | timeMid<<32 | timeLow, nil
+	case 6:
+		return k.msb>>16<<12 | k.msb&0x0fff, nil
+	}
+	return 0, ErrNotTimeBased
+}
+
+// UUIDTime returns the creation time of a version 1 or 6 KUID, to 100ns
+// precision
+func (k *KUID) UUIDTime() (time.Time, error) {
+	ticks, err := k.gregorianTicks()
+	if err != nil {
+		return time.Time{}, err
+	}
+	unix100ns := int64(ticks) - gregorianOffset
+	return time.Unix(0, unix100ns*100), nil
+}
+
+// ClockSequence returns the 14-bit clock sequence of a version 1 or 6 KUID
+func (k *KUID) ClockSequence() uint16 {
+	return uint16(k.lsb>>48) & 0x3fff
+}
+
+// Node returns the 6-byte node ID of a version 1 or 6 KUID
+func (k *KUID) Node() [6]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], k.lsb)
+	return [6]byte(b[2:])
+}
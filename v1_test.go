@@ -0,0 +1,100 @@
+package kuid
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestV1KnownUUID(t *testing.T) {
+	// The DNS namespace UUID from RFC 9562 is a v1 UUID
+	k, _ := FromUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	got, err := k.UUIDTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(1998, 2, 4, 22, 13, 53, 151182400, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("UUIDTime() = %v, want %v", got.UTC(), want)
+	}
+	if k.ClockSequence() != 0x00b4 || k.Node() != [6]byte{0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8} {
+		t.Errorf("ClockSequence() = %#x, Node() = %x", k.ClockSequence(), k.Node())
+	}
+}
+
+func TestV6KnownUUID(t *testing.T) {
+	// Test vector from RFC 9562 appendix A.5
+	k, _ := FromUUID("1ec9414c-232a-6b00-b3c8-9f6bdeced846")
+	got, err := k.UUIDTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("UUIDTime() = %v, want %v", got.UTC(), want)
+	}
+}
+
+func TestV1Generator(t *testing.T) {
+	node := []byte{1, 2, 3, 4, 5, 6}
+	g, err := NewV1Generator(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	for _, k := range []*KUID{g.NewV1(), g.NewV6()} {
+		if !k.IsRFC9562() || k.Node() != [6]byte(node) {
+			t.Errorf("%s: IsRFC9562() = %v, Node() = %x", k.ToUUID(), k.IsRFC9562(), k.Node())
+		}
+		got, _ := k.UUIDTime()
+		if d := got.Sub(now); d < 0 || d > time.Microsecond {
+			t.Errorf("%s: UUIDTime() = %v, want %v", k.ToUUID(), got, now)
+		}
+	}
+
+	// Clock going backwards bumps the clock sequence
+	a := g.NewV6()
+	now = now.Add(-time.Second)
+	b := g.NewV6()
+	if b.ClockSequence() != (a.ClockSequence()+1)&0x3fff {
+		t.Errorf("ClockSequence() = %d after regression, want %d", b.ClockSequence(), a.ClockSequence()+1)
+	}
+
+	// The clock catching up with advanced timestamps is not a regression
+	c := g.NewV6()
+	g.NewV6()
+	now = now.Add(100 * time.Nanosecond)
+	d := g.NewV6()
+	if d.ClockSequence() != c.ClockSequence() || bytes.Compare(d.Bytes(), c.Bytes()) <= 0 {
+		t.Errorf("%s after %s: clock sequence bumped or order lost", d.ToUUID(), c.ToUUID())
+	}
+
+	if _, err := NewV1Generator([]byte{1}); err != ErrInvalidNode {
+		t.Errorf("NewV1Generator() error = %v, want %v", err, ErrInvalidNode)
+	}
+}
+
+func TestV6Ordering(t *testing.T) {
+	prev, _ := NewV6KUID()
+	for i := 0; i < 1000; i++ {
+		k, _ := NewV6KUID()
+		if bytes.Compare(k.Bytes(), prev.Bytes()) <= 0 {
+			t.Fatalf("%s does not sort after %s", k.ToUUID(), prev.ToUUID())
+		}
+		prev = k
+	}
+
+	v1, _ := NewV1KUID()
+	if v1.Version() != 1 || v1.Node()[0]&1 != 1 {
+		t.Errorf("NewV1KUID() = %s, want v1 with multicast node", v1.ToUUID())
+	}
+}
+
+func TestUUIDTimeNotTimeBased(t *testing.T) {
+	k, _ := NewV4KUID()
+	if _, err := k.UUIDTime(); err != ErrNotTimeBased {
+		t.Errorf("UUIDTime() error = %v, want %v", err, ErrNotTimeBased)
+	}
+}
@@ -0,0 +1,85 @@
+package kuid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestV8Builder(t *testing.T) {
+	b, err := NewV8Builder(nil,
+		V8Field{Name: "ts", Bits: 48, Timestamp: true},
+		V8Field{Name: "region", Bits: 6},
+		V8Field{Name: "tenant", Bits: 32},
+		V8Field{Name: "shard", Bits: 36},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.UnixMilli(1714560000123)
+	b.stampFn = func() time.Time { return now }
+
+	want := map[string]uint64{"region": 63, "tenant": 0xdeadbeef, "shard": 1<<36 - 1}
+	k, err := b.New(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Version() != 8 || !k.IsRFC9562() {
+		t.Errorf("%s is not a v8 UUID", k.ToUUID())
+	}
+
+	got, err := b.Fields(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("Field %q = %#x, want %#x", name, got[name], v)
+		}
+	}
+	if ts, _ := b.Time(k, "ts"); !ts.Equal(now) {
+		t.Errorf("Time() = %v, want %v", ts, now)
+	}
+	if tenant, _ := b.Get(k, "tenant"); tenant != 0xdeadbeef {
+		t.Errorf("Get(tenant) = %#x", tenant)
+	}
+}
+
+func TestV8BuilderOrdering(t *testing.T) {
+	b, _ := NewV8Builder(nil, V8Field{Name: "ts", Bits: 48, Timestamp: true})
+	now := time.Now()
+	b.stampFn = func() time.Time { return now }
+	first, _ := b.New(nil)
+	now = now.Add(time.Millisecond)
+	second, _ := b.New(nil)
+	if bytes.Compare(first.Bytes(), second.Bytes()) >= 0 {
+		t.Errorf("Later KUID does not sort after earlier one")
+	}
+}
+
+func TestV8BuilderErrors(t *testing.T) {
+	if _, err := NewV8Builder(nil, V8Field{Name: "a", Bits: 64}, V8Field{Name: "b", Bits: 59}); err == nil {
+		t.Errorf("Layout over 122 bits accepted")
+	}
+	if _, err := NewV8Builder(nil, V8Field{Name: "a", Bits: 8}, V8Field{Name: "a", Bits: 8}); err == nil {
+		t.Errorf("Duplicate field accepted")
+	}
+	if _, err := NewV8Builder(nil, V8Field{Name: "a", Bits: 65}); err == nil {
+		t.Errorf("Field over 64 bits accepted")
+	}
+
+	b, _ := NewV8Builder(nil, V8Field{Name: "tenant", Bits: 8}, V8Field{Name: "ts", Bits: 20, Timestamp: true})
+	if _, err := b.New(map[string]uint64{"tenant": 256}); !errors.Is(err, ErrOverflow) {
+		t.Errorf("New(tenant=256) error = %v, want %v", err, ErrOverflow)
+	}
+	if _, err := b.New(map[string]uint64{"other": 1}); err == nil {
+		t.Errorf("Unknown field accepted")
+	}
+
+	ok, _ := NewV8Builder(nil, V8Field{Name: "tenant", Bits: 8})
+	v4, _ := NewV4KUID()
+	if _, err := ok.Get(v4, "tenant"); err != ErrWrongVersion {
+		t.Errorf("Get(v4) error = %v, want %v", err, ErrWrongVersion)
+	}
+}
@@ -0,0 +1,49 @@
+package kuid
+
+// A KUID is two machine words, so passing it by value is as cheap as
+// passing a pointer. Services minting or parsing millions of short-lived
+// KUIDs should use the value APIs below: they leave nothing for the garbage
+// collector, which a pool of *KUID cannot match since pooled values still
+// escape to the heap.
+
+// NewValue generates a new random KUID without allocating
+func NewValue() (KUID, error) {
+	return defaultGenerator.NewValue()
+}
+
+// ParseValue is like FromString but returns the KUID by value
+func ParseValue(s string) (KUID, error) {
+	return parseValue(s)
+}
+
+func parseValue[T string | []byte](s T) (KUID, error) {
+	if len(s) != size*2 {
+		return KUID{}, ErrInvalidLength
+	}
+	msb, err := decodeLong(s[:size])
+	if err != nil {
+		return KUID{}, err
+	}
+	lsb, err := decodeLong(s[size:])
+	if err != nil {
+		return KUID{}, err
+	}
+	return KUID{msb: msb, lsb: lsb}, nil
+}
+
+// AppendString appends the base62 form of the KUID to dst, allocating only
+// if dst lacks capacity
+func (k KUID) AppendString(dst []byte) []byte {
+	dst = appendLong(dst, k.msb)
+	return appendLong(dst, k.lsb)
+}
+
+func appendLong(dst []byte, value uint64) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, size)...)
+	for i := n + size - 1; i >= n; i-- {
+		dst[i] = base62Chars[value%base]
+		value /= base
+	}
+	return dst
+}
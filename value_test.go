@@ -0,0 +1,103 @@
+package kuid
+
+import "testing"
+
+func TestValueAPIs(t *testing.T) {
+	k, err := NewValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := []byte("id=")
+	buf = k.AppendString(buf)
+	if string(buf) != "id="+k.String() {
+		t.Errorf("AppendString() = %q, want %q", buf, "id="+k.String())
+	}
+
+	parsed, err := ParseValue(k.String())
+	if err != nil || parsed != k {
+		t.Errorf("ParseValue() = %v, %v, want %v", parsed, err, k)
+	}
+
+	for _, s := range []string{"", "short", "!!!!!!!!!!!!!!!!!!!!!!"} {
+		if _, err := ParseValue(s); err == nil {
+			t.Errorf("ParseValue(%q) succeeded", s)
+		}
+	}
+}
+
+func TestValueAPIsDoNotAllocate(t *testing.T) {
+	g, _ := NewGenerator(WithUnsafeMathRand())
+	k, _ := g.NewValue()
+	s := k.String()
+	buf := make([]byte, 0, 2*size)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		k, _ = g.NewValue()
+		k, _ = ParseValue(s)
+		buf = k.AppendString(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("value APIs allocate %v times per run", allocs)
+	}
+}
+
+// The benchmarks below compare the GC pressure of pointer and value APIs
+// when minting and formatting short-lived KUIDs
+
+func BenchmarkNewPointer(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewKUID(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewValue(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewValue(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsePointer(b *testing.B) {
+	s := "7n42DGM5Tfl2CQZcquv8Vb"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := FromString(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseValue(b *testing.B) {
+	s := "7n42DGM5Tfl2CQZcquv8Vb"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseValue(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+var stringSink string
+
+func BenchmarkString(b *testing.B) {
+	k, _ := NewValue()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		stringSink = k.String()
+	}
+}
+
+func BenchmarkAppendString(b *testing.B) {
+	k, _ := NewValue()
+	buf := make([]byte, 0, 2*size)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = k.AppendString(buf[:0])
+	}
+}
@@ -0,0 +1,47 @@
+package kuid
+
+// RFC 9562 (formerly RFC 4122) version and variant fields. The version is
+// the top nibble of byte 6 and the variant the top two bits of byte 8.
+const (
+	versionMask  = 0xf << 12
+	variantMask  = 0b11 << 62
+	variantRFC   = 0b10 << 62
+	versionShift = 12
+)
+
+// NewV4KUID generates a random KUID whose UUID form is a valid version 4
+// UUID. It carries 122 random bits instead of 128; use NewKUID when UUID
+// validators downstream do not matter.
+func NewV4KUID() (*KUID, error) {
+	return defaultGenerator.NewV4()
+}
+
+// NewV4 is like New but sets the UUID version 4 and variant bits
+func (g *Generator) NewV4() (*KUID, error) {
+	k, err := g.newRandom(func(k KUID) KUID {
+		return k.withVersion(4)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// withVersion sets the version nibble and the RFC variant bits
+func (k KUID) withVersion(v int) KUID {
+	k.msb = k.msb&^versionMask | uint64(v)<<versionShift
+	k.lsb = k.lsb&^variantMask | variantRFC
+	return k
+}
+
+// Version returns the UUID version field. It is only meaningful when
+// IsRFC9562 reports true; raw KUIDs carry random bits there.
+func (k *KUID) Version() int {
+	return int(k.msb&versionMask) >> versionShift
+}
+
+// IsRFC9562 reports whether the KUID's variant bits mark it as an RFC 9562
+// (RFC 4122) UUID
+func (k *KUID) IsRFC9562() bool {
+	return k.lsb&variantMask == variantRFC
+}
@@ -0,0 +1,58 @@
+package kuid
+
+import (
+	"regexp"
+	"testing"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewV4KUID(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		k, err := NewV4KUID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !uuidV4Pattern.MatchString(k.ToUUID()) {
+			t.Fatalf("ToUUID() = %s, not a v4 UUID", k.ToUUID())
+		}
+		if k.Version() != 4 || !k.IsRFC9562() {
+			t.Fatalf("Version() = %d, IsRFC9562() = %v", k.Version(), k.IsRFC9562())
+		}
+	}
+}
+
+func TestVersionFromUUID(t *testing.T) {
+	tests := []struct {
+		uuid    string
+		version int
+		rfc     bool
+	}{
+		{"f47ac10b-58cc-4372-a567-0e02b2c3d479", 4, true},
+		{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", 1, true},
+		{"00000000-0000-0000-0000-000000000000", 0, false},
+		{"ffffffff-ffff-ffff-ffff-ffffffffffff", 15, false},
+	}
+	for _, tt := range tests {
+		k, err := FromUUID(tt.uuid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if k.Version() != tt.version || k.IsRFC9562() != tt.rfc {
+			t.Errorf("%s: Version() = %d, IsRFC9562() = %v, want %d, %v", tt.uuid, k.Version(), k.IsRFC9562(), tt.version, tt.rfc)
+		}
+	}
+}
+
+func TestNewV4Blocklist(t *testing.T) {
+	g, _ := NewGenerator(WithBlocklist("A"))
+	for i := 0; i < 50; i++ {
+		k, err := g.NewV4()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.config().blocked(k.String()) || k.Version() != 4 {
+			t.Fatalf("NewV4() = %s", k)
+		}
+	}
+}
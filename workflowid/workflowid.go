@@ -0,0 +1,240 @@
+// Package workflowid builds deterministic Temporal and Cadence workflow
+// IDs from a workflow type, business keys and a KUID.
+//
+// IDs have the form
+//
+//	<type>:<key>:<key>...:<KUID>
+//
+// Keys are escaped, so keys containing the separator cannot collide with
+// other key lists, and the same inputs always give the same ID, which lets
+// Temporal's workflow ID reuse policy reject duplicate starts. IDs that
+// would exceed the server's length limit (1000 bytes by default) have
+// their keys cut short and followed by a hash of the full keys:
+//
+//	invoice:acme%3Aeu:2026-10~3hTn7Qk1yUe:6f1Kx0aN2bXwGmTqYc0LzR
+//
+// so they stay unique, though Parse can then only recover the type and
+// KUID.
+package workflowid
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alphabatem/kuid"
+)
+
+var (
+	ErrInvalidType = errors.New("invalid workflow type")
+	ErrInvalid     = errors.New("invalid workflow ID")
+	ErrTooLong     = errors.New("workflow type too long for maximum ID length")
+)
+
+const (
+	sep       = ':'
+	hashSep   = '~'
+	idSize    = 22
+	hashSize  = 11
+	hexDigits = "0123456789ABCDEF"
+
+	// DefaultMaxLength is the default workflow ID limit of Temporal and
+	// Cadence servers, in bytes
+	DefaultMaxLength = 1000
+)
+
+// ID is a parsed workflow ID
+type ID struct {
+	Type string
+	Keys []string
+	KUID kuid.KUID
+	// Truncated reports that the keys were cut short and are not
+	// available; rebuild the ID from its inputs to compare it
+	Truncated bool
+}
+
+// Builder builds workflow IDs up to a maximum length
+type Builder struct {
+	maxLength int
+}
+
+// NewBuilder creates a Builder for servers limiting workflow IDs to
+// maxLength bytes, or DefaultMaxLength if maxLength is 0
+func NewBuilder(maxLength int) (*Builder, error) {
+	if maxLength == 0 {
+		maxLength = DefaultMaxLength
+	}
+	if maxLength < 64 {
+		return nil, errors.New("maximum workflow ID length must be at least 64")
+	}
+	return &Builder{maxLength: maxLength}, nil
+}
+
+var defaultBuilder = &Builder{maxLength: DefaultMaxLength}
+
+// Build returns the workflow ID for a workflow of type workflowType about
+// id and keys, within DefaultMaxLength
+func Build(workflowType string, id kuid.KUID, keys ...string) (string, error) {
+	return defaultBuilder.Build(workflowType, id, keys...)
+}
+
+// Build returns the workflow ID for a workflow of type workflowType about
+// id and keys. Types may contain ASCII letters, digits and "_-."; keys may
+// be any strings.
+func (b *Builder) Build(workflowType string, id kuid.KUID, keys ...string) (string, error) {
+	if !validType(workflowType) {
+		return "", ErrInvalidType
+	}
+
+	escaped := make([]string, len(keys))
+	for i, k := range keys {
+		escaped[i] = escape(k)
+	}
+	body := strings.Join(escaped, string(sep))
+
+	// type, separators and KUID are always present
+	fixed := len(workflowType) + 1 + idSize
+	if len(keys) > 0 {
+		fixed++
+	}
+	if fixed+len(body) > b.maxLength {
+		room := b.maxLength - fixed - 1 - hashSize
+		if room < 0 {
+			return "", ErrTooLong
+		}
+		sum := sha256.Sum256([]byte(body))
+		hash, _ := kuid.FromBytes(sum[:16])
+		body = cut(body, room) + string(hashSep) + hash.String()[:hashSize]
+	}
+
+	var s strings.Builder
+	s.Grow(fixed + len(body))
+	s.WriteString(workflowType)
+	s.WriteByte(sep)
+	if len(keys) > 0 {
+		s.WriteString(body)
+		s.WriteByte(sep)
+	}
+	s.WriteString(id.String())
+	return s.String(), nil
+}
+
+// Parse parses a workflow ID produced by Build
+func Parse(s string) (*ID, error) {
+	typeEnd := strings.IndexByte(s, sep)
+	if typeEnd < 0 || len(s) < typeEnd+1+idSize || !validType(s[:typeEnd]) {
+		return nil, ErrInvalid
+	}
+	id, err := kuid.ParseValue(s[len(s)-idSize:])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	parsed := &ID{Type: s[:typeEnd], KUID: id}
+	if len(s) == typeEnd+1+idSize {
+		return parsed, nil
+	}
+
+	body, ok := strings.CutSuffix(s[typeEnd+1:len(s)-idSize], string(sep))
+	if !ok {
+		return nil, ErrInvalid
+	}
+	if i := strings.IndexByte(body, hashSep); i >= 0 {
+		if len(body)-i-1 != hashSize {
+			return nil, ErrInvalid
+		}
+		parsed.Truncated = true
+		return parsed, nil
+	}
+	for _, k := range strings.Split(body, string(sep)) {
+		key, ok := unescape(k)
+		if !ok {
+			return nil, ErrInvalid
+		}
+		parsed.Keys = append(parsed.Keys, key)
+	}
+	return parsed, nil
+}
+
+// escape percent-encodes the separators and '%' itself
+func escape(key string) string {
+	if !strings.ContainsAny(key, "%:~") {
+		return key
+	}
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; c {
+		case '%', sep, hashSep:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0xf])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// unescape reverses escape, rejecting encodings escape never produces so
+// every key list has exactly one ID
+func unescape(s string) (string, bool) {
+	if !strings.Contains(s, "%") {
+		return s, true
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", false
+		}
+		switch s[i+1 : i+3] {
+		case "25":
+			b.WriteByte('%')
+		case "3A":
+			b.WriteByte(sep)
+		case "7E":
+			b.WriteByte(hashSep)
+		default:
+			return "", false
+		}
+		i += 2
+	}
+	return b.String(), true
+}
+
+// cut shortens an escaped body to at most n bytes without splitting an
+// escape or a UTF-8 sequence
+func cut(body string, n int) string {
+	if len(body) <= n {
+		return body
+	}
+	body = body[:n]
+	if i := strings.LastIndexByte(body, '%'); i >= 0 && i > len(body)-3 {
+		body = body[:i]
+	}
+	for i := 1; i <= utf8.UTFMax-1 && i <= len(body); i++ {
+		if tail := body[len(body)-i:]; utf8.RuneStart(tail[0]) {
+			if !utf8.FullRuneInString(tail) {
+				body = body[:len(body)-i]
+			}
+			break
+		}
+	}
+	return body
+}
+
+func validType(t string) bool {
+	if t == "" || len(t) > 255 {
+		return false
+	}
+	for i := 0; i < len(t); i++ {
+		c := t[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
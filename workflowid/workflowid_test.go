@@ -0,0 +1,126 @@
+package workflowid
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/alphabatem/kuid"
+)
+
+func TestBuildParse(t *testing.T) {
+	id, _ := kuid.NewValue()
+	tests := []struct {
+		name string
+		typ  string
+		keys []string
+		want string
+	}{
+		{"no keys", "onboarding", nil, "onboarding:" + id.String()},
+		{"keys", "invoice", []string{"acme", "2026-10"}, "invoice:acme:2026-10:" + id.String()},
+		{"escaped", "invoice", []string{"a:b", "50%", "~x"}, "invoice:a%3Ab:50%25:%7Ex:" + id.String()},
+		{"empty key", "invoice.v2", []string{""}, "invoice.v2::" + id.String()},
+		{"unicode", "greet", []string{"héllo"}, "greet:héllo:" + id.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Build(tt.typ, id, tt.keys...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tt.want {
+				t.Errorf("Build() = %q, want %q", s, tt.want)
+			}
+			parsed, err := Parse(s)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", s, err)
+			}
+			want := &ID{Type: tt.typ, Keys: tt.keys, KUID: id}
+			if !reflect.DeepEqual(parsed, want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", s, parsed, want)
+			}
+		})
+	}
+}
+
+func TestNoCollisions(t *testing.T) {
+	id, _ := kuid.NewValue()
+	a, _ := Build("t", id, "a:b", "c")
+	b, _ := Build("t", id, "a", "b:c")
+	c, _ := Build("t", id, "a:b:c")
+	if a == b || b == c || a == c {
+		t.Errorf("key lists collide: %q %q %q", a, b, c)
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	id, _ := kuid.NewValue()
+	b, err := NewBuilder(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := strings.Repeat("x", 200)
+
+	s1, err := b.Build("export", id, long, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, _ := b.Build("export", id, long, "b")
+	if len(s1) > 100 || len(s2) > 100 {
+		t.Errorf("IDs longer than 100 bytes: %d, %d", len(s1), len(s2))
+	}
+	if s1 == s2 {
+		t.Error("truncated IDs collide")
+	}
+	if again, _ := b.Build("export", id, long, "a"); again != s1 {
+		t.Error("truncated ID is not deterministic")
+	}
+
+	parsed, err := Parse(s1)
+	if err != nil || !parsed.Truncated || parsed.KUID != id || parsed.Type != "export" || parsed.Keys != nil {
+		t.Errorf("Parse(%q) = %+v, %v", s1, parsed, err)
+	}
+
+	// Cuts never split an escape or a UTF-8 sequence
+	for n := 64; n < 90; n++ {
+		b, _ := NewBuilder(n)
+		for _, key := range []string{strings.Repeat("::", 50), strings.Repeat("é€😀", 20)} {
+			s, err := b.Build("t", id, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s) > n || !utf8.ValidString(s) {
+				t.Errorf("Build(max %d) = %q", n, s)
+			}
+			if _, err := Parse(s); err != nil {
+				t.Errorf("Parse(%q) error = %v", s, err)
+			}
+		}
+	}
+
+	if _, err := NewBuilder(10); err == nil {
+		t.Error("NewBuilder(10) succeeded")
+	}
+	tight, _ := NewBuilder(64)
+	if _, err := tight.Build(strings.Repeat("t", 40), id, "k"); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Build() long type error = %v", err)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	id, _ := kuid.NewValue()
+	if _, err := Build("bad type", id); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Build() error = %v", err)
+	}
+	valid := id.String()
+	for _, s := range []string{
+		"", valid, ":" + valid, "t:" + valid[1:], "t:x" + valid, "t:a%3" + ":" + valid,
+		"t:a%41:" + valid, "t:a~short:" + valid, "bad type:" + valid,
+	} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v", s, err)
+		}
+	}
+}